@@ -3,11 +3,15 @@ package main
 import (
 	"log"
 
+	"tableflip.dev/bujo/pkg/app"
 	"tableflip.dev/bujo/pkg/commands"
 )
 
 func main() {
 	if err := commands.New().Execute(); err != nil {
+		if hint := app.Hint(err); hint != "" {
+			log.Fatalf("error during command execution: %v\nhint: %s", err, hint)
+		}
 		log.Fatalf("error during command execution: %v", err)
 	}
 }
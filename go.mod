@@ -1,17 +1,19 @@
 module tableflip.dev/bujo
 
-go 1.12
+go 1.13
 
 require (
 	github.com/fatih/color v1.9.0
 	github.com/gosuri/uitable v0.0.4
 	github.com/marcusolsson/tui-go v0.4.0
+	github.com/mattn/go-runewidth v0.0.9
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/n3wscott/cli-base v0.0.0-20200320151736-40d38c556506
 	github.com/peterbourgon/diskv/v3 v3.0.0
 	github.com/spf13/cobra v1.0.0
 	github.com/spf13/viper v1.7.1
 	go.hein.dev/go-version v0.1.0
+	golang.org/x/crypto v0.14.0
 )
 
 require (
@@ -19,13 +21,12 @@ require (
 	github.com/gdamore/tcell v1.3.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.0.3 // indirect
 	github.com/mattn/go-colorable v0.1.7 // indirect
-	github.com/mattn/go-runewidth v0.0.9 // indirect
 	github.com/mitchellh/mapstructure v1.3.3 // indirect
 	github.com/pelletier/go-toml v1.8.0 // indirect
 	github.com/spf13/afero v1.3.4 // indirect
 	github.com/spf13/cast v1.3.1 // indirect
 	github.com/spf13/jwalterweatherman v1.1.0 // indirect
-	golang.org/x/sys v0.0.0-20220731174439-a90be440212d // indirect
+	golang.org/x/sys v0.13.0 // indirect
 	gopkg.in/ini.v1 v1.60.0 // indirect
 	sigs.k8s.io/yaml v1.2.0 // indirect
 )
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the journal, wrapped with the details. Compare with
+// errors.Is, the message of a wrapped error is for people.
+var (
+	// ErrCollectionNotFound is returned for a collection with no entries.
+	ErrCollectionNotFound = errors.New("collection not found")
+	// ErrEntryNotFound is returned for an id or ref that matches no entry.
+	ErrEntryNotFound = errors.New("entry not found")
+	// ErrAmbiguousRef is returned for a ref that matches more than one entry.
+	ErrAmbiguousRef = errors.New("ref matches more than one entry")
+	// ErrInvalidBullet is returned for an unknown bullet or bullet alias.
+	ErrInvalidBullet = errors.New("unknown bullet")
+	// ErrInvalidLabel is returned for an unknown color label.
+	ErrInvalidLabel = errors.New("unknown label")
+	// ErrInvalidReaction is returned for an unknown reaction.
+	ErrInvalidReaction = errors.New("unknown reaction")
+	// ErrConflict is returned when an entry was changed elsewhere since it
+	// was read.
+	ErrConflict = errors.New("entry was changed elsewhere")
+	// ErrUnsupported is returned when the journal can not do what was asked,
+	// like archiving a remote journal.
+	ErrUnsupported = errors.New("not supported by this journal")
+	// ErrValidation is returned for an invalid value, see ValidationError.
+	ErrValidation = errors.New("invalid value")
+)
+
+// ValidationError is an invalid value for a field, like a flag.
+type ValidationError struct {
+	Field string
+	Value string
+	// Reason says what is expected instead.
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("invalid %s: %q", e.Field, e.Value)
+	}
+	return fmt.Sprintf("invalid %s: %q, %s", e.Field, e.Value, e.Reason)
+}
+
+// Is makes every ValidationError match ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Invalid returns a ValidationError for the field.
+func Invalid(field, value, reason string) error {
+	return &ValidationError{Field: field, Value: value, Reason: reason}
+}
+
+// hints are what to do about each error, in the order they are checked.
+var hints = []struct {
+	err  error
+	hint string
+}{
+	{ErrCollectionNotFound, "list the collections with: bujo get --list"},
+	{ErrEntryNotFound, "show entry refs with: bujo get -i"},
+	{ErrAmbiguousRef, "use more of the id, shown with: bujo get -i"},
+	{ErrInvalidBullet, "see the bullets and their aliases with: bujo get --help"},
+	{ErrInvalidLabel, "see the labels with: bujo label --help"},
+	{ErrInvalidReaction, "see the reactions with: bujo react --help"},
+	{ErrConflict, "run the command again to use the latest version"},
+}
+
+// Hint returns what can be done to recover from err, or "" if there is
+// nothing to suggest.
+func Hint(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, h := range hints {
+		if errors.Is(err, h.err) {
+			return h.hint
+		}
+	}
+	return ""
+}
@@ -0,0 +1,242 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+
+	"tableflip.dev/bujo/pkg/logging"
+	"tableflip.dev/bujo/pkg/notify"
+)
+
+// Automation is a journal action run on a schedule, configured like:
+//
+// automations:
+//   - name: weekly-digest
+//     schedule: "0 18 * * 0"
+//     run: report notes 1w --out digest.md
+type Automation struct {
+	Name string `mapstructure:"name"`
+	// Schedule is a cron expression.
+	Schedule string `mapstructure:"schedule"`
+	// Run is the bujo command to run, without the leading "bujo". Arguments
+	// are split like a shell does, so quote those with spaces.
+	Run string `mapstructure:"run"`
+}
+
+// Validate checks the automation can be scheduled.
+func (a *Automation) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("automation %q: missing name", a.Run)
+	}
+	args, err := a.Args()
+	if err != nil {
+		return fmt.Errorf("automation %q: %v", a.Name, err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("automation %q: missing run", a.Name)
+	}
+	if _, err := ParseSchedule(a.Schedule); err != nil {
+		return fmt.Errorf("automation %q: %v", a.Name, err)
+	}
+	return nil
+}
+
+// Args are the arguments for the bujo command to run.
+func (a *Automation) Args() ([]string, error) {
+	return splitArgs(a.Run)
+}
+
+// splitArgs splits s into words like a posix shell, without expansions:
+// single quotes keep everything, double quotes keep all but \" and \\, and
+// a backslash outside quotes escapes the next character.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args  []string
+		word  strings.Builder
+		inArg bool
+		quote rune
+	)
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(rs) && (rs[i+1] == '"' || rs[i+1] == '\\') {
+				i++
+				word.WriteRune(rs[i])
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\':
+			if i+1 == len(rs) {
+				return nil, fmt.Errorf("trailing backslash in %q", s)
+			}
+			i++
+			word.WriteRune(rs[i])
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, word.String())
+				word.Reset()
+				inArg = false
+			}
+		default:
+			word.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c in %q", quote, s)
+	}
+	if inArg {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// Exec runs an automation and returns its combined output.
+type Exec func(ctx context.Context, a Automation) ([]byte, error)
+
+// Self runs the automation with the running bujo binary.
+func Self(ctx context.Context, a Automation) ([]byte, error) {
+	args, err := a.Args()
+	if err != nil {
+		return nil, err
+	}
+	bin, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, bin, args...).CombinedOutput()
+}
+
+// Scheduler runs automations when their schedules fire.
+type Scheduler struct {
+	Automations []Automation
+	Exec        Exec
+	History     *History
+	// Now defaults to time.Now.
+	Now func() time.Time
+	// Logf reports runs as they happen, if set.
+	Logf func(format string, args ...interface{})
+	// Sink is notified when a run finishes, if set.
+	Sink notify.Sink
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Scheduler) logf(format string, args ...interface{}) {
+	if s.Logf != nil {
+		s.Logf(format, args...)
+	}
+}
+
+// Run blocks, running each automation as it comes due, until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.Automations) == 0 {
+		return fmt.Errorf("no automations configured")
+	}
+	schedules := make([]*Schedule, len(s.Automations))
+	for i, a := range s.Automations {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		schedules[i], _ = ParseSchedule(a.Schedule)
+	}
+
+	next := make([]time.Time, len(s.Automations))
+	now := s.now()
+	for i, sc := range schedules {
+		next[i] = sc.Next(now)
+		s.logf("%s next runs at %s", s.Automations[i].Name, next[i].Format(time.RFC1123))
+	}
+
+	for {
+		soonest := time.Time{}
+		for _, t := range next {
+			if !t.IsZero() && (soonest.IsZero() || t.Before(soonest)) {
+				soonest = t
+			}
+		}
+		if soonest.IsZero() {
+			return fmt.Errorf("no automation will run again")
+		}
+
+		timer := time.NewTimer(soonest.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		now := s.now()
+		for i, a := range s.Automations {
+			if next[i].IsZero() || next[i].After(now) {
+				continue
+			}
+			s.RunOnce(ctx, a)
+			next[i] = schedules[i].Next(now)
+		}
+	}
+}
+
+// RunOnce runs the automation now and records it in the history.
+func (s *Scheduler) RunOnce(ctx context.Context, a Automation) Run {
+	exe := s.Exec
+	if exe == nil {
+		exe = Self
+	}
+
+	r := Run{Name: a.Name, Started: s.now()}
+	out, err := exe(ctx, a)
+	r.Finished = s.now()
+	r.Output = strings.TrimSpace(string(out))
+	if err != nil {
+		r.Error = err.Error()
+		s.logf("%s failed: %v", a.Name, err)
+		logging.Error("automation failed", "automation", a.Name, "took", r.Finished.Sub(r.Started), "err", err)
+	} else {
+		s.logf("%s ok", a.Name)
+		logging.Info("automation ran", "automation", a.Name, "took", r.Finished.Sub(r.Started))
+	}
+
+	if s.History != nil {
+		if err := s.History.Record(r); err != nil {
+			s.logf("failed to record run of %s: %v", a.Name, err)
+			logging.Warn("failed to record automation run", "automation", a.Name, "err", err)
+		}
+	}
+	if s.Sink != nil {
+		m := notify.Message{Title: a.Name + " finished"}
+		if !r.OK() {
+			m = notify.Message{Title: a.Name + " failed", Body: r.Error}
+		}
+		if err := s.Sink.Notify(ctx, m); err != nil {
+			s.logf("failed to notify for %s: %v", a.Name, err)
+			logging.Warn("failed to notify", "automation", a.Name, "err", err)
+		}
+	}
+	return r
+}
@@ -0,0 +1,37 @@
+package automation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	tests := map[string]struct {
+		run     string
+		want    []string
+		wantErr bool
+	}{
+		"plain":          {run: "report notes 1w", want: []string{"report", "notes", "1w"}},
+		"extra spaces":   {run: "  report\tnotes  ", want: []string{"report", "notes"}},
+		"single quotes":  {run: `report notes --out '~/My Notes/digest.md'`, want: []string{"report", "notes", "--out", "~/My Notes/digest.md"}},
+		"double quotes":  {run: `log "call \"mom\" back"`, want: []string{"log", `call "mom" back`}},
+		"escaped space":  {run: `log call\ mom`, want: []string{"log", "call mom"}},
+		"empty quotes":   {run: `log ''`, want: []string{"log", ""}},
+		"joined quotes":  {run: `log a'b c'"d"`, want: []string{"log", "ab cd"}},
+		"empty":          {run: "  ", want: nil},
+		"unterminated":   {run: `log 'call mom`, wantErr: true},
+		"trailing slash": {run: `log mom\`, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := Automation{Name: name, Run: tc.run}
+			got, err := a.Args()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Args() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Args() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package automation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := map[string]struct {
+		spec    string
+		dow     uint64
+		wantErr bool
+	}{
+		"any":            {spec: "0 18 * * *", dow: 0x7f},
+		"sunday":         {spec: "0 18 * * 0", dow: 1 << 0},
+		"sunday as 7":    {spec: "0 18 * * 7", dow: 1 << 0},
+		"weekdays":       {spec: "0 9 * * 1-5", dow: 0x3e},
+		"to sunday":      {spec: "0 9 * * 5-7", dow: 1<<5 | 1<<6 | 1<<0},
+		"list":           {spec: "0 9 * * 1,7", dow: 1<<1 | 1<<0},
+		"step":           {spec: "0 9 * * */2", dow: 1<<0 | 1<<2 | 1<<4 | 1<<6},
+		"too few":        {spec: "0 9 * *", wantErr: true},
+		"out of range":   {spec: "0 9 * * 8", wantErr: true},
+		"backwards":      {spec: "0 9 * * 5-1", wantErr: true},
+		"bad step":       {spec: "*/0 9 * * *", wantErr: true},
+		"bad value":      {spec: "0 x * * *", wantErr: true},
+		"minute range":   {spec: "60 9 * * *", wantErr: true},
+		"day of month 0": {spec: "0 9 0 * *", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSchedule(tc.spec)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSchedule(%q) error = %v, wantErr %v", tc.spec, err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if s.dow != tc.dow {
+				t.Errorf("dow = %b, want %b", s.dow, tc.dow)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// A Wednesday.
+	from := time.Date(2020, time.January, 1, 12, 30, 0, 0, time.UTC)
+	tests := map[string]struct {
+		spec string
+		want time.Time
+	}{
+		"every minute":  {spec: "* * * * *", want: time.Date(2020, time.January, 1, 12, 31, 0, 0, time.UTC)},
+		"later today":   {spec: "0 18 * * *", want: time.Date(2020, time.January, 1, 18, 0, 0, 0, time.UTC)},
+		"tomorrow":      {spec: "0 9 * * *", want: time.Date(2020, time.January, 2, 9, 0, 0, 0, time.UTC)},
+		"sunday":        {spec: "0 18 * * 0", want: time.Date(2020, time.January, 5, 18, 0, 0, 0, time.UTC)},
+		"sunday as 7":   {spec: "0 18 * * 7", want: time.Date(2020, time.January, 5, 18, 0, 0, 0, time.UTC)},
+		"friday to 7":   {spec: "0 9 * * 5-7", want: time.Date(2020, time.January, 3, 9, 0, 0, 0, time.UTC)},
+		"saturday to 7": {spec: "0 9 * * 6-7", want: time.Date(2020, time.January, 4, 9, 0, 0, 0, time.UTC)},
+		"day of month":  {spec: "0 0 15 * *", want: time.Date(2020, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		"either day":    {spec: "0 0 15 * 4", want: time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		"odd days":      {spec: "0 0 */2 * 4", want: time.Date(2020, time.January, 9, 0, 0, 0, 0, time.UTC)},
+		"even weekdays": {spec: "0 0 15 * */2", want: time.Date(2020, time.February, 15, 0, 0, 0, 0, time.UTC)},
+		"next month":    {spec: "0 0 1 2 *", want: time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		"leap day":      {spec: "0 0 29 2 *", want: time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		"every 15":      {spec: "*/15 * * * *", want: time.Date(2020, time.January, 1, 12, 45, 0, 0, time.UTC)},
+		"never":         {spec: "0 0 31 2 *", want: time.Time{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSchedule(tc.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(from); !got.Equal(tc.want) {
+				t.Errorf("Next(%v) = %v, want %v", from, got, tc.want)
+			}
+		})
+	}
+}
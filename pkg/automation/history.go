@@ -0,0 +1,92 @@
+package automation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxRuns is how many runs the history keeps, the oldest are dropped.
+const maxRuns = 200
+
+// Run is one run of an automation.
+type Run struct {
+	Name     string    `json:"name"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Error    string    `json:"error,omitempty"`
+	Output   string    `json:"output,omitempty"`
+}
+
+// OK is true if the run did not fail.
+func (r *Run) OK() bool {
+	return r.Error == ""
+}
+
+// History is the run history of automations, kept in a json file.
+type History struct {
+	Path string
+	// NoOutput drops the output of runs, it can have entries in it and is
+	// not kept next to an encrypted journal.
+	NoOutput bool
+}
+
+// Runs returns the recorded runs, oldest first.
+func (h *History) Runs() ([]Run, error) {
+	b, err := ioutil.ReadFile(h.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	if err := json.Unmarshal(b, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// Last returns the latest run of the named automation, or nil.
+func (h *History) Last(name string) (*Run, error) {
+	runs, err := h.Runs()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].Name == name {
+			return &runs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Record appends a run to the history.
+func (h *History) Record(r Run) error {
+	runs, err := h.Runs()
+	if err != nil {
+		return err
+	}
+	runs = append(runs, r)
+	if len(runs) > maxRuns {
+		runs = runs[len(runs)-maxRuns:]
+	}
+	if h.NoOutput {
+		for i := range runs {
+			runs[i].Output = ""
+		}
+	}
+	b, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(h.Path, b, 0600); err != nil {
+		return err
+	}
+	// A history written before keeps its mode otherwise.
+	return os.Chmod(h.Path, 0600)
+}
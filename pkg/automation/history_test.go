@@ -0,0 +1,42 @@
+package automation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistoryNoOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bujo-automations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "journal.automations.json")
+
+	plain := &History{Path: path}
+	if err := plain.Record(Run{Name: "report", Output: "call the bank"}); err != nil {
+		t.Fatal(err)
+	}
+	encrypted := &History{Path: path, NoOutput: true}
+	if err := encrypted.Record(Run{Name: "report", Output: "call the bank again"}); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := encrypted.Runs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2", len(runs))
+	}
+	for _, r := range runs {
+		if r.Output != "" {
+			t.Errorf("output %q is kept", r.Output)
+		}
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("the history is not private: %v", err)
+	}
+}
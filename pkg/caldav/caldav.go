@@ -0,0 +1,113 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client writes calendar items into a single CalDAV calendar collection.
+type Client struct {
+	// URL of the calendar collection, like
+	// https://dav.example.com/calendars/me/bujo/
+	URL      string
+	Username string
+	Password string
+
+	HTTP *http.Client
+}
+
+func (c *Client) do(ctx context.Context, method, uid string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.URL, "/")+"/"+uid+".ics", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	}
+
+	hc := c.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// Put creates or replaces the calendar item with the given uid.
+func (c *Client) Put(ctx context.Context, uid string, ics []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, uid, ics)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("caldav put %s: %s", uid, resp.Status)
+	}
+	return nil
+}
+
+// Delete removes the calendar item with the given uid. An item that is
+// already gone is not an error.
+func (c *Client) Delete(ctx context.Context, uid string) error {
+	resp, err := c.do(ctx, http.MethodDelete, uid, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("caldav delete %s: %s", uid, resp.Status)
+	}
+	return nil
+}
+
+// Event renders an all day event, with a reminder at 9am, as an iCalendar
+// document.
+func Event(uid, summary string, day time.Time) []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//tableflip.dev//bujo//EN",
+	}
+	lines = append(lines, allDay(uid, summary, day)...)
+	lines = append(lines, "END:VCALENDAR")
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// allDay is the VEVENT of an all day event, with a reminder at 9am.
+func allDay(uid, summary string, day time.Time) []string {
+	const layoutDate = "20060102"
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	return []string{
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + time.Now().UTC().Format("20060102T150405Z"),
+		"DTSTART;VALUE=DATE:" + start.Format(layoutDate),
+		"DTEND;VALUE=DATE:" + start.AddDate(0, 0, 1).Format(layoutDate),
+		"SUMMARY:" + escape(summary),
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:" + escape(summary),
+		"TRIGGER;RELATED=START:PT9H",
+		"END:VALARM",
+		"END:VEVENT",
+	}
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
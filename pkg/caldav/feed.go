@@ -0,0 +1,31 @@
+package caldav
+
+import (
+	"strings"
+	"time"
+)
+
+// Item is an all day event of a feed.
+type Item struct {
+	UID     string
+	Summary string
+	Day     time.Time
+}
+
+// Feed renders the items as one iCalendar document named name, for
+// calendar apps to import or subscribe to.
+func Feed(name string, items []Item) []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//tableflip.dev//bujo//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escape(name),
+	}
+	for _, it := range items {
+		lines = append(lines, allDay(it.UID, it.Summary, it.Day)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
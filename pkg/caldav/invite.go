@@ -0,0 +1,50 @@
+package caldav
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Attendee is who a meeting invite is sent to.
+type Attendee struct {
+	Name  string
+	Email string
+}
+
+// Invite renders a meeting request from start for length, from organizer
+// to the attendees, as an iCalendar document mail clients open as an
+// invite. The organizer can be empty.
+func Invite(uid, summary string, start time.Time, length time.Duration, organizer string, attendees []Attendee) []byte {
+	const layoutUTC = "20060102T150405Z"
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//tableflip.dev//bujo//EN",
+		"METHOD:REQUEST",
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + time.Now().UTC().Format(layoutUTC),
+		"DTSTART:" + start.UTC().Format(layoutUTC),
+		"DTEND:" + start.Add(length).UTC().Format(layoutUTC),
+		"SUMMARY:" + escape(summary),
+	}
+	if organizer != "" {
+		lines = append(lines, "ORGANIZER:mailto:"+organizer)
+	}
+	for _, a := range attendees {
+		lines = append(lines, fmt.Sprintf("ATTENDEE;CN=%s;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:%s", quote(a.Name), a.Email))
+	}
+	lines = append(lines,
+		"STATUS:CONFIRMED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// quote makes s a parameter value, which can not hold a double quote.
+func quote(s string) string {
+	return `"` + strings.Replace(s, `"`, "'", -1) + `"`
+}
@@ -0,0 +1,169 @@
+package collection
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// These layouts name the dated collections in the Legacy scheme.
+const (
+	LayoutDay    = "January 2, 2006"
+	LayoutMonth  = "January, 2006"
+	LayoutFuture = "Future - January, 2006"
+)
+
+// Aliases for the day logs around today.
+const (
+	Today     = "today"
+	Tomorrow  = "tomorrow"
+	Yesterday = "yesterday"
+)
+
+// ThisWeek is the alias for the week log of this week. The colon keeps it
+// apart from a collection named "Week".
+const ThisWeek = ":week"
+
+// These layouts are accepted for a day in Resolve, like --on accepts them.
+const (
+	layoutDate      = "2006-1-2"
+	layoutDateShort = "1/2"
+)
+
+// Kind is the kind of a collection, derived from its name.
+type Kind int
+
+const (
+	Other Kind = iota
+	Future
+	Month
+	// Week is before Day so a week log reads before its Monday.
+	Week
+	Day
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Future:
+		return "future log"
+	case Month:
+		return "month log"
+	case Week:
+		return "week log"
+	case Day:
+		return "day log"
+	default:
+		return "collection"
+	}
+}
+
+// Parse returns the kind of the collection and, for dated collections, the
+// day, the Monday of the week or the first of the month it refers to.
+func Parse(name string) (Kind, time.Time) {
+	for _, s := range schemes {
+		if kind, t, ok := s.parse(name); ok {
+			return kind, t
+		}
+	}
+	return Other, time.Time{}
+}
+
+// DayOf returns the day log collection for t.
+func DayOf(t time.Time) string {
+	return current.Of(Day, t)
+}
+
+// MonthOf returns the month log collection for t.
+func MonthOf(t time.Time) string {
+	return current.Of(Month, t)
+}
+
+// WeekOf returns the week log collection for the ISO week of t.
+func WeekOf(t time.Time) string {
+	return current.Of(Week, t)
+}
+
+// Monday returns the start of the ISO week of t.
+func Monday(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// FutureOf returns the future log collection for the month of t.
+func FutureOf(t time.Time) string {
+	return current.Of(Future, t)
+}
+
+// Resolve returns the day log for names that are a day: the today,
+// tomorrow and yesterday aliases, or a date like 2020-2-28 or 2/28, and
+// this week's log for the :week alias. Otherwise it returns name. Dated
+// logs are made by their first entry, so a resolved one does not need to
+// exist yet.
+func Resolve(name string) string {
+	if strings.EqualFold(name, ThisWeek) {
+		return WeekOf(time.Now())
+	}
+	if t, ok := dayOf(name, time.Now()); ok {
+		return DayOf(t)
+	}
+	return name
+}
+
+// dayOf returns the day a name refers to, if it is a day alias or date. A
+// date without a year is the next one, like 1/3 on 12/5 is in January.
+func dayOf(name string, now time.Time) (time.Time, bool) {
+	switch strings.ToLower(name) {
+	case Today:
+		return now, true
+	case Tomorrow:
+		return now.AddDate(0, 0, 1), true
+	case Yesterday:
+		return now.AddDate(0, 0, -1), true
+	}
+	if t, err := time.ParseInLocation(layoutDate, name, now.Location()); err == nil {
+		return t, true
+	}
+	t, err := time.ParseInLocation(layoutDateShort, name, now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		t = t.AddDate(1, 0, 0)
+	}
+	return t, true
+}
+
+// Sort orders collections the way the journal reads: dated collections
+// first in time order, with the future log, then the month log, then the
+// days of each month. Other collections follow, by name.
+func Sort(names []string) {
+	type key struct {
+		name string
+		kind Kind
+		on   time.Time
+	}
+	keys := make([]key, len(names))
+	for i, n := range names {
+		k, on := Parse(n)
+		keys[i] = key{name: n, kind: k, on: on}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if (a.kind == Other) != (b.kind == Other) {
+			return b.kind == Other
+		}
+		if a.kind == Other {
+			return a.name < b.name
+		}
+		if !a.on.Equal(b.on) {
+			return a.on.Before(b.on)
+		}
+		return a.kind < b.kind
+	})
+
+	for i, k := range keys {
+		names[i] = k.name
+	}
+}
@@ -0,0 +1,21 @@
+package collection
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveWeek(t *testing.T) {
+	week := WeekOf(time.Now())
+	tests := map[string]string{
+		":week": week,
+		":WEEK": week,
+		"week":  "week",
+		"Week":  "Week",
+	}
+	for name, want := range tests {
+		if got := Resolve(name); got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package collection
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Scheme is how dated collections are named.
+type Scheme struct {
+	Name   string
+	Day    string
+	Month  string
+	Future string
+	// Week names week logs, time layouts have no ISO week so {week} and
+	// {year} stand for them.
+	Week string
+}
+
+var (
+	// Legacy names dated collections like "January 2, 2006".
+	Legacy = Scheme{Name: "legacy", Day: LayoutDay, Month: LayoutMonth, Future: LayoutFuture, Week: "Week {week}, {year}"}
+	// ISO names dated collections like "2006-01-02".
+	ISO = Scheme{Name: "iso", Day: "2006-01-02", Month: "2006-01", Future: "Future - 2006-01", Week: "{year}-W{week}"}
+
+	schemes = []Scheme{Legacy, ISO}
+	current = Legacy
+)
+
+// SchemeFor returns the scheme with the given name, "" is Legacy.
+func SchemeFor(name string) (Scheme, error) {
+	if name == "" {
+		return Legacy, nil
+	}
+	for _, s := range schemes {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return Scheme{}, app.Invalid("collection scheme", name, "expected legacy or iso")
+}
+
+// Use sets the scheme new dated collections are named with. Collections
+// named with any scheme are still understood by Parse.
+func Use(s Scheme) {
+	current = s
+}
+
+// Current returns the scheme in use.
+func Current() Scheme {
+	return current
+}
+
+// Of returns the collection of the kind for t in the scheme.
+func (s Scheme) Of(kind Kind, t time.Time) string {
+	switch kind {
+	case Day:
+		return t.Format(s.Day)
+	case Month:
+		return t.Format(s.Month)
+	case Future:
+		return t.Format(s.Future)
+	case Week:
+		year, week := t.ISOWeek()
+		return strings.NewReplacer("{week}", fmt.Sprintf("%02d", week), "{year}", strconv.Itoa(year)).Replace(s.Week)
+	}
+	return ""
+}
+
+// parse parses a dated collection named with the scheme.
+func (s Scheme) parse(name string) (Kind, time.Time, bool) {
+	if t, err := time.ParseInLocation(s.Day, name, time.Local); err == nil {
+		return Day, t, true
+	}
+	if t, err := time.ParseInLocation(s.Month, name, time.Local); err == nil {
+		return Month, t, true
+	}
+	if t, err := time.ParseInLocation(s.Future, name, time.Local); err == nil {
+		return Future, t, true
+	}
+	if t, ok := s.parseWeek(name); ok {
+		return Week, t, true
+	}
+	return Other, time.Time{}, false
+}
+
+// parseWeek parses a week log named with the scheme, returning its Monday.
+func (s Scheme) parseWeek(name string) (time.Time, bool) {
+	if s.Week == "" {
+		return time.Time{}, false
+	}
+	pattern := regexp.QuoteMeta(s.Week)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{week}"), `(\d{1,2})`, 1)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("{year}"), `(\d{4})`, 1)
+	m := regexp.MustCompile("^" + pattern + "$").FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+	week, _ := strconv.Atoi(m[1])
+	year, _ := strconv.Atoi(m[2])
+	if strings.Index(s.Week, "{year}") < strings.Index(s.Week, "{week}") {
+		week, year = year, week
+	}
+	// January 4th is always in the first ISO week.
+	t := Monday(time.Date(year, time.January, 4, 0, 0, 0, 0, time.Local)).AddDate(0, 0, 7*(week-1))
+	if y, w := t.ISOWeek(); y != year || w != week {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Rename returns the name of the collection in the scheme, or false if it is
+// not a dated collection or is already named with the scheme.
+func (s Scheme) Rename(name string) (string, bool) {
+	kind, t := Parse(name)
+	if kind == Other {
+		return "", false
+	}
+	to := s.Of(kind, t)
+	return to, to != name
+}
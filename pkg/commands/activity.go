@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/activity"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addActivity(topLevel *cobra.Command) {
+	ao := &options.ActivityOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "activity [window]",
+		Short: "A feed of what happened in the journal, newest first.",
+		Long: `A feed of what happened in the journal, newest first.
+
+Entries are added when they were created, and completed, moved or struck
+when they were. Any other change is an edit at the time the entry was last
+written, so only the last edit of an entry is listed. Compact and encrypt
+are not edits, but a sync that writes entries again lists them as edited.
+Entries completed, moved or struck before bujo kept when are listed as
+changed when they were last written. Resolved conflicts are listed too.
+
+The window is like 3d, 2w, 1m or 1y and defaults to one week. Use -i to
+show the ref of each entry, refs can be used in place of ids.
+`,
+		Example: `
+bujo activity
+bujo activity 1m -i
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				ao.Window = args[0]
+			}
+			now := time.Now()
+			since, err := options.ParseSince(ao.Window, now)
+			if err != nil {
+				return err
+			}
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := activity.Activity{
+				Since:       since,
+				Until:       now,
+				ShowID:      ao.ShowID,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddActivityArgs(cmd, ao)
+
+	topLevel.AddCommand(cmd)
+}
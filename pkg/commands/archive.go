@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/archive"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addArchive(topLevel *cobra.Command) {
+	ao := &options.ArchiveOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "archive <collection>",
+		Short: "Move a collection out of the journal into the archive.",
+		Long: `Move a collection out of the journal into the archive.
+
+Archived collections are kept next to the journal, they are not listed by
+bujo get --list or shown in the ui until the archived toggle is on, and
+bujo search --archived and bujo export --archived still reach them. Bring
+one back with bujo unarchive.
+`,
+		Example: `
+bujo archive "Trip to Lisbon"
+bujo archive --list
+bujo unarchive "Trip to Lisbon"
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !ao.List {
+				return errors.New("requires a collection to archive")
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := archive.Archive{
+				Collection:  collection.Resolve(strings.Join(args, " ")),
+				List:        ao.List,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddArchiveArgs(cmd, ao)
+
+	topLevel.AddCommand(cmd)
+}
+
+func addUnarchive(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "unarchive <collection>",
+		Short: "Move an archived collection back into the journal.",
+		Example: `
+bujo unarchive "Trip to Lisbon"
+`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return archivedCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := archive.Archive{
+				Collection:  collection.Resolve(strings.Join(args, " ")),
+				Unarchive:   true,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+// archivedCompletions are the archived collections that start with
+// toComplete.
+func archivedCompletions(toComplete string) []string {
+	p, err := store.Load(nil)
+	if err != nil {
+		return nil
+	}
+	a, ok := p.(store.Archiver)
+	if !ok {
+		return nil
+	}
+	cs := make([]string, 0)
+	for _, c := range a.Archived(context.Background()) {
+		if strings.HasPrefix(c, toComplete) {
+			cs = append(cs, strconv.Quote(c))
+		}
+	}
+	return cs
+}
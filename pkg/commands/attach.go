@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/attach"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addAttach(topLevel *cobra.Command) {
+	ao := &options.AttachOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "attach <entry id> [url or path] [label]",
+		Short: "Link a URL or a file to an entry",
+		Long: `Link a URL or a file to an entry, like a ticket, a doc or an image.
+
+Anything with a scheme, like https:// or mailto:, is a URL, anything
+else is a file and is kept as an absolute path. Attaching the same target
+again changes its label. Attachments are listed under the entry, numbered
+from 1 for --remove and --open. --open uses the opener of the OS, open
+on macOS and xdg-open on Linux.`,
+		Example: `
+bujo attach <entry id> https://example.com/issues/42 the bug
+bujo attach <entry id> ~/Pictures/whiteboard.png
+bujo attach <entry id> --open 1
+bujo attach <entry id> --remove 2
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case len(args) < 1:
+				return errors.New("requires an entry id")
+			case ao.Open > 0 && ao.Remove > 0:
+				return errors.New("--open and --remove can not be used together")
+			case len(args) > 1 && (ao.Open > 0 || ao.Remove > 0):
+				return errors.New("a url or path can not be attached with --open or --remove")
+			case len(args) < 2 && ao.Open == 0 && ao.Remove == 0:
+				return errors.New("requires a url or path to attach, --open or --remove")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s := attach.Attach{
+				ID:     args[0],
+				Remove: ao.Remove,
+				Open:   ao.Open,
+			}
+			if len(args) > 1 {
+				s.Target = args[1]
+				s.Label = strings.Join(args[2:], " ")
+			}
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s.Persistence = p
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddAttachArgs(cmd, ao)
+
+	topLevel.AddCommand(cmd)
+}
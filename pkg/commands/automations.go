@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/automation"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/automations"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// historySuffix is added to the store path for the automation run history.
+const historySuffix = ".automations.json"
+
+func addAutomations(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "automations",
+		Short: "Run journal actions on a schedule.",
+		Long: `Run journal actions on a schedule.
+
+Automations are set in the config, the schedule is a cron expression
+and run is the bujo command to run, for example:
+
+automations:
+- name: weekly-digest
+  schedule: "0 18 * * 0"
+  run: report notes 1w --out digest.md
+- name: monthly-sweep
+  schedule: "0 9 1 * *"
+  run: maintenance --yes
+
+Saved reports with a schedule are run too, see bujo report saved --help.
+
+Each run sends a notification when it finishes, see bujo remind --help
+for where they go.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	addAutomationsList(cmd)
+	addAutomationsRun(cmd)
+
+	topLevel.AddCommand(cmd)
+}
+
+func loadAutomations() ([]automation.Automation, *automation.History, error) {
+	cfg, err := store.LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	var all []automation.Automation
+	if err := viper.UnmarshalKey("automations", &all); err != nil {
+		return nil, nil, err
+	}
+	// Saved reports with a schedule run as automations.
+	saved, err := savedReports()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, r := range saved {
+		if r.Schedule != "" {
+			all = append(all, r.Automation())
+		}
+	}
+	return all, &automation.History{Path: cfg.BasePath() + historySuffix, NoOutput: cfg.Encrypted()}, nil
+}
+
+func addAutomationsList(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the automations and when they last ran.",
+		Example: `
+bujo automations list
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, history, err := loadAutomations()
+			if err != nil {
+				return err
+			}
+
+			s := automations.List{
+				Automations: all,
+				History:     history,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+func addAutomationsRun(topLevel *cobra.Command) {
+	ao := &options.AutomationsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the automation scheduler in the foreground.",
+		Example: `
+bujo automations run
+bujo automations run --now weekly-digest
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, history, err := loadAutomations()
+			if err != nil {
+				return err
+			}
+			sink, err := notifier()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, os.Interrupt)
+			go func() {
+				<-sig
+				cancel()
+			}()
+
+			s := automations.Start{
+				Automations: all,
+				History:     history,
+				Now:         ao.Now,
+				Sink:        sink,
+			}
+			err = s.Do(ctx)
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddAutomationsArgs(cmd, ao)
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,246 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/backup"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+const signLong = `
+Exports are signed and verified with minisign, the keys are set in the
+config, for example:
+
+sign:
+  secret_key: ~/.minisign/minisign.key
+  public_key: ~/.minisign/minisign.pub
+  exports: true
+  require: true
+`
+
+const formatLong = `
+The format is json, opml or org, picked by the extension of the file
+unless --format is set. OPML and org have a top level node for each
+collection with its entries under it, tasks are TODO, DONE, WAIT or
+CANCELED in org. The other fields of an entry are kept as attributes or
+properties, so exports in any format import as they were. Outline nodes
+without them import as notes.
+`
+
+const exportFormatLong = `
+Exports can also be markdown (or md) to read or share, with a heading for
+each collection, nested collections under their parent, or csv for
+spreadsheets, with a row for each entry. What happened to each entry, as
+bujo activity shows it, is listed under it in markdown, in the last column
+of csv and in the history of json. Markdown and csv can not be imported.
+`
+
+const importFormatLong = `
+Markdown and todo.txt files from other tools import too. In markdown,
+task list items are tasks, checked ones completed, struck out items
+irrelevant and other list items notes, in the collection of the heading
+above them or today's log. In todo.txt, x is completed, (A) is the
+priority signifier, the creation date is the day log a task goes to and
+due: is when it is on. Neither has ids, so importing one again adds its
+entries again.
+`
+
+const mergeLong = `
+Entries already in the journal, by id, are merged. newest-wins keeps the
+import unless the journal changed the entry after the export was made,
+prefer-local and prefer-import always keep one side, and interactive asks
+for each entry. A summary of the merge is printed.
+`
+
+func formatCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return backup.Formats(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func importFormatCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return backup.Imports(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// signer returns the configured signer, or nil if no keys are configured.
+func signer() (backup.Signer, error) {
+	secret, err := homedir.Expand(viper.GetString("sign.secret_key"))
+	if err != nil {
+		return nil, err
+	}
+	public, err := homedir.Expand(viper.GetString("sign.public_key"))
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" && public == "" {
+		return nil, nil
+	}
+	return &backup.Minisign{SecretKey: secret, PublicKey: public}, nil
+}
+
+func addExport(topLevel *cobra.Command) {
+	eo := &options.ExportOptions{}
+	po := &options.PrivateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export the journal to a file.",
+		Long:  "Export the journal to a file.\n" + formatLong + exportFormatLong + signLong,
+		Example: `
+bujo export journal.json
+bujo export journal.json --sign
+bujo export work.opml -c Work
+bujo export today.org -c today
+bujo export october.md -c "October 2026"
+bujo export journal.csv
+bujo export trips.md --archived -c Trips
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+
+			s := backup.Export{
+				File:           args[0],
+				Format:         eo.Format,
+				IncludePrivate: po.IncludePrivate,
+				Archived:       eo.Archived,
+				Persistence:    p,
+			}
+			for _, c := range eo.Collections {
+				s.Collections = append(s.Collections, collection.Resolve(c))
+			}
+			if eo.Sign || viper.GetBool("sign.exports") {
+				if s.Signer, err = signer(); err != nil {
+					return err
+				}
+				if s.Signer == nil {
+					return errors.New("no key configured to sign with, set sign.secret_key")
+				}
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddExportArgs(cmd, eo)
+	options.AddIncludePrivateArg(cmd, po)
+	_ = cmd.RegisterFlagCompletionFunc("format", formatCompletions)
+	_ = cmd.RegisterFlagCompletionFunc("collection", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	addExportStats(cmd)
+
+	topLevel.AddCommand(cmd)
+}
+
+func addExportStats(topLevel *cobra.Command) {
+	so := &options.StatsExportOptions{}
+	po := &options.PrivateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Export what happened to each entry, for analysis.",
+		Long: `Export what happened to each entry, for analysis.
+
+Each row is an event of an entry over the window: added, completed, moved,
+struck, edited or resolved, when it happened, when the entry was created
+and how long after. For a completed task that is how long it took. The
+journal only knows the last change to an entry, as bujo activity shows.
+
+The format is csv or jsonl, a json object per line, picked by the
+extension of --out unless --format is set. Both read into pandas, duckdb
+or a spreadsheet, parquet is not built in.
+`,
+		Example: `
+bujo export stats --out events.csv
+bujo export stats --window 3m --format jsonl > events.jsonl
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := backup.ExportStats{
+				File:           so.Out,
+				Format:         so.Format,
+				IncludePrivate: po.IncludePrivate,
+				Persistence:    p,
+			}
+			if so.Window != "all" {
+				if s.Since, err = options.ParseSince(so.Window, time.Now()); err != nil {
+					return err
+				}
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddStatsExportArgs(cmd, so)
+	options.AddIncludePrivateArg(cmd, po)
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{backup.StatsCSV, backup.StatsJSONL}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	topLevel.AddCommand(cmd)
+}
+
+func addImport(topLevel *cobra.Command) {
+	io := &options.ImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import an export into the journal.",
+		Long:  "Import an export into the journal, verifying its signature if it is signed.\n" + mergeLong + formatLong + importFormatLong + signLong,
+		Example: `
+bujo import journal.json
+bujo import journal.json --require-signature
+bujo import work.opml
+bujo import journal.json --merge interactive
+bujo import notes.md
+bujo import todo.txt
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+
+			s := backup.Import{
+				File:             args[0],
+				Format:           io.Format,
+				Merge:            io.Merge,
+				RequireSignature: io.RequireSignature || viper.GetBool("sign.require"),
+				Persistence:      p,
+			}
+			if s.Merge == "" {
+				s.Merge = viper.GetString("import.merge")
+			}
+			if s.Signer, err = signer(); err != nil {
+				return err
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddImportArgs(cmd, io)
+	_ = cmd.RegisterFlagCompletionFunc("merge", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return backup.Merges, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("format", importFormatCompletions)
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/batch"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addBatch(topLevel *cobra.Command) {
+	bo := &options.BatchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Apply a file of operations to the journal, all or nothing.",
+		Long: `Apply a file of operations to the journal, all or nothing.
+
+Each line is a json object with an op of add, complete, move or mkdir:
+
+{"op": "add", "collection": "today", "bullet": "task", "message": "buy milk"}
+{"op": "complete", "id": "T-1CB"}
+{"op": "move", "id": "T-1CB", "collection": "Future - December, 2026"}
+{"op": "mkdir", "collection": "Project X"}
+
+If any operation fails nothing is written. A json report of what was done
+is written to stdout.
+`,
+		Example: `
+bujo batch -f ops.jsonl
+bujo batch -f ops.jsonl --dry-run
+cat ops.jsonl | bujo batch
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var in io.Reader = os.Stdin
+			if bo.File != "-" {
+				f, err := os.Open(bo.File)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := batch.Batch{
+				In:          in,
+				DryRun:      bo.DryRun,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddBatchArgs(cmd, bo)
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/runner/defaultbullet"
+	"tableflip.dev/bujo/pkg/runner/kind"
+	"tableflip.dev/bujo/pkg/runner/rename"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addCollection(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "collection",
+		Short: "Make, rename, archive and describe collections",
+		Long: `Make, rename, archive and describe collections.
+
+The collection commands are together here. new is bujo mkdir, archive,
+unarchive, info and icon are the commands of the same name.
+`,
+		Example: `
+bujo collection new "Project Y" --template project
+bujo collection rename "Project Y" "Project Z"
+bujo collection type today
+bujo collection default-bullet "Reading list" note
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	under(cmd, addMkdir, "new", "mkdir")
+	addRename(cmd)
+	under(cmd, addArchive, "archive")
+	under(cmd, addUnarchive, "unarchive")
+	under(cmd, addInfo, "info")
+	under(cmd, addIcon, "icon")
+	addType(cmd)
+	addDefaultBullet(cmd)
+
+	topLevel.AddCommand(cmd)
+}
+
+// under adds the command add makes to group, named name with its arguments
+// kept. A command can only have one parent, so add makes another.
+func under(group *cobra.Command, add func(*cobra.Command), name string, aliases ...string) {
+	made := &cobra.Command{}
+	add(made)
+	for _, cmd := range made.Commands() {
+		made.RemoveCommand(cmd)
+		cmd.Use = name + strings.TrimPrefix(cmd.Use, cmd.Name())
+		cmd.Aliases = aliases
+		group.AddCommand(cmd)
+	}
+}
+
+func addRename(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "rename <collection> <new name>",
+		Short: "Rename a collection, with its icon and default bullet",
+		Example: `
+bujo collection rename "Project Y" "Project Z"
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("requires a collection and its new name")
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := rename.Rename{
+				From:        collection.Resolve(args[0]),
+				To:          collection.Resolve(args[1]),
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+func addType(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "type <collection>",
+		Short: "Show the type of a collection",
+		Long: `Show the type of a collection: a day log, month log, future log or a
+collection. The type comes from the name, rename a collection to change it.`,
+		Example: `
+bujo collection type today
+bujo collection type "Project Y"
+`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := kind.Kind{
+				Collection:  collection.Resolve(strings.Join(args, " ")),
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+func addDefaultBullet(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "default-bullet <collection> [bullet]",
+		Short: "Set the bullet entries captured to a collection get",
+		Long: `Set the bullet entries captured to a collection get in the ui when
+none is picked, instead of a task. Without a bullet, the current default is
+shown. Use none to go back to tasks.`,
+		Example: `
+bujo collection default-bullet "Reading list" note
+bujo collection default-bullet "Reading list" none
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return errors.New("requires a collection and optionally a bullet")
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return []string{"task", "note", "event", "none"}, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := defaultbullet.DefaultBullet{
+				Collection:  collection.Resolve(args[0]),
+				Persistence: p,
+			}
+			if len(args) == 2 {
+				if args[1] == "none" {
+					s.Clear = true
+				} else if s.Bullet, err = glyph.BulletForAlias(args[1]); err != nil {
+					return output.HandleError(err)
+				}
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
@@ -20,6 +20,8 @@ func New() *cobra.Command {
 		},
 	}
 
+	addProfile(cmd)
+	addFirstRun(cmd)
 	AddCommands(cmd)
 	return cmd
 }
@@ -29,12 +31,48 @@ func AddCommands(topLevel *cobra.Command) {
 	addKey(topLevel)
 	addAdd(topLevel)
 	addGet(topLevel)
+	addSearch(topLevel)
+	addGrep(topLevel)
 	addComplete(topLevel)
 	addStrike(topLevel)
+	addWait(topLevel)
+	addReviewOn(topLevel)
+	addRemind(topLevel)
+	addRecur(topLevel)
+	addLabel(topLevel)
+	addPrivate(topLevel)
+	addPin(topLevel)
+	addAttach(topLevel)
+	addReact(topLevel)
+	addArchive(topLevel)
+	addUnarchive(topLevel)
+	addSync(topLevel)
+	addServe(topLevel)
+	addICS(topLevel)
+	addSplit(topLevel)
+	addJoin(topLevel)
+	addIcon(topLevel)
+	addMkdir(topLevel)
+	addCollection(topLevel)
 	addTrack(topLevel)
 	addLog(topLevel)
+	addReport(topLevel)
+	addView(topLevel)
+	addShutdown(topLevel)
+	addShare(topLevel)
+	addInvite(topLevel)
 	addCompletions(topLevel)
 	addInfo(topLevel)
+	addConfig(topLevel)
+	addActivity(topLevel)
+	addExport(topLevel)
+	addImport(topLevel)
+	addBatch(topLevel)
+	addCompactStore(topLevel)
+	addEncrypt(topLevel)
+	addMaintenance(topLevel)
+	addMigrateScheme(topLevel)
+	addAutomations(topLevel)
 	addUpgrade(topLevel)
 	addVersion(topLevel)
 
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"tableflip.dev/bujo/pkg/runner/compact"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addCompactStore(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "compact-store",
+		Short: "Compress all entries in the store.",
+		Long: `Compress all entries in the store.
+
+New entries are only written compressed when 'compress: true' is set in the
+config, entries are read transparently either way.`,
+		Example: `
+bujo compact-store
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := compact.Compact{
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
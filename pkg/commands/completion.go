@@ -30,7 +30,7 @@ To configure your bash shell to load completions for each session add to your ba
 }
 
 func collectionCompletions(toComplete string) []string {
-	p, err := store.Load(nil)
+	p, err := store.LoadCached(nil)
 	if err != nil {
 		return nil
 	}
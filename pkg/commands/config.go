@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/runner/config"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// setupSkipped are the commands that run without asking where to keep the
+// journal on the first run.
+var setupSkipped = map[string]bool{
+	"config":           true,
+	"completion":       true,
+	"help":             true,
+	"version":          true,
+	"upgrade":          true,
+	"__complete":       true,
+	"__completeNoDesc": true,
+}
+
+// addFirstRun asks where to keep the journal before the first command that
+// uses it, when there is no config and no journal yet.
+func addFirstRun(topLevel *cobra.Command) {
+	pre := topLevel.PersistentPreRunE
+	topLevel.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if pre != nil {
+			if err := pre(cmd, args); err != nil {
+				return err
+			}
+		}
+		return firstRun(cmd)
+	}
+}
+
+func firstRun(cmd *cobra.Command) error {
+	top := cmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	if !top.HasParent() || setupSkipped[top.Name()] {
+		return nil
+	}
+	// Only ask if someone is there to answer, otherwise the default is used.
+	if !interactive() {
+		return nil
+	}
+	if _, err := store.LoadConfig(); err != nil {
+		return err
+	}
+	if !store.FirstRun() {
+		return nil
+	}
+	s := config.Setup{
+		Default: store.DefaultPath(),
+	}
+	return s.Do(context.Background())
+}
+
+func addConfig(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get and set the config.",
+		Long: `Get and set the config.
+
+The config is read from $BUJO_CONFIG_PATH/.bujo.yaml, then
+$XDG_CONFIG_HOME/bujo/config.yaml (~/.config/bujo/config.yaml), then
+~/.bujo.yaml. Set writes to the first that exists, or creates the XDG one.
+
+The journal is kept in $XDG_DATA_HOME/bujo (~/.local/share/bujo) unless
+path is set, or ~/.bujo.db exists from an older version. BUJO_PATH on the
+env overrides the config.
+
+A remote journal is pulled into path before each command. Commands that
+only read, like get, search and report, read what was pulled without
+pulling again for remote_cache after a pull, 5m unless set.
+
+Logs are written as JSON lines to $XDG_STATE_HOME/bujo/bujo.log
+(~/.local/state/bujo/bujo.log) unless log.path is set. log.level is one of
+debug, info, warn, error or off, warn unless set. BUJO_LOG_LEVEL on the env
+overrides the config. The log is rotated at 1MB, keeping 3.
+
+An encrypted journal, see bujo encrypt, has encrypt.enabled set. Its
+passphrase is BUJO_PASSPHRASE on the env, or what encrypt.passphrase_command
+prints.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	addConfigSet(cmd)
+	addConfigGet(cmd)
+
+	topLevel.AddCommand(cmd)
+}
+
+func addConfigSet(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a key in the config file.",
+		Long: `Set a key in the config file.
+
+Setting path checks the journal can be kept there, creating the directory
+if needed. Existing entries are not moved.
+`,
+		Example: `
+bujo config set path ~/Documents/journal
+bujo config set ui.open month
+bujo config set compress true
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("requires a key and a value")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := store.LoadConfig(); err != nil {
+				return err
+			}
+			s := config.Set{
+				Key:   args[0],
+				Value: args[1],
+			}
+			err := s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+func addConfigGet(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a key of the config.",
+		Example: `
+bujo config get path
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("requires a key")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := store.LoadConfig(); err != nil {
+				return err
+			}
+			s := config.Get{
+				Key: args[0],
+			}
+			err := s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"tableflip.dev/bujo/pkg/runner/encrypt"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addEncrypt(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt all entries in the store.",
+		Long: `Encrypt all entries in the store, archived ones too, and write new
+entries encrypted from then on.
+
+Entries are encrypted with AES-256-GCM, with a key made from a passphrase.
+The passphrase is $BUJO_PASSPHRASE, or what the command in
+encrypt.passphrase_command prints, like a keychain:
+
+  encrypt:
+    passphrase_command: security find-generic-password -w -s bujo
+
+Collection names are the directories of the store and stay readable. The
+search index, which has the words of every message, is no longer written to
+disk and is built again each time the journal is loaded.
+
+Setting encrypt.enabled only encrypts entries as they are written. Until
+bujo encrypt is run, the journal warns about the entries left in plain
+text.
+
+There is no decrypt, export the journal to keep a readable copy.`,
+		Example: `
+BUJO_PASSPHRASE=... bujo encrypt
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := store.LoadConfig()
+			if err != nil {
+				return err
+			}
+			p, err := store.Load(cfg)
+			if err != nil {
+				return err
+			}
+			s := encrypt.Encrypt{
+				Passphrase:  cfg.Passphrase(),
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
@@ -49,6 +49,7 @@ bujo add event a fun party --on=1999-12-31
 				Persistence:   p,
 				Message:       no.Message,
 				Collection:    co.Collection,
+				After:         no.After,
 				Priority:      so.Priority,
 				Inspiration:   so.Inspiration,
 				Investigation: so.Investigation,
@@ -61,6 +62,7 @@ bujo add event a fun party --on=1999-12-31
 
 	options.AddOnArgs(cmd, oo)
 	options.AddSigArgs(cmd, so)
+	options.AddAfterArgs(cmd, no)
 	options.AddCollectionArgs(cmd, co)
 	flagName := "collection"
 	_ = cmd.RegisterFlagCompletionFunc(flagName, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -15,6 +15,7 @@ import (
 func addGet(topLevel *cobra.Command) {
 	co := &options.CollectionOptions{}
 	io := &options.IDOptions{}
+	lo := &options.LabelOptions{}
 
 	long := strings.Builder{}
 	long.WriteString("Get all or a filtered set of bullets.\n\n")
@@ -61,16 +62,23 @@ bujo get completed --all
 		},
 		ValidArgs: validArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			p, err := store.Load(nil)
+			label, err := lo.GetLabel()
+			if err != nil {
+				return err
+			}
+			p, err := store.LoadCached(nil)
 			if err != nil {
 				return err
 			}
 			s := get.Get{
 				ShowID:          io.ShowID,
 				Bullet:          co.Bullet,
+				Label:           label,
 				Persistence:     p,
 				Collection:      co.Collection,
 				ListCollections: co.List,
+				ShowHidden:      co.ShowHidden,
+				ShowDoneTime:    co.ShowDoneTime,
 			}
 			if co.All {
 				s.Collection = ""
@@ -87,7 +95,10 @@ bujo get completed --all
 	})
 
 	options.AddAllCollectionsArg(cmd, co)
+	options.AddShowHiddenArg(cmd, co)
+	options.AddShowDoneTimeArg(cmd, co)
 	options.AddShowIDArgs(cmd, io)
+	options.AddLabelArgs(cmd, lo)
 
 	topLevel.AddCommand(cmd)
 }
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	base "github.com/n3wscott/cli-base/pkg/commands/options"
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/rollup"
+	"tableflip.dev/bujo/pkg/runner/grep"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addGrep(topLevel *cobra.Command) {
+	gro := &options.GrepOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "grep <pattern>",
+		Short: "print entries with a message matching a pattern",
+		Long: `Grep prints the entries with a message matching a regular expression,
+one per line, for shell workflows. Matches are printed as
+"collection: message" and context as "collection- message", like grep.
+
+--json prints an array of the matches, each with the entries before and
+after it when --context is set.`,
+		Example: `
+bujo grep standup
+bujo grep 'deploy|release' --state open --since 2w
+bujo grep -i review -c today -C 1
+bujo grep '#work' --bullet task --json | jq '.[].message'
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("requires one pattern")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pattern := args[0]
+			if gro.IgnoreCase {
+				pattern = "(?i)" + pattern
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return output.HandleError(app.Invalid("pattern", args[0], err.Error()))
+			}
+			s := grep.Grep{
+				Pattern: re,
+				Bullet:  glyph.Any,
+				Context: gro.Context,
+				JSON:    output.JSON,
+				ShowID:  gro.ShowID,
+			}
+			for _, c := range gro.Collections {
+				s.Collections = append(s.Collections, collection.Resolve(c))
+			}
+			if gro.State != "" {
+				if s.State, err = rollup.State(gro.State); err != nil {
+					return output.HandleError(err)
+				}
+			}
+			if gro.Bullet != "" {
+				if s.Bullet, err = glyph.BulletForAlias(gro.Bullet); err != nil {
+					return output.HandleError(err)
+				}
+			}
+			if gro.Since != "" {
+				if s.Since, err = options.ParseSince(gro.Since, time.Now()); err != nil {
+					return output.HandleError(err)
+				}
+			}
+			if s.Persistence, err = store.LoadCached(nil); err != nil {
+				return err
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddGrepArgs(cmd, gro)
+	_ = cmd.RegisterFlagCompletionFunc("collection", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("state", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"open", "done", "struck", "tasks", "notes", "events", "all"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	base.AddOutputArg(cmd, output)
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/runner/icon"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addIcon(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "icon <collection> [icon]",
+		Short: "Set an icon on a collection",
+		Long: `Set an icon, like an emoji, on a collection. The icon is shown before
+the collection name in the ui and in the collection titles of get. Without an
+icon, the current icon is shown. Use none to remove the icon.`,
+		Example: `
+bujo icon "Project X" 🎯
+bujo icon today ☕
+bujo icon "Project X" none
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return errors.New("requires a collection and optionally an icon")
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return []string{"none"}, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := icon.Icon{
+				Collection:  collection.Resolve(args[0]),
+				Persistence: p,
+			}
+			if len(args) == 2 {
+				if args[1] == "none" {
+					s.Clear = true
+				} else {
+					s.Icon = args[1]
+				}
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/ics"
+	"tableflip.dev/bujo/pkg/runner/serve"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addICS(topLevel *cobra.Command) {
+	icso := &options.ICSOptions{}
+	po := &options.PrivateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "ics",
+		Short: "Export the dated entries of the journal as a calendar.",
+		Long: `Export the dated entries of the journal as a calendar.
+
+The calendar has an all day event for each open entry with a day, tasks
+and events on a date and follow ups of waiting entries, and for each
+event in a day log. Private entries are left out.
+
+With --serve the calendar is served over http, read from the journal on
+each request, for calendar apps to subscribe to. It needs serve.token,
+unless it only listens on this host, given as a token query parameter:
+
+http://host:8081/?token=a-long-random-string
+`,
+		Example: `
+bujo ics --out calendar.ics
+bujo ics --serve localhost:8081
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+
+			if icso.Serve == "" {
+				s := ics.ICS{
+					Out:            icso.Out,
+					IncludePrivate: po.IncludePrivate,
+					Persistence:    p,
+				}
+				err = s.Do(context.Background())
+				return output.HandleError(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, os.Interrupt)
+			go func() {
+				<-sig
+				cancel()
+			}()
+
+			s := serve.Serve{
+				Addr:  icso.Serve,
+				Token: viper.GetString("serve.token"),
+				Handler: &ics.Feed{
+					IncludePrivate: po.IncludePrivate,
+					Persistence:    p,
+				},
+				Persistence: p,
+			}
+			err = s.Do(ctx)
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddICSArgs(cmd, icso)
+	options.AddIncludePrivateArg(cmd, po)
+
+	topLevel.AddCommand(cmd)
+}
@@ -3,19 +3,25 @@ package commands
 import (
 	"context"
 	"github.com/spf13/cobra"
+	"strings"
+	"tableflip.dev/bujo/pkg/collection"
 	"tableflip.dev/bujo/pkg/runner/info"
 	"tableflip.dev/bujo/pkg/store"
 )
 
 func addInfo(topLevel *cobra.Command) {
 	cmd := &cobra.Command{
-		Use:   "info",
+		Use:   "info [collection]",
 		Short: "Details about collection and where they are stored.",
 		Example: `
 bujo info
+bujo info today
 `,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			p, err := store.Load(nil)
+			p, err := store.LoadCached(nil)
 			if err != nil {
 				return err
 			}
@@ -23,6 +29,9 @@ bujo info
 				Config:      nil,
 				Persistence: p,
 			}
+			if len(args) > 0 {
+				s.Collection = collection.Resolve(strings.Join(args, " "))
+			}
 			err = s.Do(context.Background())
 			return output.HandleError(err)
 		},
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/runner/invite"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addInvite(topLevel *cobra.Command) {
+	var length string
+
+	cmd := &cobra.Command{
+		Use:   "invite <entry id> [file]",
+		Short: "Write a meeting invite for an event.",
+		Long: `Write a meeting invite for an event to an .ics file, to send or open
+in a calendar.
+
+The meeting is on the day the event is on, or the day log it is in, at the
+time in its message, like 3pm, 3:30pm or 15:00. Everyone @mentioned in the
+message is invited, with their email from contacts in the config:
+
+  contacts:
+    alice: alice@example.com
+    bob: bob@example.com
+  invite:
+    organizer: me@example.com
+    length: 45m
+
+Meetings are 30m unless invite.length or --length is set. The file is
+named after the event unless given. Writing the invite again for the same
+event updates the meeting.`,
+		Example: `
+bujo add event sync with @alice at 3pm --on=3/14
+bujo invite <entry id>
+bujo invite <entry id> sync.ics --length 1h
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return errors.New("requires an entry id and optionally a file")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			m, err := inviteMeeting(length)
+			if err != nil {
+				return err
+			}
+			s := invite.Invite{
+				ID:          args[0],
+				Meeting:     m,
+				Persistence: p,
+			}
+			if len(args) == 2 {
+				s.Out = args[1]
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	cmd.Flags().StringVar(&length, "length", "", "How long the meeting is, like 45m or 1h. Defaults to invite.length in config, or 30m.")
+
+	topLevel.AddCommand(cmd)
+}
+
+// inviteMeeting is the meeting of invites from the config, with length
+// overriding invite.length if set.
+func inviteMeeting(length string) (invite.Meeting, error) {
+	m := invite.Meeting{
+		Organizer: viper.GetString("invite.organizer"),
+		Contacts:  viper.GetStringMapString("contacts"),
+		Length:    viper.GetDuration("invite.length"),
+	}
+	if length != "" {
+		d, err := time.ParseDuration(length)
+		if err != nil {
+			return m, app.Invalid("length", length, "expected a duration like 45m or 1h")
+		}
+		m.Length = d
+	}
+	return m, nil
+}
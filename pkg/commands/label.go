@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/runner/label"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addLabel(topLevel *cobra.Command) {
+	validArgs := []string{"none"}
+	for _, l := range glyph.Labels() {
+		validArgs = append(validArgs, string(l))
+	}
+
+	cmd := &cobra.Command{
+		Use:   "label <entry id> <color>",
+		Short: "Set a color label on an entry",
+		Long:  "Set a color label on an entry, one of: " + strings.Join(validArgs, ", "),
+		Example: `
+bujo label <entry id> red
+bujo label <entry id> none
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("requires an entry id and a label")
+			}
+			_, err := glyph.LabelFor(args[1])
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			l, _ := glyph.LabelFor(args[1])
+			s := label.Label{
+				ID:          args[0],
+				Label:       l,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
@@ -2,11 +2,17 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/printers"
 	"tableflip.dev/bujo/pkg/runner/log"
 	"tableflip.dev/bujo/pkg/store"
-	"time"
 )
 
 func addLog(topLevel *cobra.Command) {
@@ -16,11 +22,32 @@ func addLog(topLevel *cobra.Command) {
 	cmd := &cobra.Command{
 		Use:   "log",
 		Short: "view a log",
+		Long: `view a log
+
+The month calendar uses the weekday abbreviations of the locale from
+LANG, they can be set in the config, for example:
+
+calendar_view:
+  locale: de
+  weekdays: [So, Mo, Di, Mi, Do, Fr, Sa]
+  direction: ltr
+  week_numbers: true
+`,
 		Example: `
 bujo log --day
 bujo log --month
 bujo log --future
+bujo log --month March 2026
 `,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				if oo.OnString != "" {
+					return errors.New("set either --on or a month, not both")
+				}
+				oo.OnString = strings.Join(args, " ")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			p, err := store.Load(nil)
 			if err != nil {
@@ -43,6 +70,9 @@ bujo log --future
 				Future:      lo.Future,
 				On:          *on,
 			}
+			co := calendarOptions()
+			co.WeekNumbers = co.WeekNumbers || lo.WeekNumbers
+			s.CalendarOptions = &co
 			err = s.Do(context.Background())
 			return output.HandleError(err)
 		},
@@ -53,3 +83,24 @@ bujo log --future
 
 	topLevel.AddCommand(cmd)
 }
+
+// calendarOptions returns the calendar options for the configured locale,
+// with the overrides from the config.
+func calendarOptions() printers.CalendarOptions {
+	locale := viper.GetString("calendar_view.locale")
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	co := printers.LocaleCalendarOptions(locale)
+	if w := viper.GetStringSlice("calendar_view.weekdays"); len(w) == 7 {
+		copy(co.Weekdays[:], w)
+	}
+	switch viper.GetString("calendar_view.direction") {
+	case "rtl":
+		co.RightToLeft = true
+	case "ltr":
+		co.RightToLeft = false
+	}
+	co.WeekNumbers = viper.GetBool("calendar_view.week_numbers")
+	return co
+}
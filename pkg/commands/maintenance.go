@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/maintenance"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addMaintenance(topLevel *cobra.Command) {
+	mo := &options.MaintenanceOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Apply the retention policies to the journal.",
+		Long: `Apply the retention policies to the journal.
+
+Policies are set in the config, for example:
+
+retention:
+  archive_after: 18m
+  prune_moved_after: 6m
+
+Archive after archives day collections older than that. Prune moved after
+deletes the originals that moving an entry leaves behind, the entries
+with a moved bullet, once they were moved longer ago than that. They are
+deleted for good, how many from each collection is shown first. Entries
+moved before bujo kept when they moved are not pruned.
+
+Notes added with --expires are archived once they have expired.
+`,
+		Example: `
+bujo maintenance
+bujo maintenance --archive-after 18m --dry-run
+bujo maintenance --prune-moved-after 6m --dry-run
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+
+			s := maintenance.Maintenance{
+				Yes:         mo.Yes,
+				DryRun:      mo.DryRun,
+				Persistence: p,
+			}
+
+			after := mo.ArchiveAfter
+			if after == "" {
+				after = viper.GetString("retention.archive_after")
+			}
+			if after != "" {
+				before, err := options.ParseSince(after, time.Now())
+				if err != nil {
+					return err
+				}
+				s.ArchiveBefore = &before
+			}
+
+			moved := mo.PruneMovedAfter
+			if moved == "" {
+				moved = viper.GetString("retention.prune_moved_after")
+			}
+			if moved != "" {
+				before, err := options.ParseSince(moved, time.Now())
+				if err != nil {
+					return err
+				}
+				s.PruneMovedBefore = &before
+			}
+
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddMaintenanceArgs(cmd, mo)
+
+	topLevel.AddCommand(cmd)
+}
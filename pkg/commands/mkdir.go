@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/mkdir"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addMkdir(topLevel *cobra.Command) {
+	mo := &options.MkdirOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "mkdir <collection>",
+		Short: "Make a collection, its child collections and first entries from a template",
+		Long: `Make a collection, its child collections and first entries from a
+template.
+
+Templates are set in the config. Children are named after the collection,
+like "Project Y/Backlog", and {name} in a message is the collection's name.
+A child without entries gets a note, collections are made by their first
+entry. For example:
+
+templates:
+- name: project
+  entries:
+  - bullet: note
+    message: "goal of {name}:"
+  children:
+  - name: Backlog
+    entries:
+    - message: plan the first milestone
+  - name: Notes
+  - name: Decisions
+`,
+		Example: `
+bujo mkdir "Project Y" --template project
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("requires a collection")
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			t, err := findTemplate(mo.Template)
+			if err != nil {
+				return output.HandleError(err)
+			}
+			s := mkdir.Mkdir{
+				Collection:  collection.Resolve(args[0]),
+				Template:    t,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddMkdirArgs(cmd, mo)
+	_ = cmd.RegisterFlagCompletionFunc("template", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if _, err := store.LoadConfig(); err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return templateNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	topLevel.AddCommand(cmd)
+}
+
+// findTemplate returns the template in the config with name.
+func findTemplate(name string) (mkdir.Template, error) {
+	var all []mkdir.Template
+	if err := viper.UnmarshalKey("templates", &all); err != nil {
+		return mkdir.Template{}, err
+	}
+	for _, t := range all {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	if len(all) == 0 {
+		return mkdir.Template{}, app.Invalid("template", name, "no templates, add them to templates in the config")
+	}
+	return mkdir.Template{}, app.Invalid("template", name, "expected one of "+strings.Join(templateNames(), ", "))
+}
+
+// templateNames are the names of the templates in the config.
+func templateNames() []string {
+	var all []mkdir.Template
+	_ = viper.UnmarshalKey("templates", &all)
+	names := make([]string, 0, len(all))
+	for _, t := range all {
+		names = append(names, t.Name)
+	}
+	return names
+}
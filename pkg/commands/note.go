@@ -18,13 +18,26 @@ func addNote(topLevel *cobra.Command) {
 	no := &options.AddOptions{}
 	so := &options.SigOptions{}
 	co := &options.CollectionOptions{}
+	eo := &options.ExpiresOptions{}
 
 	cmd := &cobra.Command{
 		Use:     "note",
 		Aliases: []string{"notes"},
 		Short:   "Add a note",
+		Long: `Add a note
+
+A note starting with "= " is computed when it is shown, it counts or sums
+the entries that match every filter. Filters are open, done, struck, tasks,
+notes, events or all, a "collection" in quotes, or a #word. Sums add the
+numbers of name:N in the messages, like hours:2.
+
+  = count(open, "Work")
+  = sum(field:hours, #projectx)
+`,
 		Example: `
 bujo add note this is a note
+bujo add note park meter runs out --expires 4pm
+bujo add note '= count(open, "Work")'
 `,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
@@ -35,6 +48,10 @@ bujo add note this is a note
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			expires, err := eo.GetExpires()
+			if err != nil {
+				return err
+			}
 			p, err := store.Load(nil)
 			if err != nil {
 				return err
@@ -43,7 +60,9 @@ bujo add note this is a note
 				Bullet:        glyph.Note,
 				Persistence:   p,
 				Message:       no.Message,
+				Expires:       expires,
 				Collection:    co.Collection,
+				After:         no.After,
 				Priority:      so.Priority,
 				Inspiration:   so.Inspiration,
 				Investigation: so.Investigation,
@@ -54,6 +73,8 @@ bujo add note this is a note
 	}
 
 	options.AddSigArgs(cmd, so)
+	options.AddAfterArgs(cmd, no)
+	options.AddExpiresArgs(cmd, eo)
 	options.AddCollectionArgs(cmd, co)
 	flagName := "collection"
 	_ = cmd.RegisterFlagCompletionFunc(flagName, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/notify"
+	"tableflip.dev/bujo/pkg/runner/remind"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// notifier returns the sinks in the config, leaving out the kinds in skip.
+// The config must be loaded first.
+func notifier(skip ...string) (notify.Sink, error) {
+	var configs []notify.Config
+	if err := viper.UnmarshalKey("notify", &configs); err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		configs = notify.Default()
+	}
+	kept := configs[:0]
+	for _, c := range configs {
+		skipped := false
+		for _, s := range skip {
+			skipped = skipped || c.Kind == s
+		}
+		if !skipped {
+			kept = append(kept, c)
+		}
+	}
+	return notify.New(kept)
+}
+
+func addRemind(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "remind",
+		Short: "Notify about the follow ups that are due.",
+		Long: `Notify about the follow ups that are due.
+
+Notifications go to every sink set in the config, or to the terminal if
+there are none. The kinds of sink are terminal, bell, desktop, webhook and
+command, for example:
+
+notify:
+- kind: desktop
+- kind: webhook
+  url: https://example.com/hook
+- kind: command
+  command: say "$BUJO_NOTIFY_TITLE"
+
+Webhooks are posted {"title": ..., "body": ...}. Automations notify the
+same sinks when they finish, and the ui when it stops seeing changes.
+`,
+		Example: `
+bujo remind
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			sink, err := notifier()
+			if err != nil {
+				return err
+			}
+			s := remind.Remind{
+				On:          time.Now(),
+				Sink:        sink,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
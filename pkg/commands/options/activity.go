@@ -0,0 +1,16 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ActivityOptions
+type ActivityOptions struct {
+	Window string
+	ShowID bool
+}
+
+func AddActivityArgs(cmd *cobra.Command, o *ActivityOptions) {
+	cmd.Flags().BoolVarP(&o.ShowID, "show-id", "i", false,
+		"Show the ref of the entry, refs can be used in place of ids.")
+}
@@ -1,6 +1,16 @@
 package options
 
+import (
+	"github.com/spf13/cobra"
+)
+
 // AddOptions
 type AddOptions struct {
 	Message string
+	After   string
+}
+
+func AddAfterArgs(cmd *cobra.Command, o *AddOptions) {
+	cmd.Flags().StringVar(&o.After, "after", "",
+		"Add right after the entry with this id, in its collection.")
 }
@@ -0,0 +1,15 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ArchiveOptions
+type ArchiveOptions struct {
+	List bool
+}
+
+func AddArchiveArgs(cmd *cobra.Command, o *ArchiveOptions) {
+	cmd.Flags().BoolVarP(&o.List, "list", "l", false,
+		"List the archived collections, with how many entries each has.")
+}
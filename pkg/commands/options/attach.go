@@ -0,0 +1,18 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AttachOptions
+type AttachOptions struct {
+	Remove int
+	Open   int
+}
+
+func AddAttachArgs(cmd *cobra.Command, o *AttachOptions) {
+	cmd.Flags().IntVar(&o.Remove, "remove", 0,
+		"Remove the attachment with this number, from 1.")
+	cmd.Flags().IntVar(&o.Open, "open", 0,
+		"Open the attachment with this number, from 1.")
+}
@@ -0,0 +1,15 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AutomationsOptions
+type AutomationsOptions struct {
+	Now string
+}
+
+func AddAutomationsArgs(cmd *cobra.Command, o *AutomationsOptions) {
+	cmd.Flags().StringVar(&o.Now, "now", "",
+		"Run the named automation once, now, and exit.")
+}
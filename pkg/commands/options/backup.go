@@ -0,0 +1,56 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ExportOptions
+type ExportOptions struct {
+	Sign        bool
+	Format      string
+	Collections []string
+	Archived    bool
+}
+
+func AddExportArgs(cmd *cobra.Command, o *ExportOptions) {
+	cmd.Flags().BoolVar(&o.Sign, "sign", false,
+		"Sign the export with minisign. Defaults to sign.exports in config.")
+	cmd.Flags().StringVar(&o.Format, "format", "",
+		"The format of the export: json, opml, org, markdown or csv. Defaults to the extension of the file, or json.")
+	cmd.Flags().StringArrayVarP(&o.Collections, "collection", "c", nil,
+		"Only export this collection, can be repeated.")
+	cmd.Flags().BoolVar(&o.Archived, "archived", false,
+		"Export the archived collections too, they import back into the journal.")
+}
+
+// StatsExportOptions
+type StatsExportOptions struct {
+	Window string
+	Format string
+	Out    string
+}
+
+func AddStatsExportArgs(cmd *cobra.Command, o *StatsExportOptions) {
+	cmd.Flags().StringVar(&o.Window, "window", "all",
+		`How far back to export, like 2w, 3m or 1y, or all.`)
+	cmd.Flags().StringVar(&o.Format, "format", "",
+		"The format of the export: csv or jsonl. Defaults to the extension of --out, or csv.")
+	cmd.Flags().StringVar(&o.Out, "out", "",
+		"The file to write, stdout if not set.")
+}
+
+// ImportOptions
+type ImportOptions struct {
+	RequireSignature bool
+	Format           string
+	Merge            string
+}
+
+func AddImportArgs(cmd *cobra.Command, o *ImportOptions) {
+	cmd.Flags().BoolVar(&o.RequireSignature, "require-signature", false,
+		"Refuse exports that are not signed. Defaults to sign.require in config.")
+	cmd.Flags().StringVar(&o.Format, "format", "",
+		"The format of the file: json, opml, org, markdown or todotxt. Defaults to the extension of the file, or json.")
+	cmd.Flags().StringVar(&o.Merge, "merge", "",
+		"What to do with entries already in the journal: newest-wins, prefer-local, prefer-import or interactive. Defaults to import.merge in config, or newest-wins.")
+}
@@ -0,0 +1,18 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// BatchOptions
+type BatchOptions struct {
+	File   string
+	DryRun bool
+}
+
+func AddBatchArgs(cmd *cobra.Command, o *BatchOptions) {
+	cmd.Flags().StringVarP(&o.File, "file", "f", "-",
+		"The file of operations, one json object per line. - reads stdin.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false,
+		"Only report what would be done.")
+}
@@ -11,11 +11,14 @@ type CollectionOptions struct {
 	Collection string
 	All        bool
 	List       bool
+	ShowHidden bool
+	// ShowDoneTime shows when completed tasks were completed.
+	ShowDoneTime bool
 }
 
 func AddCollectionArgs(cmd *cobra.Command, o *CollectionOptions) {
 	cmd.Flags().StringVarP(&o.Collection, "collection", "c", "today",
-		"Specify the collection.")
+		"Specify the collection. today, tomorrow, yesterday or a date like 2/28 is that day's log, :week is this week's log, it is made if needed.")
 }
 
 func AddAllCollectionsArg(cmd *cobra.Command, o *CollectionOptions) {
@@ -24,3 +27,13 @@ func AddAllCollectionsArg(cmd *cobra.Command, o *CollectionOptions) {
 	cmd.Flags().BoolVar(&o.List, "list", false,
 		"List all collections.")
 }
+
+func AddShowHiddenArg(cmd *cobra.Command, o *CollectionOptions) {
+	cmd.Flags().BoolVar(&o.ShowHidden, "show-hidden", false,
+		"Include expired notes.")
+}
+
+func AddShowDoneTimeArg(cmd *cobra.Command, o *CollectionOptions) {
+	cmd.Flags().BoolVar(&o.ShowDoneTime, "show-done-time", false,
+		"Show when completed tasks were completed.")
+}
@@ -0,0 +1,58 @@
+package options
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// clockLayouts are accepted to expire at a time of day.
+var clockLayouts = []string{
+	"3pm",
+	"3:04pm",
+	"15:04",
+}
+
+// ExpiresOptions
+type ExpiresOptions struct {
+	Expires string
+}
+
+func AddExpiresArgs(cmd *cobra.Command, o *ExpiresOptions) {
+	cmd.Flags().StringVar(&o.Expires, "expires", "",
+		`When the note expires and is hidden, example: --expires=4pm, --expires=16:30, --expires=2h or --expires="2/28".`)
+}
+
+func (o *ExpiresOptions) GetExpires() (*time.Time, error) {
+	return ParseExpires(o.Expires, time.Now())
+}
+
+// ParseExpires parses a time of day, a duration from now or a date. A time of
+// day that has passed is tomorrow. An empty string is no expiry.
+func ParseExpires(s string, now time.Time) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		t := now.Add(d)
+		return &t, nil
+	}
+	for _, layout := range clockLayouts {
+		c, err := time.Parse(layout, strings.ToLower(s))
+		if err != nil {
+			continue
+		}
+		t := time.Date(now.Year(), now.Month(), now.Day(), c.Hour(), c.Minute(), 0, 0, now.Location())
+		if t.Before(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t, nil
+	}
+	if t, err := ParseDate(s); err == nil {
+		return t, nil
+	}
+	return nil, app.Invalid("expiry", s, "expected a time like 4pm or 16:30, a duration like 2h or a date like 2/28")
+}
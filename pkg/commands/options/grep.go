@@ -0,0 +1,33 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// GrepOptions
+type GrepOptions struct {
+	Collections []string
+	State       string
+	Since       string
+	Bullet      string
+	Context     int
+	IgnoreCase  bool
+	ShowID      bool
+}
+
+func AddGrepArgs(cmd *cobra.Command, o *GrepOptions) {
+	cmd.Flags().StringArrayVarP(&o.Collections, "collection", "c", nil,
+		"Only search this collection, can be repeated.")
+	cmd.Flags().StringVar(&o.State, "state", "",
+		"Only match entries in this state: open, done, struck, tasks, notes, events or all.")
+	cmd.Flags().StringVar(&o.Since, "since", "",
+		`Only match entries created in this window, example: --since=2w. A number followed by d, w, m or y.`)
+	cmd.Flags().StringVar(&o.Bullet, "bullet", "",
+		"Only match this kind of bullet, example: --bullet=task.")
+	cmd.Flags().IntVarP(&o.Context, "context", "C", 0,
+		"Show this many entries before and after each match.")
+	cmd.Flags().BoolVarP(&o.IgnoreCase, "ignore-case", "i", false,
+		"Match the pattern without regard to case.")
+	cmd.Flags().BoolVar(&o.ShowID, "show-id", false,
+		"Show the ref of the entry, refs can be used in place of ids.")
+}
@@ -0,0 +1,18 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ICSOptions
+type ICSOptions struct {
+	Out   string
+	Serve string
+}
+
+func AddICSArgs(cmd *cobra.Command, o *ICSOptions) {
+	cmd.Flags().StringVar(&o.Out, "out", "",
+		"The file to write, stdout if not set.")
+	cmd.Flags().StringVar(&o.Serve, "serve", "",
+		"Serve the calendar on this host and port instead of writing it, like localhost:8081.")
+}
@@ -12,7 +12,7 @@ type IDOptions struct {
 
 func AddShowIDArgs(cmd *cobra.Command, o *IDOptions) {
 	cmd.Flags().BoolVarP(&o.ShowID, "show-id", "i", false,
-		"Show the ID of the entry or collection.")
+		"Show the ref of the entry, refs can be used in place of ids.")
 }
 
 func AddIDArgs(cmd *cobra.Command, o *IDOptions) {
@@ -0,0 +1,20 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// LabelOptions
+type LabelOptions struct {
+	Label string
+}
+
+func AddLabelArgs(cmd *cobra.Command, o *LabelOptions) {
+	cmd.Flags().StringVar(&o.Label, "label", "",
+		"Only include entries with this color label.")
+}
+
+func (o *LabelOptions) GetLabel() (glyph.Label, error) {
+	return glyph.LabelFor(o.Label)
+}
@@ -9,6 +9,8 @@ type LogOptions struct {
 	Day    bool
 	Month  bool
 	Future bool
+
+	WeekNumbers bool
 }
 
 func AddLogArgs(cmd *cobra.Command, o *LogOptions) {
@@ -18,4 +20,6 @@ func AddLogArgs(cmd *cobra.Command, o *LogOptions) {
 		"Show month log.")
 	cmd.Flags().BoolVarP(&o.Future, "future", "f", false,
 		"Show future log.")
+	cmd.Flags().BoolVar(&o.WeekNumbers, "week-numbers", false,
+		"Show ISO week numbers in the month calendar. Defaults to calendar_view.week_numbers in config.")
 }
@@ -0,0 +1,24 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// MaintenanceOptions
+type MaintenanceOptions struct {
+	ArchiveAfter    string
+	PruneMovedAfter string
+	Yes             bool
+	DryRun          bool
+}
+
+func AddMaintenanceArgs(cmd *cobra.Command, o *MaintenanceOptions) {
+	cmd.Flags().StringVar(&o.ArchiveAfter, "archive-after", "",
+		`Archive day collections older than this, example: --archive-after=18m. Defaults to retention.archive_after in config.`)
+	cmd.Flags().StringVar(&o.PruneMovedAfter, "prune-moved-after", "",
+		`Delete the originals left behind by entries moved longer ago than this, example: --prune-moved-after=6m. Defaults to retention.prune_moved_after in config.`)
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false,
+		"Do not ask for confirmation.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false,
+		"Only report what would be done.")
+}
@@ -0,0 +1,15 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// MkdirOptions
+type MkdirOptions struct {
+	Template string
+}
+
+func AddMkdirArgs(cmd *cobra.Command, o *MkdirOptions) {
+	cmd.Flags().StringVarP(&o.Template, "template", "t", "",
+		"The template in the config to make the collection from.")
+}
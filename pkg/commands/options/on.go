@@ -2,6 +2,7 @@ package options
 
 import (
 	"github.com/spf13/cobra"
+	"tableflip.dev/bujo/pkg/app"
 	"time"
 )
 
@@ -10,6 +11,14 @@ const (
 	layoutISOShort = "1/2"
 )
 
+// monthLayouts are accepted to pick a month, the day is the first.
+var monthLayouts = []string{
+	"January 2006",
+	"January, 2006",
+	"Jan 2006",
+	"2006-1",
+}
+
 // AddOn
 type OnOptions struct {
 	OnString string
@@ -17,19 +26,30 @@ type OnOptions struct {
 
 func AddOnArgs(cmd *cobra.Command, o *OnOptions) {
 	cmd.Flags().StringVar(&o.OnString, "on", "",
-		`Specify a date, example: --on="2020-2-28" or --on="2/28".`)
+		`Specify a date, example: --on="2020-2-28", --on="2/28" or --on="March 2020".`)
 }
 
 func (o *OnOptions) GetOn() (*time.Time, error) {
-	if o.OnString == "" {
+	return ParseDate(o.OnString)
+}
+
+// ParseDate parses the date formats accepted on the command line. An empty
+// string is no date.
+func ParseDate(s string) (*time.Time, error) {
+	if s == "" {
 		return nil, nil
 	}
-	t, err := time.Parse(layoutISO, o.OnString)
+	for _, layout := range monthLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	t, err := time.Parse(layoutISO, s)
 	if err != nil {
 		// Let the year be the same.
-		t, err = time.Parse(layoutISOShort, o.OnString)
+		t, err = time.Parse(layoutISOShort, s)
 		if err != nil {
-			return nil, err
+			return nil, app.Invalid("date", s, `expected a date like 2020-2-28, 2/28 or "March 2020"`)
 		}
 		t = t.AddDate(time.Now().Year(), 0, 0)
 		// I am gonna assume if you said 1/3 on 12/5, you meant next year, not 11 months ago.
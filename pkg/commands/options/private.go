@@ -0,0 +1,15 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// PrivateOptions
+type PrivateOptions struct {
+	IncludePrivate bool
+}
+
+func AddIncludePrivateArg(cmd *cobra.Command, o *PrivateOptions) {
+	cmd.Flags().BoolVar(&o.IncludePrivate, "include-private", false,
+		"Include entries marked private, they are left out by default.")
+}
@@ -0,0 +1,15 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ProfileOptions
+type ProfileOptions struct {
+	Profile string
+}
+
+func AddProfileArgs(cmd *cobra.Command, o *ProfileOptions) {
+	cmd.PersistentFlags().StringVar(&o.Profile, "profile", "",
+		"Write a profile of the command: cpu, mem or trace. Written to bujo.cpu.pprof, bujo.mem.pprof or bujo.trace.")
+}
@@ -0,0 +1,25 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// RecurOptions
+type RecurOptions struct {
+	Every  string
+	Bullet string
+}
+
+func AddRecurArgs(cmd *cobra.Command, o *RecurOptions) {
+	cmd.Flags().StringVar(&o.Every, "every", "",
+		"How often it recurs: daily, weekdays, weekly, monthly or a cron expression like \"0 0 1,15 * *\".")
+	cmd.Flags().StringVarP(&o.Bullet, "bullet", "b", "task",
+		"The bullet of each instance, like task, note or event.")
+}
+
+// GetBullet returns the bullet for the bullet alias.
+func (o *RecurOptions) GetBullet() (glyph.Bullet, error) {
+	return glyph.BulletForAlias(o.Bullet)
+}
@@ -0,0 +1,62 @@
+package options
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// ReportOptions
+type ReportOptions struct {
+	Window   string
+	Markdown bool
+	Out      string
+	Tag      string
+}
+
+func AddReportArgs(cmd *cobra.Command, o *ReportOptions) {
+	cmd.Flags().BoolVar(&o.Markdown, "markdown", false,
+		"Render the report as markdown.")
+	cmd.Flags().StringVar(&o.Out, "out", "",
+		"Write the report to a file instead of stdout.")
+	AddTagArg(cmd, &o.Tag)
+}
+
+// AddTagArg adds --tag, to only report on entries with a #tag.
+func AddTagArg(cmd *cobra.Command, tag *string) {
+	cmd.Flags().StringVar(tag, "tag", "",
+		"Only include entries with this #tag, the # is optional.")
+}
+
+// GetSince returns the start of the report window, relative to now.
+func (o *ReportOptions) GetSince(now time.Time) (time.Time, error) {
+	return ParseSince(o.Window, now)
+}
+
+// ParseSince parses a window like "3d", "2w", "1m" or "1y" and returns the
+// start of the window relative to now. An empty window is one week.
+func ParseSince(window string, now time.Time) (time.Time, error) {
+	if window == "" {
+		window = "1w"
+	}
+	n, err := strconv.Atoi(window[:len(window)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, app.Invalid("window", window, "expected a number followed by d, w, m or y")
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch window[len(window)-1] {
+	case 'd':
+		return start.AddDate(0, 0, -n), nil
+	case 'w':
+		return start.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return start.AddDate(0, -n, 0), nil
+	case 'y':
+		return start.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, app.Invalid("window", window, "expected a number followed by d, w, m or y")
+	}
+}
@@ -0,0 +1,18 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// SchemeOptions
+type SchemeOptions struct {
+	To     string
+	DryRun bool
+}
+
+func AddSchemeArgs(cmd *cobra.Command, o *SchemeOptions) {
+	cmd.Flags().StringVar(&o.To, "to", "",
+		`The scheme to rename dated collections to, legacy or iso. Defaults to collections.scheme in config.`)
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false,
+		"Only report what would be renamed.")
+}
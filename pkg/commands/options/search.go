@@ -0,0 +1,24 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// SearchOptions
+type SearchOptions struct {
+	Tags     bool
+	Links    string
+	ShowID   bool
+	Archived bool
+}
+
+func AddSearchArgs(cmd *cobra.Command, o *SearchOptions) {
+	cmd.Flags().BoolVar(&o.Tags, "tags", false,
+		"List the #tags and @mentions in use, with how many entries have each.")
+	cmd.Flags().StringVar(&o.Links, "links", "",
+		"Find the entries in other collections that mention this collection.")
+	cmd.Flags().BoolVarP(&o.ShowID, "show-id", "i", false,
+		"Show the ref of the entry, refs can be used in place of ids.")
+	cmd.Flags().BoolVar(&o.Archived, "archived", false,
+		"Search the archived collections too.")
+}
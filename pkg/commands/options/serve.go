@@ -0,0 +1,18 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ServeOptions
+type ServeOptions struct {
+	Addr     string
+	ReadOnly bool
+}
+
+func AddServeArgs(cmd *cobra.Command, o *ServeOptions) {
+	cmd.Flags().StringVar(&o.Addr, "addr", "",
+		`The host and port to listen on. Defaults to serve.addr in config, or localhost:8080.`)
+	cmd.Flags().BoolVar(&o.ReadOnly, "read-only", false,
+		`Refuse every request that would change the journal. Defaults to serve.read_only in config.`)
+}
@@ -0,0 +1,21 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ShareOptions
+type ShareOptions struct {
+	Target string
+	URL    string
+	Public bool
+}
+
+func AddShareArgs(cmd *cobra.Command, o *ShareOptions) {
+	cmd.Flags().StringVar(&o.Target, "target", "",
+		"Where to share to, one of 'gist' or 'post'. Defaults to share.target in config.")
+	cmd.Flags().StringVar(&o.URL, "url", "",
+		"Endpoint to share to. Defaults to share.url in config.")
+	cmd.Flags().BoolVar(&o.Public, "public", false,
+		"Make the shared gist public.")
+}
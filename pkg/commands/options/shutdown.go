@@ -0,0 +1,18 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ShutdownOptions
+type ShutdownOptions struct {
+	Steps []string
+	Inbox string
+}
+
+func AddShutdownArgs(cmd *cobra.Command, o *ShutdownOptions) {
+	cmd.Flags().StringSliceVar(&o.Steps, "steps", nil,
+		`The steps to walk in order, example: --steps=review,priorities. Defaults to shutdown.steps in config.`)
+	cmd.Flags().StringVar(&o.Inbox, "inbox", "",
+		`The collection reviewed by the inbox step. Defaults to shutdown.inbox in config, or Inbox.`)
+}
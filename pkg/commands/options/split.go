@@ -0,0 +1,28 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// SplitOptions
+type SplitOptions struct {
+	At          []int
+	Interactive bool
+}
+
+func AddSplitArgs(cmd *cobra.Command, o *SplitOptions) {
+	cmd.Flags().IntSliceVar(&o.At, "at", nil,
+		"The parts, from 1, that start a new entry, example: --at=2,4. Defaults to every part.")
+	cmd.Flags().BoolVarP(&o.Interactive, "interactive", "I", false,
+		"Pick the parts that start a new entry.")
+}
+
+// JoinOptions
+type JoinOptions struct {
+	Separator string
+}
+
+func AddJoinArgs(cmd *cobra.Command, o *JoinOptions) {
+	cmd.Flags().StringVar(&o.Separator, "sep", "; ",
+		"What goes between the joined messages.")
+}
@@ -0,0 +1,15 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// StrikeOptions
+type StrikeOptions struct {
+	Reason string
+}
+
+func AddStrikeArgs(cmd *cobra.Command, o *StrikeOptions) {
+	cmd.Flags().StringVarP(&o.Reason, "reason", "r", "",
+		"Why it is struck. Asked for if not set, unless strike.ask_reason is false in config.")
+}
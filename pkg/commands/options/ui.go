@@ -0,0 +1,21 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// UIOptions
+type UIOptions struct {
+	Open    string
+	Columns string
+	Layout  string
+}
+
+func AddUIArgs(cmd *cobra.Command, o *UIOptions) {
+	cmd.Flags().StringVar(&o.Open, "open", "",
+		`What to open to: today, month, future, last or a collection name. Defaults to ui.open in config.`)
+	cmd.Flags().StringVar(&o.Columns, "columns", "",
+		`How many columns the collection is shown in: auto, 1, 2 or 3. Defaults to ui.columns in config.`)
+	cmd.Flags().StringVar(&o.Layout, "layout", "",
+		`A layout saved in ui.layouts to start with, it takes the place of the compact, columns and bar settings. Defaults to ui.layout in config.`)
+}
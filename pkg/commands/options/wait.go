@@ -0,0 +1,24 @@
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// WaitOptions
+type WaitOptions struct {
+	For      string
+	FollowUp string
+}
+
+func AddWaitArgs(cmd *cobra.Command, o *WaitOptions) {
+	cmd.Flags().StringVar(&o.For, "for", "",
+		"Who or what the entry is waiting for.")
+	cmd.Flags().StringVar(&o.FollowUp, "follow-up", "",
+		`Date to follow up on, example: --follow-up="2020-2-28" or --follow-up="2/28".`)
+}
+
+func (o *WaitOptions) GetFollowUp() (*time.Time, error) {
+	return ParseDate(o.FollowUp)
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/runner/pin"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addPin(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "pin <entry id>",
+		Short: "Pin an entry to the top of its collection, or unpin it if it already is.",
+		Long: `Pin an entry to the top of its collection, or unpin it if it already is.
+
+The ui shows pinned entries first in their collection, before the open
+entries, unless they are done.
+`,
+		Example: `
+bujo pin <entry id>
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := pin.Pin{
+				ID:          args[0],
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
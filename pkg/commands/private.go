@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/runner/private"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addPrivate(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "private <entry id>",
+		Short: "Mark an entry private, or not private if it already is.",
+		Long: `Mark an entry private, or not private if it already is.
+
+Private entries are left out of export, share and report unless
+--include-private is given.
+`,
+		Example: `
+bujo private <entry id>
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := private.Private{
+				ID:          args[0],
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
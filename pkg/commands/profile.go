@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/profile"
+)
+
+// addProfile adds the --profile flag to every command, profiling from before
+// the command runs until after it returns.
+func addProfile(topLevel *cobra.Command) {
+	po := &options.ProfileOptions{}
+	var stop func() error
+
+	topLevel.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if po.Profile == "" {
+			return nil
+		}
+		var err error
+		stop, err = profile.Start(po.Profile, profile.File(po.Profile))
+		return err
+	}
+	topLevel.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		if stop == nil {
+			return nil
+		}
+		if err := stop(); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "%s profile written to %s\n", po.Profile, profile.File(po.Profile))
+		return nil
+	}
+
+	options.AddProfileArgs(topLevel, po)
+	_ = topLevel.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return profile.Kinds(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
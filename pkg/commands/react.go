@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/runner/react"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addReact(topLevel *cobra.Command) {
+	long := strings.Builder{}
+	long.WriteString(`React to an entry with a quick flag, or take the reaction back if it was
+already given. Reactions are for journals shared with sync, each is kept
+with who gave it and when. They are shown after the message, and a
+question is open until someone reacts done after it.
+
+Who reacts is author in config, or the login name if it is not set:
+
+author: scott
+
+The reactions and their aliases are:
+
+`)
+	for _, r := range glyph.Reactions() {
+		g := r.Glyph()
+		long.WriteString(fmt.Sprintf("%s %s: %s\n", g.Symbol, g.Meaning, strings.Join(g.Aliases, ", ")))
+	}
+	long.WriteString(`
+List the entries with open questions with a view, see bujo view --help.`)
+
+	cmd := &cobra.Command{
+		Use:   "react <entry id> <reaction>",
+		Short: "React to an entry with 👍, ✅ or ❓",
+		Long:  long.String(),
+		Example: `
+bujo react <entry id> +1
+bujo react <entry id> question
+bujo react <entry id> done
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("requires an entry id and a reaction")
+			}
+			_, err := glyph.ReactionForAlias(args[1])
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			r, _ := glyph.ReactionForAlias(args[1])
+			s := react.React{
+				ID:          args[0],
+				Reaction:    r,
+				By:          author(),
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+// author is who reactions are by, author in config or the login name.
+func author() string {
+	if a := viper.GetString("author"); a != "" {
+		return a
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
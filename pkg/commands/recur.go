@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/recur"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addRecur(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "recur",
+		Short: "Add entries to the day log on a schedule.",
+		Long: `Add entries to the day log on a schedule.
+
+Recurring entries are kept in the Recurring collection. The ui adds the
+instances due each day when it starts and when the day rolls over, and
+bujo recur run adds them from the shell or an automation. Days the
+journal is not opened on are skipped. bujo recur stop stops an entry from
+recurring, and recurring entries that are completed, struck or moved add
+no instances.
+
+A rule is daily, weekdays, weekly (on the weekday it was added), monthly
+(on the day of the month it was added) or a cron expression, of which
+only the day of month, month and day of week are used.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	addRecurAdd(cmd)
+	addRecurList(cmd)
+	addRecurSet(cmd)
+	addRecurStop(cmd)
+	addRecurRun(cmd)
+
+	topLevel.AddCommand(cmd)
+}
+
+func addRecurAdd(topLevel *cobra.Command) {
+	ro := &options.RecurOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "add <message>",
+		Short: "Add a recurring entry.",
+		Example: `
+bujo recur add water the plants --every weekly
+bujo recur add stand up --every weekdays -b event
+bujo recur add pay rent --every "0 0 1 * *"
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("requires a message")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bullet, err := ro.GetBullet()
+			if err != nil {
+				return err
+			}
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := recur.Add{
+				Bullet:      bullet,
+				Message:     strings.Join(args, " "),
+				Rule:        ro.Every,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddRecurArgs(cmd, ro)
+	_ = cmd.MarkFlagRequired("every")
+
+	topLevel.AddCommand(cmd)
+}
+
+func addRecurList(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the recurring entries and the last day they were added.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := recur.List{Persistence: p}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+func addRecurSet(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "set <entry id> <rule>",
+		Short: "Change how often an entry recurs.",
+		Long: `Change how often an entry recurs. The id is of the recurring entry or
+any of the entries it added.`,
+		Example: `
+bujo recur set T-1CB monthly
+`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := recur.Set{
+				ID:          args[0],
+				Rule:        args[1],
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+func addRecurStop(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "stop <entry id>",
+		Short: "Stop an entry from recurring.",
+		Long: `Stop an entry from recurring. The id is of the recurring entry or any
+of the entries it added. The entries already added are kept.`,
+		Example: `
+bujo recur stop T-1CB
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := recur.Stop{
+				ID:          args[0],
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
+
+func addRecurRun(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Add the recurring entries due today to today's log.",
+		Example: `
+bujo recur run
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := recur.Run{Persistence: p}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/report"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addReport(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report on the journal",
+		Example: `
+bujo report notes 1m
+bujo report stats 3m
+bujo report saved weekly
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	addReportNotes(cmd)
+	addReportStats(cmd)
+	addReportSaved(cmd)
+
+	topLevel.AddCommand(cmd)
+}
+
+func addReportNotes(topLevel *cobra.Command) {
+	ro := &options.ReportOptions{}
+	lo := &options.LabelOptions{}
+	po := &options.PrivateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "notes [window]",
+		Short: "A digest of notes over a window, grouped by day",
+		Example: `
+bujo report notes 1m
+bujo report notes 2w --markdown --out digest.md
+bujo report notes 1m --tag work
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				ro.Window = args[0]
+			}
+			since, err := ro.GetSince(time.Now())
+			if err != nil {
+				return err
+			}
+			label, err := lo.GetLabel()
+			if err != nil {
+				return err
+			}
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := report.Notes{
+				Since:          since,
+				Label:          label,
+				Tag:            ro.Tag,
+				Markdown:       ro.Markdown || ro.Out != "",
+				IncludePrivate: po.IncludePrivate,
+				Persistence:    p,
+			}
+			if ro.Out != "" {
+				f, err := os.Create(ro.Out)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				s.Out = f
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddReportArgs(cmd, ro)
+	options.AddLabelArgs(cmd, lo)
+	options.AddIncludePrivateArg(cmd, po)
+
+	topLevel.AddCommand(cmd)
+}
+
+func addReportStats(topLevel *cobra.Command) {
+	var tag string
+
+	cmd := &cobra.Command{
+		Use:   "stats [window]",
+		Short: "The weekly mix of tasks, notes and events added, and how long tasks take to complete",
+		Example: `
+bujo report stats
+bujo report stats 3m
+bujo report stats 3m --tag work
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window := ""
+			if len(args) == 1 {
+				window = args[0]
+			}
+			now := time.Now()
+			since, err := options.ParseSince(window, now)
+			if err != nil {
+				return err
+			}
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := report.Stats{
+				Since:       since,
+				Until:       now,
+				Tag:         tag,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddTagArg(cmd, &tag)
+
+	topLevel.AddCommand(cmd)
+}
+
+// savedReports returns the reports saved in the config.
+func savedReports() ([]report.Saved, error) {
+	var saved []report.Saved
+	if err := viper.UnmarshalKey("reports", &saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func addReportSaved(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "saved [name]",
+		Short: "Run a notes digest saved in the config, or list them",
+		Long: `Run a notes digest saved in the config, or list them.
+
+Saved reports are set in the config. The window, label, tag, grouping by
+day or collection and format are those of bujo report notes. A report
+with out is written there as markdown, {date} in out is the day it runs.
+A report with a schedule is also run by bujo automations run, for
+example:
+
+reports:
+- name: weekly
+  window: 1w
+  group: collection
+  out: ~/digests/weekly-{date}.md
+  schedule: "0 18 * * 0"
+`,
+		Example: `
+bujo report saved
+bujo report saved weekly
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := store.LoadConfig(); err != nil {
+				return err
+			}
+			saved, err := savedReports()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				s := report.ListSaved{Reports: saved}
+				err = s.Do(context.Background())
+				return output.HandleError(err)
+			}
+
+			var found *report.Saved
+			for i := range saved {
+				if saved[i].Name == args[0] {
+					found = &saved[i]
+				}
+			}
+			if found == nil {
+				return app.Invalid("report", args[0], "there is no saved report with that name")
+			}
+			since, err := options.ParseSince(found.Window, time.Now())
+			if err != nil {
+				return err
+			}
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := report.Run{
+				Report:      *found,
+				Since:       since,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
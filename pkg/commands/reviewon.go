@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/reviewon"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addReviewOn(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "review-on <entry id> <date>",
+		Short: "Bring an entry back up on a day to look at it again",
+		Long: `Bring an entry back up on a day to look at it again, apart from when it
+is due or on. The entry is listed under Review in bujo log --day on that
+day, or after if the log was not looked at, and the review is cleared once
+it has been shown. A date of none clears it.`,
+		Example: `
+bujo review-on <entry id> 3/14
+bujo review-on <entry id> none
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("requires an entry id and a date")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s := reviewon.ReviewOn{ID: args[0]}
+			if date := strings.Join(args[1:], " "); date != "none" {
+				on, err := options.ParseDate(date)
+				if err != nil {
+					return err
+				}
+				s.On = on
+			}
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s.Persistence = p
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/scheme"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addMigrateScheme(topLevel *cobra.Command) {
+	so := &options.SchemeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "migrate-scheme",
+		Short: "Rename dated collections to a collection naming scheme.",
+		Long: `Rename dated collections to a collection naming scheme.
+
+The legacy scheme names day logs like "January 2, 2006", the iso scheme
+like "2006-01-02". New collections are named with the scheme set in the
+config, set it and then migrate the journal to it:
+
+collections:
+  scheme: iso
+`,
+		Example: `
+bujo migrate-scheme
+bujo migrate-scheme --to legacy --dry-run
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+
+			to := collection.Current()
+			if so.To != "" {
+				if to, err = collection.SchemeFor(so.To); err != nil {
+					return err
+				}
+			}
+
+			s := scheme.Migrate{
+				Scheme:      to,
+				DryRun:      so.DryRun,
+				SessionPath: viper.GetString("path") + sessionSuffix,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddSchemeArgs(cmd, so)
+
+	topLevel.AddCommand(cmd)
+}
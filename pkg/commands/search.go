@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/search"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addSearch(topLevel *cobra.Command) {
+	so := &options.SearchOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "search",
+		Aliases: []string{"find"},
+		Short:   "search entries by words, #tags and @mentions",
+		Long: `Search finds the entries with every word of the query. Words match
+whole and ignore case, a #tag or @mention only matches the tag or mention,
+a plain word also matches tags and mentions of that name.
+
+The words of every entry are kept in an index next to the journal, it is
+brought up to date as it is used so searches stay fast. Archived collections
+are not in the index, --archived searches them too.`,
+		Example: `
+bujo search standup
+bujo search #work @sam
+bujo search --archived #trip
+bujo search --tags
+bujo search --links Ideas
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 && !so.Tags && so.Links == "" {
+				return errors.New("requires something to search for")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := search.Search{
+				Query:       strings.Join(args, " "),
+				Tags:        so.Tags,
+				ShowID:      so.ShowID,
+				Archived:    so.Archived,
+				Persistence: p,
+			}
+			if so.Links != "" {
+				s.Links = collection.Resolve(so.Links)
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddSearchArgs(cmd, so)
+
+	topLevel.AddCommand(cmd)
+}
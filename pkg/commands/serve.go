@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/serve"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addServe(topLevel *cobra.Command) {
+	so := &options.ServeOptions{}
+	po := &options.PrivateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the journal as an http/json api.",
+		Long: `Serve the journal as an http/json api, for other tools to read and write
+the same journal. It serves until interrupted.
+
+  GET  /v1/collections                 the collection names
+  GET  /v1/entries?collection=<name>   the entries, of every collection if no name
+  GET  /v1/entries/<id>                an entry, by id or ref
+  POST /v1/entries                     add {"collection", "bullet", "message"}
+  POST /v1/entries/<id>/complete       complete a task
+  POST /v1/entries/<id>/move           move {"collection"}
+  GET  /v1/report/notes?since=<date>   the notes digest, &tag, &label, &group
+  GET  /v1/events                      changes to the journal, as server-sent events
+  GET  /v1/watch?collection=<name>     changes to one collection, as server-sent events
+
+Events are json. /v1/events sends a change event for each collection that
+changed, with the entries that changed. /v1/watch starts with a snapshot
+event of the entries of the collection, then sends a change event for
+each change to it, and another snapshot when it has to be read again. A
+day alias like today follows the day as it rolls over.
+
+Changes are sent as application/json, and are answered with the report
+of a batch, see bujo batch --help.
+
+Private entries are left out, an entry that is private is not found,
+unless --include-private or serve.include_private is set.
+
+Every request needs the token as a bearer token, or a token query
+parameter. Without a token the api can only listen on this host, and it
+is read-only. On this host, requests must name it as localhost or a
+loopback address. The defaults can be set in the config, for example:
+
+serve:
+  addr: localhost:8080
+  token: a-long-random-string
+  read_only: true
+  include_private: false
+`,
+		Example: `
+bujo serve
+bujo serve --addr :8080 --read-only
+curl -H "Authorization: Bearer $TOKEN" localhost:8080/v1/collections
+curl -N "localhost:8080/v1/watch?collection=today&token=$TOKEN"
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, os.Interrupt)
+			go func() {
+				<-sig
+				cancel()
+			}()
+
+			s := serve.Serve{
+				Addr:        so.Addr,
+				Token:       viper.GetString("serve.token"),
+				ReadOnly:    so.ReadOnly || viper.GetBool("serve.read_only"),
+				Persistence: p,
+
+				IncludePrivate: po.IncludePrivate || viper.GetBool("serve.include_private"),
+			}
+			if s.Addr == "" {
+				s.Addr = viper.GetString("serve.addr")
+			}
+			err = s.Do(ctx)
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddServeArgs(cmd, so)
+	options.AddIncludePrivateArg(cmd, po)
+
+	topLevel.AddCommand(cmd)
+}
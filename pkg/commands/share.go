@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/share"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addShare(topLevel *cobra.Command) {
+	co := &options.CollectionOptions{}
+	so := &options.ShareOptions{}
+	po := &options.PrivateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "share [entry id]",
+		Short: "Share an entry or a collection as markdown",
+		Example: `
+bujo share <entry id>
+bujo share --collection "January 2, 2006" --target post --url https://paste.example.com
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			t, err := shareTarget(so)
+			if err != nil {
+				return err
+			}
+			s := share.Share{
+				ID:             strings.Join(args, " "),
+				Collection:     co.Collection,
+				Target:         t,
+				IncludePrivate: po.IncludePrivate,
+				Persistence:    p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddCollectionArgs(cmd, co)
+	flagName := "collection"
+	_ = cmd.RegisterFlagCompletionFunc(flagName, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return collectionCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	options.AddShareArgs(cmd, so)
+	options.AddIncludePrivateArg(cmd, po)
+
+	topLevel.AddCommand(cmd)
+}
+
+// shareTarget resolves the share target from flags, falling back to config.
+// Must be called after the config has been loaded.
+func shareTarget(so *options.ShareOptions) (share.Target, error) {
+	target := so.Target
+	if target == "" {
+		target = viper.GetString("share.target")
+	}
+	url := so.URL
+	if url == "" {
+		url = viper.GetString("share.url")
+	}
+	token := viper.GetString("share.token")
+	if token == "" {
+		token = os.Getenv("BUJO_SHARE_TOKEN")
+	}
+	return share.TargetFor(target, url, token, so.Public || viper.GetBool("share.public"))
+}
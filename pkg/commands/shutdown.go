@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/shutdown"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addShutdown(topLevel *cobra.Command) {
+	so := &options.ShutdownOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "shutdown",
+		Short: "Walk the end of day checklist.",
+		Long: `Walk the end of day checklist.
+
+The steps are:
+
+  review      complete, migrate or strike today's open tasks.
+  inbox       complete, migrate or strike the open tasks in the inbox.
+  priorities  add up to three priority tasks to tomorrow.
+  reflect     add a note about the day to today.
+
+The steps and the inbox collection are set in the config, for example:
+
+shutdown:
+  steps: [review, priorities, reflect]
+  inbox: Inbox
+`,
+		Example: `
+bujo shutdown
+bujo shutdown --steps review,reflect
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+
+			s := shutdown.Shutdown{
+				Steps:       so.Steps,
+				Inbox:       so.Inbox,
+				On:          time.Now(),
+				AskReason:   askReason(),
+				Persistence: p,
+			}
+			if len(s.Steps) == 0 {
+				s.Steps = viper.GetStringSlice("shutdown.steps")
+			}
+			if len(s.Steps) == 0 {
+				s.Steps = shutdown.DefaultSteps()
+			}
+			if s.Inbox == "" {
+				s.Inbox = viper.GetString("shutdown.inbox")
+			}
+			if s.Inbox == "" {
+				s.Inbox = "Inbox"
+			}
+
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddShutdownArgs(cmd, so)
+
+	topLevel.AddCommand(cmd)
+}
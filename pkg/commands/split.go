@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/split"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addSplit(topLevel *cobra.Command) {
+	so := &options.SplitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "split",
+		Short: "split an entry into one entry per line",
+		Long: `split an entry into one entry per line
+
+An entry on a single line is split on "; ".`,
+		Example: `
+bujo split <entry id>
+bujo split <entry id> --at 3
+bujo split <entry id> --interactive
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("requires a entry id")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := split.Split{
+				ID:          args[0],
+				At:          so.At,
+				Interactive: so.Interactive,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddSplitArgs(cmd, so)
+
+	topLevel.AddCommand(cmd)
+}
+
+func addJoin(topLevel *cobra.Command) {
+	jo := &options.JoinOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "join",
+		Short: "join entries of a collection into the first of them",
+		Example: `
+bujo join <entry id> <entry id>...
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return errors.New("requires at least two entry ids")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			s := split.Join{
+				IDs:         args,
+				Separator:   jo.Separator,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddJoinArgs(cmd, jo)
+
+	topLevel.AddCommand(cmd)
+}
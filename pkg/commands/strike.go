@@ -3,8 +3,12 @@ package commands
 import (
 	"context"
 	"errors"
-	"github.com/spf13/cobra"
+	"os"
 	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
 	"tableflip.dev/bujo/pkg/commands/options"
 	"tableflip.dev/bujo/pkg/runner/strike"
 	"tableflip.dev/bujo/pkg/store"
@@ -12,13 +16,20 @@ import (
 
 func addStrike(topLevel *cobra.Command) {
 	io := &options.IDOptions{}
+	so := &options.StrikeOptions{}
 
 	cmd := &cobra.Command{
 		Use:     "strike",
 		Aliases: []string{"irrelevant"},
 		Short:   "mark something irrelevant",
+		Long: `Mark something irrelevant.
+
+Why it was struck is asked for, enter skips it. The reason is shown after
+the entry and in the activity. Set strike.ask_reason to false in config to
+not be asked, --reason still sets one.`,
 		Example: `
 bujo strike <entry id>
+bujo strike <entry id> --reason "vendor cancelled"
 `,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
@@ -36,6 +47,8 @@ bujo strike <entry id>
 			}
 			s := strike.Strike{
 				ID:          io.ID,
+				Reason:      so.Reason,
+				Ask:         askReason() && interactive(),
 				Persistence: p,
 			}
 			err = s.Do(context.Background())
@@ -43,5 +56,19 @@ bujo strike <entry id>
 		},
 	}
 
+	options.AddStrikeArgs(cmd, so)
+
 	topLevel.AddCommand(cmd)
 }
+
+// askReason returns true if why an entry is struck is asked for, which is
+// strike.ask_reason in config and on unless set.
+func askReason() bool {
+	return !viper.IsSet("strike.ask_reason") || viper.GetBool("strike.ask_reason")
+}
+
+// interactive returns true if someone is at the terminal to answer.
+func interactive() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/runner/sync"
+	"tableflip.dev/bujo/pkg/store"
+	jsync "tableflip.dev/bujo/pkg/sync"
+)
+
+// gitSync is the sync of the journal set in the config, nil if there is
+// none.
+func gitSync() (*jsync.Git, error) {
+	cfg, err := store.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	remote := viper.GetString("sync.remote")
+	if remote == "" {
+		return nil, nil
+	}
+	if cfg.Remote() != "" {
+		return nil, errors.New("sync: the journal is remote, sync is for a local journal")
+	}
+	g := &jsync.Git{
+		Dir:    cfg.BasePath(),
+		Remote: remote,
+		Branch: viper.GetString("sync.branch"),
+	}
+	return g, g.Validate()
+}
+
+func addSync(topLevel *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync the journal with a git repository.",
+		Long: `Sync the journal with a git repository, to write it on more than one host.
+
+The local changes are committed, the remote ones merged and the result
+pushed. The repository is kept next to the journal, with the path of the
+journal and .git added. The system git is used, so ssh keys and credential
+helpers work as they do for git.
+
+An entry changed on both sides keeps both versions, the remote one in a
+conflict folder next to the local one. Review them with 'r' in bujo ui.
+
+Sync is set in the config, for example:
+
+sync:
+  remote: git@github.com:me/journal.git
+  branch: main
+  interval: 15m
+
+bujo ui syncs every interval while it is open, and with 'y'.
+`,
+		Example: `
+bujo sync
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			g, err := gitSync()
+			if err != nil {
+				return err
+			}
+			s := sync.Sync{Git: g}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	topLevel.AddCommand(cmd)
+}
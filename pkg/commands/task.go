@@ -15,6 +15,7 @@ import (
 
 func addTask(topLevel *cobra.Command) {
 	no := &options.AddOptions{}
+	oo := &options.OnOptions{}
 	so := &options.SigOptions{}
 	co := &options.CollectionOptions{}
 
@@ -23,6 +24,7 @@ func addTask(topLevel *cobra.Command) {
 		Short: "Add a task",
 		Example: `
 bujo add task do this task
+bujo add task file taxes --on=4/15
 `,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
@@ -38,21 +40,30 @@ bujo add task do this task
 				return err
 			}
 
+			on, err := oo.GetOn()
+			if err != nil {
+				return err
+			}
+
 			s := add.Add{
 				Bullet:        glyph.Task,
 				Persistence:   p,
 				Message:       no.Message,
 				Collection:    co.Collection,
+				After:         no.After,
 				Priority:      so.Priority,
 				Inspiration:   so.Inspiration,
 				Investigation: so.Investigation,
+				On:            on,
 			}
 			err = s.Do(context.Background())
 			return output.HandleError(err)
 		},
 	}
 
+	options.AddOnArgs(cmd, oo)
 	options.AddSigArgs(cmd, so)
+	options.AddAfterArgs(cmd, no)
 	options.AddCollectionArgs(cmd, co)
 
 	flagName := "collection"
@@ -2,19 +2,191 @@ package commands
 
 import (
 	"context"
+	"sort"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/health"
+	"tableflip.dev/bujo/pkg/notify"
+	"tableflip.dev/bujo/pkg/profile"
 	"tableflip.dev/bujo/pkg/store"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"tableflip.dev/bujo/pkg/runner/ui"
 )
 
+// sessionSuffix is added to the store path for the ui session.
+const sessionSuffix = ".session"
+
 func addUI(topLevel *cobra.Command) {
+	uo := &options.UIOptions{}
+
 	cmd := &cobra.Command{
 		Use:   "ui",
 		Short: "open the text-based user interface",
+		Long: `open the text-based user interface
+
+What the ui opens to can be set in the config, for example:
+
+ui:
+  open: today
+  compact: auto
+  idle_lock: 5m
+  columns: auto
+
+Compact hides the index unless it is focused, auto is compact in
+terminals narrower than 80 columns. Idle lock hides the journal behind
+a lock screen after that long without a key press. Any key unlocks it,
+or the passphrase if the journal is encrypted.
+
+Columns splits the collection into up to three columns side by side,
+auto adds a column for every 100 columns of terminal width. Left and
+right move between the columns.
+
+In the capture prompt, start with ">tomorrow" or a date like ">2/28" to
+add to that day's log, after any '-' or 'o' for the bullet. Without a
+bullet the entry is a task, unless the collection has a default bullet,
+see bujo collection default-bullet --help.
+
+Press ctrl+d at the end of the day to review today's open tasks one at a
+time: 'c' completes, 't' migrates to tomorrow, 'f' moves to the future
+log, 's' strikes and space keeps it. After the last, a note about the
+day can be added to today, like bujo shutdown.
+
+Press ctrl+w for this week's log, like "Week 42, 2026". Under its own
+entries are the seven days of the week side by side, days without entries
+are narrowed to their name. With a week log highlighted in the index,
+'[' and ']' move to the week before or after.
+
+Press 'f' to complete the selected task and add a follow up to it, tab
+picks whether it goes to tomorrow, next week, this month or where the
+task was.
+
+Press 'g' to migrate the open tasks of the collection, space marks tasks
+and 1-4 moves them all to today, tomorrow, this month or the future log.
+
+Press 'v' to mark the selected entry private, private entries are left
+out of exports, shares and reports.
+
+Press space to preview the start of the selected entry's message, with
+its word count and how long it takes to read.
+
+Press ctrl+k to attach a URL or a file to the selected entry, with a
+label after it, or -1 to remove the first. The entry shows how many it
+has and the preview lists them. Press ctrl+g to open one with the opener
+of the OS, see bujo attach --help.
+
+Press '+' to react to the selected entry with 👍, ✅ or ❓ as author in
+config, again to take it back. The reactions are shown after the message
+and the preview lists who gave them, see bujo react --help.
+
+Lines of a message that start with [ ] or [x] are a checklist, write them
+with 'E'. The entry shows how many are checked, like [2/5], and the
+preview lists them numbered, 1 to 9 check or uncheck one.
+
+Press 'E' to edit the message of the selected entry in $VISUAL or $EDITOR.
+
+Press 'a' for a feed of the last week of activity, enter jumps to the entry.
+
+Press 'b' and a letter to mark the selected entry, and ' and the letter
+to jump back to it. Ctrl+O goes back to where the ui was before a jump,
+tab goes forward again.
+
+Press 'd' to show when completed tasks were done, or set
+ui.show_done_time in config to show it from the start.
+
+Recurring entries due today are added to today's log when the ui starts
+and when the day rolls over. Press 'w' on one of them, or an entry it
+added, to change how often it recurs, see bujo recur --help.
+
+Collections show their pinned entries first, then the open ones, with the
+completed and struck entries last behind a Done divider, enter on it
+shows or hides them. Press 'u' to pin or unpin the selected entry.
+
+Press '-' to strike the selected entry, it asks why unless
+strike.ask_reason is false in config.
+
+Press '*' to change the bullet or signifier of the selected entry, 1 to 5
+pick a bullet and a to d a signifier.
+
+Press ctrl+e on an event to write a meeting invite for it to an .ics
+file, see bujo invite --help.
+
+Press ctrl+r to import a markdown task list or todo.txt file, see bujo
+import --help.
+
+Press '/' to search every collection, the results narrow as you type and
+enter jumps to the picked entry.
+
+Press '#' for the #tags in use with how many entries have each, enter
+lists the entries with a tag and enter again jumps to one.
+
+Views saved in the config are at the top of the index, marked with ◇,
+and show the entries they pick from across the journal as if they were
+one collection, see bujo view --help.
+
+As it starts, the ui checks the journal and its search index can be
+written, the durations and true or false values of the config parse and
+the clock is not behind the newest entry. Problems are shown with how to
+fix them in a banner under the collection, ESC dismisses it.
+
+Press 'i' for the counts, dates and disk usage of the open collection.
+
+Press 'z' to archive the highlighted collection, see bujo archive --help.
+Press ctrl+a to show the archived collections at the bottom of the index,
+marked with ▤. They are read only, 'z' on one unarchives it.
+
+Press 'q', ESC or ctrl+c to quit. While the prompt has text, or a share,
+trace or sync is not done, the ui asks to quit again first, unless
+ui.confirm_quit is false in config.
+
+Press 'P' to write a 10 second trace of the ui to bujo.trace. Press
+ctrl+l to change the log level until the ui quits.
+
+Press 'y' to sync the journal with the git repository of sync.remote in
+config, and it syncs every sync.interval while the ui is open, see bujo
+sync --help. When a sync leaves more than one version of an entry, press
+'r' to review them side by side and keep one, or merge them.
+
+The colors are for a dark terminal unless ui.theme is light, solarized,
+a theme of ui.themes or auto. Auto is light or dark as ui.theme_schedule
+says, the hours it is light like 07:00-19:00, and switches as the ui
+runs. Without a schedule it asks the terminal for its background as the
+ui starts. Press ctrl+t to switch to the next theme, it is saved as
+ui.theme. A theme in ui.themes says what the eight colors the ui uses
+are shown as, by name, number of the 256 color palette or #rrggbb:
+
+ui:
+  themes:
+    paper:
+      white: "#333333"
+      yellow: 136
+      cyan: 30
+
+What has focus can be made easier to see in config: ui.focus.style is how
+the selected row is shown (reverse, bold or underline), ui.focus.color
+colors it and the border of what has focus, and ui.focus.marker is put
+before the title of the focused pane. ui.cursor.shape sets the cursor of
+prompts to block, underline or bar, and ui.cursor.blink makes it blink.
+
+Press ctrl+p and "save <name>" to save the layout as it is, the compact
+mode, columns and bar segments, to ui.layouts in config. Press ctrl+p and
+the name of a saved layout to switch to it, or start with one with
+--layout or ui.layout, to keep a layout for each terminal bujo runs in.
+
+The bottom bar is made of segments, ui.bar.left and ui.bar.right list
+the ones shown at each end in order: mode (what the keys act on),
+context (the open collection), status, pending (the bullet and
+collection of the entry being captured), clock, sync (when the journal
+was last synced), entry (the ref, collection, age and tags of the
+selected entry) and help. The default is entry and status on the left
+and sync and help on the right. What does not fit of the right is cut.
+`,
 		Example: `
 bujo ui
+bujo ui --open month
+bujo ui --open "Future - December, 2026"
+bujo ui --layout work-laptop
 `,
 		ValidArgs: []string{},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -22,10 +194,90 @@ bujo ui
 			if err != nil {
 				return err
 			}
-			i := ui.UI{Persistence: p}
+			i := ui.UI{
+				Persistence:  p,
+				EagerSelect:  viper.GetBool("ui.eager_select"),
+				Open:         uo.Open,
+				Columns:      uo.Columns,
+				Compact:      viper.GetString("ui.compact"),
+				IdleLock:     viper.GetDuration("ui.idle_lock"),
+				SessionPath:  viper.GetString("path") + sessionSuffix,
+				ShowDoneTime: viper.GetBool("ui.show_done_time"),
+				AskReason:    askReason(),
+				Author:       author(),
+				ConfirmQuit:  !viper.IsSet("ui.confirm_quit") || viper.GetBool("ui.confirm_quit"),
+				TracePath:    profile.File(profile.Trace),
+				Focus: ui.Focus{
+					Style:  viper.GetString("ui.focus.style"),
+					Color:  viper.GetString("ui.focus.color"),
+					Marker: viper.GetString("ui.focus.marker"),
+					Cursor: viper.GetString("ui.cursor.shape"),
+					Blink:  viper.GetBool("ui.cursor.blink"),
+				},
+				Theme: ui.Theme{
+					Mode:     viper.GetString("ui.theme"),
+					Schedule: viper.GetString("ui.theme_schedule"),
+				},
+				Bar: ui.Bar{
+					Left:  viper.GetStringSlice("ui.bar.left"),
+					Right: viper.GetStringSlice("ui.bar.right"),
+				},
+			}
+			if err := viper.UnmarshalKey("ui.themes", &i.Theme.Palettes); err != nil {
+				return err
+			}
+			if err := viper.UnmarshalKey("ui.layouts", &i.Layouts); err != nil {
+				return err
+			}
+			i.Layout = uo.Layout
+			if i.Layout == "" {
+				i.Layout = viper.GetString("ui.layout")
+			}
+			if i.Sync, err = gitSync(); err != nil {
+				return err
+			}
+			i.SyncEvery = viper.GetDuration("sync.interval")
+			i.Problems = health.Setup(viper.GetString("path"))
+			// Without a --length the meeting can not be invalid.
+			i.Meeting, _ = inviteMeeting("")
+			if i.Views, err = savedViews(); err != nil {
+				return err
+			}
+			if i.Open == "" {
+				i.Open = viper.GetString("ui.open")
+			}
+			if i.Columns == "" {
+				i.Columns = viper.GetString("ui.columns")
+			}
+			// The terminal sink would print over the ui.
+			if sink, err := notifier(notify.KindTerminal); err == nil {
+				i.Notify = sink
+			}
+			// Sharing is optional in the ui, only enable it if configured.
+			if t, err := shareTarget(&options.ShareOptions{}); err == nil {
+				i.Share = t
+			}
 			return i.Do(context.Background())
 		},
 	}
 
+	options.AddUIArgs(cmd, uo)
+	_ = cmd.RegisterFlagCompletionFunc("open", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		opens := []string{ui.OpenToday, ui.OpenMonth, ui.OpenFuture, ui.OpenLast}
+		return append(opens, collectionCompletions(toComplete)...), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = cmd.RegisterFlagCompletionFunc("layout", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if _, err := store.LoadConfig(); err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := make([]string, 0)
+		for name := range viper.GetStringMap("ui.layouts") {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+
 	topLevel.AddCommand(cmd)
 }
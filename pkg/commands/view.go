@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/views"
+	"tableflip.dev/bujo/pkg/store"
+	"tableflip.dev/bujo/pkg/view"
+)
+
+// savedViews are the views in the config.
+func savedViews() ([]view.View, error) {
+	var all []view.View
+	if err := viper.UnmarshalKey("views", &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+func addView(topLevel *cobra.Command) {
+	co := &options.CollectionOptions{}
+	io := &options.IDOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "view [name]",
+		Short: "Show a view saved in the config, or list them",
+		Long: `Show a view saved in the config, or list them.
+
+A view picks entries from across the journal as if they were one
+collection: from the collections matching collections, where * matches
+any part of a name, with the bullet, label and #tag given. Sort is order,
+priority or created, and group collection keeps the entries of each
+collection together. Views are also in the index of the ui, for example:
+
+views:
+- name: Work focus
+  collections: ["Project*"]
+  bullet: task
+  tag: work
+  sort: priority
+  group: collection
+
+Reaction picks the entries someone reacted to with it, see bujo react
+--help. A question only counts while it is open, so this lists the
+entries with open questions:
+
+views:
+- name: Open questions
+  reaction: question
+`,
+		Example: `
+bujo view
+bujo view "Work focus"
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := store.LoadConfig(); err != nil {
+				return err
+			}
+			all, err := savedViews()
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				s := views.List{Views: all}
+				err = s.Do(context.Background())
+				return output.HandleError(err)
+			}
+
+			found := view.Find(all, args[0])
+			if found == nil {
+				return app.Invalid("view", args[0], "there is no view with that name")
+			}
+			p, err := store.LoadCached(nil)
+			if err != nil {
+				return err
+			}
+			s := views.Show{
+				View:        *found,
+				ShowID:      io.ShowID,
+				ShowHidden:  co.ShowHidden,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddShowHiddenArg(cmd, co)
+	options.AddShowIDArgs(cmd, io)
+
+	topLevel.AddCommand(cmd)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"tableflip.dev/bujo/pkg/commands/options"
+	"tableflip.dev/bujo/pkg/runner/wait"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func addWait(topLevel *cobra.Command) {
+	io := &options.IDOptions{}
+	wo := &options.WaitOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "wait",
+		Aliases: []string{"waiting"},
+		Short:   "mark something as waiting for someone or something",
+		Example: `
+bujo wait <entry id> --for "a reply from Sam" --follow-up 2/28
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("requires a entry id")
+			}
+			io.ID = strings.Join(args, " ")
+
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := store.Load(nil)
+			if err != nil {
+				return err
+			}
+			followUp, err := wo.GetFollowUp()
+			if err != nil {
+				return err
+			}
+			s := wait.Wait{
+				ID:          io.ID,
+				On:          wo.For,
+				FollowUp:    followUp,
+				Persistence: p,
+			}
+			err = s.Do(context.Background())
+			return output.HandleError(err)
+		},
+	}
+
+	options.AddWaitArgs(cmd, wo)
+
+	topLevel.AddCommand(cmd)
+}
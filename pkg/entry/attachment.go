@@ -0,0 +1,76 @@
+package entry
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Attachment kinds.
+const (
+	AttachURL  = "url"
+	AttachFile = "file"
+)
+
+// Attachment is a link from an entry to a URL or a file, like a ticket, a
+// doc or an image.
+type Attachment struct {
+	// Kind is AttachURL or AttachFile.
+	Kind string `json:"kind"`
+	// Label is what the attachment is shown as, the target if empty.
+	Label  string `json:"label,omitempty"`
+	Target string `json:"target"`
+}
+
+// NewAttachment returns an attachment of target, a URL if it has a scheme
+// and a file otherwise. File paths are made absolute so they open from
+// anywhere, ~/ is left for the opener to expand.
+func NewAttachment(target, label string) Attachment {
+	target = strings.TrimSpace(target)
+	// One letter is a drive, like C:\.
+	if u, err := url.Parse(target); err == nil && len(u.Scheme) > 1 {
+		if u.Scheme != "file" {
+			return Attachment{Kind: AttachURL, Label: label, Target: target}
+		}
+		target = u.Path
+	}
+	if !strings.HasPrefix(target, "~") {
+		if abs, err := filepath.Abs(target); err == nil {
+			target = abs
+		}
+	}
+	return Attachment{Kind: AttachFile, Label: label, Target: target}
+}
+
+// String is the label and the target, or the target if there is no label.
+func (a Attachment) String() string {
+	if a.Label == "" {
+		return a.Target
+	}
+	return a.Label + " <" + a.Target + ">"
+}
+
+// AddAttachment attaches a to the entry. An attachment of the same target
+// is replaced, to change its label.
+func (e *Entry) AddAttachment(a Attachment) {
+	for i, o := range e.Attachments {
+		if o.Target == a.Target {
+			e.Attachments[i] = a
+			return
+		}
+	}
+	e.Attachments = append(e.Attachments, a)
+}
+
+// RemoveAttachment removes attachment n, from 0. It returns false if there
+// is no attachment n.
+func (e *Entry) RemoveAttachment(n int) bool {
+	if n < 0 || n >= len(e.Attachments) {
+		return false
+	}
+	e.Attachments = append(e.Attachments[:n], e.Attachments[n+1:]...)
+	if len(e.Attachments) == 0 {
+		e.Attachments = nil
+	}
+	return true
+}
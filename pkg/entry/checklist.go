@@ -0,0 +1,96 @@
+package entry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Checklist item marks, at the start of a line of a message.
+const (
+	unchecked = "[ ] "
+	checked   = "[x] "
+)
+
+// CheckItem is an item of the checklist of a message.
+type CheckItem struct {
+	Text string
+	Done bool
+}
+
+// checkLine returns the item on a line of a message, ok is false if the
+// line is not one.
+func checkLine(line string) (item CheckItem, ok bool) {
+	trimmed := strings.TrimSpace(line) + " "
+	switch {
+	case strings.HasPrefix(trimmed, unchecked):
+		return CheckItem{Text: strings.TrimSpace(trimmed[len(unchecked):])}, true
+	case strings.HasPrefix(strings.ToLower(trimmed), checked):
+		return CheckItem{Text: strings.TrimSpace(trimmed[len(checked):]), Done: true}, true
+	}
+	return CheckItem{}, false
+}
+
+// Checklist returns the items of the message, its lines that start with
+// "[ ] " or "[x] ". A checklist is lighter than an entry per item.
+func (e *Entry) Checklist() []CheckItem {
+	items := make([]CheckItem, 0)
+	for _, line := range strings.Split(e.Message, "\n") {
+		if item, ok := checkLine(line); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Progress is how many items of the checklist are checked, like 2/5, or
+// empty if the message has no checklist.
+func (e *Entry) Progress() string {
+	items := e.Checklist()
+	if len(items) == 0 {
+		return ""
+	}
+	done := 0
+	for _, i := range items {
+		if i.Done {
+			done++
+		}
+	}
+	return fmt.Sprintf("%d/%d", done, len(items))
+}
+
+// Headline is the message without its checklist, what the line of the
+// entry shows.
+func (e *Entry) Headline() string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(e.Message, "\n") {
+		if _, ok := checkLine(line); !ok {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// ToggleCheck checks or unchecks item n of the checklist, from 0. It
+// returns false if there is no item n.
+func (e *Entry) ToggleCheck(n int) bool {
+	lines := strings.Split(e.Message, "\n")
+	for i, line := range lines {
+		item, ok := checkLine(line)
+		if !ok {
+			continue
+		}
+		if n > 0 {
+			n--
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		mark := checked
+		if item.Done {
+			mark = unchecked
+		}
+		lines[i] = indent + mark + item.Text
+		e.Message = strings.Join(lines, "\n")
+		return true
+	}
+	return false
+}
@@ -26,19 +26,117 @@ type Entry struct {
 	Bullet     glyph.Bullet    `json:"bullet"`
 	Schema     string          `json:"schema"`
 	Created    Timestamp       `json:"created"`
+	Order      int64           `json:"order,omitempty"`
 	Collection string          `json:"collection"`
 	On         *Timestamp      `json:"on,omitempty"`
 	Signifier  glyph.Signifier `json:"signifier,omitempty"`
 	Message    string          `json:"message,omitempty"`
+	Label      glyph.Label     `json:"label,omitempty"`
+	WaitingOn  string          `json:"waitingOn,omitempty"`
+	FollowUp   *Timestamp      `json:"followUp,omitempty"`
+	// ReviewOn is when the entry comes back up to be looked at again, apart
+	// from when it is due. It is cleared once it has.
+	ReviewOn *Timestamp `json:"reviewOn,omitempty"`
+	// Expires is when a note stops being shown and can be archived.
+	Expires *Timestamp `json:"expires,omitempty"`
+	// CalendarUID is the uid of the calendar item kept for the entry.
+	CalendarUID string `json:"calendarUid,omitempty"`
+	// Private entries are left out of exports, shares and digests.
+	Private bool `json:"private,omitempty"`
+	// Pinned entries are shown first in their collection in the ui.
+	Pinned bool `json:"pinned,omitempty"`
+	// Reason is why the entry was struck, if one was given.
+	Reason string `json:"reason,omitempty"`
+	// Recurrence is set on recurring entries and the instances they add.
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	// Attachments are the URLs and files linked to the entry.
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Reactions are the quick flags people put on the entry, oldest first.
+	Reactions []Reaction `json:"reactions,omitempty"`
+	// MovedAt is when the entry was moved, set on the original left behind.
+	MovedAt *Timestamp `json:"movedAt,omitempty"`
+	// CompletedAt is when the entry was last completed.
+	CompletedAt *Timestamp `json:"completedAt,omitempty"`
+	// StruckAt is when the entry was last struck.
+	StruckAt *Timestamp `json:"struckAt,omitempty"`
 }
 
+// Recurrence is how often a recurring entry is added to the day log.
+type Recurrence struct {
+	// Rule is daily, weekdays, weekly, monthly or a cron expression, see
+	// the recur package.
+	Rule string `json:"rule"`
+	// Of is the id of the recurring entry an instance was added for. It is
+	// empty on the recurring entry.
+	Of string `json:"of,omitempty"`
+	// Last is the last day the recurring entry added an instance.
+	Last *Timestamp `json:"last,omitempty"`
+}
+
+func (r *Recurrence) clone() *Recurrence {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.Last = r.Last.clone()
+	return &c
+}
+
+// Complete marks the entry completed, now.
 func (e *Entry) Complete() {
 	e.Bullet = glyph.Completed
+	e.CompletedAt = &Timestamp{Time: time.Now()}
+}
+
+// CanComplete returns true if the entry is a task that is open or waiting.
+func (e *Entry) CanComplete() bool {
+	return e.Bullet == glyph.Task || e.Bullet == glyph.Waiting
 }
 
-func (e *Entry) Strike() {
+// Strike marks the entry irrelevant, and why if reason is set.
+func (e *Entry) Strike(reason string) {
 	e.Bullet = glyph.Irrelevant
 	e.Signifier = glyph.None
+	e.Reason = reason
+	e.StruckAt = &Timestamp{Time: time.Now()}
+}
+
+// Wait marks the entry as waiting on someone or something, with an optional
+// date to follow up on it.
+func (e *Entry) Wait(on string, followUp *time.Time) {
+	e.Bullet = glyph.Waiting
+	e.WaitingOn = on
+	e.FollowUp = nil
+	if followUp != nil {
+		e.FollowUp = &Timestamp{Time: *followUp}
+	}
+}
+
+// FollowUpDue returns true if the entry is waiting and the follow up date is
+// on or before the given time.
+func (e *Entry) FollowUpDue(now time.Time) bool {
+	if e.Bullet != glyph.Waiting || e.FollowUp == nil {
+		return false
+	}
+	return e.FollowUp.SameDay(now) || e.FollowUp.Before(now)
+}
+
+// ReviewDue returns true if the entry has a review date on or before the
+// given time.
+func (e *Entry) ReviewDue(now time.Time) bool {
+	if e.ReviewOn == nil {
+		return false
+	}
+	return e.ReviewOn.SameDay(now) || e.ReviewOn.Before(now)
+}
+
+// Expired returns true if the entry is a note that expires on or before the
+// given time.
+func (e *Entry) Expired(now time.Time) bool {
+	if e.Bullet != glyph.Note || e.Expires == nil {
+		return false
+	}
+	return !e.Expires.After(now)
 }
 
 func (e *Entry) Move(bullet glyph.Bullet, collection string) *Entry {
@@ -50,11 +148,58 @@ func (e *Entry) Move(bullet glyph.Bullet, collection string) *Entry {
 		Signifier:  e.Signifier,
 		Bullet:     e.Bullet,
 		Message:    e.Message,
+		Label:      e.Label,
+		WaitingOn:  e.WaitingOn,
+		FollowUp:   e.FollowUp,
+		ReviewOn:   e.ReviewOn,
+		Expires:    e.Expires,
+		// The calendar item follows the entry.
+		CalendarUID: e.CalendarUID,
+		Private:     e.Private,
+		Pinned:      e.Pinned,
+		// So does the rule, the original no longer recurs.
+		Recurrence:  e.Recurrence.clone(),
+		Attachments: e.Attachments,
+		Reactions:   e.Reactions,
 	}
 	e.Bullet = bullet
+	e.Recurrence = nil
+	e.MovedAt = &Timestamp{Time: time.Now()}
 	return ne
 }
 
+// Clone returns a copy of the entry that shares nothing with it, so either
+// can be changed in place.
+func (e *Entry) Clone() *Entry {
+	c := *e
+	c.On = e.On.clone()
+	c.FollowUp = e.FollowUp.clone()
+	c.ReviewOn = e.ReviewOn.clone()
+	c.Expires = e.Expires.clone()
+	c.MovedAt = e.MovedAt.clone()
+	c.CompletedAt = e.CompletedAt.clone()
+	c.StruckAt = e.StruckAt.clone()
+	c.Recurrence = e.Recurrence.clone()
+	if e.Attachments != nil {
+		c.Attachments = append([]Attachment(nil), e.Attachments...)
+	}
+	if e.Reactions != nil {
+		c.Reactions = append([]Reaction(nil), e.Reactions...)
+	}
+	return &c
+}
+
+// WithoutPrivate returns the entries that are not private.
+func WithoutPrivate(entries []*Entry) []*Entry {
+	public := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		if !e.Private {
+			public = append(public, e)
+		}
+	}
+	return public
+}
+
 func (e *Entry) Title() string {
 	return e.Collection
 }
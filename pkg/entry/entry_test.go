@@ -0,0 +1,62 @@
+package entry
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// full returns an entry with every field of a reference type set.
+func full() *Entry {
+	at := func() *Timestamp { return &Timestamp{Time: time.Now()} }
+	e := New("Today", glyph.Task, "task\n[ ] item #tag")
+	e.On, e.FollowUp, e.ReviewOn, e.Expires = at(), at(), at(), at()
+	e.MovedAt, e.CompletedAt, e.StruckAt = at(), at(), at()
+	e.Recurrence = &Recurrence{Rule: "daily", Last: at()}
+	e.Attachments = []Attachment{NewAttachment("https://example.com", "")}
+	e.React(glyph.ReactionUp, "me", time.Now())
+	return e
+}
+
+func TestCloneSharesNothing(t *testing.T) {
+	e := full()
+	c := e.Clone()
+	if !reflect.DeepEqual(e, c) {
+		t.Fatalf("got %+v, want %+v", c, e)
+	}
+	shared(t, "Entry", reflect.ValueOf(e).Elem(), reflect.ValueOf(c).Elem())
+}
+
+// shared fails for each pointer or slice that a and b share.
+func shared(t *testing.T, path string, a, b reflect.Value) {
+	t.Helper()
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() {
+			t.Errorf("%s is not set by full", path)
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			t.Errorf("%s is shared", path)
+			return
+		}
+		shared(t, path, a.Elem(), b.Elem())
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 {
+			t.Errorf("%s is not set by full", path)
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			t.Errorf("%s is shared", path)
+		}
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			shared(t, path+"."+a.Type().Field(i).Name, a.Field(i), b.Field(i))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package entry
+
+import (
+	"sort"
+)
+
+// SortKey is the position of the entry in its collection. Entries without a
+// manual order are ordered by when they were created.
+func (e *Entry) SortKey() int64 {
+	if e.Order != 0 {
+		return e.Order
+	}
+	return e.Created.UnixNano()
+}
+
+// Sort orders entries by their position in the collection.
+func Sort(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].SortKey() < entries[j].SortKey()
+	})
+}
+
+// orderGap is the room left between entries that are given a new order.
+const orderGap = 1 << 20
+
+// OrderAfter returns the order that places an entry right after prev in
+// sorted, the entries of its collection in order. When there is no room
+// between prev and the entry after it, the entries after prev are given new
+// orders to make room. They are returned, and have to be stored again.
+func OrderAfter(sorted []*Entry, prev *Entry) (int64, []*Entry) {
+	at := prev.SortKey()
+	var rest []*Entry
+	for i, e := range sorted {
+		if e == prev || (e.ID != "" && e.ID == prev.ID) {
+			rest = sorted[i+1:]
+			break
+		}
+	}
+	if len(rest) == 0 {
+		return at + 1, nil
+	}
+	if gap := rest[0].SortKey() - at; gap > 1 {
+		return at + gap/2, nil
+	}
+
+	// Ties are listed in no set order, so rather than land on top of the
+	// next entry, move it and any after it that are in the way.
+	order := at + orderGap
+	last := order
+	moved := make([]*Entry, 0)
+	for _, e := range rest {
+		if e.SortKey() > last {
+			break
+		}
+		last += orderGap
+		e.Order = last
+		moved = append(moved, e)
+	}
+	return order, moved
+}
@@ -0,0 +1,52 @@
+package entry
+
+import (
+	"testing"
+
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestOrderAfter(t *testing.T) {
+	at := func(orders ...int64) []*Entry {
+		all := make([]*Entry, 0, len(orders))
+		for _, o := range orders {
+			e := New("Today", glyph.Task, "task")
+			e.Order = o
+			all = append(all, e)
+		}
+		return all
+	}
+	tests := map[string]struct {
+		orders []int64
+		prev   int
+		moved  int
+	}{
+		"last":         {orders: []int64{10, 20}, prev: 1},
+		"room":         {orders: []int64{10, 20}, prev: 0},
+		"next to":      {orders: []int64{10, 11, 10 + 3*orderGap}, prev: 0, moved: 1},
+		"tied":         {orders: []int64{10, 10, 10}, prev: 0, moved: 2},
+		"tied in room": {orders: []int64{10, 10, 10 + 3*orderGap}, prev: 0, moved: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			all := at(tc.orders...)
+			prev := all[tc.prev]
+			order, moved := OrderAfter(all, prev)
+			if len(moved) != tc.moved {
+				t.Errorf("moved %d entries, want %d", len(moved), tc.moved)
+			}
+			e := New("Today", glyph.Task, "new")
+			e.Order = order
+			all = append(all, e)
+			Sort(all)
+			for i, o := range all {
+				if i > 0 && o.SortKey() <= all[i-1].SortKey() {
+					t.Errorf("order %d at %d is not after %d", o.SortKey(), i, all[i-1].SortKey())
+				}
+			}
+			if all[tc.prev+1] != e {
+				t.Errorf("new entry is not right after prev")
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package entry
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// Reaction is a record of someone reacting to an entry, kept in the order
+// they reacted.
+type Reaction struct {
+	Kind glyph.Reaction `json:"kind"`
+	// By is who reacted, the author in their config.
+	By string    `json:"by"`
+	At Timestamp `json:"at"`
+}
+
+// React adds the reaction of kind by someone, or takes it back if they had
+// already reacted with it. It returns true if the reaction was added.
+func (e *Entry) React(kind glyph.Reaction, by string, at time.Time) bool {
+	for i, r := range e.Reactions {
+		if r.Kind == kind && r.By == by {
+			e.Reactions = append(e.Reactions[:i], e.Reactions[i+1:]...)
+			if len(e.Reactions) == 0 {
+				e.Reactions = nil
+			}
+			return false
+		}
+	}
+	e.Reactions = append(e.Reactions, Reaction{Kind: kind, By: by, At: Timestamp{Time: at}})
+	return true
+}
+
+// HasReaction is true if someone reacted to the entry with kind. A question
+// only counts while it is open, see OpenQuestion.
+func (e *Entry) HasReaction(kind glyph.Reaction) bool {
+	if kind == glyph.ReactionQuestion {
+		return e.OpenQuestion()
+	}
+	for _, r := range e.Reactions {
+		if r.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenQuestion is true if the last question on the entry was asked after
+// the last done reaction, which answers it.
+func (e *Entry) OpenQuestion() bool {
+	var asked, answered time.Time
+	for _, r := range e.Reactions {
+		switch {
+		case r.Kind == glyph.ReactionQuestion && r.At.After(asked):
+			asked = r.At.Time
+		case r.Kind == glyph.ReactionDone && r.At.After(answered):
+			answered = r.At.Time
+		}
+	}
+	return !asked.IsZero() && asked.After(answered)
+}
+
+// ReactedBy returns who reacted with each kind, in the order they reacted.
+func (e *Entry) ReactedBy() map[glyph.Reaction][]string {
+	by := make(map[glyph.Reaction][]string)
+	for _, r := range e.Reactions {
+		by[r.Kind] = append(by[r.Kind], r.By)
+	}
+	return by
+}
+
+// Cluster is the reactions to the entry as a compact run of their symbols,
+// each with how many reacted if more than one, like "👍2 ❓". It is empty
+// if there are none.
+func (e *Entry) Cluster() string {
+	by := e.ReactedBy()
+	kinds := make([]glyph.Reaction, 0, len(by))
+	for k := range by {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i].Glyph().Order < kinds[j].Glyph().Order
+	})
+	parts := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		part := k.String()
+		if n := len(by[k]); n > 1 {
+			part += strconv.Itoa(n)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,40 @@
+package entry
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tags returns the #tags of the message, lower case and without the #, in
+// the order they are first used. They are read from the message each time,
+// so they can not drift from it.
+func (e *Entry) Tags() []string {
+	seen := make(map[string]bool)
+	tags := make([]string, 0)
+	fields := strings.FieldsFunc(strings.ToLower(e.Message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '#'
+	})
+	for _, f := range fields {
+		if !strings.HasPrefix(f, "#") {
+			continue
+		}
+		tag := strings.Trim(f, "#-_")
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag returns true if the message has #tag. The tag can be given with or
+// without the #, in any case.
+func (e *Entry) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	for _, t := range e.Tags() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -18,6 +18,15 @@ type Timestamp struct {
 	time.Time
 }
 
+// clone returns a copy of t, nil if t is nil.
+func (t *Timestamp) clone() *Timestamp {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
 func (t Timestamp) SameDay(then time.Time) bool {
 	if t.Local().Day() == then.Local().Day() &&
 		t.Local().Month() == then.Local().Month() &&
@@ -39,7 +48,9 @@ func (t *Timestamp) MarshalJSON() ([]byte, error) {
 	if t == nil || t.IsZero() {
 		return []byte(`""`), nil
 	}
-	return []byte(fmt.Sprintf("%q", t)), nil
+	// Keep the nanoseconds, entries added in the same second are ordered by
+	// when they were created.
+	return []byte(fmt.Sprintf("%q", FormatTime(t.Time))), nil
 }
 
 func (t *Timestamp) UnmarshalJSON(b []byte) error {
@@ -3,6 +3,8 @@ package glyph
 import (
 	"fmt"
 	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
 )
 
 type Glyph struct {
@@ -30,6 +32,7 @@ const (
 	Event           Bullet = "evnt"
 	Any             Bullet = "any"
 	Occurrence      Bullet = "occr"
+	Waiting         Bullet = "wait"
 
 	Priority      Signifier = "pri0"
 	Inspiration   Signifier = "insp"
@@ -37,6 +40,12 @@ const (
 	None          Signifier = "none"
 )
 
+// Recurring is shown after recurring entries and the instances they add.
+const Recurring = "↻"
+
+// Attachment is shown in front of the attachments of an entry.
+const Attachment = "↗"
+
 func DefaultBullets() map[Bullet]Glyph {
 	return map[Bullet]Glyph{
 		Task: {
@@ -95,6 +104,14 @@ func DefaultBullets() map[Bullet]Glyph {
 			Printed: true,
 			Order:   7,
 		},
+		Waiting: {
+			Symbol:  "⧖",
+			Meaning: "task waiting for",
+			Noun:    "waiting",
+			Aliases: []string{"w", "wait", "waiting"},
+			Printed: true,
+			Order:   8,
+		},
 		Any: {
 			Meaning: "any",
 			Noun:    "any",
@@ -166,7 +183,7 @@ func BulletForAlias(alias string) (Bullet, error) {
 			}
 		}
 	}
-	return Any, fmt.Errorf("unknown bullet alias: %s", alias)
+	return Any, fmt.Errorf("%w alias: %s", app.ErrInvalidBullet, alias)
 }
 
 func (b Bullet) Glyph() Glyph {
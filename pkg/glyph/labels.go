@@ -0,0 +1,64 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Label is a user assigned color on an entry, orthogonal to the bullet and
+// signifier.
+type Label string
+
+// These values are what is stored into the database.
+// Do not change unless you are ok with loosing data.
+const (
+	NoLabel      Label = ""
+	LabelRed     Label = "red"
+	LabelYellow  Label = "yellow"
+	LabelGreen   Label = "green"
+	LabelCyan    Label = "cyan"
+	LabelBlue    Label = "blue"
+	LabelMagenta Label = "magenta"
+)
+
+// Labels returns the labels in the order they are cycled through.
+func Labels() []Label {
+	return []Label{LabelRed, LabelYellow, LabelGreen, LabelCyan, LabelBlue, LabelMagenta}
+}
+
+// LabelFor returns the label for a name, "none" is no label.
+func LabelFor(name string) (Label, error) {
+	name = strings.ToLower(name)
+	if name == "" || name == "none" {
+		return NoLabel, nil
+	}
+	for _, l := range Labels() {
+		if string(l) == name {
+			return l, nil
+		}
+	}
+	return NoLabel, fmt.Errorf("%w: %s", app.ErrInvalidLabel, name)
+}
+
+// Next returns the label after l, wrapping around to no label.
+func (l Label) Next() Label {
+	labels := Labels()
+	if l == NoLabel {
+		return labels[0]
+	}
+	for i, o := range labels {
+		if o == l && i+1 < len(labels) {
+			return labels[i+1]
+		}
+	}
+	return NoLabel
+}
+
+func (l Label) String() string {
+	if l == NoLabel {
+		return "none"
+	}
+	return string(l)
+}
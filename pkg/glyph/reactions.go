@@ -0,0 +1,76 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Reaction is a quick flag someone puts on an entry of a shared journal.
+type Reaction string
+
+// These values are what is stored into the database.
+// Do not change unless you are ok with loosing data.
+const (
+	ReactionUp       Reaction = "up"
+	ReactionDone     Reaction = "done"
+	ReactionQuestion Reaction = "question"
+)
+
+// Reactions returns the reactions in the order they are shown.
+func Reactions() []Reaction {
+	return []Reaction{ReactionUp, ReactionDone, ReactionQuestion}
+}
+
+func DefaultReactions() map[Reaction]Glyph {
+	return map[Reaction]Glyph{
+		ReactionUp: {
+			Symbol:  "👍",
+			Meaning: "agreed",
+			Noun:    "up",
+			Aliases: []string{"+1", "up", "ok", "yes"},
+			Printed: true,
+			Order:   1,
+		},
+		ReactionDone: {
+			Symbol:  "✅",
+			Meaning: "checked, answers open questions",
+			Noun:    "done",
+			Aliases: []string{"done", "check", "checked", "answered"},
+			Printed: true,
+			Order:   2,
+		},
+		ReactionQuestion: {
+			Symbol:  "❓",
+			Meaning: "open question",
+			Noun:    "question",
+			Aliases: []string{"?", "question", "ask"},
+			Printed: true,
+			Order:   3,
+		},
+	}
+}
+
+// ReactionForAlias returns the reaction for its symbol or an alias.
+func ReactionForAlias(alias string) (Reaction, error) {
+	for r, g := range DefaultReactions() {
+		if alias == g.Symbol {
+			return r, nil
+		}
+		for _, a := range g.Aliases {
+			if strings.EqualFold(a, alias) {
+				return r, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s", app.ErrInvalidReaction, alias)
+}
+
+func (r Reaction) Glyph() Glyph {
+	return DefaultReactions()[r]
+}
+
+func (r Reaction) String() string {
+	return r.Glyph().String()
+}
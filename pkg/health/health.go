@@ -0,0 +1,118 @@
+package health
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// clockSkew is how far ahead of the clock the newest entry can be before
+// the clock is thought to be wrong.
+const clockSkew = 5 * time.Minute
+
+// durationKeys and boolKeys are the config keys read as durations and
+// bools. A value that does not parse is read as zero or false, silently.
+var (
+	durationKeys = []string{"invite.length", "remote_cache", "sync.interval", "ui.idle_lock"}
+	boolKeys     = []string{
+		"calendar_view.week_numbers", "compress", "encrypt.enabled", "serve.read_only",
+		"share.public", "sign.exports", "sign.require", "strike.ask_reason",
+		"ui.confirm_quit", "ui.cursor.blink", "ui.eager_select", "ui.show_done_time",
+	}
+)
+
+// Problem is something wrong with the setup, and how to fix it.
+type Problem struct {
+	What string
+	Fix  string
+}
+
+func (p Problem) String() string {
+	if p.Fix == "" {
+		return p.What
+	}
+	return fmt.Sprintf("%s, fix: %s", p.What, p.Fix)
+}
+
+// Setup checks that the journal at path and its search index can be
+// written, that an encrypted journal is all encrypted and that the config
+// values parse. The checks are quick enough to
+// run as a command starts.
+func Setup(path string) []Problem {
+	problems := make([]Problem, 0)
+	if err := store.CheckWritable(path); err != nil {
+		problems = append(problems, Problem{
+			What: fmt.Sprintf("the journal can not be written: %v", err),
+			Fix:  "bujo config set path <a directory you own>",
+		})
+	} else if err := store.CheckIndex(path); err != nil {
+		problems = append(problems, Problem{
+			What: fmt.Sprintf("the search index can not be saved, every search reads the whole journal: %v", err),
+			Fix:  "remove it, or make it writable",
+		})
+	}
+	if viper.GetBool("encrypt.enabled") {
+		if err := store.CheckEncrypted(path); err != nil {
+			problems = append(problems, Problem{
+				What: fmt.Sprintf("the journal is encrypted but %v", err),
+				Fix:  "bujo encrypt",
+			})
+		}
+	}
+	return append(problems, Config()...)
+}
+
+// Config checks the config values that are read as durations or bools
+// parse as one.
+func Config() []Problem {
+	problems := make([]Problem, 0)
+	for _, k := range durationKeys {
+		s, ok := viper.Get(k).(string)
+		if !ok {
+			continue
+		}
+		if _, err := time.ParseDuration(s); err != nil {
+			problems = append(problems, Problem{
+				What: fmt.Sprintf("%s is %q, not a duration, it is read as 0", k, s),
+				Fix:  fmt.Sprintf("bujo config set %s 15m", k),
+			})
+		}
+	}
+	for _, k := range boolKeys {
+		s, ok := viper.Get(k).(string)
+		if !ok {
+			continue
+		}
+		if _, err := strconv.ParseBool(s); err != nil {
+			problems = append(problems, Problem{
+				What: fmt.Sprintf("%s is %q, not true or false, it is read as false", k, s),
+				Fix:  fmt.Sprintf("bujo config set %s true", k),
+			})
+		}
+	}
+	return problems
+}
+
+// Clock checks the clock is not behind the newest of the entries, which
+// would date what is added before what is already there.
+func Clock(entries []*entry.Entry, now time.Time) []Problem {
+	var newest time.Time
+	for _, e := range entries {
+		if e.Created.After(newest) {
+			newest = e.Created.Time
+		}
+	}
+	if ahead := newest.Sub(now); ahead > clockSkew {
+		return []Problem{{
+			What: fmt.Sprintf("the clock is %s behind the newest entry, new entries would sort before it", printers.Duration(ahead)),
+			Fix:  "set the system clock, or its time zone",
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// Package integration drives the runners the commands use against a journal
+// in a temp dir, loaded the way the commands load it, over several days.
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/runner/add"
+	"tableflip.dev/bujo/pkg/runner/batch"
+	"tableflip.dev/bujo/pkg/runner/report"
+	"tableflip.dev/bujo/pkg/runner/shutdown"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// journal loads a new journal at BUJO_PATH in a temp dir, with the config
+// and state kept in the temp dir too. The func returned removes it and puts
+// the env back.
+func journal(t *testing.T) (store.Persistence, string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "bujo-integration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "journal")
+	env := map[string]string{
+		"HOME":             dir,
+		"BUJO_PATH":        path,
+		"BUJO_CONFIG_PATH": filepath.Join(dir, "config"),
+		"BUJO_PASSPHRASE":  "",
+		"XDG_CONFIG_HOME":  filepath.Join(dir, "config"),
+		"XDG_DATA_HOME":    filepath.Join(dir, "data"),
+		"XDG_STATE_HOME":   filepath.Join(dir, "state"),
+		"BUJO_LOG_LEVEL":   "",
+		"BUJO_REMOTE":      "",
+		"BUJO_COMPRESS":    "",
+	}
+	restore := setenv(env)
+	cleanup := func() {
+		restore()
+		_ = os.RemoveAll(dir)
+	}
+
+	p, err := store.Load(nil)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return p, path, cleanup
+}
+
+// setenv sets the env and returns a func to put it back.
+func setenv(env map[string]string) func() {
+	was := make(map[string]*string, len(env))
+	for k, v := range env {
+		if old, ok := os.LookupEnv(k); ok {
+			was[k] = &old
+		} else {
+			was[k] = nil
+		}
+		_ = os.Setenv(k, v)
+	}
+	return func() {
+		for k, v := range was {
+			if v == nil {
+				_ = os.Unsetenv(k)
+			} else {
+				_ = os.Setenv(k, *v)
+			}
+		}
+	}
+}
+
+func find(entries []*entry.Entry, message string) *entry.Entry {
+	for _, e := range entries {
+		if e.Message == message {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestJournalIsAtBujoPath(t *testing.T) {
+	p, path, cleanup := journal(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := (&add.Add{Collection: "Inbox", Bullet: glyph.Task, Message: "file it", Persistence: p}).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	files := 0
+	err := filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && !fi.IsDir() {
+			files++
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 1 {
+		t.Errorf("got %d files in %s, want 1", files, path)
+	}
+}
+
+func TestAddMigrateReport(t *testing.T) {
+	p, _, cleanup := journal(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	today := time.Now()
+	yesterday := today.AddDate(0, 0, -1)
+	day1, day2 := collection.DayOf(yesterday), collection.DayOf(today)
+
+	// Day one: plans and a note.
+	for _, a := range []add.Add{
+		{Collection: day1, Bullet: glyph.Task, Message: "write the report"},
+		{Collection: day1, Bullet: glyph.Task, Message: "call the bank", Priority: true},
+		{Collection: day1, Bullet: glyph.Note, Message: "the bank closes at 4"},
+	} {
+		a.Persistence = p
+		if err := a.Do(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(p.List(ctx, day1)); got != 3 {
+		t.Fatalf("%s has %d entries, want 3", day1, got)
+	}
+
+	// Shutting down day one completes the first task and migrates the
+	// second.
+	sd := &shutdown.Shutdown{
+		Steps:       []string{shutdown.StepReview},
+		On:          yesterday,
+		In:          strings.NewReader("c\nm\n"),
+		Persistence: p,
+	}
+	if err := sd.Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	first := p.List(ctx, day1)
+	if e := find(first, "write the report"); e == nil || e.Bullet != glyph.Completed {
+		t.Errorf("write the report is %+v, want it completed", e)
+	}
+	if e := find(first, "call the bank"); e == nil || e.Bullet != glyph.MovedCollection {
+		t.Errorf("call the bank is %+v, want it moved", e)
+	}
+	second := p.List(ctx, day2)
+	moved := find(second, "call the bank")
+	if moved == nil || moved.Bullet != glyph.Task || moved.Signifier != glyph.Priority {
+		t.Fatalf("%s has %+v, want the migrated priority task", day2, second)
+	}
+
+	// Day two: a batch completes the migrated task and adds a note.
+	in := strings.NewReader(`{"op":"complete","id":"` + moved.ID + `"}
+{"op":"add","collection":"` + day2 + `","bullet":"note","message":"the bank is sorted"}
+`)
+	out := &bytes.Buffer{}
+	if err := (&batch.Batch{In: in, Out: out, Persistence: p}).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var r batch.Report
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Applied || len(r.Results) != 2 {
+		t.Errorf("got %+v, want both ops applied", r)
+	}
+	if e := find(p.List(ctx, day2), "call the bank"); e == nil || e.Bullet != glyph.Completed {
+		t.Errorf("call the bank is %+v, want it completed", e)
+	}
+
+	// The notes digest has both notes, and nothing else.
+	digest := &bytes.Buffer{}
+	notes := &report.Notes{
+		Since:       yesterday.AddDate(0, 0, -1),
+		Group:       report.GroupCollection,
+		Markdown:    true,
+		Out:         digest,
+		Persistence: p,
+	}
+	if err := notes.Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{day1, "the bank closes at 4", day2, "the bank is sorted"} {
+		if !strings.Contains(digest.String(), want) {
+			t.Errorf("the digest is missing %q:\n%s", want, digest)
+		}
+	}
+	if strings.Contains(digest.String(), "write the report") {
+		t.Errorf("the digest has a task:\n%s", digest)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	p, _, cleanup := journal(t)
+	defer cleanup()
+	w, ok := p.(store.Watcher)
+	if !ok {
+		t.Fatal("the journal can not be watched")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	existing := entry.New("Inbox", glyph.Task, "existing")
+	if err := p.Store(existing); err != nil {
+		t.Fatal(err)
+	}
+	events, err := w.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch is seen as one change to each collection it touched.
+	in := strings.NewReader(`{"op":"add","collection":"Inbox","message":"one"}
+{"op":"add","collection":"Inbox","message":"two"}
+{"op":"move","id":"` + existing.ID + `","collection":"Project"}
+`)
+	if err := (&batch.Batch{In: in, Out: ioutil.Discard, Persistence: p}).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]map[string]string)
+	for len(got) < 2 {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatalf("the watch ended, got %v", got)
+			}
+			if _, seen := got[ev.Collection]; seen {
+				t.Fatalf("%s changed twice, got %+v", ev.Collection, ev)
+			}
+			kinds := make(map[string]string)
+			for _, c := range ev.Changes {
+				message := ""
+				if c.Entry != nil {
+					message = c.Entry.Message
+				}
+				kinds[message] = c.Kind
+			}
+			got[ev.Collection] = kinds
+		case <-ctx.Done():
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+
+	want := map[string]map[string]string{
+		"Inbox":   {"one": store.ChangeAdded, "two": store.ChangeAdded, "existing": store.ChangeModified},
+		"Project": {"existing": store.ChangeAdded},
+	}
+	for c, kinds := range want {
+		for message, kind := range kinds {
+			if got[c][message] != kind {
+				t.Errorf("%s in %s was %q, want %q", message, c, got[c][message], kind)
+			}
+		}
+		if len(got[c]) != len(kinds) {
+			t.Errorf("%s got %v, want %v", c, got[c], kinds)
+		}
+	}
+}
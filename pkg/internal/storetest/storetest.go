@@ -0,0 +1,24 @@
+// Package storetest has helpers for the tests of packages that use a store.
+// The tests of the store package itself can not import it, they keep their
+// own config.
+package storetest
+
+import (
+	"time"
+
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Dir is the config of a plain local journal in a directory, like a temp
+// dir made for a test.
+type Dir string
+
+var _ store.Config = Dir("")
+
+func (d Dir) BasePath() string                { return string(d) }
+func (d Dir) Compress() bool                  { return false }
+func (d Dir) Remote() string                  { return "" }
+func (d Dir) RemoteCache() time.Duration      { return 0 }
+func (d Dir) Calendar() store.CalendarAccount { return store.CalendarAccount{} }
+func (d Dir) Encrypted() bool                 { return false }
+func (d Dir) Passphrase() string              { return "" }
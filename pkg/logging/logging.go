@@ -0,0 +1,253 @@
+// Package logging writes leveled, structured logs as JSON lines to a file,
+// rotated as it grows, so what bujo did can be looked at after the fact.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Level is how much is logged, each level logs itself and the levels above
+// it.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	// LevelOff logs nothing.
+	LevelOff
+)
+
+var levelNames = []string{"debug", "info", "warn", "error", "off"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelOff {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// Levels are the names of the levels, for ParseLevel.
+func Levels() []string {
+	return append([]string{}, levelNames...)
+}
+
+// ParseLevel returns the level named s. Empty is LevelWarn.
+func ParseLevel(s string) (Level, error) {
+	if s == "" {
+		return LevelWarn, nil
+	}
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return LevelOff, app.Invalid("log level", s, "expected "+strings.Join(levelNames, ", "))
+}
+
+// Defaults for the size of a log file before it is rotated, and how many
+// rotated files are kept.
+const (
+	defaultMaxSize = 1 << 20
+	defaultKeep    = 3
+)
+
+// Logger writes records at or above its level to a file. The file is opened
+// on the first record, so nothing is written until there is something to
+// log. Once it is bigger than MaxSize it is moved to path.1, path.1 to
+// path.2 and so on, keeping Keep of them.
+type Logger struct {
+	mu    sync.Mutex
+	path  string
+	level Level
+	f     *os.File
+	size  int64
+
+	MaxSize int64
+	Keep    int
+}
+
+// New returns a logger writing to path. An empty path logs nothing.
+func New(path string, level Level) *Logger {
+	return &Logger{
+		path:    path,
+		level:   level,
+		MaxSize: defaultMaxSize,
+		Keep:    defaultKeep,
+	}
+}
+
+// Path is the file the logger writes to.
+func (l *Logger) Path() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.path
+}
+
+// Level is the lowest level logged.
+func (l *Logger) Level() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// SetLevel changes the lowest level logged.
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetPath changes the file logged to, closing the one in use.
+func (l *Logger) SetPath(path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if path == l.path {
+		return
+	}
+	l.close()
+	l.path = path
+}
+
+// Close closes the log file, it is opened again by the next record.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.close()
+}
+
+func (l *Logger) close() error {
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f, l.size = nil, 0
+	return err
+}
+
+func (l *Logger) Debug(msg string, kv ...interface{}) { l.Log(LevelDebug, msg, kv...) }
+func (l *Logger) Info(msg string, kv ...interface{})  { l.Log(LevelInfo, msg, kv...) }
+func (l *Logger) Warn(msg string, kv ...interface{})  { l.Log(LevelWarn, msg, kv...) }
+func (l *Logger) Error(msg string, kv ...interface{}) { l.Log(LevelError, msg, kv...) }
+
+// Log writes a record of msg with the fields in kv, which are pairs of a
+// key and its value. Failing to write the log is not reported, logging is
+// never the reason a command fails.
+func (l *Logger) Log(level Level, msg string, kv ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level < l.level || l.level == LevelOff || l.path == "" {
+		return
+	}
+
+	line := record(time.Now(), level, msg, kv)
+	if l.f != nil && l.MaxSize > 0 && l.size+int64(len(line)) > l.MaxSize {
+		l.rotate()
+	}
+	if l.f == nil && !l.open() {
+		return
+	}
+	n, _ := l.f.Write(line)
+	l.size += int64(n)
+}
+
+func (l *Logger) open() bool {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return false
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return false
+	}
+	l.f = f
+	if fi, err := f.Stat(); err == nil {
+		l.size = fi.Size()
+	}
+	if l.MaxSize > 0 && l.size >= l.MaxSize {
+		l.rotate()
+		return l.open()
+	}
+	return true
+}
+
+// rotate moves the log file out of the way of a new one.
+func (l *Logger) rotate() {
+	l.close()
+	for i := l.Keep; i > 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", l.path, i-1), fmt.Sprintf("%s.%d", l.path, i))
+	}
+	if l.Keep > 0 {
+		_ = os.Rename(l.path, l.path+".1")
+	} else {
+		_ = os.Remove(l.path)
+	}
+}
+
+// record is the JSON line of a log record. Errors and Stringers are written
+// as their text, a key without a value is logged as !MISSING.
+func record(at time.Time, level Level, msg string, kv []interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"time":`)
+	writeValue(&b, at.Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeValue(&b, level.String())
+	b.WriteString(`,"msg":`)
+	writeValue(&b, msg)
+	for i := 0; i < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+		var value interface{} = "!MISSING"
+		if i+1 < len(kv) {
+			value = kv[i+1]
+		}
+		b.WriteByte(',')
+		writeValue(&b, key)
+		b.WriteByte(':')
+		writeValue(&b, value)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeValue(b *bytes.Buffer, v interface{}) {
+	switch t := v.(type) {
+	case error:
+		v = t.Error()
+	case fmt.Stringer:
+		v = t.String()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(data)
+}
+
+// std is the logger the package functions write to, it logs nothing until
+// it is configured.
+var std = New("", LevelOff)
+
+// Default is the logger shared by the whole of bujo.
+func Default() *Logger {
+	return std
+}
+
+// Configure points the shared logger at path and sets its level.
+func Configure(path string, level Level) {
+	std.SetPath(path)
+	std.SetLevel(level)
+}
+
+func Debug(msg string, kv ...interface{}) { std.Log(LevelDebug, msg, kv...) }
+func Info(msg string, kv ...interface{})  { std.Log(LevelInfo, msg, kv...) }
+func Warn(msg string, kv ...interface{})  { std.Log(LevelWarn, msg, kv...) }
+func Error(msg string, kv ...interface{}) { std.Log(LevelError, msg, kv...) }
@@ -0,0 +1,92 @@
+// Package notify sends messages to the user over the channels they picked in
+// the config, like a desktop notification or a webhook.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Message is what is sent to the user.
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+// Sink sends messages over one channel.
+type Sink interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// Config is a sink in the config, like:
+//
+// notify:
+//   - kind: desktop
+//   - kind: webhook
+//     url: https://example.com/hook
+//   - kind: command
+//     command: say "$BUJO_NOTIFY_TITLE"
+type Config struct {
+	Kind string `mapstructure:"kind"`
+	// URL is where the webhook sink posts to.
+	URL string `mapstructure:"url"`
+	// Command is run by the command sink.
+	Command string `mapstructure:"command"`
+}
+
+// Factory makes a sink from its config.
+type Factory func(c Config) (Sink, error)
+
+var registry = make(map[string]Factory)
+
+// Register makes a kind of sink available to the config.
+func Register(kind string, f Factory) {
+	registry[kind] = f
+}
+
+// Kinds are the registered kinds of sink, sorted.
+func Kinds() []string {
+	kinds := make([]string, 0, len(registry))
+	for k := range registry {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// New returns a sink that sends to every configured sink.
+func New(configs []Config) (Sink, error) {
+	all := make(multi, 0, len(configs))
+	for _, c := range configs {
+		f, ok := registry[c.Kind]
+		if !ok {
+			return nil, app.Invalid("notify kind", c.Kind, fmt.Sprintf("expected one of %s", strings.Join(Kinds(), ", ")))
+		}
+		s, err := f(c)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, s)
+	}
+	return all, nil
+}
+
+// multi sends to each of its sinks, a failing sink does not stop the rest.
+type multi []Sink
+
+func (m multi) Notify(ctx context.Context, msg Message) error {
+	var failed []string
+	for _, s := range m {
+		if err := s.Notify(ctx, msg); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("notify failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
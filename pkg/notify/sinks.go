@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Kinds of sink.
+const (
+	// KindTerminal prints the message to stderr and rings the bell.
+	KindTerminal = "terminal"
+	// KindBell only rings the terminal bell.
+	KindBell = "bell"
+	// KindDesktop shows a desktop notification.
+	KindDesktop = "desktop"
+	// KindWebhook posts the message as json to a url.
+	KindWebhook = "webhook"
+	// KindCommand runs a shell command with the message on the env.
+	KindCommand = "command"
+)
+
+// webhookTimeout is how long a webhook has to answer.
+const webhookTimeout = 10 * time.Second
+
+func init() {
+	Register(KindTerminal, func(c Config) (Sink, error) {
+		return &terminal{out: os.Stderr, text: true}, nil
+	})
+	Register(KindBell, func(c Config) (Sink, error) {
+		return &terminal{out: os.Stderr}, nil
+	})
+	Register(KindDesktop, func(c Config) (Sink, error) {
+		return desktop{}, nil
+	})
+	Register(KindWebhook, func(c Config) (Sink, error) {
+		if c.URL == "" {
+			return nil, app.Invalid("notify url", c.URL, "a webhook needs a url")
+		}
+		return &webhook{url: c.URL, client: &http.Client{Timeout: webhookTimeout}}, nil
+	})
+	Register(KindCommand, func(c Config) (Sink, error) {
+		if c.Command == "" {
+			return nil, app.Invalid("notify command", c.Command, "a command is required")
+		}
+		return command(c.Command), nil
+	})
+}
+
+// Default is used when no sinks are configured.
+func Default() []Config {
+	return []Config{{Kind: KindTerminal}}
+}
+
+type terminal struct {
+	out io.Writer
+	// text prints the message after the bell.
+	text bool
+}
+
+func (t *terminal) Notify(ctx context.Context, m Message) error {
+	if !t.text {
+		_, err := fmt.Fprint(t.out, "\a")
+		return err
+	}
+	if m.Body == "" {
+		_, err := fmt.Fprintf(t.out, "\a%s\n", m.Title)
+		return err
+	}
+	_, err := fmt.Fprintf(t.out, "\a%s: %s\n", m.Title, m.Body)
+	return err
+}
+
+type desktop struct{}
+
+func (desktop) Notify(ctx context.Context, m Message) error {
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		return exec.CommandContext(ctx, "notify-send", "bujo: "+m.Title, m.Body).Run()
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(m.Body), strconv.Quote("bujo: "+m.Title))
+		return exec.CommandContext(ctx, "osascript", "-e", script).Run()
+	default:
+		return fmt.Errorf("desktop notifications on %s are %w", runtime.GOOS, app.ErrUnsupported)
+	}
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+func (w *webhook) Notify(ctx context.Context, m Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s: %s", w.url, resp.Status)
+	}
+	return nil
+}
+
+// command runs with the message in $BUJO_NOTIFY_TITLE and $BUJO_NOTIFY_BODY.
+type command string
+
+func (c command) Notify(ctx context.Context, m Message) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", string(c))
+	cmd.Env = append(os.Environ(), "BUJO_NOTIFY_TITLE="+m.Title, "BUJO_NOTIFY_BODY="+m.Body)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify command: %v: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package printers
+
+import (
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/store"
+)
+
+const layoutActivity = "Jan 2 3:04pm"
+
+// ActivityLine describes an activity on one line: when, what and the entry,
+// with the collection it is in.
+func ActivityLine(a store.Activity) string {
+	line := fmt.Sprintf("%-13s %-9s %s  (%s)", a.At.Local().Format(layoutActivity), a.Kind, a.Entry.String(), a.Entry.Collection)
+	if a.Kind == store.ActivityStruck && a.Entry.Reason != "" {
+		line += ": " + a.Entry.Reason
+	}
+	return line
+}
@@ -0,0 +1,59 @@
+package printers
+
+import (
+	"strings"
+)
+
+// CalendarOptions change how the rows of the calendar are rendered.
+type CalendarOptions struct {
+	// Weekdays are the abbreviations of the days of the week, from Sunday.
+	Weekdays [7]string
+	// RightToLeft puts the day after its entries, for right to left
+	// languages.
+	RightToLeft bool
+	// WeekNumbers shows the ISO week number in the gutter of each row.
+	WeekNumbers bool
+}
+
+// DefaultCalendarOptions abbreviates the days of the week to their first
+// letter in english.
+func DefaultCalendarOptions() CalendarOptions {
+	return CalendarOptions{
+		Weekdays: [7]string{"S", "M", "T", "W", "T", "F", "S"},
+	}
+}
+
+// localeWeekdays are the abbreviations of the days of the week, from
+// Sunday, by language.
+var localeWeekdays = map[string][7]string{
+	"en": {"S", "M", "T", "W", "T", "F", "S"},
+	"de": {"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	"es": {"do", "lu", "ma", "mi", "ju", "vi", "sá"},
+	"fr": {"di", "lu", "ma", "me", "je", "ve", "sa"},
+	"it": {"do", "lu", "ma", "me", "gi", "ve", "sa"},
+	"nl": {"zo", "ma", "di", "wo", "do", "vr", "za"},
+	"pt": {"do", "se", "te", "qu", "qu", "se", "sá"},
+	"ar": {"ح", "ن", "ث", "ر", "خ", "ج", "س"},
+	"he": {"א", "ב", "ג", "ד", "ה", "ו", "ש"},
+}
+
+// rightToLeft are the languages written right to left.
+var rightToLeft = map[string]bool{
+	"ar": true,
+	"he": true,
+}
+
+// LocaleCalendarOptions returns the calendar options for a locale, like
+// "de_DE.UTF-8" or "fr". Unknown locales get the defaults.
+func LocaleCalendarOptions(locale string) CalendarOptions {
+	o := DefaultCalendarOptions()
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	if w, ok := localeWeekdays[lang]; ok {
+		o.Weekdays = w
+		o.RightToLeft = rightToLeft[lang]
+	}
+	return o
+}
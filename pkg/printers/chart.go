@@ -0,0 +1,65 @@
+package printers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sparks are the levels of a sparkline, lowest first.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Bar is n as a bar out of max, width runes wide when n is max, padded to
+// width.
+func Bar(n, max, width int) string {
+	w := 0
+	if max > 0 {
+		w = n * width / max
+	}
+	if n > 0 && w == 0 {
+		w = 1
+	}
+	return strings.Repeat("█", w) + strings.Repeat(" ", width-w)
+}
+
+// Sparkline is a rune per value, scaled between the smallest and largest
+// value.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > min {
+			i = int((v - min) / (max - min) * float64(len(sparks)-1))
+		}
+		b.WriteRune(sparks[i])
+	}
+	return b.String()
+}
+
+// Duration formats d for people, to the two largest units of days, hours and
+// minutes.
+func Duration(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
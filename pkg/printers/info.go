@@ -0,0 +1,53 @@
+package printers
+
+import (
+	"fmt"
+	"sort"
+
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+const layoutModified = "January 2, 2006 3:04pm"
+
+// InfoLines describes a collection, one fact per line.
+func InfoLines(info store.CollectionInfo) []string {
+	lines := []string{
+		fmt.Sprintf("type: %s", info.Kind),
+		fmt.Sprintf("entries: %d", info.Entries),
+	}
+
+	bullets := glyph.DefaultBullets()
+	counted := make([]glyph.Bullet, 0, len(info.ByBullet))
+	for b := range info.ByBullet {
+		counted = append(counted, b)
+	}
+	sort.Slice(counted, func(i, j int) bool {
+		return bullets[counted[i]].Order < bullets[counted[j]].Order
+	})
+	for _, b := range counted {
+		g := bullets[b]
+		lines = append(lines, fmt.Sprintf("  %s %s: %d", g.Symbol, g.Meaning, info.ByBullet[b]))
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("created: %s", info.Created.Local().Format(layoutModified)),
+		fmt.Sprintf("modified: %s", info.Modified.Local().Format(layoutModified)),
+		fmt.Sprintf("disk usage: %s", size(info.Bytes)),
+	)
+	return lines
+}
+
+// size formats bytes for people.
+func size(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,90 @@
+package printers
+
+import (
+	"fmt"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// Markdown renders a collection of entries as a markdown document, suitable
+// for sharing outside of the journal.
+func Markdown(title string, entries ...*entry.Entry) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+
+	if len(entries) == 0 {
+		sb.WriteString("_none_\n")
+		return sb.String()
+	}
+
+	occurred := 0
+	for _, e := range entries {
+		if e.Bullet == glyph.Occurrence {
+			occurred++
+			continue
+		}
+		sb.WriteString(MarkdownEntry(e))
+		sb.WriteString("\n")
+	}
+	if occurred > 0 {
+		sb.WriteString(fmt.Sprintf("- %s %d times\n", glyph.Occurrence, occurred))
+	}
+	return sb.String()
+}
+
+// MarkdownEntry renders a single entry as a markdown list item. Attached
+// URLs are links under it, attached files are left out, their paths only
+// make sense on this machine.
+func MarkdownEntry(e *entry.Entry) string {
+	item := markdownItem(e)
+	for _, a := range e.Attachments {
+		if a.Kind != entry.AttachURL {
+			continue
+		}
+		label := a.Label
+		if label == "" {
+			label = a.Target
+		}
+		item += fmt.Sprintf("\n  - [%s](%s)", label, a.Target)
+	}
+	return item
+}
+
+// markdownItem is the list item of an entry, without its attachments.
+func markdownItem(e *entry.Entry) string {
+	msg := e.Message
+	if e.Signifier != "" && e.Signifier != glyph.None {
+		msg = fmt.Sprintf("%s %s", e.Signifier.String(), msg)
+	}
+	if c := e.Cluster(); c != "" {
+		msg += " " + c
+	}
+
+	switch e.Bullet {
+	case glyph.Task:
+		if e.On != nil {
+			return fmt.Sprintf("- [ ] %s _(%s)_", msg, e.On.Format(layoutUS))
+		}
+		return fmt.Sprintf("- [ ] %s", msg)
+	case glyph.Completed:
+		return fmt.Sprintf("- [x] %s", msg)
+	case glyph.Irrelevant:
+		return fmt.Sprintf("- ~~%s~~", msg)
+	case glyph.Event:
+		if e.On != nil {
+			return fmt.Sprintf("- %s %s _(%s)_", e.Bullet.String(), msg, e.On.Format(layoutUS))
+		}
+		return fmt.Sprintf("- %s %s", e.Bullet.String(), msg)
+	case glyph.Waiting:
+		if w := waitingFor(e); w != "" {
+			return fmt.Sprintf("- [ ] %s %s _(%s)_", e.Bullet.String(), msg, w)
+		}
+		return fmt.Sprintf("- [ ] %s %s", e.Bullet.String(), msg)
+	case glyph.Note:
+		return fmt.Sprintf("- %s", msg)
+	default:
+		return fmt.Sprintf("- %s %s", e.Bullet.String(), msg)
+	}
+}
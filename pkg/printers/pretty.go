@@ -6,10 +6,24 @@ import (
 	"strings"
 	"tableflip.dev/bujo/pkg/entry"
 	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/rollup"
 )
 
 type PrettyPrint struct {
 	ShowID bool
+	// Refs are shown in place of ids, by id, if set.
+	Refs map[string]string
+	// CalendarOptions are used for the calendar, DefaultCalendarOptions if
+	// nil.
+	CalendarOptions *CalendarOptions
+	// Icons are shown before titles, by collection, if set.
+	Icons map[string]string
+	// All is what computed entries are evaluated against, they are shown as
+	// they were written if nil.
+	All []*entry.Entry
+	// ShowDoneTime shows when completed entries were completed after them,
+	// for those that say.
+	ShowDoneTime bool
 }
 
 var (
@@ -26,7 +40,15 @@ func (pp *PrettyPrint) Title(title string) {
 	if pp.ShowID {
 		_, _ = t.Print(spacing)
 	}
-	_, _ = t.Println(title)
+	_, _ = t.Println(pp.iconed(title))
+}
+
+// iconed returns title with its icon, if it has one.
+func (pp *PrettyPrint) iconed(title string) string {
+	if icon, ok := pp.Icons[title]; ok {
+		return icon + " " + title
+	}
+	return title
 }
 
 func (pp *PrettyPrint) TitleWithCount(title string, count int) {
@@ -36,7 +58,7 @@ func (pp *PrettyPrint) TitleWithCount(title string, count int) {
 	if pp.ShowID {
 		_, _ = t.Print(spacing)
 	}
-	_, _ = t.Print(title)
+	_, _ = t.Print(pp.iconed(title))
 	_, _ = c.Printf(" - %d", count)
 
 	switch count {
@@ -48,9 +70,26 @@ func (pp *PrettyPrint) TitleWithCount(title string, count int) {
 }
 
 const (
-	layoutUS = "January 2, 2006"
+	layoutUS      = "January 2, 2006"
+	layoutExpires = "3:04pm, January 2"
+	layoutDone    = "15:04"
+	layoutDoneDay = "15:04, January 2"
 )
 
+// DoneTime describes when e was completed, like "done 14:32". The day is
+// added if it was not completed on the day it was added. It is empty if e
+// is not completed or does not say when it was.
+func DoneTime(e *entry.Entry) string {
+	if e.Bullet != glyph.Completed || e.CompletedAt == nil {
+		return ""
+	}
+	at := e.CompletedAt.Time
+	if !e.Created.SameDay(at) {
+		return "done " + at.Local().Format(layoutDoneDay)
+	}
+	return "done " + at.Local().Format(layoutDone)
+}
+
 func (pp *PrettyPrint) Collection(entries ...*entry.Entry) {
 	if len(entries) == 0 {
 		f := color.New(color.Faint, color.Italic)
@@ -66,26 +105,97 @@ func (pp *PrettyPrint) Collection(entries ...*entry.Entry) {
 	fi := color.New(color.Faint, color.Italic)
 	y := color.New(color.FgHiYellow, color.Italic, color.Faint)
 
+	labelled := false
+	for _, e := range entries {
+		if e.Label != glyph.NoLabel {
+			labelled = true
+			break
+		}
+	}
+
 	occurred := 0
 	for _, e := range entries {
 		if pp.ShowID {
-			_, _ = y.Print(e.ID)
-			_, _ = y.Print(strings.Repeat(" ", len(spacing)-len(e.ID)))
+			id := e.ID
+			if r, ok := pp.Refs[e.ID]; ok {
+				id = r
+			}
+			_, _ = y.Print(id)
+			_, _ = y.Print(strings.Repeat(" ", len(spacing)-len(id)))
+		}
+		if labelled && e.Bullet != glyph.Occurrence {
+			_, _ = labelColor(e.Label).Print(gutter(e.Label))
+		}
+		msg := e.Message
+		if p := e.Progress(); p != "" {
+			msg = e.Headline() + " [" + p + "]"
+		}
+		if pp.All != nil {
+			msg = rollup.Render(msg, pp.All)
+		}
+		if c := e.Cluster(); c != "" {
+			msg += "  " + c
 		}
 		switch e.Bullet {
 		case glyph.Occurrence:
 			occurred++
+			continue
 		case glyph.Irrelevant:
 			_, _ = t.Printf("%s ", e.Signifier.String())
-			_, _ = co.Printf("%s %s\n", e.Bullet.String(), e.Message)
-		case glyph.Event:
-			_, _ = t.Printf("%s %s %s", e.Signifier.String(), e.Bullet.String(), e.Message)
+			_, _ = co.Printf("%s %s", e.Bullet.String(), msg)
+			if e.Reason != "" {
+				_, _ = fi.Printf(" (%s)", e.Reason)
+			}
+		case glyph.Event, glyph.Task:
+			_, _ = t.Printf("%s %s %s", e.Signifier.String(), e.Bullet.String(), msg)
 			if e.On != nil {
 				_, _ = fi.Printf(" (%s)", e.On.Format(layoutUS))
 			}
-			_, _ = t.Println("")
+		case glyph.Waiting:
+			_, _ = t.Printf("%s %s %s", e.Signifier.String(), e.Bullet.String(), msg)
+			if w := waitingFor(e); w != "" {
+				_, _ = fi.Printf(" (%s)", w)
+			}
+		case glyph.Note:
+			_, _ = t.Printf("%s %s %s", e.Signifier.String(), e.Bullet.String(), msg)
+			if e.Expires != nil {
+				_, _ = fi.Printf(" (expires %s)", e.Expires.Local().Format(layoutExpires))
+			}
+		case glyph.Completed:
+			_, _ = t.Printf("%s %s %s", e.Signifier.String(), e.Bullet.String(), msg)
+			if done := DoneTime(e); pp.ShowDoneTime && done != "" {
+				_, _ = fi.Printf(" (%s)", done)
+			}
 		default:
-			_, _ = t.Printf("%s %s %s\n", e.Signifier.String(), e.Bullet.String(), e.Message)
+			_, _ = t.Printf("%s %s %s", e.Signifier.String(), e.Bullet.String(), msg)
+		}
+		if e.ReviewOn != nil {
+			_, _ = fi.Printf(" (review %s)", e.ReviewOn.Local().Format(layoutUS))
+		}
+		if e.Recurrence != nil {
+			_, _ = fi.Print(" " + recurrence(e))
+		}
+		if e.Private {
+			_, _ = fi.Print(" (private)")
+		}
+		if e.Pinned {
+			_, _ = fi.Print(" (pinned)")
+		}
+		_, _ = t.Println("")
+		// The checklist is under the entry, in line with its message.
+		indent := "    "
+		if pp.ShowID {
+			indent = spacing + indent
+		}
+		for _, i := range e.Checklist() {
+			mark := "[ ]"
+			if i.Done {
+				mark = "[x]"
+			}
+			_, _ = fi.Printf("%s%s %s\n", indent, mark, i.Text)
+		}
+		for i, a := range e.Attachments {
+			_, _ = fi.Printf("%s%s %d %s\n", indent, glyph.Attachment, i+1, a)
 		}
 	}
 	if occurred > 0 {
@@ -93,3 +203,52 @@ func (pp *PrettyPrint) Collection(entries ...*entry.Entry) {
 	}
 	_, _ = t.Println("")
 }
+
+// waitingFor describes who or what an entry is waiting for and when to
+// follow up.
+func waitingFor(e *entry.Entry) string {
+	parts := make([]string, 0, 2)
+	if e.WaitingOn != "" {
+		parts = append(parts, "waiting on "+e.WaitingOn)
+	}
+	if e.FollowUp != nil {
+		parts = append(parts, "follow up "+e.FollowUp.Local().Format(layoutUS))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// recurrence marks a recurring entry with its rule, and an instance it
+// added with the glyph alone, the rule is kept on the recurring entry.
+func recurrence(e *entry.Entry) string {
+	if e.Recurrence.Of != "" {
+		return glyph.Recurring
+	}
+	return glyph.Recurring + " " + e.Recurrence.Rule
+}
+
+// gutter is the mark printed in front of a labelled entry.
+func gutter(l glyph.Label) string {
+	if l == glyph.NoLabel {
+		return " "
+	}
+	return "▌"
+}
+
+func labelColor(l glyph.Label) *color.Color {
+	switch l {
+	case glyph.LabelRed:
+		return color.New(color.FgRed)
+	case glyph.LabelYellow:
+		return color.New(color.FgYellow)
+	case glyph.LabelGreen:
+		return color.New(color.FgGreen)
+	case glyph.LabelCyan:
+		return color.New(color.FgCyan)
+	case glyph.LabelBlue:
+		return color.New(color.FgBlue)
+	case glyph.LabelMagenta:
+		return color.New(color.FgMagenta)
+	default:
+		return color.New()
+	}
+}
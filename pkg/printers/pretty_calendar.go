@@ -7,6 +7,7 @@ import (
 	"strings"
 	"tableflip.dev/bujo/pkg/entry"
 	"time"
+	"unicode/utf8"
 )
 
 func (pp *PrettyPrint) Calendar(on time.Time, entries ...*entry.Entry) {
@@ -93,47 +94,102 @@ func (pp *PrettyPrint) PrintMonthLong(then time.Time, entries ...*entry.Entry) {
 	i := color.New(color.Italic)
 	s := color.New(color.Underline)
 	bs := color.New(color.Underline, color.Bold)
+	f := color.New(color.Faint)
 
-	d := StartDay(then)
+	o := DefaultCalendarOptions()
+	if pp.CalendarOptions != nil {
+		o = *pp.CalendarOptions
+	}
+
+	// The day column is as wide as the widest weekday abbreviation.
+	dayWidth := 0
+	for _, w := range o.Weekdays {
+		if n := utf8.RuneCountInString(w); n > dayWidth {
+			dayWidth = n
+		}
+	}
+
+	type row struct {
+		day     time.Time
+		entries []string
+	}
+	rows := make([]row, 0, DaysIn(then))
+	widest := 0
 	hasOpenDueDate := false
-	for i := 0; i < DaysIn(then); i++ {
-		// TODO: all this logic can get cleaner.
-		now := time.Now()
-		printer := p
+	for d := 0; d < DaysIn(then); d++ {
+		r := row{day: time.Date(then.Year(), then.Month(), d+1, 0, 0, 0, 0, time.Local)}
+		for _, e := range entries {
+			if e.On == nil {
+				hasOpenDueDate = true
+				continue
+			}
+			if e.On.Year() == then.Local().Year() && e.On.Month() == then.Local().Month() && e.On.Day() == d+1 {
+				line := fmt.Sprintf("%s %s %s", e.Signifier.String(), e.Bullet.String(), e.Message)
+				r.entries = append(r.entries, line)
+				if n := utf8.RuneCountInString(line); n > widest {
+					widest = n
+				}
+			}
+		}
+		rows = append(rows, r)
+	}
 
-		if now.Month() == then.Local().Month() && now.Year() == then.Year() && now.Local().Day() == i+1 {
+	now := time.Now()
+	for _, r := range rows {
+		printer := p
+		today := now.Year() == r.day.Year() && now.Month() == r.day.Month() && now.Day() == r.day.Day()
+		if today {
 			printer = b
 		}
-		if d == time.Sunday {
+		if r.day.Weekday() == time.Sunday {
 			printer = s
-			if now.Month() == then.Local().Month() && now.Year() == then.Year() && now.Local().Day() == i+1 {
+			if today {
 				printer = bs
 			}
 		}
-		_, _ = printer.Printf("%2d %s", i+1, d.String()[0:1])
 
-		found := false
-		for _, e := range entries {
-			if found {
-				_, _ = p.Print("      ") // space.
-			} else {
-				_, _ = p.Print("  ") // space.
-			}
-			if e.On == nil {
-				hasOpenDueDate = true
+		week := ""
+		if o.WeekNumbers {
+			_, w := r.day.ISOWeek()
+			week = fmt.Sprintf("W%02d", w)
+		}
+		wd := o.Weekdays[r.day.Weekday()]
+		wd += strings.Repeat(" ", dayWidth-utf8.RuneCountInString(wd))
+
+		lines := r.entries
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		for n, line := range lines {
+			first := n == 0
+			if o.RightToLeft {
+				_, _ = p.Print(line + strings.Repeat(" ", widest-utf8.RuneCountInString(line)))
+				if first {
+					_, _ = p.Print("  ")
+					_, _ = printer.Printf("%s %2d", wd, r.day.Day())
+					if week != "" {
+						_, _ = f.Printf(" %s", week)
+					}
+				}
+				_, _ = p.Println("")
 				continue
 			}
-			if e.On.Year() == then.Local().Year() && e.On.Month() == then.Local().Month() && e.On.Day() == i {
-				found = true
-				_, _ = p.Printf("%s %s %s\n", e.Signifier.String(), e.Bullet.String(), e.Message)
+			if week != "" {
+				if first {
+					_, _ = f.Printf("%s ", week)
+				} else {
+					_, _ = p.Print(strings.Repeat(" ", len(week)+1))
+				}
 			}
-		}
-		d++
-		if d > time.Saturday {
-			d = time.Sunday
-		}
-		if !found {
-			_, _ = p.Printf("\n")
+			if first {
+				_, _ = printer.Printf("%2d %s", r.day.Day(), wd)
+			} else {
+				_, _ = p.Print(strings.Repeat(" ", 3+dayWidth))
+			}
+			if line != "" {
+				_, _ = p.Printf("  %s", line)
+			}
+			_, _ = p.Println("")
 		}
 	}
 
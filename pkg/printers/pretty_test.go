@@ -0,0 +1,36 @@
+package printers
+
+import (
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestDoneTime(t *testing.T) {
+	created := time.Date(2021, 3, 4, 9, 0, 0, 0, time.Local)
+	at := func(t time.Time) *entry.Timestamp { return &entry.Timestamp{Time: t} }
+
+	tests := []struct {
+		name        string
+		bullet      glyph.Bullet
+		completedAt *entry.Timestamp
+		want        string
+	}{
+		{"same day", glyph.Completed, at(created.Add(5*time.Hour + 32*time.Minute)), "done 14:32"},
+		{"later day", glyph.Completed, at(created.AddDate(0, 0, 2)), "done 09:00, March 6"},
+		{"not saying", glyph.Completed, nil, ""},
+		{"opened again", glyph.Task, at(created), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := entry.New("Today", tt.bullet, "task")
+			e.Created = entry.Timestamp{Time: created}
+			e.CompletedAt = tt.completedAt
+			if got := DoneTime(e); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Kinds of profile.
+const (
+	CPU   = "cpu"
+	Mem   = "mem"
+	Trace = "trace"
+)
+
+// Kinds returns the kinds of profile that can be started.
+func Kinds() []string {
+	return []string{CPU, Mem, Trace}
+}
+
+// File is the default file a profile of kind is written to.
+func File(kind string) string {
+	if kind == Trace {
+		return "bujo.trace"
+	}
+	return fmt.Sprintf("bujo.%s.pprof", kind)
+}
+
+// Start starts a profile of kind written to path. The returned stop must be
+// called to finish writing the profile.
+func Start(kind, path string) (func() error, error) {
+	switch kind {
+	case CPU, Mem, Trace:
+	default:
+		return nil, app.Invalid("profile", kind, fmt.Sprintf("expected one of %v", Kinds()))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case CPU:
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		}, nil
+
+	case Trace:
+		if err := trace.Start(f); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return func() error {
+			trace.Stop()
+			return f.Close()
+		}, nil
+
+	default: // Mem
+		return func() error {
+			// Get up-to-date statistics for the heap profile.
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				_ = f.Close()
+				return err
+			}
+			return f.Close()
+		}, nil
+	}
+}
+
+// Capture writes a trace of the next d to path, or until ctx is done. Only
+// one trace can run at a time, Capture fails if a trace is already running.
+func Capture(ctx context.Context, path string, d time.Duration) error {
+	stop, err := Start(Trace, path)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+	return stop()
+}
@@ -0,0 +1,160 @@
+package recur
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/automation"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Collection is where recurring entries are kept. Their instances are added
+// to the day logs.
+const Collection = "Recurring"
+
+// Rules other than a cron expression.
+const (
+	Daily    = "daily"
+	Weekdays = "weekdays"
+	// Weekly is on the weekday the recurring entry was made.
+	Weekly = "weekly"
+	// Monthly is on the day of the month the recurring entry was made, or
+	// the last day of shorter months.
+	Monthly = "monthly"
+)
+
+// Valid returns an error if rule is not a known rule or a cron expression.
+func Valid(rule string) error {
+	switch strings.ToLower(rule) {
+	case Daily, Weekdays, Weekly, Monthly:
+		return nil
+	}
+	if _, err := automation.ParseSchedule(rule); err != nil {
+		return app.Invalid("rule", rule, "expected daily, weekdays, weekly, monthly or a cron expression: "+err.Error())
+	}
+	return nil
+}
+
+// On returns true if a recurring entry made on from with rule has an
+// instance on the day of t. Only the day fields of a cron expression are
+// used.
+func On(rule string, from, t time.Time) bool {
+	switch strings.ToLower(rule) {
+	case Daily:
+		return true
+	case Weekdays:
+		return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+	case Weekly:
+		return t.Weekday() == from.Weekday()
+	case Monthly:
+		last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+		return t.Day() == from.Day() || (from.Day() > last && t.Day() == last)
+	}
+	s, err := automation.ParseSchedule(rule)
+	return err == nil && s.OnDay(t)
+}
+
+// New makes a recurring entry.
+func New(bullet glyph.Bullet, message, rule string) *entry.Entry {
+	e := entry.New(Collection, bullet, message)
+	e.Recurrence = &entry.Recurrence{Rule: rule}
+	return e
+}
+
+// Recurring returns true if e is a recurring entry, and not an instance.
+func Recurring(e *entry.Entry) bool {
+	return e.Recurrence != nil && e.Recurrence.Of == ""
+}
+
+// open returns the bullet an instance of e is added with, and false if e
+// is completed, struck or moved and adds no instances.
+func open(e *entry.Entry) (glyph.Bullet, bool) {
+	switch e.Bullet {
+	case glyph.Task, glyph.Waiting:
+		return glyph.Task, true
+	case glyph.Note, glyph.Event:
+		return e.Bullet, true
+	}
+	return "", false
+}
+
+// Due returns the instances the open recurring entries in all have on the
+// day of now, and the recurring entries updated with the day. Days the
+// journal was not opened on are skipped, only now's day log gets instances.
+func Due(all []*entry.Entry, now time.Time) (instances, updated []*entry.Entry) {
+	for _, e := range all {
+		if !Recurring(e) {
+			continue
+		}
+		bullet, ok := open(e)
+		if !ok {
+			continue
+		}
+		r := e.Recurrence
+		if r.Last != nil && r.Last.SameDay(now) {
+			continue
+		}
+		if !On(r.Rule, e.Created.Local(), now.Local()) {
+			continue
+		}
+		i := entry.New(collection.DayOf(now), bullet, e.Message)
+		i.Signifier = e.Signifier
+		i.Label = e.Label
+		i.Private = e.Private
+		i.Recurrence = &entry.Recurrence{Rule: r.Rule, Of: e.ID}
+		instances = append(instances, i)
+
+		r.Last = &entry.Timestamp{Time: now}
+		updated = append(updated, e)
+	}
+	return instances, updated
+}
+
+// Add stores the instances due on the day of now, and the recurring entries
+// that added them, and returns the instances. With a store that has
+// transactions, they are written all or nothing, so a failed write does
+// not add an instance twice.
+func Add(ctx context.Context, p store.Persistence, now time.Time) ([]*entry.Entry, error) {
+	instances, updated := Due(p.List(ctx, Collection), now)
+	if len(instances) == 0 {
+		return nil, nil
+	}
+	write := func(s func(e *entry.Entry) error) error {
+		for _, e := range append(append([]*entry.Entry{}, instances...), updated...) {
+			if err := s(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if t, ok := p.(store.Transactor); ok {
+		err := t.Transact(ctx, func(tx store.Tx) error {
+			return write(tx.Store)
+		})
+		return instances, err
+	}
+	return instances, write(p.Store)
+}
+
+// Of returns the recurring entry of e out of all: e itself, or the entry
+// that added it. It is nil if e does not recur or its recurring entry is
+// gone.
+func Of(all []*entry.Entry, e *entry.Entry) *entry.Entry {
+	if e.Recurrence == nil {
+		return nil
+	}
+	if Recurring(e) {
+		return e
+	}
+	for _, o := range all {
+		if o.ID == e.Recurrence.Of {
+			return o
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package recur
+
+import (
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		entry  func() *entry.Entry
+		bullet glyph.Bullet
+	}{
+		"task": {
+			entry:  func() *entry.Entry { return New(glyph.Task, "water the plants", Daily) },
+			bullet: glyph.Task,
+		},
+		"note": {
+			entry:  func() *entry.Entry { return New(glyph.Note, "drink water", Daily) },
+			bullet: glyph.Note,
+		},
+		"event": {
+			entry:  func() *entry.Entry { return New(glyph.Event, "stand up", Daily) },
+			bullet: glyph.Event,
+		},
+		"waiting": {
+			entry: func() *entry.Entry {
+				e := New(glyph.Task, "rent", Daily)
+				e.Wait("the landlord", nil)
+				return e
+			},
+			bullet: glyph.Task,
+		},
+		"completed": {
+			entry: func() *entry.Entry {
+				e := New(glyph.Task, "water the plants", Daily)
+				e.Complete()
+				return e
+			},
+		},
+		"struck": {
+			entry: func() *entry.Entry {
+				e := New(glyph.Task, "water the plants", Daily)
+				e.Strike("")
+				return e
+			},
+		},
+		"moved": {
+			entry: func() *entry.Entry {
+				e := New(glyph.Task, "water the plants", Daily)
+				_ = e.Move(glyph.MovedCollection, "Garden")
+				return e
+			},
+		},
+		"stopped": {
+			entry: func() *entry.Entry {
+				e := New(glyph.Task, "water the plants", Daily)
+				e.Recurrence = nil
+				return e
+			},
+		},
+		"already added": {
+			entry: func() *entry.Entry {
+				e := New(glyph.Task, "water the plants", Daily)
+				e.Recurrence.Last = &entry.Timestamp{Time: now}
+				return e
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := tc.entry()
+			instances, updated := Due([]*entry.Entry{e}, now)
+			if tc.bullet == "" {
+				if len(instances) != 0 || len(updated) != 0 {
+					t.Fatalf("got %d instances, want none", len(instances))
+				}
+				return
+			}
+			if len(instances) != 1 || len(updated) != 1 {
+				t.Fatalf("got %d instances, want 1", len(instances))
+			}
+			if got := instances[0].Bullet; got != tc.bullet {
+				t.Errorf("instance bullet = %s, want %s", got, tc.bullet)
+			}
+			if got := instances[0].Recurrence.Of; got != e.ID {
+				t.Errorf("instance of %q, want %q", got, e.ID)
+			}
+		})
+	}
+}
+
+func TestMoveTakesRule(t *testing.T) {
+	e := New(glyph.Task, "water the plants", Daily)
+	moved := e.Move(glyph.MovedCollection, "Garden")
+	if e.Recurrence != nil {
+		t.Error("the original still recurs")
+	}
+	if moved.Recurrence == nil || moved.Recurrence.Rule != Daily {
+		t.Fatalf("the moved entry does not recur, got %+v", moved.Recurrence)
+	}
+	_, updated := Due([]*entry.Entry{moved}, time.Now())
+	if len(updated) != 1 || e.Recurrence != nil {
+		t.Error("adding an instance of the moved entry changed the original")
+	}
+}
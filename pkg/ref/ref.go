@@ -0,0 +1,102 @@
+// Package ref gives entries short human refs, like T-4F2, that can be used
+// in place of their ids.
+package ref
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// MinLength is the shortest id part of a ref.
+const MinLength = 3
+
+// Refs returns the ref of every entry, by id. The id part of a ref is the
+// shortest prefix of the id that is unique in the journal, so refs grow as
+// the journal does.
+func Refs(all []*entry.Entry) map[string]string {
+	ids := make([]string, 0, len(all))
+	for _, e := range all {
+		ids = append(ids, e.ID)
+	}
+	sort.Strings(ids)
+
+	refs := make(map[string]string, len(all))
+	for i, id := range ids {
+		n := MinLength
+		if i > 0 {
+			n = max(n, common(id, ids[i-1])+1)
+		}
+		if i+1 < len(ids) {
+			n = max(n, common(id, ids[i+1])+1)
+		}
+		if n > len(id) {
+			n = len(id)
+		}
+		refs[id] = strings.ToUpper(id[:n])
+	}
+	for _, e := range all {
+		refs[e.ID] = kind(e) + "-" + refs[e.ID]
+	}
+	return refs
+}
+
+// Resolve finds the entry an id or a ref refers to. The kind of a ref is
+// not checked, it changes with the bullet.
+func Resolve(all []*entry.Entry, idOrRef string) (*entry.Entry, error) {
+	for _, e := range all {
+		if e.ID == idOrRef {
+			return e, nil
+		}
+	}
+
+	prefix := strings.ToLower(idOrRef)
+	if i := strings.Index(prefix, "-"); i >= 0 {
+		prefix = prefix[i+1:]
+	}
+	if len(prefix) < MinLength {
+		return nil, fmt.Errorf("%w: %s", app.ErrEntryNotFound, idOrRef)
+	}
+
+	var found *entry.Entry
+	for _, e := range all {
+		if !strings.HasPrefix(e.ID, prefix) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%s: %w", idOrRef, app.ErrAmbiguousRef)
+		}
+		found = e
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", app.ErrEntryNotFound, idOrRef)
+	}
+	return found, nil
+}
+
+// kind is the letter a ref starts with, from the meaning of the bullet.
+func kind(e *entry.Entry) string {
+	meaning := e.Bullet.Glyph().Meaning
+	if meaning == "" {
+		return "E"
+	}
+	return strings.ToUpper(meaning[:1])
+}
+
+func common(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
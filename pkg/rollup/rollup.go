@@ -0,0 +1,225 @@
+// Package rollup evaluates computed entries, entries with a message like
+// `= count(open, "Project X")`, against the journal.
+package rollup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// prefix starts the message of a computed entry.
+const prefix = "= "
+
+// Functions of a rollup.
+const (
+	// Count counts the entries matching the filters.
+	Count = "count"
+	// Sum adds up a field, like hours:2, of the entries matching the filters.
+	Sum = "sum"
+)
+
+// states filter entries by bullet.
+var states = map[string]func(glyph.Bullet) bool{
+	"open": func(b glyph.Bullet) bool {
+		return b == glyph.Task || b == glyph.Waiting
+	},
+	"done": func(b glyph.Bullet) bool {
+		return b == glyph.Completed
+	},
+	"struck": func(b glyph.Bullet) bool {
+		return b == glyph.Irrelevant
+	},
+	"tasks": func(b glyph.Bullet) bool {
+		return b == glyph.Task || b == glyph.Waiting || b == glyph.Completed || b == glyph.Irrelevant
+	},
+	"notes": func(b glyph.Bullet) bool {
+		return b == glyph.Note
+	},
+	"events": func(b glyph.Bullet) bool {
+		return b == glyph.Event
+	},
+	"all": func(b glyph.Bullet) bool {
+		return true
+	},
+}
+
+// State returns the check for a named state of the bullet, like open or done,
+// for other filters of the journal to agree with rollups.
+func State(name string) (func(glyph.Bullet) bool, error) {
+	state, ok := states[name]
+	if !ok {
+		return nil, app.Invalid("state", name, "expected open, done, struck, tasks, notes, events or all")
+	}
+	return state, nil
+}
+
+// Is returns true if the message is a computed entry.
+func Is(message string) bool {
+	return strings.HasPrefix(message, prefix)
+}
+
+// Any returns true if any of the entries is a computed entry.
+func Any(entries []*entry.Entry) bool {
+	for _, e := range entries {
+		if Is(e.Message) {
+			return true
+		}
+	}
+	return false
+}
+
+// Render returns the message of a computed entry with its value, like
+// `count(open, "Project X") = 4`. Other messages are returned as they are.
+func Render(message string, all []*entry.Entry) string {
+	if !Is(message) {
+		return message
+	}
+	expr := strings.TrimSpace(strings.TrimPrefix(message, prefix))
+	v, err := Eval(expr, all)
+	if err != nil {
+		return fmt.Sprintf("%s = ? (%v)", expr, err)
+	}
+	return fmt.Sprintf("%s = %s", expr, v)
+}
+
+// Eval evaluates expr, like `sum(field:hours, #client)`, against all.
+//
+// Filters are a state (open, done, struck, tasks, notes, events or all), a
+// quoted collection name, or a #word the message has. Entries must match
+// every filter. Moved entries, tracks and computed entries are not counted.
+func Eval(expr string, all []*entry.Entry) (string, error) {
+	open := strings.Index(expr, "(")
+	if open < 0 || !strings.HasSuffix(expr, ")") {
+		return "", app.Invalid("rollup", expr, `expected a function like count(open, "Project X")`)
+	}
+	fn := strings.TrimSpace(expr[:open])
+	args, err := split(expr[open+1 : len(expr)-1])
+	if err != nil {
+		return "", err
+	}
+
+	field := ""
+	if fn == Sum {
+		if len(args) == 0 || !strings.HasPrefix(args[0], "field:") {
+			return "", app.Invalid("rollup", expr, "sum needs a field first, like sum(field:hours)")
+		}
+		field = strings.TrimPrefix(args[0], "field:")
+		args = args[1:]
+	} else if fn != Count {
+		return "", app.Invalid("rollup function", fn, fmt.Sprintf("expected %s or %s", Count, Sum))
+	}
+
+	match, err := filter(args)
+	if err != nil {
+		return "", err
+	}
+
+	count := 0
+	sum := 0.0
+	for _, e := range all {
+		switch e.Bullet {
+		case glyph.MovedCollection, glyph.MovedFuture, glyph.Occurrence:
+			continue
+		}
+		if Is(e.Message) || !match(e) {
+			continue
+		}
+		count++
+		if field != "" {
+			sum += value(e.Message, field)
+		}
+	}
+	if fn == Count {
+		return strconv.Itoa(count), nil
+	}
+	return strconv.FormatFloat(sum, 'f', -1, 64), nil
+}
+
+// split splits the arguments on commas outside of quotes, unquoting them.
+func split(s string) ([]string, error) {
+	args := make([]string, 0)
+	var b strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			b.WriteRune(r)
+		case r == ',' && !quoted:
+			args = append(args, strings.TrimSpace(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, app.Invalid("rollup", s, "a quote is not closed")
+	}
+	if last := strings.TrimSpace(b.String()); last != "" || len(args) > 0 {
+		args = append(args, last)
+	}
+	return args, nil
+}
+
+// filter returns a match for entries that pass every filter in args.
+func filter(args []string) (func(*entry.Entry) bool, error) {
+	checks := make([]func(*entry.Entry) bool, 0, len(args))
+	for _, arg := range args {
+		arg := arg
+		switch {
+		case strings.HasPrefix(arg, `"`):
+			name := strings.Trim(arg, `"`)
+			checks = append(checks, func(e *entry.Entry) bool {
+				return e.Collection == name
+			})
+		case strings.HasPrefix(arg, "#"):
+			checks = append(checks, func(e *entry.Entry) bool {
+				return hasWord(e.Message, arg)
+			})
+		default:
+			state, ok := states[arg]
+			if !ok {
+				return nil, app.Invalid("rollup filter", arg, `expected a state like open or done, a "collection" or a #word`)
+			}
+			checks = append(checks, func(e *entry.Entry) bool {
+				return state(e.Bullet)
+			})
+		}
+	}
+	return func(e *entry.Entry) bool {
+		for _, check := range checks {
+			if !check(e) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func hasWord(message, word string) bool {
+	for _, w := range strings.Fields(message) {
+		if strings.EqualFold(strings.TrimRight(w, ".,;:!?"), word) {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the number of the field in message, like 2.5 for hours:2.5,
+// or 0.
+func value(message, field string) float64 {
+	for _, w := range strings.Fields(message) {
+		if !strings.HasPrefix(w, field+":") {
+			continue
+		}
+		if v, err := strconv.ParseFloat(strings.TrimPrefix(w, field+":"), 64); err == nil {
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+package rollup
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		invalid bool
+	}{
+		{in: "", want: []string{}},
+		{in: "   ", want: []string{}},
+		{in: "open", want: []string{"open"}},
+		{in: ` open , done `, want: []string{"open", "done"}},
+		{in: `open, "Project X"`, want: []string{"open", `"Project X"`}},
+		{in: `"A, B", #tag`, want: []string{`"A, B"`, "#tag"}},
+		{in: "open,", want: []string{"open", ""}},
+		{in: ", open", want: []string{"", "open"}},
+		{in: `open, "Project X`, invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := split(tt.in)
+			if tt.invalid {
+				if !errors.Is(err, app.ErrValidation) {
+					t.Fatalf("got %q, %v, want it invalid", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// journal has an entry of each kind in two collections.
+func journal() []*entry.Entry {
+	e := func(collection string, bullet glyph.Bullet, message string) *entry.Entry {
+		return entry.New(collection, bullet, message)
+	}
+	return []*entry.Entry{
+		e("Project X", glyph.Task, "design #client hours:2"),
+		e("Project X", glyph.Waiting, "review hours:1.5"),
+		e("Project X", glyph.Completed, "kickoff #client hours:1"),
+		e("Project X", glyph.Irrelevant, "dropped hours:4"),
+		e("Project X", glyph.Note, "notes #client"),
+		e("Project X", glyph.MovedCollection, "moved #client hours:8"),
+		e("Project X", glyph.MovedFuture, "later #client hours:8"),
+		e("Project X", glyph.Occurrence, "tracked #client hours:8"),
+		e("Project X", glyph.Task, `= count(open) hours:8`),
+		e("A, B", glyph.Task, "comma hours:3 #Client."),
+		e("A, B", glyph.Event, "meeting hours:x"),
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		invalid bool
+	}{
+		{name: "no filters", expr: "count()", want: "7"},
+		{name: "state", expr: "count(open)", want: "3"},
+		{name: "done", expr: "count(done)", want: "1"},
+		{name: "struck", expr: "count(struck)", want: "1"},
+		{name: "tasks", expr: "count(tasks)", want: "5"},
+		{name: "notes", expr: "count(notes)", want: "1"},
+		{name: "events", expr: "count(events)", want: "1"},
+		{name: "all", expr: "count(all)", want: "7"},
+		{name: "collection", expr: `count(open, "Project X")`, want: "2"},
+		{name: "quoted comma", expr: `count("A, B")`, want: "2"},
+		{name: "tag", expr: "count(#client)", want: "4"},
+		{name: "every filter", expr: `count(open, #client, "Project X")`, want: "1"},
+		{name: "spaces", expr: "count ( open , #client )", want: "2"},
+		{name: "sum", expr: "sum(field:hours)", want: "11.5"},
+		{name: "sum filtered", expr: `sum(field:hours, open, "Project X")`, want: "3.5"},
+		{name: "sum not a number", expr: `sum(field:hours, events)`, want: "0"},
+		{name: "sum without a field", expr: "sum(open)", invalid: true},
+		{name: "sum of nothing", expr: "sum()", invalid: true},
+		{name: "unknown state", expr: "count(finished)", invalid: true},
+		{name: "empty argument", expr: "count(open, , done)", invalid: true},
+		{name: "trailing comma", expr: "count(open,)", invalid: true},
+		{name: "unclosed quote", expr: `count("Project X)`, invalid: true},
+		{name: "unknown function", expr: "avg(open)", invalid: true},
+		{name: "not a function", expr: "open", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Eval(tt.expr, journal())
+			if tt.invalid {
+				if !errors.Is(err, app.ErrValidation) {
+					t.Fatalf("got %q, %v, want it invalid", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"plain message", "plain message"},
+		{`= count(open, "Project X") `, `count(open, "Project X") = 2`},
+		// The error follows in parens.
+		{"= count(finished)", "count(finished) = ? ("},
+	}
+	for _, tt := range tests {
+		if got := Render(tt.message, journal()); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("Render(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
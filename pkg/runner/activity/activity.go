@@ -0,0 +1,56 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Activity is a feed of what happened in the journal, newest first.
+type Activity struct {
+	Since time.Time
+	Until time.Time
+	// ShowID shows the ref of each entry, to jump to it with other commands.
+	ShowID bool
+
+	Persistence store.Persistence
+}
+
+func (n *Activity) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not show activity, no persistence")
+	}
+	h, ok := n.Persistence.(store.Historian)
+	if !ok {
+		return fmt.Errorf("activity is %w", app.ErrUnsupported)
+	}
+
+	all := h.Activity(ctx, n.Since, n.Until)
+
+	var refs map[string]string
+	if n.ShowID {
+		refs = ref.Refs(n.Persistence.ListAll(ctx))
+	}
+
+	pp := printers.PrettyPrint{}
+	fmt.Println("")
+	pp.Title(fmt.Sprintf("Activity since %s", n.Since.Format("January 2, 2006")))
+	if len(all) == 0 {
+		fmt.Println(" none")
+		return nil
+	}
+	for _, a := range all {
+		line := printers.ActivityLine(a)
+		if r, ok := refs[a.Entry.ID]; ok {
+			line += " " + r
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
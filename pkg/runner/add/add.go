@@ -2,7 +2,12 @@ package add
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
 	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
 	"tableflip.dev/bujo/pkg/store"
 	"time"
 
@@ -13,10 +18,14 @@ import (
 type Add struct {
 	Entry entry.Entry
 
-	Bullet        glyph.Bullet
-	Collection    string
+	Bullet     glyph.Bullet
+	Collection string
+	// After is the id of the entry to add the new entry after. The new entry
+	// is added to the collection of that entry.
+	After         string
 	Message       string
 	On            *time.Time
+	Expires       *time.Time
 	Priority      bool
 	Inspiration   bool
 	Investigation bool
@@ -24,21 +33,35 @@ type Add struct {
 	Persistence store.Persistence
 }
 
-const (
-	layoutISO = "2006-01-02"
-	layoutUS  = "January 2, 2006"
-)
-
 func (n *Add) Do(ctx context.Context) error {
-	if n.Collection == "today" {
-		n.Collection = time.Now().Format(layoutUS)
+	n.Collection = collection.Resolve(n.Collection)
+
+	var order int64
+	if n.After != "" {
+		if n.Persistence == nil {
+			return errors.New("can not add after an entry, no persistence")
+		}
+		var moved []*entry.Entry
+		var err error
+		if n.Collection, order, moved, err = n.after(ctx); err != nil {
+			return err
+		}
+		for _, m := range moved {
+			if err := n.Persistence.Store(m); err != nil {
+				return err
+			}
+		}
 	}
 
 	e := entry.New(n.Collection, n.Bullet, n.Message)
+	e.Order = order
 
 	if n.On != nil {
 		e.On = &entry.Timestamp{Time: *n.On}
 	}
+	if n.Expires != nil {
+		e.Expires = &entry.Timestamp{Time: *n.Expires}
+	}
 
 	switch {
 	case n.Priority:
@@ -63,3 +86,20 @@ func (n *Add) Do(ctx context.Context) error {
 
 	return nil
 }
+
+// after finds the collection and order to add an entry after n.After, and
+// the entries that were moved to make room for it.
+func (n *Add) after(ctx context.Context) (string, int64, []*entry.Entry, error) {
+	prev, err := ref.Resolve(n.Persistence.ListAll(ctx), n.After)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	all := n.Persistence.List(ctx, prev.Collection)
+	for _, e := range all {
+		if e.ID == prev.ID {
+			order, moved := entry.OrderAfter(all, e)
+			return prev.Collection, order, moved, nil
+		}
+	}
+	return "", 0, nil, fmt.Errorf("%w: %s", app.ErrEntryNotFound, n.After)
+}
@@ -0,0 +1,85 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Archive moves a collection out of the journal into the archive, or back.
+// Archived collections are not listed or shown in the ui, search and export
+// still reach them with --archived.
+type Archive struct {
+	Collection string
+	// Unarchive moves the collection back into the journal.
+	Unarchive bool
+	// List lists the archived collections instead.
+	List bool
+
+	Persistence store.Persistence
+}
+
+func (n *Archive) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not archive, no persistence")
+	}
+	a, ok := n.Persistence.(store.Archiver)
+	if !ok {
+		return fmt.Errorf("archiving is %w", app.ErrUnsupported)
+	}
+	if n.List {
+		return n.list(ctx, a)
+	}
+
+	if n.Unarchive {
+		if !archived(ctx, a, n.Collection) {
+			return fmt.Errorf("%w: %s is not archived", app.ErrCollectionNotFound, n.Collection)
+		}
+		moved, err := a.Unarchive(ctx, n.Collection)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("unarchived %s, %d entries\n", n.Collection, moved)
+		return nil
+	}
+
+	if len(n.Persistence.List(ctx, n.Collection)) == 0 {
+		return fmt.Errorf("%w: %s", app.ErrCollectionNotFound, n.Collection)
+	}
+	moved, err := a.Archive(ctx, n.Collection)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("archived %s, %d entries\n", n.Collection, moved)
+	return nil
+}
+
+// list prints the archived collections with how many entries each has.
+func (n *Archive) list(ctx context.Context, a store.Archiver) error {
+	counts := make(map[string]int)
+	for _, e := range a.ListArchived(ctx) {
+		counts[e.Collection]++
+	}
+	collections := a.Archived(ctx)
+	if len(collections) == 0 {
+		fmt.Println("no archived collections")
+		return nil
+	}
+	for _, c := range collections {
+		fmt.Printf("%s (%d)\n", c, counts[c])
+	}
+	return nil
+}
+
+// archived is true if collection is in the archive.
+func archived(ctx context.Context, a store.Archiver, collection string) bool {
+	for _, c := range a.Archived(ctx) {
+		if c == collection {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package attach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Attach links a URL or a file to an entry, removes one of its attachments
+// or opens one.
+type Attach struct {
+	ID string
+	// Target is the URL or file to attach, with an optional Label.
+	Target string
+	Label  string
+	// Remove is the attachment to remove, from 1.
+	Remove int
+	// Open is the attachment to open, from 1.
+	Open        int
+	Persistence store.Persistence
+}
+
+func (n *Attach) Do(ctx context.Context) error {
+	pp := printers.PrettyPrint{ShowID: true}
+
+	if n.Persistence == nil {
+		return errors.New("can not attach, no persistence")
+	}
+
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case n.Open > 0:
+		if n.Open > len(e.Attachments) {
+			return app.Invalid("attachment", strconv.Itoa(n.Open), fmt.Sprintf("the entry has %d", len(e.Attachments)))
+		}
+		return Open(e.Attachments[n.Open-1])
+	case n.Remove > 0:
+		if !e.RemoveAttachment(n.Remove - 1) {
+			return app.Invalid("attachment", strconv.Itoa(n.Remove), fmt.Sprintf("the entry has %d", len(e.Attachments)))
+		}
+	case n.Target != "":
+		e.AddAttachment(entry.NewAttachment(n.Target, n.Label))
+	}
+	if n.Target != "" || n.Remove > 0 {
+		if err := n.Persistence.Store(e); err != nil {
+			return err
+		}
+	}
+
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
+	fmt.Println("")
+	pp.Title(e.Collection)
+	pp.Collection(all...)
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package attach
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/mitchellh/go-homedir"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// Open opens the attachment with the opener of the OS, a browser for a URL
+// and the app for the kind of file. It does not wait for the opener.
+func Open(a entry.Attachment) error {
+	target := a.Target
+	if a.Kind == entry.AttachFile {
+		path, err := homedir.Expand(target)
+		if err != nil {
+			return err
+		}
+		target = path
+		if _, err := os.Stat(target); err != nil {
+			return err
+		}
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("xdg-open", target)
+	default:
+		return fmt.Errorf("opening attachments on %s is %w", runtime.GOOS, app.ErrUnsupported)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
@@ -0,0 +1,106 @@
+package automations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+
+	"tableflip.dev/bujo/pkg/automation"
+	"tableflip.dev/bujo/pkg/notify"
+)
+
+const layoutRun = "Mon Jan 2, 2006 15:04"
+
+// List prints the configured automations with their last run.
+type List struct {
+	Automations []automation.Automation
+	History     *automation.History
+}
+
+func (n *List) Do(ctx context.Context) error {
+	if n.History == nil {
+		return errors.New("can not list automations, no history")
+	}
+	if len(n.Automations) == 0 {
+		fmt.Println("no automations configured")
+		return nil
+	}
+
+	bold := color.New(color.Bold)
+	tbl := uitable.New()
+	tbl.Separator = "  "
+	tbl.AddRow(bold.Sprint("Name"), bold.Sprint("Schedule"), bold.Sprint("Next"), bold.Sprint("Last"), bold.Sprint("Result"))
+
+	now := time.Now()
+	for _, a := range n.Automations {
+		next := "invalid schedule"
+		if s, err := automation.ParseSchedule(a.Schedule); err == nil {
+			next = s.Next(now).Format(layoutRun)
+		}
+
+		last, result := "never", ""
+		r, err := n.History.Last(a.Name)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			last = r.Started.Format(layoutRun)
+			result = color.GreenString("ok")
+			if !r.OK() {
+				result = color.RedString(r.Error)
+			}
+		}
+		tbl.AddRow(a.Name, a.Schedule, next, last, result)
+	}
+
+	_, _ = fmt.Fprintln(color.Output, tbl)
+	return nil
+}
+
+// Start runs the scheduler in the foreground until ctx is done.
+type Start struct {
+	Automations []automation.Automation
+	History     *automation.History
+	// Now runs the named automation once and exits, if set.
+	Now string
+	// Sink is notified as runs finish, if set.
+	Sink notify.Sink
+}
+
+func (n *Start) Do(ctx context.Context) error {
+	if n.History == nil {
+		return errors.New("can not run automations, no history")
+	}
+
+	s := &automation.Scheduler{
+		Automations: n.Automations,
+		Exec:        automation.Self,
+		History:     n.History,
+		Logf:        log.Printf,
+		Sink:        n.Sink,
+	}
+
+	if n.Now != "" {
+		for _, a := range n.Automations {
+			if a.Name != n.Now {
+				continue
+			}
+			r := s.RunOnce(ctx, a)
+			if r.Output != "" {
+				fmt.Println(r.Output)
+			}
+			if !r.OK() {
+				return errors.New(r.Error)
+			}
+			return nil
+		}
+		return fmt.Errorf("no automation named %q", n.Now)
+	}
+
+	return s.Run(ctx)
+}
@@ -0,0 +1,310 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Version of the export format.
+const Version = "v1"
+
+// document is the export format.
+type document struct {
+	Version  string    `json:"version"`
+	Exported time.Time `json:"exported"`
+	Entries  []record  `json:"entries"`
+	// History is what happened to the entries, oldest first. It is not
+	// imported.
+	History []event `json:"history,omitempty"`
+}
+
+// event is an activity of an entry, by its id.
+type event struct {
+	ID   string    `json:"id"`
+	At   time.Time `json:"at"`
+	Kind string    `json:"kind"`
+}
+
+// record is an entry with its id, the id is not part of the entry json.
+type record struct {
+	ID    string       `json:"id"`
+	Entry *entry.Entry `json:"entry"`
+}
+
+// Export writes the entries of the journal to a file.
+type Export struct {
+	File string
+	// Format is one of Formats, defaults to the FormatOf the file.
+	Format string
+	// Collections limits the export to these collections, if set.
+	Collections []string
+	// Signer signs the export, if set.
+	Signer Signer
+	// IncludePrivate exports private entries too.
+	IncludePrivate bool
+	// Archived exports the archived collections too.
+	Archived bool
+
+	Persistence store.Persistence
+}
+
+func (n *Export) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not export, no persistence")
+	}
+	format := formatName(n.Format)
+	if format == "" {
+		format = FormatOf(n.File)
+	}
+	if err := ValidFormat(format); err != nil {
+		return err
+	}
+	only := make(map[string]bool, len(n.Collections))
+	for _, c := range n.Collections {
+		only[c] = true
+	}
+
+	exported := time.Now()
+	entries := make([]*entry.Entry, 0)
+	private := 0
+	all := n.Persistence.ListAll(ctx)
+	if n.Archived {
+		a, ok := n.Persistence.(store.Archiver)
+		if !ok {
+			return fmt.Errorf("exporting the archive is %w", app.ErrUnsupported)
+		}
+		all = append(all, a.ListArchived(ctx)...)
+	}
+	for _, e := range all {
+		if len(only) > 0 && !only[e.Collection] {
+			continue
+		}
+		if e.Private && !n.IncludePrivate {
+			private++
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	var h history
+	if historian, ok := n.Persistence.(store.Historian); ok {
+		exporting := make(map[string]bool, len(entries))
+		for _, e := range entries {
+			exporting[e.ID] = true
+		}
+		all := make([]store.Activity, 0)
+		for _, a := range historian.Activity(ctx, time.Time{}, exported.Add(time.Minute)) {
+			if exporting[a.Entry.ID] {
+				all = append(all, a)
+			}
+		}
+		h = historyOf(all)
+	}
+
+	var b []byte
+	var err error
+	switch format {
+	case FormatOPML:
+		b, err = toOPML(entries, exported)
+	case FormatOrg:
+		b = toOrg(entries, exported)
+	case FormatMarkdown:
+		b = toMarkdown(entries, h, exported)
+	case FormatCSV:
+		b, err = toCSV(entries, h)
+	default:
+		doc := document{Version: Version, Exported: exported}
+		for _, e := range entries {
+			doc.Entries = append(doc.Entries, record{ID: e.ID, Entry: e})
+			for _, a := range h[e.ID] {
+				doc.History = append(doc.History, event{ID: e.ID, At: a.At, Kind: a.Kind})
+			}
+		}
+		b, err = json.MarshalIndent(doc, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(n.File, b, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("exported %d entries to %s\n", len(entries), n.File)
+	if private > 0 {
+		fmt.Printf("left out %d private entries, export them with --include-private\n", private)
+	}
+
+	if n.Signer == nil {
+		return nil
+	}
+	if err := n.Signer.Sign(ctx, n.File); err != nil {
+		return fmt.Errorf("failed to sign export: %v", err)
+	}
+	fmt.Printf("signed to %s\n", SignatureOf(n.File))
+	return nil
+}
+
+// Import restores the entries of an export into the journal. Entries that
+// are already in the journal are merged by the Merge policy.
+type Import struct {
+	File string
+	// Format is one of Formats, defaults to the FormatOf the file.
+	Format string
+	// Merge is one of Merges, defaults to MergeNewest.
+	Merge string
+	// In is where MergeInteractive reads answers from, defaults to stdin.
+	In io.Reader
+	// Signer verifies the export, if it is signed.
+	Signer Signer
+	// RequireSignature fails the import of an export that is not signed.
+	RequireSignature bool
+
+	Persistence store.Persistence
+}
+
+func (n *Import) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not import, no persistence")
+	}
+	if err := ValidMerge(n.Merge); err != nil {
+		return err
+	}
+	if err := n.verify(ctx); err != nil {
+		return err
+	}
+
+	entries, exported, err := Read(n.File, n.Format)
+	if err != nil {
+		return err
+	}
+
+	in := n.In
+	if in == nil {
+		in = os.Stdin
+	}
+	m, err := newMerger(ctx, n.Persistence, n.Merge, exported, in).merge(entries)
+	if err != nil {
+		return err
+	}
+
+	// An import is all or nothing if the store supports it.
+	restore := func(store, remove func(e *entry.Entry) error) error {
+		for _, e := range m.replace {
+			if err := remove(e); err != nil {
+				return err
+			}
+		}
+		for _, e := range m.store {
+			if err := store(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if t, ok := n.Persistence.(store.Transactor); ok {
+		err = t.Transact(ctx, func(tx store.Tx) error {
+			return restore(tx.Store, tx.Delete)
+		})
+	} else {
+		err = restore(n.Persistence.Store, func(e *entry.Entry) error {
+			return fmt.Errorf("moving %s to another collection is %w", e.ID, app.ErrUnsupported)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("imported %d entries from %s: %s\n", len(entries), n.File, m)
+	return nil
+}
+
+// Read returns the entries of a file to import, and when it was made. The
+// format is one of Imports, and defaults to the FormatOf the file.
+func Read(file, format string) ([]*entry.Entry, time.Time, error) {
+	format = formatName(format)
+	if format == "" {
+		format = FormatOf(file)
+	}
+	if err := ValidImport(format); err != nil {
+		return nil, time.Time{}, err
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	// Only json says when it was made, for the rest the file does.
+	var made time.Time
+	if fi, err := os.Stat(file); err == nil {
+		made = fi.ModTime()
+	}
+
+	var entries []*entry.Entry
+	switch format {
+	case FormatOPML:
+		entries, err = fromOPML(b)
+	case FormatOrg:
+		entries, err = fromOrg(b)
+	case FormatMarkdown:
+		entries, err = fromMarkdown(b, time.Now())
+	case FormatTodoTxt:
+		entries, err = fromTodoTxt(b, time.Now())
+	default:
+		entries, made, err = fromJSON(b)
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %w", file, err)
+	}
+	// An id names the file the entry is stored in. Any id the journal would
+	// not have made is dropped, and the entry gets a new one when stored.
+	for _, e := range entries {
+		if !store.ValidID(e.ID) {
+			e.ID = ""
+		}
+	}
+	return entries, made, nil
+}
+
+// fromJSON reads the entries of a json export, and when it was exported.
+func fromJSON(b []byte) ([]*entry.Entry, time.Time, error) {
+	doc := document{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, time.Time{}, fmt.Errorf("not an export: %v", err)
+	}
+	if doc.Version != Version {
+		return nil, time.Time{}, fmt.Errorf("unsupported export version %q", doc.Version)
+	}
+	entries := make([]*entry.Entry, 0, len(doc.Entries))
+	for _, r := range doc.Entries {
+		if r.Entry == nil {
+			continue
+		}
+		r.Entry.ID = r.ID
+		entries = append(entries, r.Entry)
+	}
+	return entries, doc.Exported, nil
+}
+
+func (n *Import) verify(ctx context.Context) error {
+	if _, err := os.Stat(SignatureOf(n.File)); os.IsNotExist(err) {
+		if n.RequireSignature {
+			return fmt.Errorf("%s is not signed", n.File)
+		}
+		return nil
+	}
+	if n.Signer == nil {
+		return fmt.Errorf("%s is signed but no key is configured to verify it", n.File)
+	}
+	if err := n.Signer.Verify(ctx, n.File); err != nil {
+		return fmt.Errorf("signature verification failed: %v", err)
+	}
+	fmt.Printf("verified signature of %s\n", n.File)
+	return nil
+}
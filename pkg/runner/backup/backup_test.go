@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadDropsForeignIDs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bujo-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "export.json")
+	export := `{"version": "v1", "entries": [
+		{"id": "../../../../.bashrc", "entry": {"collection": "Work", "bullet": "task", "message": "escape"}},
+		{"id": "0123456789ABCDEF", "entry": {"collection": "Work", "bullet": "task", "message": "upper"}},
+		{"id": "0123456789abcdef0", "entry": {"collection": "Work", "bullet": "task", "message": "long"}},
+		{"id": "0123456789abcdef", "entry": {"collection": "Work", "bullet": "task", "message": "kept"}}
+	]}`
+	if err := ioutil.WriteFile(file, []byte(export), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := Read(file, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"escape": "",
+		"upper":  "",
+		"long":   "",
+		"kept":   "0123456789abcdef",
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for _, e := range entries {
+		if e.ID != want[e.Message] {
+			t.Errorf("%s has id %q, want %q", e.Message, e.ID, want[e.Message])
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// checkboxes are the markdown task list items, and the bullet of each.
+var checkboxes = map[string]glyph.Bullet{
+	"[ ] ": glyph.Task,
+	"[x] ": glyph.Completed,
+	"[X] ": glyph.Completed,
+}
+
+// fromMarkdown reads the entries of a markdown document: task list items
+// are tasks, checked ones completed, struck out items are irrelevant and
+// other list items notes. Headings name the collection of the items under
+// them, a deeper heading nests in the one above it, as markdown exports
+// are written. Items before any heading go to the day log of now.
+func fromMarkdown(b []byte, now time.Time) ([]*entry.Entry, error) {
+	entries := make([]*entry.Entry, 0)
+	// headings are the open headings, by level.
+	type heading struct {
+		level int
+		name  string
+	}
+	headings := make([]heading, 0)
+	name := collection.DayOf(now)
+	var last *entry.Entry
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			for len(headings) > 0 && headings[len(headings)-1].level >= level {
+				headings = headings[:len(headings)-1]
+			}
+			headings = append(headings, heading{level: level, name: strings.TrimSpace(trimmed[level:])})
+			// A top level heading over deeper ones is the title of the
+			// document, not a collection.
+			parts := make([]string, 0, len(headings))
+			for i, h := range headings {
+				if i == 0 && h.level == 1 && len(headings) > 1 {
+					continue
+				}
+				parts = append(parts, h.name)
+			}
+			name = collection.Resolve(strings.Join(parts, collectionSeparator))
+			last = nil
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			item := strings.TrimSpace(trimmed[2:])
+			// The history of an exported entry is not imported.
+			if text != trimmed && strings.HasPrefix(item, "_") && strings.HasSuffix(item, "_") {
+				continue
+			}
+			last = listItem(name, item)
+			entries = append(entries, last)
+		case trimmed == "":
+			last = nil
+		case last != nil && text != trimmed:
+			// Indented lines carry on the message of the item above.
+			last.Message += "\n" + trimmed
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// listItem returns the entry of a markdown list item, without its "- ".
+func listItem(name, item string) *entry.Entry {
+	bullet := glyph.Note
+	for box, b := range checkboxes {
+		if strings.HasPrefix(item, box) {
+			bullet, item = b, strings.TrimSpace(item[len(box):])
+			break
+		}
+	}
+	if bullet == glyph.Note && strings.HasPrefix(item, "~~") && strings.HasSuffix(item, "~~") && len(item) > 4 {
+		bullet, item = glyph.Irrelevant, item[2:len(item)-2]
+	}
+	// Bullets and signifiers written as their symbol, as exports do.
+	if bullet == glyph.Note || bullet == glyph.Task {
+		for b, g := range glyph.DefaultBullets() {
+			if g.Printed && strings.HasPrefix(item, g.Symbol+" ") {
+				bullet, item = b, strings.TrimSpace(item[len(g.Symbol)+1:])
+				break
+			}
+		}
+	}
+	e := newOutlined(name, item)
+	e.Bullet = bullet
+	for s, g := range glyph.DefaultSignifiers() {
+		if g.Printed && strings.HasPrefix(e.Message, g.Symbol+" ") {
+			e.Signifier, e.Message = s, strings.TrimSpace(e.Message[len(g.Symbol)+1:])
+			break
+		}
+	}
+	return e
+}
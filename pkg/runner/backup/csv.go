@@ -0,0 +1,60 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// csvHeader are the columns of a csv export.
+var csvHeader = []string{"collection", "id", "bullet", "signifier", "label", "message", "created", "on", "private", "history"}
+
+// toCSV renders the entries as a row each, with the history of the entry
+// in the last column. CSV is for spreadsheets, it does not import.
+func toCSV(entries []*entry.Entry, h history) ([]byte, error) {
+	b := &bytes.Buffer{}
+	w := csv.NewWriter(b)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	names, byName := grouped(entries)
+	for _, name := range names {
+		for _, e := range byName[name] {
+			on := ""
+			if e.On != nil && !e.On.IsZero() {
+				on = entry.FormatTime(e.On.Time)
+			}
+			signifier := ""
+			if e.Signifier != glyph.None {
+				signifier = e.Signifier.String()
+			}
+			events := make([]string, 0, len(h[e.ID]))
+			for _, a := range h[e.ID] {
+				events = append(events, fmt.Sprintf("%s %s", a.Kind, entry.FormatTime(a.At)))
+			}
+			row := []string{
+				e.Collection,
+				e.ID,
+				e.Bullet.String(),
+				signifier,
+				string(e.Label),
+				e.Message,
+				entry.FormatTime(e.Created.Time),
+				on,
+				strconv.FormatBool(e.Private),
+				strings.Join(events, "; "),
+			}
+			if err := w.Write(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
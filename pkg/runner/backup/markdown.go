@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// collectionSeparator splits the name of a collection nested in another.
+const collectionSeparator = "/"
+
+// layoutHistory is when an activity in the history of an entry happened.
+const layoutHistory = "Jan 2, 2006 3:04pm"
+
+// history is what happened to each entry, by id, oldest first.
+type history map[string][]store.Activity
+
+// historyOf returns the history of the journal, nil if it has none.
+func historyOf(all []store.Activity) history {
+	if len(all) == 0 {
+		return nil
+	}
+	h := make(history)
+	// The activity is newest first.
+	for i := len(all) - 1; i >= 0; i-- {
+		a := all[i]
+		h[a.Entry.ID] = append(h[a.Entry.ID], a)
+	}
+	return h
+}
+
+// toMarkdown renders the entries as a document with a heading for each
+// collection, nested collections under their parent, and the history of
+// each entry listed under it. Markdown is for reading, it does not import.
+func toMarkdown(entries []*entry.Entry, h history, exported time.Time) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "# bujo\n\n_exported %s_\n", exported.Format("January 2, 2006 3:04pm"))
+
+	names, byName := grouped(entries)
+	headed := make(map[string]bool)
+	for _, name := range names {
+		// Parents without entries still head their children.
+		parts := strings.Split(name, collectionSeparator)
+		for i := range parts {
+			path := strings.Join(parts[:i+1], collectionSeparator)
+			if headed[path] {
+				continue
+			}
+			headed[path] = true
+			level := i + 2
+			if level > 6 {
+				level = 6
+			}
+			fmt.Fprintf(b, "\n%s %s\n\n", strings.Repeat("#", level), parts[i])
+		}
+
+		for _, e := range byName[name] {
+			if e.Bullet == glyph.Occurrence {
+				continue
+			}
+			lines := strings.Split(printers.MarkdownEntry(e), "\n")
+			b.WriteString(lines[0] + "\n")
+			for _, line := range lines[1:] {
+				fmt.Fprintf(b, "  %s\n", line)
+			}
+			for _, a := range h[e.ID] {
+				fmt.Fprintf(b, "  - _%s %s_\n", a.Kind, a.At.Local().Format(layoutHistory))
+			}
+		}
+	}
+	return b.Bytes()
+}
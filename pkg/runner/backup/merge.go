@@ -0,0 +1,210 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Merge policies for imported entries that are already in the journal.
+const (
+	// MergeNewest keeps the import if the journal has not changed the entry
+	// since the export was made, and the journal's entry otherwise.
+	MergeNewest = "newest-wins"
+	// MergeLocal keeps the journal's entry.
+	MergeLocal = "prefer-local"
+	// MergeImport replaces the journal's entry with the import.
+	MergeImport = "prefer-import"
+	// MergeInteractive asks for each entry.
+	MergeInteractive = "interactive"
+)
+
+// Merges are the merge policies.
+var Merges = []string{MergeNewest, MergeLocal, MergeImport, MergeInteractive}
+
+// ValidMerge returns an error if policy is not one of Merges. Empty is
+// MergeNewest.
+func ValidMerge(policy string) error {
+	if policy == "" {
+		return nil
+	}
+	for _, m := range Merges {
+		if policy == m {
+			return nil
+		}
+	}
+	return app.Invalid("merge", policy, "expected "+strings.Join(Merges, ", "))
+}
+
+// merged is what an import does with each entry, and the summary of it.
+type merged struct {
+	// store are the entries to write.
+	store []*entry.Entry
+	// replace are the journal's entries that imports in another collection
+	// replace, they are deleted.
+	replace []*entry.Entry
+
+	added, same, imported, kept, both int
+}
+
+func (m *merged) String() string {
+	parts := []string{fmt.Sprintf("%d new", m.added)}
+	if m.same > 0 {
+		parts = append(parts, fmt.Sprintf("%d unchanged", m.same))
+	}
+	if m.imported > 0 {
+		parts = append(parts, fmt.Sprintf("%d replaced by the import", m.imported))
+	}
+	if m.kept > 0 {
+		parts = append(parts, fmt.Sprintf("%d kept from the journal", m.kept))
+	}
+	if m.both > 0 {
+		parts = append(parts, fmt.Sprintf("%d kept both", m.both))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// merger decides what to do with imported entries that are already in the
+// journal, by id.
+type merger struct {
+	policy string
+	// exported is when the import was made.
+	exported time.Time
+	local    map[string]*entry.Entry
+	// changed is when each entry in the journal last changed, if known.
+	changed map[string]time.Time
+	in      *bufio.Reader
+}
+
+func newMerger(ctx context.Context, p store.Persistence, policy string, exported time.Time, in io.Reader) *merger {
+	m := &merger{
+		policy:   policy,
+		exported: exported,
+		local:    make(map[string]*entry.Entry),
+		changed:  make(map[string]time.Time),
+		in:       bufio.NewReader(in),
+	}
+	if m.policy == "" {
+		m.policy = MergeNewest
+	}
+	for _, e := range p.ListAll(ctx) {
+		m.local[e.ID] = e
+		m.changed[e.ID] = e.Created.Time
+	}
+	if h, ok := p.(store.Historian); ok && m.policy == MergeNewest {
+		for _, a := range h.Activity(ctx, time.Time{}, time.Now().Add(time.Minute)) {
+			if a.At.After(m.changed[a.Entry.ID]) {
+				m.changed[a.Entry.ID] = a.At
+			}
+		}
+	}
+	return m
+}
+
+// merge sorts the imported entries into what to write and what to replace.
+func (m *merger) merge(entries []*entry.Entry) (*merged, error) {
+	r := &merged{}
+	for _, e := range entries {
+		local, ok := m.local[e.ID]
+		if e.ID == "" || !ok {
+			r.added++
+			r.store = append(r.store, e)
+			continue
+		}
+		if same(local, e) {
+			r.same++
+			continue
+		}
+
+		keep, err := m.pick(local, e)
+		if err != nil {
+			return nil, err
+		}
+		switch keep {
+		case MergeLocal:
+			r.kept++
+		case MergeImport:
+			r.imported++
+			r.store = append(r.store, e)
+			if local.Collection != e.Collection {
+				r.replace = append(r.replace, local)
+			}
+		default:
+			r.both++
+			// An id made from the entry would be the id it already has.
+			id := make([]byte, 8)
+			if _, err := rand.Read(id); err != nil {
+				return nil, err
+			}
+			e.ID = fmt.Sprintf("%x", id)
+			r.store = append(r.store, e)
+		}
+	}
+	return r, nil
+}
+
+// pick returns which of the versions to keep, MergeLocal, MergeImport or
+// "both".
+func (m *merger) pick(local, imported *entry.Entry) (string, error) {
+	switch m.policy {
+	case MergeLocal:
+		return MergeLocal, nil
+	case MergeImport:
+		return MergeImport, nil
+	case MergeInteractive:
+		return m.ask(local, imported)
+	default:
+		if m.changed[local.ID].After(m.exported) {
+			return MergeLocal, nil
+		}
+		return MergeImport, nil
+	}
+}
+
+// ask shows both versions and reads which to keep.
+func (m *merger) ask(local, imported *entry.Entry) (string, error) {
+	fmt.Printf("\n%s is in the journal and the import:\n", local.ID)
+	fmt.Printf("  journal: %s  (%s)\n", local.String(), local.Collection)
+	fmt.Printf("  import:  %s  (%s)\n", imported.String(), imported.Collection)
+	for {
+		fmt.Print("Keep the [j]ournal's, the [i]mport or [b]oth? ")
+		answer, err := m.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "j", "journal":
+			return MergeLocal, nil
+		case "i", "import":
+			return MergeImport, nil
+		case "b", "both":
+			return "both", nil
+		}
+		if err == io.EOF {
+			fmt.Println("")
+			return "", fmt.Errorf("no answer for %s", local.ID)
+		}
+	}
+}
+
+// same returns true if the entries are the same apart from their id.
+func same(a, b *entry.Entry) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// opml is an OPML 2.0 document.
+type opml struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []outline `xml:"outline"`
+}
+
+// outline is a node of an OPML document, a collection or an entry. The
+// properties of an entry are its other attributes.
+type outline struct {
+	Text     string     `xml:"text,attr"`
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Outlines []outline  `xml:"outline"`
+}
+
+// toOPML renders the entries with a node for each collection.
+func toOPML(entries []*entry.Entry, exported time.Time) ([]byte, error) {
+	doc := opml{
+		Version: "2.0",
+		Head:    opmlHead{Title: "bujo", DateCreated: exported.Format(time.RFC1123Z)},
+	}
+	names, byName := grouped(entries)
+	for _, name := range names {
+		c := outline{Text: name}
+		for _, e := range byName[name] {
+			o := outline{Text: e.Message}
+			for _, p := range properties(e) {
+				o.Attrs = append(o.Attrs, xml.Attr{Name: xml.Name{Local: p.name}, Value: p.value})
+			}
+			c.Outlines = append(c.Outlines, o)
+		}
+		doc.Body.Outlines = append(doc.Body.Outlines, c)
+	}
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// fromOPML reads the entries of an OPML document. Top level nodes are
+// collections, the nodes under them entries, deeper nodes are flattened
+// into the collection as the journal has no nesting.
+func fromOPML(b []byte) ([]*entry.Entry, error) {
+	doc := opml{}
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("not an OPML document: %v", err)
+	}
+	entries := make([]*entry.Entry, 0)
+	var walk func(name string, nodes []outline) error
+	walk = func(name string, nodes []outline) error {
+		for _, o := range nodes {
+			e := newOutlined(name, o.Text)
+			for _, a := range o.Attrs {
+				if err := setProperty(e, a.Name.Local, a.Value); err != nil {
+					return fmt.Errorf("%s %q: %w", name, o.Text, err)
+				}
+			}
+			entries = append(entries, e)
+			if err := walk(name, o.Outlines); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, c := range doc.Body.Outlines {
+		if err := walk(c.Text, c.Outlines); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
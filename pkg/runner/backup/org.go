@@ -0,0 +1,125 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// orgKeywords are the org todo keywords for bullets, the rest have none.
+var orgKeywords = map[glyph.Bullet]string{
+	glyph.Task:       "TODO",
+	glyph.Waiting:    "WAIT",
+	glyph.Completed:  "DONE",
+	glyph.Irrelevant: "CANCELED",
+}
+
+// toOrg renders the entries with a heading for each collection. The first
+// line of a message is its heading, the rest follows the properties.
+func toOrg(entries []*entry.Entry, exported time.Time) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "#+TITLE: bujo\n#+DATE: %s\n#+TODO: TODO WAIT | DONE CANCELED\n", exported.Format("[2006-01-02 Mon 15:04]"))
+
+	names, byName := grouped(entries)
+	for _, name := range names {
+		fmt.Fprintf(b, "* %s\n", name)
+		for _, e := range byName[name] {
+			lines := strings.Split(e.Message, "\n")
+			heading := lines[0]
+			if k, ok := orgKeywords[e.Bullet]; ok {
+				heading = k + " " + heading
+			}
+			fmt.Fprintf(b, "** %s\n:PROPERTIES:\n", heading)
+			for _, p := range properties(e) {
+				fmt.Fprintf(b, ":%s: %s\n", strings.ToUpper(p.name), p.value)
+			}
+			b.WriteString(":END:\n")
+			for _, line := range lines[1:] {
+				// Keep body lines from being read as headings.
+				if strings.HasPrefix(line, "*") || strings.HasPrefix(line, ",") {
+					line = "," + line
+				}
+				fmt.Fprintln(b, line)
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+// fromOrg reads the entries of an org file. Level one headings are
+// collections, deeper headings are entries in them.
+func fromOrg(b []byte) ([]*entry.Entry, error) {
+	entries := make([]*entry.Entry, 0)
+	name := ""
+	var e *entry.Entry
+	// keyword is the one taken off the heading of e.
+	keyword := ""
+	inProperties := false
+
+	done := func() {
+		if e == nil {
+			return
+		}
+		// A heading can start with a keyword that is not its bullet's.
+		if keyword != "" && orgKeywords[e.Bullet] != keyword {
+			e.Message = keyword + " " + e.Message
+		}
+		entries = append(entries, e)
+		e = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "* "):
+			done()
+			name = strings.TrimSpace(text[2:])
+		case strings.HasPrefix(text, "**"):
+			done()
+			if name == "" {
+				return nil, fmt.Errorf("line %d: an entry needs a collection heading above it", line)
+			}
+			heading := strings.TrimSpace(strings.TrimLeft(text, "*"))
+			keyword = ""
+			for bullet, k := range orgKeywords {
+				if strings.HasPrefix(heading, k+" ") {
+					keyword = k
+					heading = strings.TrimPrefix(heading, k+" ")
+					e = newOutlined(name, heading)
+					e.Bullet = bullet
+				}
+			}
+			if e == nil {
+				e = newOutlined(name, heading)
+			}
+		case e == nil:
+			// Settings and text outside of entries.
+		case text == ":PROPERTIES:":
+			inProperties = true
+		case text == ":END:":
+			inProperties = false
+		case inProperties:
+			parts := strings.SplitN(strings.TrimPrefix(text, ":"), ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("line %d: not a property: %q", line, text)
+			}
+			if err := setProperty(e, parts[0], strings.TrimSpace(parts[1])); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		default:
+			e.Message += "\n" + strings.TrimPrefix(text, ",")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	done()
+	return entries, nil
+}
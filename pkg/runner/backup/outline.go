@@ -0,0 +1,216 @@
+package backup
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// Formats of an export.
+const (
+	FormatJSON = "json"
+	// FormatOPML is an outline with a node per collection and its entries
+	// under it, for outliners like Logseq.
+	FormatOPML = "opml"
+	// FormatOrg is an org-mode file with a heading per collection.
+	FormatOrg = "org"
+	// FormatMarkdown is a document to read, with a heading per collection.
+	// Its task lists import, the rest of an entry does not.
+	FormatMarkdown = "markdown"
+	// FormatCSV is a row per entry, for spreadsheets. It can not be
+	// imported.
+	FormatCSV = "csv"
+	// FormatTodoTxt is a todo.txt file, a task a line. It can only be
+	// imported.
+	FormatTodoTxt = "todotxt"
+)
+
+// formatAliases are other names a format can be given by.
+var formatAliases = map[string]string{
+	"md":       FormatMarkdown,
+	"todo.txt": FormatTodoTxt,
+}
+
+// Formats are the formats of an export.
+func Formats() []string {
+	return []string{FormatJSON, FormatOPML, FormatOrg, FormatMarkdown, FormatCSV}
+}
+
+// Imports are the formats that can be imported.
+func Imports() []string {
+	return []string{FormatJSON, FormatOPML, FormatOrg, FormatMarkdown, FormatTodoTxt}
+}
+
+// formatName returns the format an alias is for, or format.
+func formatName(format string) string {
+	if f, ok := formatAliases[strings.ToLower(format)]; ok {
+		return f
+	}
+	return format
+}
+
+// FormatOf returns the format of a file from its extension, json if it is
+// not one of the others.
+func FormatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".opml":
+		return FormatOPML
+	case ".org":
+		return FormatOrg
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".csv":
+		return FormatCSV
+	case ".txt":
+		return FormatTodoTxt
+	default:
+		return FormatJSON
+	}
+}
+
+// ValidFormat checks format is one of Formats.
+func ValidFormat(format string) error {
+	return oneOf(format, Formats())
+}
+
+// ValidImport checks format is one of Imports.
+func ValidImport(format string) error {
+	return oneOf(format, Imports())
+}
+
+func oneOf(format string, formats []string) error {
+	for _, f := range formats {
+		if format == f {
+			return nil
+		}
+	}
+	return app.Invalid("format", format, fmt.Sprintf("expected one of %s", strings.Join(formats, ", ")))
+}
+
+// property is a field of an entry kept in an outline as text, so the entry
+// can be imported again as it was.
+type property struct {
+	name  string
+	value string
+}
+
+// properties returns the fields of e an outline keeps besides its message
+// and collection, fields that are not set are left out.
+func properties(e *entry.Entry) []property {
+	ps := []property{
+		{"id", e.ID},
+		{"bullet", string(e.Bullet)},
+		{"created", entry.FormatTime(e.Created.Time)},
+	}
+	add := func(name, value string) {
+		if value != "" {
+			ps = append(ps, property{name, value})
+		}
+	}
+	stamp := func(t *entry.Timestamp) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return entry.FormatTime(t.Time)
+	}
+	if e.Signifier != glyph.None {
+		add("signifier", string(e.Signifier))
+	}
+	if e.Order != 0 {
+		add("order", strconv.FormatInt(e.Order, 10))
+	}
+	add("on", stamp(e.On))
+	add("label", string(e.Label))
+	add("waitingOn", e.WaitingOn)
+	add("followUp", stamp(e.FollowUp))
+	add("reviewOn", stamp(e.ReviewOn))
+	add("expires", stamp(e.Expires))
+	add("calendarUid", e.CalendarUID)
+	if e.Private {
+		add("private", "true")
+	}
+	if e.Pinned {
+		add("pinned", "true")
+	}
+	return ps
+}
+
+// newOutlined returns the entry for a node of an outline, before its
+// properties are set. Nodes that were not exported by bujo stay notes
+// created now.
+func newOutlined(name, message string) *entry.Entry {
+	return entry.New(name, glyph.Note, message)
+}
+
+// setProperty sets a field of e read from an outline, names are matched
+// without case.
+func setProperty(e *entry.Entry, name, value string) error {
+	stamp := func() (*entry.Timestamp, error) {
+		t, err := entry.ParseTime(value)
+		if err != nil {
+			return nil, app.Invalid(name, value, "expected an RFC 3339 time")
+		}
+		return &entry.Timestamp{Time: t}, nil
+	}
+	var err error
+	switch strings.ToLower(name) {
+	case "id":
+		e.ID = value
+	case "bullet":
+		if _, ok := glyph.DefaultBullets()[glyph.Bullet(value)]; !ok {
+			return fmt.Errorf("%w: %q", app.ErrInvalidBullet, value)
+		}
+		e.Bullet = glyph.Bullet(value)
+	case "created":
+		var t *entry.Timestamp
+		if t, err = stamp(); err == nil {
+			e.Created = *t
+		}
+	case "signifier":
+		e.Signifier = glyph.Signifier(value)
+	case "order":
+		if e.Order, err = strconv.ParseInt(value, 10, 64); err != nil {
+			err = app.Invalid(name, value, "expected a number")
+		}
+	case "on":
+		e.On, err = stamp()
+	case "label":
+		e.Label, err = glyph.LabelFor(value)
+	case "waitingon":
+		e.WaitingOn = value
+	case "followup":
+		e.FollowUp, err = stamp()
+	case "reviewon":
+		e.ReviewOn, err = stamp()
+	case "expires":
+		e.Expires, err = stamp()
+	case "calendaruid":
+		e.CalendarUID = value
+	case "private":
+		e.Private = value == "true"
+	case "pinned":
+		e.Pinned = value == "true"
+	}
+	return err
+}
+
+// grouped returns the entries by collection, with the collections in
+// journal order.
+func grouped(entries []*entry.Entry) ([]string, map[string][]*entry.Entry) {
+	byName := make(map[string][]*entry.Entry)
+	names := make([]string, 0)
+	for _, e := range entries {
+		if _, ok := byName[e.Collection]; !ok {
+			names = append(names, e.Collection)
+		}
+		byName[e.Collection] = append(byName[e.Collection], e)
+	}
+	collection.Sort(names)
+	return names, byName
+}
@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Signer signs exports and verifies them on import.
+type Signer interface {
+	// Sign writes the signature of the file to SignatureOf(file).
+	Sign(ctx context.Context, file string) error
+	// Verify returns an error if SignatureOf(file) is not a good signature
+	// of the file.
+	Verify(ctx context.Context, file string) error
+}
+
+// SignatureOf returns where the signature of the file is kept.
+func SignatureOf(file string) string {
+	return file + ".minisig"
+}
+
+// Minisign signs with the minisign tool, https://jedisct1.github.io/minisign/
+type Minisign struct {
+	// SecretKey is the path of the key to sign with.
+	SecretKey string
+	// PublicKey is the path of the key to verify with.
+	PublicKey string
+}
+
+func (m *Minisign) Sign(ctx context.Context, file string) error {
+	if m.SecretKey == "" {
+		return fmt.Errorf("no secret key to sign with")
+	}
+	cmd := exec.CommandContext(ctx, "minisign", "-S", "-s", m.SecretKey, "-m", file, "-x", SignatureOf(file))
+	// minisign asks for the password of the key.
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	return run(cmd)
+}
+
+func (m *Minisign) Verify(ctx context.Context, file string) error {
+	if m.PublicKey == "" {
+		return fmt.Errorf("no public key to verify with")
+	}
+	return run(exec.CommandContext(ctx, "minisign", "-V", "-q", "-p", m.PublicKey, "-m", file, "-x", SignatureOf(file)))
+}
+
+func run(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %v: %s", cmd.Args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
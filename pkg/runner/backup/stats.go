@@ -0,0 +1,180 @@
+package backup
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Formats of a stats export.
+const (
+	StatsCSV   = "csv"
+	StatsJSONL = "jsonl"
+)
+
+// statsHeader are the columns of a csv stats export.
+var statsHeader = []string{"id", "collection", "bullet", "signifier", "event", "at", "created", "age_seconds", "tags"}
+
+// StatsRecord is something that happened to an entry, a row of a stats
+// export.
+type StatsRecord struct {
+	ID         string    `json:"id"`
+	Collection string    `json:"collection"`
+	Bullet     string    `json:"bullet"`
+	Signifier  string    `json:"signifier,omitempty"`
+	Event      string    `json:"event"`
+	At         time.Time `json:"at"`
+	Created    time.Time `json:"created"`
+	// AgeSeconds is how long after the entry was created the event was, how
+	// long a task took to complete for a completed event.
+	AgeSeconds int64    `json:"age_seconds"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// ExportStats writes what happened to each entry over a window, a record
+// per event, for notebooks and spreadsheets to analyze.
+type ExportStats struct {
+	// File is written, stdout if empty.
+	File string
+	// Format is StatsCSV or StatsJSONL, defaults to the extension of the
+	// file, or csv.
+	Format string
+	// Since is the start of the window, zero for all of the journal.
+	Since          time.Time
+	IncludePrivate bool
+
+	Persistence store.Persistence
+}
+
+func (n *ExportStats) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not export stats, no persistence")
+	}
+	h, ok := n.Persistence.(store.Historian)
+	if !ok {
+		return fmt.Errorf("stats are %w", app.ErrUnsupported)
+	}
+	format, err := n.format()
+	if err != nil {
+		return err
+	}
+
+	all := h.Activity(ctx, n.Since, time.Now().Add(time.Minute))
+	records := make([]StatsRecord, 0, len(all))
+	// The activity is newest first.
+	for i := len(all) - 1; i >= 0; i-- {
+		a := all[i]
+		if a.Entry.Private && !n.IncludePrivate {
+			continue
+		}
+		records = append(records, statsRecord(a))
+	}
+
+	var out io.Writer = os.Stdout
+	if n.File != "" {
+		f, err := os.Create(n.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if format == StatsJSONL {
+		err = writeJSONL(out, records)
+	} else {
+		err = writeStatsCSV(out, records)
+	}
+	if err != nil {
+		return err
+	}
+	if n.File != "" {
+		fmt.Printf("wrote %d events to %s\n", len(records), n.File)
+	}
+	return nil
+}
+
+// format is the format to write, checked.
+func (n *ExportStats) format() (string, error) {
+	format := strings.ToLower(n.Format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(n.File)) {
+		case ".jsonl", ".ndjson":
+			format = StatsJSONL
+		default:
+			format = StatsCSV
+		}
+	}
+	switch format {
+	case StatsCSV, StatsJSONL:
+		return format, nil
+	case "parquet":
+		return "", app.Invalid("format", format, "parquet is not built in, use csv or jsonl and convert it, pandas and duckdb read both")
+	}
+	return "", app.Invalid("format", format, "expected csv or jsonl")
+}
+
+func statsRecord(a store.Activity) StatsRecord {
+	e := a.Entry
+	r := StatsRecord{
+		ID:         e.ID,
+		Collection: e.Collection,
+		Bullet:     string(e.Bullet),
+		Event:      a.Kind,
+		At:         a.At,
+		Created:    e.Created.Time,
+		AgeSeconds: int64(a.At.Sub(e.Created.Time) / time.Second),
+		Tags:       e.Tags(),
+	}
+	if e.Signifier != glyph.None {
+		r.Signifier = string(e.Signifier)
+	}
+	return r
+}
+
+func writeStatsCSV(out io.Writer, records []StatsRecord) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(statsHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			r.ID,
+			r.Collection,
+			r.Bullet,
+			r.Signifier,
+			r.Event,
+			entry.FormatTime(r.At),
+			entry.FormatTime(r.Created),
+			strconv.FormatInt(r.AgeSeconds, 10),
+			strings.Join(r.Tags, " "),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeJSONL(out io.Writer, records []StatsRecord) error {
+	enc := json.NewEncoder(out)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
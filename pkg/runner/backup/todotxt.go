@@ -0,0 +1,113 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// layoutTodoTxt is how dates are written in todo.txt.
+const layoutTodoTxt = "2006-01-02"
+
+// fromTodoTxt reads the tasks of a todo.txt file, a task a line like:
+//
+//	x 2026-10-16 2026-10-12 call the bank +house due:2026-10-20
+//	(A) 2026-10-14 book flights @phone
+//
+// Tasks marked x are completed. Priority (A) is the priority signifier,
+// other priorities stay in the message. A task goes to the day log of the
+// day it was created, or of now if it has no date, and due: is when it is
+// on. +projects and @contexts are left in the message.
+func fromTodoTxt(b []byte, now time.Time) ([]*entry.Entry, error) {
+	entries := make([]*entry.Entry, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		e, err := todoTxtTask(text, now)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// todoTxtTask returns the entry of a line of todo.txt.
+func todoTxtTask(text string, now time.Time) (*entry.Entry, error) {
+	fields := strings.Fields(text)
+	bullet, signifier := glyph.Task, glyph.None
+
+	// date takes a date off the front of fields, if there is one.
+	date := func() (time.Time, bool) {
+		if len(fields) == 0 {
+			return time.Time{}, false
+		}
+		t, err := time.ParseInLocation(layoutTodoTxt, fields[0], time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		fields = fields[1:]
+		return t, true
+	}
+
+	var completed *entry.Timestamp
+	if fields[0] == "x" {
+		bullet = glyph.Completed
+		fields = fields[1:]
+		// The completion date is followed by the creation date.
+		if t, ok := date(); ok {
+			completed = &entry.Timestamp{Time: t}
+		}
+	}
+	if len(fields) > 0 && len(fields[0]) == 3 && fields[0][0] == '(' && fields[0][2] == ')' && fields[0][1] >= 'A' && fields[0][1] <= 'Z' {
+		if fields[0] == "(A)" {
+			signifier = glyph.Priority
+			fields = fields[1:]
+		}
+	}
+	created, dated := date()
+	if !dated {
+		created = now
+	}
+
+	words := make([]string, 0, len(fields))
+	var on *entry.Timestamp
+	for _, f := range fields {
+		switch {
+		case strings.HasPrefix(f, "due:"):
+			t, err := time.ParseInLocation(layoutTodoTxt, f[len("due:"):], time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("due date %q is not like %s", f[len("due:"):], layoutTodoTxt)
+			}
+			on = &entry.Timestamp{Time: t}
+		case f == "pri:A" && bullet == glyph.Completed:
+			// Completed tasks keep their priority as a tag.
+			signifier = glyph.Priority
+		default:
+			words = append(words, f)
+		}
+	}
+
+	e := newOutlined(collection.DayOf(created), strings.Join(words, " "))
+	e.Bullet = bullet
+	e.Signifier = signifier
+	e.On = on
+	e.CompletedAt = completed
+	if dated {
+		e.Created = entry.Timestamp{Time: created}
+	}
+	return e, nil
+}
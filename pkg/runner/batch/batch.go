@@ -0,0 +1,286 @@
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Operations accepted in a batch.
+const (
+	OpAdd      = "add"
+	OpComplete = "complete"
+	OpMove     = "move"
+	OpMkdir    = "mkdir"
+)
+
+// Result statuses.
+const (
+	StatusOK      = "ok"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// Op is one line of a batch file.
+type Op struct {
+	Op string `json:"op"`
+	// ID is the id or ref of the entry to complete or move.
+	ID string `json:"id,omitempty"`
+	// Collection is where to add, today if empty, move to or the collection
+	// to make.
+	Collection string `json:"collection,omitempty"`
+	Bullet     string `json:"bullet,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Result is what happened to an Op.
+type Result struct {
+	Line   int    `json:"line"`
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	// ID is the entry that was added, completed or moved to.
+	ID         string `json:"id,omitempty"`
+	Collection string `json:"collection,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Report is written once the batch is done.
+type Report struct {
+	DryRun bool `json:"dryRun"`
+	// Applied is true if the changes were written, a batch is applied all or
+	// nothing.
+	Applied bool     `json:"applied"`
+	Results []Result `json:"results"`
+}
+
+// Batch applies a stream of operations to the journal in one transaction.
+type Batch struct {
+	// In is read for operations, one json object per line.
+	In io.Reader
+	// Out is where the report is written, defaults to stdout.
+	Out    io.Writer
+	DryRun bool
+
+	Persistence store.Persistence
+}
+
+func (n *Batch) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not run batch, no persistence")
+	}
+	t, ok := n.Persistence.(store.Transactor)
+	if !ok {
+		return fmt.Errorf("batch is %w", app.ErrUnsupported)
+	}
+
+	ops, err := read(n.In)
+	if err != nil {
+		return err
+	}
+
+	report := &Report{DryRun: n.DryRun}
+	if n.DryRun {
+		err = runOps(ctx, n.Persistence, discard{}, ops, report)
+	} else {
+		err = t.Transact(ctx, func(tx store.Tx) error {
+			return runOps(ctx, n.Persistence, tx, ops, report)
+		})
+		report.Applied = err == nil
+	}
+
+	if werr := n.write(report); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// Apply applies ops to the journal and reports what happened to each, the
+// Line of a result is the index of its op plus one. With a store that has
+// transactions they are applied all or nothing, otherwise one at a time
+// until one fails.
+func Apply(ctx context.Context, p store.Persistence, ops []Op) (*Report, error) {
+	report := &Report{}
+	var err error
+	if t, ok := p.(store.Transactor); ok {
+		err = t.Transact(ctx, func(tx store.Tx) error {
+			return runOps(ctx, p, tx, ops, report)
+		})
+	} else {
+		err = runOps(ctx, p, direct{p}, ops, report)
+	}
+	report.Applied = err == nil
+	return report, err
+}
+
+// read parses the operations, skipping blank lines.
+func read(in io.Reader) ([]Op, error) {
+	ops := make([]Op, 0)
+	scanner := bufio.NewScanner(in)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			ops = append(ops, Op{})
+			continue
+		}
+		op := Op{}
+		if err := json.Unmarshal([]byte(text), &op); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		ops = append(ops, op)
+	}
+	return ops, scanner.Err()
+}
+
+// runOps applies each op to tx, stopping at the first that fails.
+func runOps(ctx context.Context, p store.Persistence, tx store.Tx, ops []Op, report *Report) error {
+	all := p.ListAll(ctx)
+	for i, op := range ops {
+		if op.Op == "" {
+			continue
+		}
+		r := Result{Line: i + 1, Op: op.Op, Status: StatusOK}
+		added, err := apply(tx, all, op, &r)
+		if err != nil {
+			r.Status = StatusFailed
+			r.Message = err.Error()
+			report.Results = append(report.Results, r)
+			return fmt.Errorf("line %d: %w", r.Line, err)
+		}
+		all = append(all, added...)
+		report.Results = append(report.Results, r)
+	}
+	return nil
+}
+
+// apply applies a single op and returns the entries it added.
+func apply(tx store.Tx, all []*entry.Entry, op Op, r *Result) ([]*entry.Entry, error) {
+	switch op.Op {
+	case OpAdd:
+		if op.Message == "" {
+			return nil, app.Invalid("message", op.Message, "a message is required to add")
+		}
+		bullet := glyph.Task
+		if op.Bullet != "" {
+			var err error
+			if bullet, err = glyph.BulletForAlias(op.Bullet); err != nil {
+				return nil, err
+			}
+		}
+		name := op.Collection
+		if name == "" {
+			name = collection.Today
+		}
+		e := entry.New(collection.Resolve(name), bullet, op.Message)
+		if err := tx.Store(e); err != nil {
+			return nil, err
+		}
+		r.ID, r.Collection = e.ID, e.Collection
+		return []*entry.Entry{e}, nil
+
+	case OpComplete:
+		e, err := ref.Resolve(all, op.ID)
+		if err != nil {
+			return nil, err
+		}
+		r.ID, r.Collection = e.ID, e.Collection
+		if e.Bullet == glyph.Completed {
+			r.Status = StatusSkipped
+			r.Message = "already completed"
+			return nil, nil
+		}
+		if !e.CanComplete() {
+			return nil, app.Invalid("id", op.ID, fmt.Sprintf("only tasks can be completed, it is marked %q", e.Bullet.Glyph().Meaning))
+		}
+		e.Complete()
+		if err := tx.Store(e); err != nil {
+			return nil, err
+		}
+		return nil, nil
+
+	case OpMove:
+		e, err := ref.Resolve(all, op.ID)
+		if err != nil {
+			return nil, err
+		}
+		to := collection.Resolve(op.Collection)
+		if to == "" {
+			return nil, app.Invalid("collection", op.Collection, "a collection is required to move to")
+		}
+		bullet := glyph.MovedCollection
+		if kind, _ := collection.Parse(to); kind == collection.Future {
+			bullet = glyph.MovedFuture
+		}
+		moved := e.Move(bullet, to)
+		if err := tx.Store(e); err != nil {
+			return nil, err
+		}
+		if err := tx.Store(moved); err != nil {
+			return nil, err
+		}
+		r.ID, r.Collection = moved.ID, moved.Collection
+		return []*entry.Entry{moved}, nil
+
+	case OpMkdir:
+		name := collection.Resolve(op.Collection)
+		if name == "" {
+			return nil, app.Invalid("collection", op.Collection, "a collection name is required")
+		}
+		// Collections are made by their first entry, there is nothing to
+		// write.
+		r.Status = StatusSkipped
+		r.Collection = name
+		r.Message = "collections are created with their first entry"
+		return nil, nil
+
+	default:
+		return nil, app.Invalid("op", op.Op, fmt.Sprintf("expected one of %s, %s, %s or %s", OpAdd, OpComplete, OpMove, OpMkdir))
+	}
+}
+
+func (n *Batch) write(report *Report) error {
+	out := n.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// discard is a transaction for a dry run, nothing is written. Entries get
+// the ids they would be stored with.
+type discard struct{}
+
+func (discard) Store(e *entry.Entry) error {
+	store.AssignID(e)
+	return nil
+}
+
+func (discard) Delete(e *entry.Entry) error { return nil }
+
+// direct is a transaction for a store without them, each entry is written
+// as it is stored.
+type direct struct {
+	store.Persistence
+}
+
+func (direct) Delete(e *entry.Entry) error {
+	return fmt.Errorf("deleting entries is %w", app.ErrUnsupported)
+}
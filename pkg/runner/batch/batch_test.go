@@ -0,0 +1,145 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/internal/storetest"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// journal returns a journal with the entries, and a func to remove it.
+func journal(t *testing.T, entries ...*entry.Entry) (store.Persistence, func()) {
+	t.Helper()
+	path, err := ioutil.TempDir("", "bujo-batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := func() { _ = os.RemoveAll(path) }
+	p, err := store.Load(storetest.Dir(path))
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if err := p.Store(e); err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+	}
+	return p, cleanup
+}
+
+func run(t *testing.T, p store.Persistence, dryRun bool, ops string) (*Report, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	err := (&Batch{In: strings.NewReader(ops), Out: out, DryRun: dryRun, Persistence: p}).Do(context.Background())
+	report := &Report{}
+	if jerr := json.Unmarshal(out.Bytes(), report); jerr != nil {
+		t.Fatal(jerr)
+	}
+	return report, err
+}
+
+func TestDryRunAddHasID(t *testing.T) {
+	p, cleanup := journal(t)
+	defer cleanup()
+
+	report, err := run(t, p, true, `{"op":"add","collection":"Inbox","message":"one"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 1 || len(report.Results[0].ID) != 16 {
+		t.Errorf("got %+v, want the id the entry would have", report.Results)
+	}
+	if report.Applied || len(p.ListAll(context.Background())) != 0 {
+		t.Error("a dry run wrote to the journal")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	task := entry.New("Inbox", glyph.Task, "task")
+	waiting := entry.New("Inbox", glyph.Waiting, "waiting")
+	done := entry.New("Inbox", glyph.Completed, "done")
+	note := entry.New("Inbox", glyph.Note, "note")
+	event := entry.New("Inbox", glyph.Event, "event")
+	moved := entry.New("Inbox", glyph.MovedCollection, "moved")
+
+	tests := []struct {
+		name    string
+		e       *entry.Entry
+		invalid bool
+		status  string
+	}{
+		{"task", task, false, StatusOK},
+		{"waiting", waiting, false, StatusOK},
+		{"already completed", done, false, StatusSkipped},
+		{"note", note, true, StatusFailed},
+		{"event", event, true, StatusFailed},
+		{"moved", moved, true, StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, cleanup := journal(t, tt.e)
+			defer cleanup()
+
+			report, err := run(t, p, false, `{"op":"complete","id":"`+tt.e.ID+`"}`)
+			if got := errors.Is(err, app.ErrValidation); got != tt.invalid {
+				t.Fatalf("got %v, want invalid %v", err, tt.invalid)
+			}
+			if len(report.Results) != 1 || report.Results[0].Status != tt.status {
+				t.Fatalf("got %+v, want %s", report.Results, tt.status)
+			}
+			want := tt.e.Bullet
+			if tt.status == StatusOK {
+				want = glyph.Completed
+			}
+			if got := p.ListAll(context.Background())[0].Bullet; got != want {
+				t.Errorf("the entry is %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	one := entry.New("Inbox", glyph.Task, "one")
+	two := entry.New("Inbox", glyph.Task, "two")
+	p, cleanup := journal(t, one, two)
+	defer cleanup()
+	ctx := context.Background()
+
+	report, err := Apply(ctx, p, []Op{
+		{Op: OpMove, ID: one.ID, Collection: "Project"},
+		{Op: OpMove, ID: "0000000000000000", Collection: "Project"},
+		{Op: OpMove, ID: two.ID, Collection: "Project"},
+	})
+	if err == nil {
+		t.Fatal("a missing entry was moved")
+	}
+	if report.Applied || len(report.Results) != 2 || report.Results[1].Line != 2 || report.Results[1].Status != StatusFailed {
+		t.Fatalf("got %+v, want the second op failed", report)
+	}
+	if got := len(p.List(ctx, "Project")); got != 0 {
+		t.Errorf("%d entries moved, want none", got)
+	}
+
+	report, err = Apply(ctx, p, []Op{
+		{Op: OpMove, ID: one.ID, Collection: "Project"},
+		{Op: OpMove, ID: two.ID, Collection: "Project"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Applied || len(p.List(ctx, "Project")) != 2 {
+		t.Errorf("got %+v, want both moved", report)
+	}
+}
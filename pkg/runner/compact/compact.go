@@ -0,0 +1,32 @@
+package compact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+type Compact struct {
+	Persistence store.Persistence
+}
+
+func (n *Compact) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not compact, no persistence")
+	}
+
+	c, ok := n.Persistence.(store.Compactor)
+	if !ok {
+		return fmt.Errorf("compaction is %w", app.ErrUnsupported)
+	}
+
+	converted, err := c.Compact(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("compressed %d entries\n", converted)
+	return nil
+}
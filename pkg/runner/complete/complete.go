@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"tableflip.dev/bujo/pkg/app"
 	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
 	"tableflip.dev/bujo/pkg/store"
 )
 
@@ -20,22 +22,23 @@ func (n *Complete) Do(ctx context.Context) error {
 		return errors.New("can not complete, no persistence")
 	}
 
-	collection := ""
 	all := n.Persistence.ListAll(ctx)
-	for _, e := range all {
-		if e.ID == n.ID {
-			e.Complete()
-			if err := n.Persistence.Store(e); err != nil {
-				return err
-			}
-			collection = e.Collection
-			break
-		}
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	if !e.CanComplete() {
+		return app.Invalid("id", n.ID, fmt.Sprintf("only tasks can be completed, it is marked %q", e.Bullet.Glyph().Meaning))
+	}
+	e.Complete()
+	if err := n.Persistence.Store(e); err != nil {
+		return err
 	}
 
-	all = n.Persistence.List(ctx, collection)
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
 	fmt.Println("")
-	pp.Title(collection)
+	pp.Title(e.Collection)
 	pp.Collection(all...)
 
 	return nil
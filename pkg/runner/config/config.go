@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Set sets a key in the config file.
+type Set struct {
+	Key   string
+	Value string
+}
+
+func (n *Set) Do(ctx context.Context) error {
+	var value interface{} = n.Value
+	switch n.Value {
+	case "true":
+		value = true
+	case "false":
+		value = false
+	}
+	if n.Key == "path" {
+		path, err := Path(n.Value)
+		if err != nil {
+			return err
+		}
+		value = path
+	}
+
+	file, err := store.SetConfig(n.Key, value)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s set to %v in %s\n", n.Key, value, file)
+	return nil
+}
+
+// Get prints a key of the config, after the env and defaults are applied.
+type Get struct {
+	Key string
+}
+
+func (n *Get) Do(ctx context.Context) error {
+	if !viper.IsSet(n.Key) {
+		return fmt.Errorf("%s is not set", n.Key)
+	}
+	fmt.Println(viper.Get(n.Key))
+	return nil
+}
+
+// Path expands and checks a journal path, it returns the absolute path if
+// the journal can be kept there.
+func Path(path string) (string, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return "", err
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return "", err
+	}
+	if err := store.CheckWritable(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
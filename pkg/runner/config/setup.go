@@ -0,0 +1,62 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Setup asks where to keep the journal the first time bujo runs, and writes
+// the answer to the config.
+type Setup struct {
+	// Default is the path used for an empty answer.
+	Default string
+	// In is where answers are read from, defaults to stdin.
+	In io.Reader
+}
+
+func (n *Setup) Do(ctx context.Context) error {
+	in := n.In
+	if in == nil {
+		in = os.Stdin
+	}
+	r := bufio.NewReader(in)
+
+	fmt.Println("There is no journal yet.")
+	for {
+		fmt.Printf("Where should it be kept? [%s] ", n.Default)
+		answer, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		eof := err == io.EOF
+		if eof {
+			fmt.Println("")
+		}
+
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			answer = n.Default
+		}
+		path, err := Path(answer)
+		if err != nil {
+			if eof {
+				return err
+			}
+			fmt.Println(err)
+			continue
+		}
+
+		file, err := store.SetConfig("path", path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The journal is kept in %s, change it with bujo config set path in %s\n\n", path, file)
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package defaultbullet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// DefaultBullet sets the bullet entries captured to a collection get when
+// none is picked.
+type DefaultBullet struct {
+	Collection string
+	// Bullet is set on the collection. Empty shows the current default,
+	// unless Clear is set.
+	Bullet      glyph.Bullet
+	Clear       bool
+	Persistence store.Persistence
+}
+
+func (n *DefaultBullet) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not set default bullet, no persistence")
+	}
+	b, ok := n.Persistence.(store.BulletDefaulter)
+	if !ok {
+		return fmt.Errorf("default bullets are %w", app.ErrUnsupported)
+	}
+
+	switch n.Bullet {
+	case "", glyph.Task, glyph.Note, glyph.Event:
+	default:
+		return app.Invalid("bullet", n.Bullet.Glyph().Meaning, "expected task, note or event")
+	}
+	if n.Bullet != "" && len(n.Persistence.List(ctx, n.Collection)) == 0 {
+		return fmt.Errorf("%w: %s", app.ErrCollectionNotFound, n.Collection)
+	}
+	if n.Bullet != "" || n.Clear {
+		if err := b.SetDefaultBullet(ctx, n.Collection, n.Bullet); err != nil {
+			return err
+		}
+	}
+
+	bullet, ok := b.DefaultBullets(ctx)[n.Collection]
+	if !ok {
+		bullet = glyph.Task
+	}
+	fmt.Printf("%s: %s %s\n", n.Collection, bullet.String(), bullet.Glyph().Meaning)
+	return nil
+}
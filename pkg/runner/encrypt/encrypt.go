@@ -0,0 +1,43 @@
+package encrypt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+type Encrypt struct {
+	Passphrase  string
+	Persistence store.Persistence
+}
+
+func (n *Encrypt) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not encrypt, no persistence")
+	}
+	if n.Passphrase == "" {
+		return app.Invalid("passphrase", "", "set $BUJO_PASSPHRASE or encrypt.passphrase_command in the config")
+	}
+
+	e, ok := n.Persistence.(store.Encrypter)
+	if !ok {
+		return fmt.Errorf("encryption is %w", app.ErrUnsupported)
+	}
+
+	converted, err := e.Encrypt(ctx, n.Passphrase)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("encrypted %d entries\n", converted)
+
+	// New entries are encrypted from now on.
+	file, err := store.SetConfig("encrypt.enabled", true)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("encrypt.enabled set in %s\n", file)
+	return nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"tableflip.dev/bujo/pkg/collection"
 	"tableflip.dev/bujo/pkg/entry"
 	"tableflip.dev/bujo/pkg/glyph"
 	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/rollup"
 	"tableflip.dev/bujo/pkg/store"
 	"time"
 )
@@ -16,24 +19,27 @@ type Get struct {
 	ListCollections bool
 	CalendarView    bool
 	// used for calendar view
-	On          time.Time
-	Bullet      glyph.Bullet
-	Collection  string
-	Persistence store.Persistence
+	On              time.Time
+	CalendarOptions *printers.CalendarOptions
+	Bullet          glyph.Bullet
+	Label           glyph.Label
+	ShowHidden      bool // include expired notes.
+	ShowDoneTime    bool // show when completed tasks were completed.
+	Collection      string
+	Persistence     store.Persistence
+
+	icons map[string]string
 }
 
-// TODO: make the today logic a base thing or something.
-const (
-	layoutISO     = "2006-01-02"
-	layoutUS      = "January 2, 2006"
-	layoutUSMonth = "January, 2006"
-)
-
 func (n *Get) Do(ctx context.Context) error {
 	if n.Persistence == nil {
 		return errors.New("can not get, no persistence")
 	}
 
+	if i, ok := n.Persistence.(store.Iconer); ok {
+		n.icons = i.Icons(ctx)
+	}
+
 	if n.ListCollections {
 		return n.listCollections(ctx)
 	}
@@ -44,27 +50,31 @@ func (n *Get) Do(ctx context.Context) error {
 
 	switch n.Bullet {
 	case glyph.Occurrence:
-		if n.Collection == "today" {
-			n.Collection = time.Now().Format(layoutUSMonth)
+		if n.Collection == collection.Today {
+			n.Collection = collection.MonthOf(time.Now())
 		}
 		return n.asTrack(ctx)
 	default:
-		if n.Collection == "today" {
-			n.Collection = time.Now().Format(layoutUS)
-		}
+		n.Collection = collection.Resolve(n.Collection)
 		return n.asCollection(ctx)
 	}
 }
 
 func (n *Get) listCollections(ctx context.Context) error {
-	pp := printers.PrettyPrint{} // show id not supported for tracks yet.
+	pp := printers.PrettyPrint{Icons: n.icons} // show id not supported for tracks yet.
 
 	fmt.Println("")
 
 	m := n.Persistence.MapAll(ctx)
 
-	for collection, entries := range m {
-		pp.TitleWithCount(collection, len(entries))
+	names := make([]string, 0, len(m))
+	for c := range m {
+		names = append(names, c)
+	}
+	collection.Sort(names)
+
+	for _, c := range names {
+		pp.TitleWithCount(c, len(m[c]))
 		pp.NewLine()
 	}
 
@@ -77,7 +87,7 @@ func (n *Get) asTrack(ctx context.Context) error {
 		return errors.New("a collection is required for trackers")
 	}
 
-	pp := printers.PrettyPrint{} // show id not supported for tracks yet.
+	pp := printers.PrettyPrint{Icons: n.icons} // show id not supported for tracks yet.
 
 	fmt.Println("")
 
@@ -94,7 +104,7 @@ func (n *Get) asCalendar(ctx context.Context, on time.Time) error {
 		return errors.New("a collection is required for calendar view")
 	}
 
-	pp := printers.PrettyPrint{} // show id not supported for tracks yet.
+	pp := printers.PrettyPrint{CalendarOptions: n.CalendarOptions, Icons: n.icons} // show id not supported for tracks yet.
 
 	fmt.Println("")
 
@@ -108,13 +118,20 @@ func (n *Get) asCalendar(ctx context.Context, on time.Time) error {
 }
 
 func (n *Get) asCollection(ctx context.Context) error {
-	pp := printers.PrettyPrint{ShowID: n.ShowID}
+	pp := printers.PrettyPrint{ShowID: n.ShowID, Icons: n.icons}
+	if n.ShowID {
+		pp.Refs = ref.Refs(n.Persistence.ListAll(ctx))
+	}
+	pp.ShowDoneTime = n.ShowDoneTime
 
 	fmt.Println("")
 
 	if n.Collection != "" {
 		all := n.Persistence.List(ctx, n.Collection)
 		all = n.filtered(all)
+		if rollup.Any(all) {
+			pp.All = n.Persistence.ListAll(ctx)
+		}
 
 		pp.Title(n.Collection)
 		pp.Collection(all...)
@@ -123,8 +140,22 @@ func (n *Get) asCollection(ctx context.Context) error {
 	}
 
 	allm := n.Persistence.MapAll(ctx)
-	for c, all := range allm {
-		all = n.filtered(all)
+	names := make([]string, 0, len(allm))
+	for c := range allm {
+		names = append(names, c)
+		if pp.All == nil && rollup.Any(allm[c]) {
+			pp.All = make([]*entry.Entry, 0)
+		}
+	}
+	if pp.All != nil {
+		for _, c := range names {
+			pp.All = append(pp.All, allm[c]...)
+		}
+	}
+	collection.Sort(names)
+
+	for _, c := range names {
+		all := n.filtered(allm[c])
 		if len(all) == 0 {
 			continue
 		}
@@ -136,8 +167,15 @@ func (n *Get) asCollection(ctx context.Context) error {
 }
 
 func (n *Get) filtered(all []*entry.Entry) []*entry.Entry {
+	now := time.Now()
 	c := make([]*entry.Entry, 0, len(all))
 	for _, a := range all {
+		if !n.ShowHidden && a.Expired(now) {
+			continue
+		}
+		if n.Label != glyph.NoLabel && n.Label != a.Label {
+			continue
+		}
 		if n.Bullet == glyph.Any || n.Bullet == a.Bullet {
 			c = append(c, a)
 		}
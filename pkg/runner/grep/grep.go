@@ -0,0 +1,176 @@
+package grep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/fatih/color"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Grep prints the entries with a message matching a pattern, with the
+// entries around them in their collection for context, like grep.
+type Grep struct {
+	Pattern *regexp.Regexp
+	// Collections limits the search to these collections, if set.
+	Collections []string
+	// State limits the search to bullets in a state, see rollup.State.
+	State func(glyph.Bullet) bool
+	// Bullet limits the search to a kind of bullet, glyph.Any for all.
+	Bullet glyph.Bullet
+	// Since leaves out entries created before it, if set.
+	Since time.Time
+	// Context is how many entries before and after a match are shown.
+	Context int
+	// JSON prints the matches as a json array.
+	JSON bool
+	// ShowID shows the ref of each entry, to jump to it with other commands.
+	ShowID bool
+
+	Persistence store.Persistence
+}
+
+// Match is a matching entry in the json output.
+type Match struct {
+	Line
+	Before []Line `json:"before,omitempty"`
+	After  []Line `json:"after,omitempty"`
+}
+
+// Line is an entry in the json output.
+type Line struct {
+	ID         string       `json:"id"`
+	Ref        string       `json:"ref,omitempty"`
+	Collection string       `json:"collection"`
+	Bullet     glyph.Bullet `json:"bullet"`
+	Created    time.Time    `json:"created"`
+	Message    string       `json:"message"`
+}
+
+func (n *Grep) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not grep, no persistence")
+	}
+
+	var refs map[string]string
+	if n.ShowID || n.JSON {
+		refs = ref.Refs(n.Persistence.ListAll(ctx))
+	}
+
+	matches := make([]Match, 0)
+	for _, c := range n.collections(ctx) {
+		entries := n.Persistence.List(ctx, c)
+		for i, e := range entries {
+			if !n.matches(e) {
+				continue
+			}
+			m := Match{Line: line(e, refs)}
+			for j := i - n.Context; j < i; j++ {
+				if j >= 0 {
+					m.Before = append(m.Before, line(entries[j], refs))
+				}
+			}
+			for j := i + 1; j <= i+n.Context && j < len(entries); j++ {
+				m.After = append(m.After, line(entries[j], refs))
+			}
+			matches = append(matches, m)
+		}
+	}
+
+	if n.JSON {
+		b, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	n.print(matches)
+	return nil
+}
+
+// collections are the collections to search, in order.
+func (n *Grep) collections(ctx context.Context) []string {
+	if len(n.Collections) > 0 {
+		return n.Collections
+	}
+	all := n.Persistence.Collections(ctx, "")
+	collection.Sort(all)
+	return all
+}
+
+func (n *Grep) matches(e *entry.Entry) bool {
+	if e.Bullet == glyph.Occurrence {
+		return false
+	}
+	if n.Bullet != glyph.Any && e.Bullet != n.Bullet {
+		return false
+	}
+	if n.State != nil && !n.State(e.Bullet) {
+		return false
+	}
+	if !n.Since.IsZero() && e.Created.Time.Before(n.Since) {
+		return false
+	}
+	return n.Pattern.MatchString(e.Message)
+}
+
+func line(e *entry.Entry, refs map[string]string) Line {
+	return Line{
+		ID:         e.ID,
+		Ref:        refs[e.ID],
+		Collection: e.Collection,
+		Bullet:     e.Bullet,
+		Created:    e.Created.Time,
+		Message:    e.Message,
+	}
+}
+
+// print writes matches like grep: "collection:" before a match,
+// "collection-" before context and "--" between matches with context.
+func (n *Grep) print(matches []Match) {
+	hit := color.New(color.FgRed, color.Bold)
+	faint := color.New(color.Faint)
+	sep := color.New(color.FgCyan)
+
+	printLine := func(l Line, mark string, matched bool) {
+		if n.ShowID {
+			_, _ = faint.Printf("%s ", l.Ref)
+		}
+		_, _ = sep.Printf("%s%s ", l.Collection, mark)
+		if !matched {
+			_, _ = faint.Printf("%s %s\n", l.Bullet.String(), l.Message)
+			return
+		}
+		fmt.Printf("%s ", l.Bullet.String())
+		last := 0
+		for _, loc := range n.Pattern.FindAllStringIndex(l.Message, -1) {
+			fmt.Print(l.Message[last:loc[0]])
+			_, _ = hit.Print(l.Message[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		fmt.Println(l.Message[last:])
+	}
+
+	for i, m := range matches {
+		if n.Context > 0 && i > 0 {
+			_, _ = sep.Println("--")
+		}
+		for _, l := range m.Before {
+			printLine(l, "-", false)
+		}
+		printLine(m.Line, ":", true)
+		for _, l := range m.After {
+			printLine(l, "-", false)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package icon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+type Icon struct {
+	Collection string
+	// Icon is set on the collection. Empty shows the current icon, unless
+	// Clear is set.
+	Icon        string
+	Clear       bool
+	Persistence store.Persistence
+}
+
+func (n *Icon) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not set icon, no persistence")
+	}
+	i, ok := n.Persistence.(store.Iconer)
+	if !ok {
+		return fmt.Errorf("collection icons are %w", app.ErrUnsupported)
+	}
+
+	if n.Icon != "" && len(n.Persistence.List(ctx, n.Collection)) == 0 {
+		return fmt.Errorf("%w: %s", app.ErrCollectionNotFound, n.Collection)
+	}
+	if n.Icon != "" || n.Clear {
+		if err := i.SetIcon(ctx, n.Collection, n.Icon); err != nil {
+			return err
+		}
+	}
+
+	pp := printers.PrettyPrint{Icons: i.Icons(ctx)}
+	fmt.Println("")
+	pp.TitleWithCount(n.Collection, len(n.Persistence.List(ctx, n.Collection)))
+
+	return nil
+}
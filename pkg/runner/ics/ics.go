@@ -0,0 +1,110 @@
+package ics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"tableflip.dev/bujo/pkg/caldav"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/logging"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// calendarName is what calendar apps call the feed.
+const calendarName = "bujo"
+
+// ICS writes the calendar feed of the journal to a file.
+type ICS struct {
+	// Out is the file written, stdout if empty.
+	Out            string
+	IncludePrivate bool
+
+	Persistence store.Persistence
+}
+
+func (n *ICS) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not export a calendar, no persistence")
+	}
+
+	var out io.Writer = os.Stdout
+	if n.Out != "" {
+		f, err := os.Create(n.Out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	count, err := ExportICS(ctx, n.Persistence, out, n.IncludePrivate)
+	if err != nil {
+		return err
+	}
+	if n.Out != "" {
+		fmt.Printf("wrote %d events to %s\n", count, n.Out)
+	}
+	return nil
+}
+
+// ExportICS writes the calendar feed of the journal to w and returns how
+// many events it has: open entries with a day, like a task on a date or a
+// follow up, and the events of day logs. Private entries are left out
+// unless includePrivate.
+func ExportICS(ctx context.Context, p store.Persistence, w io.Writer, includePrivate bool) (int, error) {
+	items := make([]caldav.Item, 0)
+	for _, e := range p.ListAll(ctx) {
+		if e.Private && !includePrivate {
+			continue
+		}
+		if day, ok := dayOf(e); ok {
+			// The uid is the entry, a calendar that subscribed updates it.
+			items = append(items, caldav.Item{UID: e.ID + "@bujo", Summary: e.Message, Day: day})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Day.Before(items[j].Day)
+	})
+	_, err := w.Write(caldav.Feed(calendarName, items))
+	return len(items), err
+}
+
+// dayOf is the day of the entry in the calendar, if it has one.
+func dayOf(e *entry.Entry) (time.Time, bool) {
+	if due := store.Due(e); due != nil {
+		return due.Time, true
+	}
+	if e.Bullet != glyph.Event {
+		return time.Time{}, false
+	}
+	kind, day := collection.Parse(e.Collection)
+	return day, kind == collection.Day
+}
+
+// Feed serves the calendar feed, read from the journal on each request, for
+// calendar apps to subscribe to.
+type Feed struct {
+	IncludePrivate bool
+
+	Persistence store.Persistence
+}
+
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="bujo.ics"`)
+	if _, err := ExportICS(r.Context(), f.Persistence, w, f.IncludePrivate); err != nil {
+		logging.Warn("writing the calendar feed", "err", err)
+	}
+}
@@ -3,21 +3,24 @@ package info
 import (
 	"context"
 	"fmt"
+	"github.com/spf13/viper"
 	"os"
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/printers"
 	"tableflip.dev/bujo/pkg/store"
 )
 
 type Info struct {
 	Config      store.Config
 	Persistence store.Persistence
+	// Collection is described instead of the journal, if set.
+	Collection string
 }
 
 func (n *Info) Do(ctx context.Context) error {
-
-	if override := os.Getenv("BUJO_CONFIG_PATH"); override != "" {
-		fmt.Println("BUJO_CONFIG_PATH found on env, using ", override)
-	} else {
-		fmt.Println("BUJO_CONFIG_PATH env var not set")
+	if n.Collection != "" {
+		return n.collectionInfo(ctx)
 	}
 
 	if n.Config == nil {
@@ -28,7 +31,22 @@ func (n *Info) Do(ctx context.Context) error {
 		}
 	}
 
+	if file := viper.ConfigFileUsed(); file != "" {
+		fmt.Println("Config file: ", file)
+	} else {
+		fmt.Println("Config file: none, bujo config set writes ", store.ConfigFile())
+	}
+	if path := os.Getenv("BUJO_PATH"); path != "" {
+		fmt.Println("BUJO_PATH found on env, using ", path)
+	}
 	fmt.Println("Config.path: ", n.Config.BasePath())
+	fmt.Println("Config.compress: ", n.Config.Compress())
+	if n.Config.Remote() != "" {
+		fmt.Println("Config.remote: ", n.Config.Remote())
+	}
+	if c := n.Config.Calendar(); c.URL != "" {
+		fmt.Println("Config.calendar: ", c.URL)
+	}
 
 	if n.Persistence == nil {
 		return fmt.Errorf("Failed to create persistence object.")
@@ -36,7 +54,9 @@ func (n *Info) Do(ctx context.Context) error {
 
 	fmt.Printf("Collections:\n")
 	foundCollections := 0
-	for _, k := range n.Persistence.Collections(ctx, "") {
+	collections := n.Persistence.Collections(ctx, "")
+	collection.Sort(collections)
+	for _, k := range collections {
 		fmt.Printf("  %s\n", k)
 		foundCollections++
 	}
@@ -47,3 +67,22 @@ func (n *Info) Do(ctx context.Context) error {
 
 	return nil
 }
+
+func (n *Info) collectionInfo(ctx context.Context) error {
+	i, ok := n.Persistence.(store.Inspector)
+	if !ok {
+		return fmt.Errorf("collection info is %w", app.ErrUnsupported)
+	}
+	info, err := i.CollectionInfo(ctx, n.Collection)
+	if err != nil {
+		return err
+	}
+
+	pp := printers.PrettyPrint{}
+	fmt.Println("")
+	pp.Title(info.Name)
+	for _, line := range printers.InfoLines(info) {
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package invite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/caldav"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// DefaultLength is how long a meeting is when the config does not say.
+const DefaultLength = 30 * time.Minute
+
+// Invite writes a meeting invite for an event to an .ics file.
+type Invite struct {
+	ID string
+	// Out is the file written, the summary of the event as a file name if
+	// empty.
+	Out     string
+	Meeting Meeting
+
+	Persistence store.Persistence
+}
+
+// Meeting is what an invite needs beyond the event itself.
+type Meeting struct {
+	// Length of the meeting, DefaultLength if 0.
+	Length time.Duration
+	// Organizer is the email the invite is from, it can be empty.
+	Organizer string
+	// Contacts are emails by @mention, without the @.
+	Contacts map[string]string
+}
+
+func (n *Invite) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not invite, no persistence")
+	}
+
+	e, err := ref.Resolve(n.Persistence.ListAll(ctx), n.ID)
+	if err != nil {
+		return err
+	}
+	ics, attendees, err := n.Meeting.For(e)
+	if err != nil {
+		return err
+	}
+	out := n.Out
+	if out == "" {
+		out = FileName(e)
+	}
+	if err := ioutil.WriteFile(out, ics, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote an invite for %d to %s\n", attendees, out)
+	return nil
+}
+
+// For renders the invite of an event: at the time in its message, on the
+// day it is on or the day log it is in, to the @mentions of its message.
+// It returns how many are invited.
+func (m Meeting) For(e *entry.Entry) ([]byte, int, error) {
+	if e.Bullet != glyph.Event {
+		return nil, 0, app.Invalid("entry", e.ID, "only events can be sent as invites")
+	}
+	start, err := startOf(e)
+	if err != nil {
+		return nil, 0, err
+	}
+	attendees, err := m.attendees(e.Message)
+	if err != nil {
+		return nil, 0, err
+	}
+	length := m.Length
+	if length <= 0 {
+		length = DefaultLength
+	}
+	// The uid is the entry, an invite sent again updates the meeting.
+	uid := e.ID + "@bujo"
+	return caldav.Invite(uid, e.Message, start, length, m.Organizer, attendees), len(attendees), nil
+}
+
+// attendees are the @mentions of message, with their email from the
+// contacts.
+func (m Meeting) attendees(message string) ([]caldav.Attendee, error) {
+	attendees := make([]caldav.Attendee, 0)
+	unknown := make([]string, 0)
+	for _, t := range store.Terms(message) {
+		if !strings.HasPrefix(t, "@") {
+			continue
+		}
+		name := t[1:]
+		email, ok := m.Contacts[name]
+		if !ok {
+			unknown = append(unknown, t)
+			continue
+		}
+		attendees = append(attendees, caldav.Attendee{Name: name, Email: email})
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, app.Invalid("attendees", strings.Join(unknown, ", "), "add their emails to contacts in the config")
+	}
+	if len(attendees) == 0 {
+		return nil, app.Invalid("attendees", "", "@mention who to invite in the event")
+	}
+	return attendees, nil
+}
+
+// clock matches a time of day in a message, like 3pm, 3:30pm or 15:00.
+var clock = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s?(am|pm)\b|\b(\d{1,2}):(\d{2})\b`)
+
+// startOf is when the event starts.
+func startOf(e *entry.Entry) (time.Time, error) {
+	var day time.Time
+	if e.On != nil {
+		day = e.On.Time
+	} else if kind, t := collection.Parse(e.Collection); kind == collection.Day {
+		day = t
+	} else {
+		return time.Time{}, app.Invalid("entry", e.ID, "the event needs a day, add it with --on or to a day log")
+	}
+
+	m := clock.FindStringSubmatch(e.Message)
+	if m == nil {
+		return time.Time{}, app.Invalid("entry", e.ID, "the event needs a time, like 3pm or 15:00, in its message")
+	}
+	var hour, minute int
+	if m[1] != "" {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+		if hour < 1 || hour > 12 {
+			return time.Time{}, app.Invalid("time", m[0], "expected an hour from 1 to 12")
+		}
+		hour %= 12
+		if strings.EqualFold(m[3], "pm") {
+			hour += 12
+		}
+	} else {
+		hour, _ = strconv.Atoi(m[4])
+		minute, _ = strconv.Atoi(m[5])
+		if hour > 23 {
+			return time.Time{}, app.Invalid("time", m[0], "expected an hour from 0 to 23")
+		}
+	}
+	if minute > 59 {
+		return time.Time{}, app.Invalid("time", m[0], "expected minutes from 0 to 59")
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local), nil
+}
+
+// FileName is the name of the invite file of an event, from its message.
+func FileName(e *entry.Entry) string {
+	words := make([]string, 0)
+	for _, t := range store.Terms(e.Message) {
+		if strings.HasPrefix(t, "@") || strings.HasPrefix(t, "#") {
+			continue
+		}
+		words = append(words, t)
+		if len(words) == 5 {
+			break
+		}
+	}
+	if len(words) == 0 {
+		return "invite.ics"
+	}
+	return strings.Join(words, "-") + ".ics"
+}
@@ -0,0 +1,39 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Kind shows the type of a collection, which comes from its name.
+type Kind struct {
+	Collection  string
+	Persistence store.Persistence
+}
+
+func (n *Kind) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not show type, no persistence")
+	}
+	if len(n.Persistence.List(ctx, n.Collection)) == 0 {
+		return fmt.Errorf("%w: %s", app.ErrCollectionNotFound, n.Collection)
+	}
+
+	kind, on := collection.Parse(n.Collection)
+	switch {
+	case on.IsZero():
+		fmt.Printf("%s: %s\n", n.Collection, kind)
+	case kind == collection.Week:
+		fmt.Printf("%s: %s from %s\n", n.Collection, kind, on.Format("Monday, January 2, 2006"))
+	case kind == collection.Day:
+		fmt.Printf("%s: %s of %s\n", n.Collection, kind, on.Format("Monday, January 2, 2006"))
+	default:
+		fmt.Printf("%s: %s of %s\n", n.Collection, kind, on.Format("January 2006"))
+	}
+	return nil
+}
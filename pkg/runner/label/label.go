@@ -0,0 +1,44 @@
+package label
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+type Label struct {
+	ID          string
+	Label       glyph.Label
+	Persistence store.Persistence
+}
+
+func (n *Label) Do(ctx context.Context) error {
+	pp := printers.PrettyPrint{ShowID: true}
+
+	if n.Persistence == nil {
+		return errors.New("can not label, no persistence")
+	}
+
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	e.Label = n.Label
+	if err := n.Persistence.Store(e); err != nil {
+		return err
+	}
+
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
+	fmt.Println("")
+	pp.Title(e.Collection)
+	pp.Collection(all...)
+
+	return nil
+}
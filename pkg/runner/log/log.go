@@ -3,7 +3,12 @@ package log
 import (
 	"context"
 	"errors"
+	"fmt"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
 	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
 	"tableflip.dev/bujo/pkg/runner/get"
 	"tableflip.dev/bujo/pkg/store"
 	"time"
@@ -15,16 +20,11 @@ type Log struct {
 	Month       bool
 	Future      bool
 	On          time.Time
+	// CalendarOptions are used for the month calendar, if set.
+	CalendarOptions *printers.CalendarOptions
 	// TODO: a range.
 }
 
-// TODO: make the today logic a base thing or something.
-const (
-	layoutUSDay         = "January 2, 2006"
-	layoutUSMonth       = "January, 2006"
-	layoutUSFutureMonth = "Future - January, 2006"
-)
-
 func (n *Log) Do(ctx context.Context) error {
 
 	//pp := printers.PrettyPrint{}
@@ -38,10 +38,9 @@ func (n *Log) Do(ctx context.Context) error {
 	//  ● 21: This event is happening WAY later maybe, if I get to it.
 	//
 	if n.Future {
-		collection := n.On.Format(layoutUSFutureMonth)
 		g := get.Get{
 			Bullet:      glyph.Any, //  Really this should filter on tasks and events.
-			Collection:  collection,
+			Collection:  collection.FutureOf(n.On),
 			Persistence: n.Persistence,
 		}
 		if err := g.Do(ctx); err != nil {
@@ -51,13 +50,13 @@ func (n *Log) Do(ctx context.Context) error {
 
 	// Calendar View.
 	if n.Month {
-		collection := n.On.Format(layoutUSMonth)
 		g := get.Get{
-			CalendarView: true,
-			Bullet:       glyph.Event,
-			Collection:   collection,
-			Persistence:  n.Persistence,
-			On:           n.On,
+			CalendarView:    true,
+			CalendarOptions: n.CalendarOptions,
+			Bullet:          glyph.Event,
+			Collection:      collection.MonthOf(n.On),
+			Persistence:     n.Persistence,
+			On:              n.On,
 		}
 		if err := g.Do(ctx); err != nil {
 			return err
@@ -66,10 +65,9 @@ func (n *Log) Do(ctx context.Context) error {
 
 	// Task View.
 	if n.Month {
-		collection := n.On.Format(layoutUSMonth)
 		g := get.Get{
 			Bullet:      glyph.Task,
-			Collection:  collection,
+			Collection:  collection.MonthOf(n.On),
 			Persistence: n.Persistence,
 			On:          n.On,
 		}
@@ -80,16 +78,66 @@ func (n *Log) Do(ctx context.Context) error {
 
 	// Day view.
 	if n.Day {
-		collection := n.On.Format(layoutUSDay)
 		g := get.Get{
 			Bullet:      glyph.Any,
-			Collection:  collection,
+			Collection:  collection.DayOf(n.On),
 			Persistence: n.Persistence,
 		}
 		if err := g.Do(ctx); err != nil {
 			return err
 		}
+		n.followUps(ctx)
+		n.reviews(ctx)
 	}
 
 	return nil
 }
+
+// followUps prints the waiting entries that are due for a follow up.
+func (n *Log) followUps(ctx context.Context) {
+	due := make([]*entry.Entry, 0)
+	all := n.Persistence.ListAll(ctx)
+	for _, e := range all {
+		if e.FollowUpDue(n.On) {
+			due = append(due, e)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	pp := printers.PrettyPrint{ShowID: true, Refs: ref.Refs(all)}
+	pp.Title("Follow up")
+	pp.Collection(due...)
+}
+
+// reviews prints the entries that are due to be looked at again, and clears
+// the review date of those due by now so they come up once.
+func (n *Log) reviews(ctx context.Context) {
+	due := make([]*entry.Entry, 0)
+	all := n.Persistence.ListAll(ctx)
+	for _, e := range all {
+		if e.ReviewDue(n.On) {
+			due = append(due, e)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	pp := printers.PrettyPrint{ShowID: true, Refs: ref.Refs(all)}
+	pp.Title("Review")
+	pp.Collection(due...)
+
+	// A log of a day ahead shows what comes up then, without clearing it.
+	now := time.Now()
+	for _, e := range due {
+		if !e.ReviewDue(now) {
+			continue
+		}
+		e.ReviewOn = nil
+		if err := n.Persistence.Store(e); err != nil {
+			fmt.Printf("failed to clear the review of %s: %v\n", e.ID, err)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package maintenance
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Maintenance applies the retention policies to the journal.
+type Maintenance struct {
+	// ArchiveBefore archives day collections for days before it, if set.
+	ArchiveBefore *time.Time
+	// PruneMovedBefore deletes the originals left behind by entries moved
+	// before it, if set.
+	PruneMovedBefore *time.Time
+	// Yes skips the confirmation.
+	Yes bool
+	// DryRun only reports what would be done.
+	DryRun bool
+	// In is where the confirmation is read from, defaults to stdin.
+	In io.Reader
+
+	Persistence store.Persistence
+}
+
+func (n *Maintenance) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not run maintenance, no persistence")
+	}
+
+	if err := n.archiveExpired(ctx); err != nil {
+		return err
+	}
+
+	if n.ArchiveBefore == nil {
+		fmt.Println("no retention policy configured for day collections")
+	} else if err := n.archive(ctx); err != nil {
+		return err
+	}
+
+	if n.PruneMovedBefore == nil {
+		return nil
+	}
+	return n.pruneMoved(ctx)
+}
+
+// archiveExpired archives the notes that have expired. Expiring was asked for
+// when the note was added, so there is no confirmation.
+func (n *Maintenance) archiveExpired(ctx context.Context) error {
+	now := time.Now()
+	expired := make([]*entry.Entry, 0)
+	for _, e := range n.Persistence.ListAll(ctx) {
+		if e.Expired(now) {
+			expired = append(expired, e)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	fmt.Printf("%d expired notes will be archived:\n", len(expired))
+	for _, e := range expired {
+		fmt.Printf("  %s: %s\n", e.Collection, e.Message)
+	}
+	if n.DryRun {
+		return nil
+	}
+
+	a, ok := n.Persistence.(store.Archiver)
+	if !ok {
+		return fmt.Errorf("archiving is %w", app.ErrUnsupported)
+	}
+	moved, err := a.ArchiveEntries(ctx, expired...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("archived %d expired notes\n", moved)
+	return nil
+}
+
+func (n *Maintenance) archive(ctx context.Context) error {
+	a, ok := n.Persistence.(store.Archiver)
+	if !ok {
+		return fmt.Errorf("archiving is %w", app.ErrUnsupported)
+	}
+
+	old := n.oldDays(ctx)
+	if len(old) == 0 {
+		fmt.Printf("no day collections before %s to archive\n", collection.DayOf(*n.ArchiveBefore))
+		return nil
+	}
+
+	fmt.Printf("%d day collections before %s will be archived:\n", len(old), collection.DayOf(*n.ArchiveBefore))
+	for _, c := range old {
+		fmt.Printf("  %s\n", c)
+	}
+	if n.DryRun {
+		return nil
+	}
+	if !n.Yes && !n.confirm("Archive them?") {
+		fmt.Println("skipped")
+		return nil
+	}
+
+	moved := 0
+	for _, c := range old {
+		m, err := a.Archive(ctx, c)
+		moved += m
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("archived %d entries\n", moved)
+	return nil
+}
+
+// pruneMoved deletes the moved originals, the entries left behind with a
+// moved bullet, that were moved before PruneMovedBefore. They are deleted
+// for good, not archived. Originals moved before the journal kept when they
+// moved go by when they were written, they moved some time after.
+func (n *Maintenance) pruneMoved(ctx context.Context) error {
+	t, ok := n.Persistence.(store.Transactor)
+	if !ok {
+		return fmt.Errorf("deleting entries is %w", app.ErrUnsupported)
+	}
+
+	moved := make([]*entry.Entry, 0)
+	unknown := 0
+	for _, e := range n.Persistence.ListAll(ctx) {
+		switch e.Bullet {
+		case glyph.MovedCollection, glyph.MovedFuture:
+		default:
+			continue
+		}
+		at := e.Created.Time
+		if e.MovedAt != nil {
+			at = e.MovedAt.Time
+		}
+		if !at.Before(*n.PruneMovedBefore) {
+			continue
+		}
+		if e.MovedAt == nil {
+			unknown++
+		}
+		moved = append(moved, e)
+	}
+	if len(moved) == 0 {
+		fmt.Printf("no moved originals from before %s to prune\n", collection.DayOf(*n.PruneMovedBefore))
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, e := range moved {
+		counts[e.Collection]++
+	}
+	names := make([]string, 0, len(counts))
+	for c := range counts {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+	fmt.Printf("%d moved originals from before %s will be deleted:\n", len(moved), collection.DayOf(*n.PruneMovedBefore))
+	for _, c := range names {
+		fmt.Printf("  %s: %d\n", c, counts[c])
+	}
+	if unknown > 0 {
+		fmt.Printf("%d of them do not say when they moved, they were written before %s\n", unknown, collection.DayOf(*n.PruneMovedBefore))
+	}
+	if n.DryRun {
+		return nil
+	}
+	if !n.Yes && !n.confirm("Delete them? This can not be undone.") {
+		fmt.Println("skipped")
+		return nil
+	}
+
+	err := t.Transact(ctx, func(tx store.Tx) error {
+		for _, e := range moved {
+			if err := tx.Delete(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("pruned %d moved originals\n", len(moved))
+	return nil
+}
+
+// oldDays returns the day collections before ArchiveBefore, oldest first.
+func (n *Maintenance) oldDays(ctx context.Context) []string {
+	type day struct {
+		name string
+		on   time.Time
+	}
+	days := make([]day, 0)
+	for _, c := range n.Persistence.Collections(ctx, "") {
+		kind, on := collection.Parse(c)
+		if kind != collection.Day {
+			continue
+		}
+		if on.Before(*n.ArchiveBefore) {
+			days = append(days, day{name: c, on: on})
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].on.Before(days[j].on)
+	})
+
+	names := make([]string, 0, len(days))
+	for _, d := range days {
+		names = append(names, d.name)
+	}
+	return names
+}
+
+func (n *Maintenance) confirm(question string) bool {
+	in := n.In
+	if in == nil {
+		in = os.Stdin
+	}
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,64 @@
+package maintenance
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/internal/storetest"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+func TestPruneMoved(t *testing.T) {
+	path, err := ioutil.TempDir("", "bujo-maintenance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+	p, err := store.Load(storetest.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	old := entry.New("Inbox", glyph.Task, "moved long ago")
+	moved := old.Move(glyph.MovedCollection, "Project")
+	old.MovedAt = &entry.Timestamp{Time: now.AddDate(0, -7, 0)}
+	recent := entry.New("Inbox", glyph.Task, "moved today")
+	again := recent.Move(glyph.MovedFuture, "Future")
+	unknown := entry.New("Inbox", glyph.MovedCollection, "moved before the time was kept")
+	unknownOld := entry.New("Inbox", glyph.MovedCollection, "written long ago, moved before the time was kept")
+	unknownOld.Created = entry.Timestamp{Time: now.AddDate(-1, 0, 0)}
+	for _, e := range []*entry.Entry{old, moved, recent, again, unknown, unknownOld} {
+		if err := p.Store(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := now.AddDate(0, -6, 0)
+	n := &Maintenance{PruneMovedBefore: &before, Yes: true, Persistence: p}
+	if err := n.Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	left := make(map[string]bool)
+	for _, e := range p.ListAll(ctx) {
+		left[e.ID] = true
+	}
+	if left[old.ID] {
+		t.Error("the original moved before the policy was kept")
+	}
+	if left[unknownOld.ID] {
+		t.Error("the original written before the policy was kept")
+	}
+	for _, e := range []*entry.Entry{moved, recent, again, unknown} {
+		if !left[e.ID] {
+			t.Errorf("%q was pruned", e.Message)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package mkdir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// namePlaceholder in a seed message is replaced with the name of the new
+// collection.
+const namePlaceholder = "{name}"
+
+// childSeparator is between the name of a collection and its children.
+const childSeparator = "/"
+
+// Template is a collection scaffold kept in the config by name, like:
+//
+//	templates:
+//	  - name: project
+//	    entries:
+//	      - bullet: note
+//	        message: "goal of {name}:"
+//	    children:
+//	      - name: Backlog
+//	        entries:
+//	          - message: plan the first milestone
+//	      - name: Notes
+//	      - name: Decisions
+type Template struct {
+	Name string `mapstructure:"name"`
+	// Entries are added to the new collection.
+	Entries []Seed `mapstructure:"entries"`
+	// Children are collections named after the new one, like
+	// "Project Y/Backlog".
+	Children []Child `mapstructure:"children"`
+}
+
+// Child is a collection made with the new one.
+type Child struct {
+	Name string `mapstructure:"name"`
+	// Entries are added to the child. A child without entries gets a note
+	// naming the collection it belongs to, collections are made by their
+	// first entry.
+	Entries []Seed `mapstructure:"entries"`
+}
+
+// Seed is an entry a template adds.
+type Seed struct {
+	// Bullet is a bullet alias, task if empty.
+	Bullet  string `mapstructure:"bullet"`
+	Message string `mapstructure:"message"`
+}
+
+// Validate checks the template can be used.
+func (t *Template) Validate() error {
+	if t.Name == "" {
+		return errors.New("template: missing name")
+	}
+	seeds := append([]Seed{}, t.Entries...)
+	for _, c := range t.Children {
+		if c.Name == "" {
+			return fmt.Errorf("template %q: a child is missing its name", t.Name)
+		}
+		seeds = append(seeds, c.Entries...)
+	}
+	for _, s := range seeds {
+		if s.Message == "" {
+			return fmt.Errorf("template %q: an entry is missing its message", t.Name)
+		}
+		if s.Bullet != "" {
+			if _, err := glyph.BulletForAlias(s.Bullet); err != nil {
+				return fmt.Errorf("template %q: %w", t.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// entries are what the template adds for a collection named name.
+func (t *Template) entries(name string) []*entry.Entry {
+	seed := func(collection string, s Seed) *entry.Entry {
+		bullet := glyph.Task
+		if s.Bullet != "" {
+			bullet, _ = glyph.BulletForAlias(s.Bullet)
+		}
+		return entry.New(collection, bullet, strings.Replace(s.Message, namePlaceholder, name, -1))
+	}
+
+	all := make([]*entry.Entry, 0)
+	for _, s := range t.Entries {
+		all = append(all, seed(name, s))
+	}
+	for _, c := range t.Children {
+		child := name + childSeparator + c.Name
+		if len(c.Entries) == 0 {
+			all = append(all, entry.New(child, glyph.Note, fmt.Sprintf("%s of %s", c.Name, name)))
+		}
+		for _, s := range c.Entries {
+			all = append(all, seed(child, s))
+		}
+	}
+	return all
+}
+
+// Mkdir makes a collection and its children from a template.
+type Mkdir struct {
+	Collection  string
+	Template    Template
+	Persistence store.Persistence
+}
+
+func (n *Mkdir) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not make collection, no persistence")
+	}
+	t, ok := n.Persistence.(store.Transactor)
+	if !ok {
+		return fmt.Errorf("templates are %w", app.ErrUnsupported)
+	}
+	if n.Collection == "" {
+		return app.Invalid("collection", n.Collection, "a collection name is required")
+	}
+	if err := n.Template.Validate(); err != nil {
+		return err
+	}
+
+	entries := n.Template.entries(n.Collection)
+	if len(entries) == 0 {
+		return app.Invalid("template", n.Template.Name, "it has no entries or children, collections are made by their first entry")
+	}
+	names := make([]string, 0)
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if counts[e.Collection] == 0 {
+			names = append(names, e.Collection)
+		}
+		counts[e.Collection]++
+	}
+	for _, name := range names {
+		if len(n.Persistence.List(ctx, name)) > 0 {
+			return fmt.Errorf("%s already has entries", name)
+		}
+	}
+
+	err := t.Transact(ctx, func(tx store.Tx) error {
+		for _, e := range entries {
+			if err := tx.Store(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	pp := printers.PrettyPrint{}
+	for _, name := range names {
+		pp.TitleWithCount(name, counts[name])
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package pin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Pin toggles whether an entry is pinned. Pinned entries are shown first in
+// their collection in the ui.
+type Pin struct {
+	ID          string
+	Persistence store.Persistence
+}
+
+func (n *Pin) Do(ctx context.Context) error {
+	pp := printers.PrettyPrint{ShowID: true}
+
+	if n.Persistence == nil {
+		return errors.New("can not pin, no persistence")
+	}
+
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	e.Pinned = !e.Pinned
+	if err := n.Persistence.Store(e); err != nil {
+		return err
+	}
+
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
+	fmt.Println("")
+	pp.Title(e.Collection)
+	pp.Collection(all...)
+
+	return nil
+}
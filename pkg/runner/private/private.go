@@ -0,0 +1,44 @@
+package private
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Private toggles whether an entry is private. Private entries are left out
+// of exports, shares and digests unless they are asked for.
+type Private struct {
+	ID          string
+	Persistence store.Persistence
+}
+
+func (n *Private) Do(ctx context.Context) error {
+	pp := printers.PrettyPrint{ShowID: true}
+
+	if n.Persistence == nil {
+		return errors.New("can not mark private, no persistence")
+	}
+
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	e.Private = !e.Private
+	if err := n.Persistence.Store(e); err != nil {
+		return err
+	}
+
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
+	fmt.Println("")
+	pp.Title(e.Collection)
+	pp.Collection(all...)
+
+	return nil
+}
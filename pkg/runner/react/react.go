@@ -0,0 +1,57 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// React puts a reaction by an author on an entry, or takes it back if they
+// had already reacted with it.
+type React struct {
+	ID       string
+	Reaction glyph.Reaction
+	// By is who reacts.
+	By          string
+	Persistence store.Persistence
+}
+
+func (n *React) Do(ctx context.Context) error {
+	pp := printers.PrettyPrint{ShowID: true}
+
+	if n.Persistence == nil {
+		return errors.New("can not react, no persistence")
+	}
+	if strings.TrimSpace(n.By) == "" {
+		return app.Invalid("author", n.By, "set author in config")
+	}
+
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	added := e.React(n.Reaction, n.By, time.Now())
+	if err := n.Persistence.Store(e); err != nil {
+		return err
+	}
+	if !added {
+		fmt.Printf("took back %s by %s\n", n.Reaction, n.By)
+	}
+
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
+	fmt.Println("")
+	pp.Title(e.Collection)
+	pp.Collection(all...)
+
+	return nil
+}
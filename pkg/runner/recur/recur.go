@@ -0,0 +1,196 @@
+package recur
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/recur"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Add makes a recurring entry, and adds its instance to today's log if it
+// has one today.
+type Add struct {
+	Bullet      glyph.Bullet
+	Message     string
+	Rule        string
+	Persistence store.Persistence
+}
+
+func (n *Add) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not add recurring entry, no persistence")
+	}
+	if err := recur.Valid(n.Rule); err != nil {
+		return err
+	}
+	if n.Message == "" {
+		return app.Invalid("message", n.Message, "a message is required")
+	}
+
+	e := recur.New(n.Bullet, n.Message, n.Rule)
+	if err := n.Persistence.Store(e); err != nil {
+		return err
+	}
+	added, err := recur.Add(ctx, n.Persistence, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s every %s: %s\n", glyph.Recurring, n.Rule, n.Message)
+	if len(added) > 0 {
+		fmt.Printf("added to %s\n", collection.DayOf(time.Now()))
+	}
+	return nil
+}
+
+// Set changes the rule of a recurring entry.
+type Set struct {
+	ID          string
+	Rule        string
+	Persistence store.Persistence
+}
+
+func (n *Set) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not set rule, no persistence")
+	}
+	if err := recur.Valid(n.Rule); err != nil {
+		return err
+	}
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	r, err := SetRule(all, e, n.Rule)
+	if err != nil {
+		return err
+	}
+	if err := n.Persistence.Store(r); err != nil {
+		return err
+	}
+	fmt.Printf("%s every %s: %s\n", glyph.Recurring, n.Rule, r.Message)
+	return nil
+}
+
+// SetRule changes the rule of the recurring entry of e, which is the
+// recurring entry or one of its instances, and returns the recurring entry
+// to store.
+func SetRule(all []*entry.Entry, e *entry.Entry, rule string) (*entry.Entry, error) {
+	if err := recur.Valid(rule); err != nil {
+		return nil, err
+	}
+	r := recur.Of(all, e)
+	if r == nil {
+		return nil, app.Invalid("entry", e.ID, "it does not recur, add recurring entries with bujo recur add")
+	}
+	r.Recurrence.Rule = rule
+	return r, nil
+}
+
+// Stop stops an entry from recurring. The entry and the instances it added
+// are kept.
+type Stop struct {
+	ID          string
+	Persistence store.Persistence
+}
+
+func (n *Stop) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not stop recurring entry, no persistence")
+	}
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	r := recur.Of(all, e)
+	if r == nil || r.Recurrence == nil {
+		return app.Invalid("entry", n.ID, "it does not recur")
+	}
+	rule := r.Recurrence.Rule
+	r.Recurrence = nil
+	if t, ok := n.Persistence.(store.Transactor); ok {
+		err = t.Transact(ctx, func(tx store.Tx) error {
+			return tx.Store(r)
+		})
+	} else {
+		err = n.Persistence.Store(r)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("stopped %s every %s: %s\n", glyph.Recurring, rule, r.Message)
+	return nil
+}
+
+// Run adds the instances due today.
+type Run struct {
+	Persistence store.Persistence
+}
+
+func (n *Run) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not add recurring entries, no persistence")
+	}
+	added, err := recur.Add(ctx, n.Persistence, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(added) == 0 {
+		fmt.Println("nothing recurs today that is not already added")
+		return nil
+	}
+	pp := printers.PrettyPrint{}
+	fmt.Println("")
+	pp.Title(added[0].Collection)
+	pp.Collection(added...)
+	return nil
+}
+
+// List prints the recurring entries.
+type List struct {
+	Persistence store.Persistence
+}
+
+func (n *List) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not list recurring entries, no persistence")
+	}
+	all := n.Persistence.ListAll(ctx)
+	refs := ref.Refs(all)
+
+	bold := color.New(color.Bold)
+	tbl := uitable.New()
+	tbl.Separator = "  "
+	tbl.AddRow(bold.Sprint("Ref"), bold.Sprint("Rule"), bold.Sprint("Last"), bold.Sprint("Entry"))
+	found := false
+	for _, e := range n.Persistence.List(ctx, recur.Collection) {
+		if !recur.Recurring(e) {
+			continue
+		}
+		found = true
+		last := "-"
+		if e.Recurrence.Last != nil {
+			last = e.Recurrence.Last.Local().Format("2006-01-02")
+		}
+		tbl.AddRow(refs[e.ID], e.Recurrence.Rule, last, e.Bullet.String()+" "+e.Message)
+	}
+	if !found {
+		fmt.Println("no recurring entries, add them with bujo recur add")
+		return nil
+	}
+	_, _ = fmt.Fprintln(color.Output, tbl)
+	return nil
+}
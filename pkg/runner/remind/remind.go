@@ -0,0 +1,55 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/notify"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Remind sends a notification for the waiting entries with a follow up due.
+type Remind struct {
+	On   time.Time
+	Sink notify.Sink
+
+	Persistence store.Persistence
+}
+
+func (n *Remind) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not remind, no persistence")
+	}
+	if n.Sink == nil {
+		return errors.New("can not remind, nowhere to notify")
+	}
+
+	due := make([]*entry.Entry, 0)
+	for _, e := range n.Persistence.ListAll(ctx) {
+		if e.FollowUpDue(n.On) {
+			due = append(due, e)
+		}
+	}
+	if len(due) == 0 {
+		fmt.Println("no follow ups due")
+		return nil
+	}
+
+	lines := make([]string, 0, len(due))
+	for _, e := range due {
+		line := e.Message
+		if e.WaitingOn != "" {
+			line += " (waiting on " + e.WaitingOn + ")"
+		}
+		lines = append(lines, line)
+	}
+	title := "1 follow up due"
+	if len(due) > 1 {
+		title = fmt.Sprintf("%d follow ups due", len(due))
+	}
+	return n.Sink.Notify(ctx, notify.Message{Title: title, Body: strings.Join(lines, "\n")})
+}
@@ -0,0 +1,65 @@
+package rename
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Rename moves every entry of a collection to a new name, with its icon and
+// default bullet.
+type Rename struct {
+	From        string
+	To          string
+	Persistence store.Persistence
+}
+
+func (n *Rename) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not rename, no persistence")
+	}
+	r, ok := n.Persistence.(store.Renamer)
+	if !ok {
+		return fmt.Errorf("renaming collections is %w", app.ErrUnsupported)
+	}
+
+	if len(n.Persistence.List(ctx, n.From)) == 0 {
+		return fmt.Errorf("%w: %s", app.ErrCollectionNotFound, n.From)
+	}
+	if n.To == n.From {
+		return app.Invalid("collection", n.To, "is the name it has")
+	}
+	if len(n.Persistence.List(ctx, n.To)) > 0 {
+		return app.Invalid("collection", n.To, "already exists, split or join entries into it instead")
+	}
+
+	moved, err := r.Rename(ctx, n.From, n.To)
+	if err != nil {
+		return err
+	}
+	if i, ok := n.Persistence.(store.Iconer); ok {
+		if icon, ok := i.Icons(ctx)[n.From]; ok {
+			if err := i.SetIcon(ctx, n.To, icon); err != nil {
+				return err
+			}
+			if err := i.SetIcon(ctx, n.From, ""); err != nil {
+				return err
+			}
+		}
+	}
+	if b, ok := n.Persistence.(store.BulletDefaulter); ok {
+		if bullet, ok := b.DefaultBullets(ctx)[n.From]; ok {
+			if err := b.SetDefaultBullet(ctx, n.To, bullet); err != nil {
+				return err
+			}
+			if err := b.SetDefaultBullet(ctx, n.From, ""); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("%s -> %s, %d entries\n", n.From, n.To, moved)
+	return nil
+}
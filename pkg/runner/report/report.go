@@ -0,0 +1,155 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Groupings of a notes digest.
+const (
+	GroupDay        = "day"
+	GroupCollection = "collection"
+)
+
+// Notes is a digest of the notes taken over a window, grouped by day.
+type Notes struct {
+	Since time.Time
+	Label glyph.Label
+	// Tag only includes notes with this #tag, if set.
+	Tag string
+	// Group is GroupDay or GroupCollection, empty is GroupDay.
+	Group       string
+	Markdown    bool
+	Out         io.Writer
+	Persistence store.Persistence
+	// IncludePrivate adds private notes to the digest.
+	IncludePrivate bool
+}
+
+func (n *Notes) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not report, no persistence")
+	}
+
+	days := n.Sections(ctx)
+
+	if n.Markdown {
+		out := n.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		for _, d := range days {
+			if _, err := fmt.Fprintln(out, printers.Markdown(d.Title, d.Entries...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	pp := printers.PrettyPrint{}
+	fmt.Println("")
+	if len(days) == 0 {
+		pp.Title("Notes")
+		pp.Collection()
+		return nil
+	}
+	for _, d := range days {
+		pp.Title(d.Title)
+		pp.Collection(d.Entries...)
+	}
+	return nil
+}
+
+// Sections returns the notes of the digest, grouped as n.Group says.
+func (n *Notes) Sections(ctx context.Context) []Section {
+	group := byDay
+	if n.Group == GroupCollection {
+		group = byCollection
+	}
+	return group(n.Persistence.ListAll(ctx), n.Since, func(e *entry.Entry) bool {
+		if n.Label != glyph.NoLabel && n.Label != e.Label {
+			return false
+		}
+		if n.Tag != "" && !e.HasTag(n.Tag) {
+			return false
+		}
+		if e.Private && !n.IncludePrivate {
+			return false
+		}
+		return e.Bullet == glyph.Note
+	})
+}
+
+// Section is a day or collection of a digest and its notes.
+type Section struct {
+	Title   string
+	Entries []*entry.Entry
+}
+
+// byDay groups the kept entries created on or after since by the day they
+// were created, oldest first.
+func byDay(all []*entry.Entry, since time.Time, keep func(*entry.Entry) bool) []Section {
+	return grouped(all, since, keep, func(e *entry.Entry) string {
+		return collection.DayOf(e.Created.Local())
+	})
+}
+
+// byCollection groups the kept entries created on or after since by their
+// collection, in the order the collections sort.
+func byCollection(all []*entry.Entry, since time.Time, keep func(*entry.Entry) bool) []Section {
+	days := grouped(all, since, keep, func(e *entry.Entry) string {
+		return e.Collection
+	})
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = d.Title
+	}
+	collection.Sort(names)
+	at := make(map[string]int, len(names))
+	for i, name := range names {
+		at[name] = i
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		return at[days[i].Title] < at[days[j].Title]
+	})
+	return days
+}
+
+// grouped groups the kept entries created on or after since by title,
+// oldest first within each group, in the order each group was first seen.
+func grouped(all []*entry.Entry, since time.Time, keep func(*entry.Entry) bool, title func(*entry.Entry) string) []Section {
+	filtered := make([]*entry.Entry, 0, len(all))
+	for _, e := range all {
+		if keep(e) && !e.Created.Before(since) {
+			filtered = append(filtered, e)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Created.Before(filtered[j].Created.Time)
+	})
+
+	days := make([]Section, 0)
+	at := make(map[string]int)
+	for _, e := range filtered {
+		t := title(e)
+		i, ok := at[t]
+		if !ok {
+			i = len(days)
+			at[t] = i
+			days = append(days, Section{Title: t})
+		}
+		days[i].Entries = append(days[i].Entries, e)
+	}
+	return days
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/mitchellh/go-homedir"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/automation"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Formats of a saved report.
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
+// datePlaceholder in the out path of a saved report is replaced with the day
+// it runs, so scheduled reports do not overwrite each other.
+const datePlaceholder = "{date}"
+
+// Saved is a notes digest kept in the config by name, run with bujo report
+// saved <name> or on a schedule, like:
+//
+// reports:
+//   - name: weekly
+//     window: 1w
+//     label: red
+//     group: collection
+//     format: markdown
+//     out: ~/digests/weekly-{date}.md
+//     schedule: "0 18 * * 0"
+type Saved struct {
+	Name string `mapstructure:"name"`
+	// Window is how far back the digest goes, like 1w.
+	Window string `mapstructure:"window"`
+	// Label only includes notes with this color label, if set.
+	Label string `mapstructure:"label"`
+	// Tag only includes notes with this #tag, if set.
+	Tag string `mapstructure:"tag"`
+	// Group is GroupDay or GroupCollection.
+	Group string `mapstructure:"group"`
+	// Format is FormatText or FormatMarkdown, a report with out is always
+	// markdown.
+	Format string `mapstructure:"format"`
+	// Out is the file the report is written to, if set.
+	Out string `mapstructure:"out"`
+	// Schedule is a cron expression to run the report on, if set.
+	Schedule string `mapstructure:"schedule"`
+	// IncludePrivate adds private notes to the report.
+	IncludePrivate bool `mapstructure:"include_private"`
+}
+
+// Validate checks the report can be run, and scheduled if it has a
+// schedule. The window is checked when the report is run.
+func (s *Saved) Validate() error {
+	if s.Name == "" {
+		return errors.New("saved report: missing name")
+	}
+	switch s.Group {
+	case "", GroupDay, GroupCollection:
+	default:
+		return app.Invalid("group", s.Group, "expected day or collection")
+	}
+	switch s.Format {
+	case "", FormatMarkdown:
+	case FormatText:
+		if s.Out != "" {
+			return app.Invalid("format", s.Format, "reports written to a file are markdown")
+		}
+	default:
+		return app.Invalid("format", s.Format, "expected text or markdown")
+	}
+	if _, err := glyph.LabelFor(s.Label); err != nil {
+		return err
+	}
+	if s.Schedule != "" {
+		if _, err := automation.ParseSchedule(s.Schedule); err != nil {
+			return fmt.Errorf("saved report %q: %v", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// OutPath is the file the report is written to on the day of now, with ~
+// expanded. It is empty if the report is printed.
+func (s *Saved) OutPath(now time.Time) (string, error) {
+	if s.Out == "" {
+		return "", nil
+	}
+	path := strings.Replace(s.Out, datePlaceholder, now.Format("2006-01-02"), -1)
+	return homedir.Expand(path)
+}
+
+// Automation runs the report on its schedule.
+func (s *Saved) Automation() automation.Automation {
+	return automation.Automation{
+		Name:     "report-" + s.Name,
+		Schedule: s.Schedule,
+		Run:      "report saved " + s.Name,
+	}
+}
+
+// Run runs a saved report.
+type Run struct {
+	Report Saved
+	// Since is the start of the report's window.
+	Since       time.Time
+	Persistence store.Persistence
+}
+
+func (n *Run) Do(ctx context.Context) error {
+	if err := n.Report.Validate(); err != nil {
+		return err
+	}
+	label, _ := glyph.LabelFor(n.Report.Label)
+	notes := Notes{
+		Since:          n.Since,
+		Label:          label,
+		Tag:            n.Report.Tag,
+		Group:          n.Report.Group,
+		Markdown:       n.Report.Format != FormatText,
+		IncludePrivate: n.Report.IncludePrivate,
+		Persistence:    n.Persistence,
+	}
+
+	path, err := n.Report.OutPath(time.Now())
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return notes.Do(ctx)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	notes.Out = f
+	if err := notes.Do(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s to %s\n", n.Report.Name, path)
+	return nil
+}
+
+// ListSaved prints the saved reports.
+type ListSaved struct {
+	Reports []Saved
+}
+
+func (n *ListSaved) Do(ctx context.Context) error {
+	if len(n.Reports) == 0 {
+		fmt.Println("no saved reports, add them to reports in the config")
+		return nil
+	}
+
+	bold := color.New(color.Bold)
+	tbl := uitable.New()
+	tbl.Separator = "  "
+	tbl.AddRow(bold.Sprint("Name"), bold.Sprint("Window"), bold.Sprint("Schedule"), bold.Sprint("Out"))
+	for _, r := range n.Reports {
+		window, schedule, out := r.Window, r.Schedule, r.Out
+		if window == "" {
+			window = "1w"
+		}
+		if schedule == "" {
+			schedule = "-"
+		}
+		if out == "" {
+			out = "-"
+		}
+		tbl.AddRow(r.Name, window, schedule, out)
+	}
+	_, _ = fmt.Fprintln(color.Output, tbl)
+	return nil
+}
@@ -0,0 +1,164 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// barWidth is how wide the bar of the busiest week is.
+const barWidth = 12
+
+// Stats is the weekly mix of tasks, notes and events added over a window,
+// and how long tasks took to complete.
+type Stats struct {
+	Since time.Time
+	Until time.Time
+	// Tag only counts entries with this #tag, if set.
+	Tag         string
+	Persistence store.Persistence
+}
+
+// week is what was added and completed in a week.
+type week struct {
+	start                time.Time
+	tasks, notes, events int
+	latencies            []time.Duration
+}
+
+func (n *Stats) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not report, no persistence")
+	}
+	h, ok := n.Persistence.(store.Historian)
+	if !ok {
+		return fmt.Errorf("stats are %w", app.ErrUnsupported)
+	}
+
+	weeks := make(map[time.Time]*week)
+	at := func(t time.Time) *week {
+		start := weekOf(t)
+		w, ok := weeks[start]
+		if !ok {
+			w = &week{start: start}
+			weeks[start] = w
+		}
+		return w
+	}
+
+	all := make([]time.Duration, 0)
+	for _, a := range h.Activity(ctx, n.Since, n.Until) {
+		if n.Tag != "" && !a.Entry.HasTag(n.Tag) {
+			continue
+		}
+		switch a.Kind {
+		case store.ActivityAdded:
+			w := at(a.At)
+			switch kindOf(a.Entry) {
+			case glyph.Task:
+				w.tasks++
+			case glyph.Note:
+				w.notes++
+			case glyph.Event:
+				w.events++
+			}
+		case store.ActivityCompleted:
+			latency := a.At.Sub(a.Entry.Created.Time)
+			w := at(a.At)
+			w.latencies = append(w.latencies, latency)
+			all = append(all, latency)
+		}
+	}
+
+	ordered := make([]*week, 0, len(weeks))
+	for _, w := range weeks {
+		ordered = append(ordered, w)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].start.Before(ordered[j].start)
+	})
+
+	pp := printers.PrettyPrint{}
+	fmt.Println("")
+	pp.Title(fmt.Sprintf("Stats since %s", n.Since.Format("January 2, 2006")))
+	if len(ordered) == 0 {
+		fmt.Println(" none")
+		return nil
+	}
+
+	max := 0
+	for _, w := range ordered {
+		for _, c := range []int{w.tasks, w.notes, w.events} {
+			if c > max {
+				max = c
+			}
+		}
+	}
+	fmt.Printf("%-8s %-*s %-*s %-*s\n", "week of", barWidth+4, "tasks", barWidth+4, "notes", barWidth+4, "events")
+	for _, w := range ordered {
+		fmt.Printf("%-8s", w.start.Format("Jan 2"))
+		for _, c := range []int{w.tasks, w.notes, w.events} {
+			fmt.Printf(" %s %3d", printers.Bar(c, max, barWidth), c)
+		}
+		fmt.Println("")
+	}
+
+	fmt.Println("")
+	if len(all) == 0 {
+		fmt.Println("no tasks completed")
+		return nil
+	}
+	fmt.Printf("completed %d tasks, median %s, mean %s from add to complete\n", len(all), printers.Duration(median(all)), printers.Duration(mean(all)))
+	medians := make([]float64, 0, len(ordered))
+	for _, w := range ordered {
+		if len(w.latencies) > 0 {
+			medians = append(medians, float64(median(w.latencies)))
+		}
+	}
+	fmt.Printf("weekly median %s\n", printers.Sparkline(medians))
+	return nil
+}
+
+// kindOf is the bullet an entry was added as: a task, note or event. Moved
+// entries are counted where they were moved to, and tracks are not counted.
+func kindOf(e *entry.Entry) glyph.Bullet {
+	switch e.Bullet {
+	case glyph.Task, glyph.Completed, glyph.Irrelevant, glyph.Waiting:
+		return glyph.Task
+	case glyph.Note, glyph.Event:
+		return e.Bullet
+	default:
+		return glyph.Any
+	}
+}
+
+// weekOf returns the start of the week of t, on Sunday like the calendar.
+func weekOf(t time.Time) time.Time {
+	t = t.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+func median(ds []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return sorted[len(sorted)/2]
+}
+
+func mean(ds []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	return sum / time.Duration(len(ds))
+}
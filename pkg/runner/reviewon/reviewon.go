@@ -0,0 +1,51 @@
+package reviewon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// ReviewOn sets the day an entry comes back up in the day log to be looked
+// at again.
+type ReviewOn struct {
+	ID string
+	// On is the day of the review, nil clears it.
+	On          *time.Time
+	Persistence store.Persistence
+}
+
+func (n *ReviewOn) Do(ctx context.Context) error {
+	pp := printers.PrettyPrint{ShowID: true}
+
+	if n.Persistence == nil {
+		return errors.New("can not set review, no persistence")
+	}
+
+	all := n.Persistence.ListAll(ctx)
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	e.ReviewOn = nil
+	if n.On != nil {
+		e.ReviewOn = &entry.Timestamp{Time: *n.On}
+	}
+	if err := n.Persistence.Store(e); err != nil {
+		return err
+	}
+
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
+	fmt.Println("")
+	pp.Title(e.Collection)
+	pp.Collection(all...)
+
+	return nil
+}
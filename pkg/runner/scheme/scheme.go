@@ -0,0 +1,87 @@
+package scheme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Migrate renames the dated collections of the journal to a collection
+// naming scheme.
+type Migrate struct {
+	Scheme collection.Scheme
+	// DryRun only reports what would be renamed.
+	DryRun bool
+	// SessionPath is the ui session, the collection it remembers is renamed
+	// too, if set.
+	SessionPath string
+
+	Persistence store.Persistence
+}
+
+func (n *Migrate) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not migrate, no persistence")
+	}
+	r, ok := n.Persistence.(store.Renamer)
+	if !ok {
+		return fmt.Errorf("renaming collections is %w", app.ErrUnsupported)
+	}
+
+	all := n.Persistence.Collections(ctx, "")
+	collection.Sort(all)
+
+	renames := make(map[string]string)
+	order := make([]string, 0)
+	for _, c := range all {
+		if to, ok := n.Scheme.Rename(c); ok {
+			renames[c] = to
+			order = append(order, c)
+		}
+	}
+	if len(order) == 0 {
+		fmt.Printf("all dated collections are named with the %s scheme\n", n.Scheme.Name)
+		return nil
+	}
+
+	for i, from := range order {
+		to := renames[from]
+		if n.DryRun {
+			fmt.Printf("[%d/%d] %s -> %s\n", i+1, len(order), from, to)
+			continue
+		}
+		moved, err := r.Rename(ctx, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to rename %s: %v", from, err)
+		}
+		fmt.Printf("[%d/%d] %s -> %s, %d entries\n", i+1, len(order), from, to, moved)
+	}
+	if n.DryRun {
+		return nil
+	}
+
+	return n.renameSession(renames)
+}
+
+// renameSession updates the collection the ui session remembers.
+func (n *Migrate) renameSession(renames map[string]string) error {
+	if n.SessionPath == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(n.SessionPath)
+	if err != nil {
+		// No session yet.
+		return nil
+	}
+	to, ok := renames[strings.TrimSpace(string(b))]
+	if !ok {
+		return nil
+	}
+	return ioutil.WriteFile(n.SessionPath, []byte(to+"\n"), 0644)
+}
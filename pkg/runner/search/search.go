@@ -0,0 +1,169 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Search finds entries by the words, #tags and @mentions of their messages.
+// It uses the index of the store when it has one, and reads every entry
+// otherwise.
+type Search struct {
+	// Query is the terms an entry must all have, see store.Terms.
+	Query string
+	// Tags lists the #tags and @mentions in use instead, with their counts.
+	Tags bool
+	// Links is a collection to find the backlinks of: the entries in other
+	// collections that mention its name.
+	Links string
+	// ShowID shows the ref of each entry, to jump to it with other commands.
+	ShowID bool
+	// Archived searches the archived collections too.
+	Archived bool
+
+	Persistence store.Persistence
+}
+
+func (n *Search) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not search, no persistence")
+	}
+	if n.Tags {
+		return n.tags(ctx)
+	}
+
+	query, title := n.Query, fmt.Sprintf("Search %q", n.Query)
+	if n.Links != "" {
+		query, title = n.Links, fmt.Sprintf("Links to %s", n.Links)
+	}
+	found, err := n.find(ctx, query)
+	if err != nil {
+		return err
+	}
+	if n.Links != "" {
+		linked := make([]*entry.Entry, 0, len(found))
+		for _, e := range found {
+			if e.Collection != n.Links {
+				linked = append(linked, e)
+			}
+		}
+		found = linked
+	}
+
+	pp := printers.PrettyPrint{ShowID: n.ShowID}
+	if n.ShowID {
+		pp.Refs = ref.Refs(n.Persistence.ListAll(ctx))
+	}
+	fmt.Println("")
+	pp.TitleWithCount(title, len(found))
+	if len(found) == 0 {
+		fmt.Println(" none")
+		return nil
+	}
+
+	// Grouped by collection, in the order the collections sort.
+	byCollection := make(map[string][]*entry.Entry)
+	collections := make([]string, 0)
+	for _, e := range found {
+		if _, ok := byCollection[e.Collection]; !ok {
+			collections = append(collections, e.Collection)
+		}
+		byCollection[e.Collection] = append(byCollection[e.Collection], e)
+	}
+	sort.Strings(collections)
+	for _, c := range collections {
+		fmt.Println("")
+		pp.Title(c)
+		pp.Collection(byCollection[c]...)
+	}
+	return nil
+}
+
+// find returns the entries with every term of query, and the archived ones
+// if asked for.
+func (n *Search) find(ctx context.Context, query string) ([]*entry.Entry, error) {
+	terms := store.Terms(query)
+	var found []*entry.Entry
+	if s, ok := n.Persistence.(store.Searcher); ok {
+		found = s.Search(ctx, query)
+	} else {
+		found = matching(terms, n.Persistence.ListAll(ctx))
+	}
+	if !n.Archived {
+		return found, nil
+	}
+	a, ok := n.Persistence.(store.Archiver)
+	if !ok {
+		return nil, fmt.Errorf("searching the archive is %w", app.ErrUnsupported)
+	}
+	return append(found, matching(terms, a.ListArchived(ctx))...), nil
+}
+
+// matching returns the entries with every one of terms.
+func matching(terms []string, entries []*entry.Entry) []*entry.Entry {
+	found := make([]*entry.Entry, 0)
+	if len(terms) == 0 {
+		return found
+	}
+	for _, e := range entries {
+		has := make(map[string]bool)
+		for _, t := range store.Terms(e.Message) {
+			has[t] = true
+		}
+		all := true
+		for _, t := range terms {
+			all = all && has[t]
+		}
+		if all {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// tags prints the #tags and @mentions in use, most used first.
+func (n *Search) tags(ctx context.Context) error {
+	counts := make(map[string]int)
+	if s, ok := n.Persistence.(store.Searcher); ok {
+		counts = s.Tags(ctx)
+	} else {
+		for _, e := range n.Persistence.ListAll(ctx) {
+			for _, t := range store.Terms(e.Message) {
+				if t[0] == '#' || t[0] == '@' {
+					counts[t]++
+				}
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(counts))
+	for t := range counts {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+
+	pp := printers.PrettyPrint{}
+	fmt.Println("")
+	pp.Title("Tags and mentions")
+	if len(tags) == 0 {
+		fmt.Println(" none")
+		return nil
+	}
+	for _, t := range tags {
+		fmt.Printf(" %-24s %d\n", t, counts[t])
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/logging"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/runner/batch"
+	"tableflip.dev/bujo/pkg/runner/report"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// API is the http/json api of the journal, the routes are listed in bujo
+// serve --help. Changes are made as a batch of one op and answered with its
+// report, errors are answered as {"error", "hint"}.
+type API struct {
+	ReadOnly bool
+	// IncludePrivate serves private entries too. Without it they are left
+	// out, whatever a request asks for.
+	IncludePrivate bool
+
+	Persistence store.Persistence
+}
+
+// record is an entry with its id and ref, neither is part of the entry
+// json.
+type record struct {
+	ID    string       `json:"id"`
+	Ref   string       `json:"ref,omitempty"`
+	Entry *entry.Entry `json:"entry"`
+}
+
+// records are the entries with their refs among all.
+func records(all, entries []*entry.Entry) []record {
+	refs := ref.Refs(all)
+	r := make([]record, 0, len(entries))
+	for _, e := range entries {
+		r = append(r, record{ID: e.ID, Ref: refs[e.ID], Entry: e})
+	}
+	return r
+}
+
+// visible are the entries that are served.
+func (a *API) visible(entries []*entry.Entry) []*entry.Entry {
+	if a.IncludePrivate {
+		return entries
+	}
+	return entry.WithoutPrivate(entries)
+}
+
+// visibleEvent is ev as it is served. A private entry that was added
+// is left out, and one that changed is sent as removed, it may have just
+// been made private. ok is false if nothing of ev is left to send.
+func (a *API) visibleEvent(ev store.Event) (store.Event, bool) {
+	if len(ev.Changes) == 0 || a.IncludePrivate {
+		return ev, true
+	}
+	changes := make([]store.Change, 0, len(ev.Changes))
+	for _, c := range ev.Changes {
+		switch {
+		case c.Entry == nil || !c.Entry.Private:
+			changes = append(changes, c)
+		case c.Kind == store.ChangeModified:
+			changes = append(changes, store.Change{ID: c.ID, Kind: store.ChangeRemoved})
+		}
+	}
+	ev.Changes = changes
+	return ev, len(changes) > 0
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logging.Debug("api request", "method", r.Method, "path", r.URL.Path)
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/v1/collections":
+		a.get(w, r, a.collections)
+	case path == "/v1/entries" && r.Method == http.MethodPost:
+		a.change(w, r, "", batch.OpAdd)
+	case path == "/v1/entries":
+		a.get(w, r, a.entries)
+	case strings.HasPrefix(path, "/v1/entries/"):
+		id := strings.TrimPrefix(path, "/v1/entries/")
+		switch {
+		case strings.HasSuffix(id, "/complete"):
+			a.change(w, r, strings.TrimSuffix(id, "/complete"), batch.OpComplete)
+		case strings.HasSuffix(id, "/move"):
+			a.change(w, r, strings.TrimSuffix(id, "/move"), batch.OpMove)
+		case !strings.Contains(id, "/"):
+			a.get(w, r, func(w http.ResponseWriter, r *http.Request) { a.entry(w, r, id) })
+		default:
+			writeError(w, http.StatusNotFound, fmt.Errorf("no api at %s", r.URL.Path))
+		}
+	case path == "/v1/report/notes":
+		a.get(w, r, a.notes)
+	case path == "/v1/events":
+		a.get(w, r, a.events)
+	case path == "/v1/watch":
+		a.get(w, r, a.watch)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no api at %s", r.URL.Path))
+	}
+}
+
+// get only lets GET requests through to fn.
+func (a *API) get(w http.ResponseWriter, r *http.Request, fn http.HandlerFunc) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed", r.Method))
+		return
+	}
+	fn(w, r)
+}
+
+func (a *API) collections(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]string{
+		"collections": a.Persistence.Collections(r.Context(), ""),
+	})
+}
+
+func (a *API) entries(w http.ResponseWriter, r *http.Request) {
+	all := a.Persistence.ListAll(r.Context())
+	name := r.URL.Query().Get("collection")
+	if name == "" {
+		writeJSON(w, http.StatusOK, records(all, a.visible(all)))
+		return
+	}
+	entries := a.visible(a.Persistence.List(r.Context(), name))
+	if len(entries) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", app.ErrCollectionNotFound, name))
+		return
+	}
+	writeJSON(w, http.StatusOK, records(all, entries))
+}
+
+func (a *API) entry(w http.ResponseWriter, r *http.Request, id string) {
+	all := a.Persistence.ListAll(r.Context())
+	e, err := ref.Resolve(all, id)
+	if err == nil && len(a.visible([]*entry.Entry{e})) == 0 {
+		err = fmt.Errorf("%w: %s", app.ErrEntryNotFound, id)
+	}
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, records(all, []*entry.Entry{e})[0])
+}
+
+func (a *API) notes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day()-7, 0, 0, 0, 0, time.Local)
+	if s := q.Get("since"); s != "" {
+		var err error
+		if since, err = time.ParseInLocation("2006-01-02", s, time.Local); err != nil {
+			writeError(w, http.StatusBadRequest, app.Invalid("since", s, "expected a date like 2006-01-02"))
+			return
+		}
+	}
+	label, err := glyph.LabelFor(q.Get("label"))
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	group := q.Get("group")
+	switch group {
+	case "", report.GroupDay, report.GroupCollection:
+	default:
+		writeError(w, http.StatusBadRequest, app.Invalid("group", group, "expected day or collection"))
+		return
+	}
+	n := report.Notes{
+		Since:       since,
+		Label:       label,
+		Tag:         strings.TrimPrefix(q.Get("tag"), "#"),
+		Group:       group,
+		Persistence: a.Persistence,
+
+		IncludePrivate: a.IncludePrivate,
+	}
+	type section struct {
+		Title   string   `json:"title"`
+		Entries []record `json:"entries"`
+	}
+	all := a.Persistence.ListAll(r.Context())
+	sections := make([]section, 0)
+	for _, s := range n.Sections(r.Context()) {
+		sections = append(sections, section{Title: s.Title, Entries: records(all, s.Entries)})
+	}
+	writeJSON(w, http.StatusOK, map[string][]section{"sections": sections})
+}
+
+// change applies a change to the entry with id as a batch of one op, with
+// the collection, bullet and message of the body.
+func (a *API) change(w http.ResponseWriter, r *http.Request, id, op string) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed", r.Method))
+		return
+	}
+	if a.ReadOnly {
+		writeError(w, http.StatusForbidden, errors.New("the journal is served read-only"))
+		return
+	}
+	// A browser only sends json to another origin once it is let to, so
+	// pages can not make changes with a form or a simple fetch.
+	if t, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || t != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("changes are sent as application/json"))
+		return
+	}
+	o := batch.Op{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("reading the body: %v", err))
+			return
+		}
+	}
+	o.Op, o.ID = op, id
+	line, err := json.Marshal(o)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := &bytes.Buffer{}
+	b := batch.Batch{
+		In:          bytes.NewReader(line),
+		Out:         out,
+		Persistence: a.Persistence,
+	}
+	status := http.StatusOK
+	if err := b.Do(r.Context()); err != nil {
+		status = statusOf(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(out.Bytes())
+}
+
+// events streams the changes to the journal as they are seen, one event of
+// json per collection that changed.
+func (a *API) events(w http.ResponseWriter, r *http.Request) {
+	events, flusher, ok := a.stream(w, r)
+	if !ok {
+		return
+	}
+	for ev := range events {
+		ev, ok := a.visibleEvent(ev)
+		if !ok {
+			continue
+		}
+		if err := send(w, flusher, "change", ev); err != nil {
+			return
+		}
+	}
+}
+
+// watch streams the changes to one collection, for clients that only
+// track it. It starts with a snapshot of the entries, and sends another
+// whenever the collection has to be read again, or a day alias like today
+// moves on to the next day.
+func (a *API) watch(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("collection")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, app.Invalid("collection", name, "expected a collection to watch"))
+		return
+	}
+	events, flusher, ok := a.stream(w, r)
+	if !ok {
+		return
+	}
+	watched := collection.Resolve(name)
+	if err := a.snapshot(w, flusher, r, watched); err != nil {
+		return
+	}
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Collection != watched {
+				continue
+			}
+			var err error
+			if len(ev.Changes) == 0 {
+				err = a.snapshot(w, flusher, r, watched)
+			} else if ev, ok := a.visibleEvent(ev); ok {
+				err = send(w, flusher, "change", ev)
+			}
+			if err != nil {
+				return
+			}
+		case <-ticker.C:
+			if now := collection.Resolve(name); now != watched {
+				watched = now
+				if err := a.snapshot(w, flusher, r, watched); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// snapshot sends the entries of a collection that are served as a
+// snapshot event.
+func (a *API) snapshot(w http.ResponseWriter, flusher http.Flusher, r *http.Request, name string) error {
+	type snapshot struct {
+		Collection string   `json:"collection"`
+		Entries    []record `json:"entries"`
+	}
+	all := a.Persistence.ListAll(r.Context())
+	return send(w, flusher, "snapshot", snapshot{
+		Collection: name,
+		Entries:    records(all, a.visible(a.Persistence.List(r.Context(), name))),
+	})
+}
+
+// stream starts a stream of server-sent events, with the changes to the
+// journal to send. It answers with an error if it can not.
+func (a *API) stream(w http.ResponseWriter, r *http.Request) (<-chan store.Event, http.Flusher, bool) {
+	watcher, ok := a.Persistence.(store.Watcher)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("events are %w", app.ErrUnsupported))
+		return nil, nil, false
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("events can not be streamed"))
+		return nil, nil, false
+	}
+	events, err := watcher.Watch(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, nil, false
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return events, flusher, true
+}
+
+// send sends v as a server-sent event of json. An error is only returned
+// if the client is gone.
+func send(w http.ResponseWriter, flusher http.Flusher, event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logging.Warn("skipping an event", "event", event, "err", err)
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// statusOf is the http status of an error of the journal.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, app.ErrEntryNotFound), errors.Is(err, app.ErrCollectionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrAmbiguousRef), errors.Is(err, app.ErrValidation),
+		errors.Is(err, app.ErrInvalidBullet), errors.Is(err, app.ErrInvalidLabel):
+		return http.StatusBadRequest
+	case errors.Is(err, app.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, app.ErrUnsupported):
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	body := map[string]string{"error": err.Error()}
+	if hint := app.Hint(err); hint != "" {
+		body["hint"] = hint
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		logging.Warn("writing a response", "err", err)
+	}
+}
@@ -0,0 +1,254 @@
+package serve
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// memory is a journal of the entries it is given, with events sent through
+// its FakeWatcher.
+type memory struct {
+	*store.FakeWatcher
+	entries []*entry.Entry
+}
+
+func (m *memory) MapAll(ctx context.Context) map[string][]*entry.Entry {
+	all := make(map[string][]*entry.Entry)
+	for _, e := range m.entries {
+		all[e.Collection] = append(all[e.Collection], e)
+	}
+	return all
+}
+
+func (m *memory) ListAll(ctx context.Context) []*entry.Entry { return m.entries }
+
+func (m *memory) List(ctx context.Context, collection string) []*entry.Entry {
+	return m.MapAll(ctx)[collection]
+}
+
+func (m *memory) Collections(ctx context.Context, prefix string) []string {
+	names := make([]string, 0)
+	for name := range m.MapAll(ctx) {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (m *memory) Store(e *entry.Entry) error { return nil }
+
+// journal has a public and a private entry in Project.
+func journal() *memory {
+	public := entry.New("Project", glyph.Task, "public")
+	public.ID = "aaaa1111"
+	private := entry.New("Project", glyph.Task, "private")
+	private.ID = "bbbb2222"
+	private.Private = true
+	return &memory{FakeWatcher: store.NewFakeWatcher(), entries: []*entry.Entry{public, private}}
+}
+
+func get(t *testing.T, a *API, url string) (int, []record) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	if w.Code != http.StatusOK {
+		return w.Code, nil
+	}
+	var got []record
+	if strings.HasPrefix(url, "/v1/entries/") {
+		var one record
+		if err := json.Unmarshal(w.Body.Bytes(), &one); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, []record{one}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	return w.Code, got
+}
+
+func messages(records []record) string {
+	m := make([]string, 0, len(records))
+	for _, r := range records {
+		m = append(m, r.Entry.Message)
+	}
+	return strings.Join(m, ",")
+}
+
+func TestPrivateEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		include bool
+		url     string
+		status  int
+		want    string
+	}{
+		{"all", false, "/v1/entries", http.StatusOK, "public"},
+		{"collection", false, "/v1/entries?collection=Project", http.StatusOK, "public"},
+		{"public entry", false, "/v1/entries/aaaa1111", http.StatusOK, "public"},
+		{"private entry", false, "/v1/entries/bbbb2222", http.StatusNotFound, ""},
+		{"asked for", false, "/v1/entries?include_private=true", http.StatusOK, "public"},
+		{"asked for entry", false, "/v1/entries/bbbb2222?include_private=true", http.StatusNotFound, ""},
+		{"served", true, "/v1/entries?collection=Project", http.StatusOK, "public,private"},
+		{"served entry", true, "/v1/entries/bbbb2222", http.StatusOK, "private"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &API{IncludePrivate: tt.include, Persistence: journal()}
+			status, got := get(t, a, tt.url)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if m := messages(got); m != tt.want {
+				t.Errorf("got %q, want %q", m, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleEvent(t *testing.T) {
+	j := journal()
+	public, private := j.entries[0], j.entries[1]
+	ev := store.Event{Collection: "Project", Changes: []store.Change{
+		{ID: public.ID, Kind: store.ChangeModified, Entry: public},
+		{ID: private.ID, Kind: store.ChangeAdded, Entry: private},
+		{ID: private.ID, Kind: store.ChangeModified, Entry: private},
+		{ID: "cccc3333", Kind: store.ChangeRemoved},
+	}}
+	a := &API{Persistence: j}
+
+	got, ok := a.visibleEvent(ev)
+	if !ok {
+		t.Fatal("nothing left of the event")
+	}
+	want := []store.Change{
+		{ID: public.ID, Kind: store.ChangeModified, Entry: public},
+		{ID: private.ID, Kind: store.ChangeRemoved},
+		{ID: "cccc3333", Kind: store.ChangeRemoved},
+	}
+	if len(got.Changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(got.Changes), len(want))
+	}
+	for i := range want {
+		if got.Changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got.Changes[i], want[i])
+		}
+	}
+
+	added := store.Event{Collection: "Project", Changes: ev.Changes[1:2]}
+	if _, ok := a.visibleEvent(added); ok {
+		t.Error("an event of only a private entry added is sent")
+	}
+	served := &API{IncludePrivate: true, Persistence: j}
+	if got, _ := served.visibleEvent(ev); len(got.Changes) != len(ev.Changes) {
+		t.Errorf("got %d changes when private entries are served, want %d", len(got.Changes), len(ev.Changes))
+	}
+}
+
+// next reads the next server-sent event.
+func next(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var event, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestWatchPrivateEntries(t *testing.T) {
+	j := journal()
+	public, private := j.entries[0], j.entries[1]
+	srv := httptest.NewServer(&API{Persistence: j})
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v1/watch?collection=Project", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body := bufio.NewReader(resp.Body)
+
+	event, data := next(t, body)
+	var snapshot struct {
+		Entries []record `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if event != "snapshot" || messages(snapshot.Entries) != "public" {
+		t.Fatalf("got %s of %q, want a snapshot of %q", event, messages(snapshot.Entries), "public")
+	}
+
+	// The private entry is left out, the change after it is the next event.
+	j.Send(
+		store.Event{Collection: "Project", Changes: []store.Change{{ID: private.ID, Kind: store.ChangeAdded, Entry: private}}},
+		store.Event{Collection: "Project", Changes: []store.Change{{ID: public.ID, Kind: store.ChangeModified, Entry: public}}},
+	)
+	event, data = next(t, body)
+	var ev store.Event
+	if err := json.Unmarshal([]byte(data), &ev); err != nil {
+		t.Fatal(err)
+	}
+	if event != "change" || len(ev.Changes) != 1 || ev.Changes[0].ID != public.ID {
+		t.Errorf("got %s of %s, want a change of %s", event, data, public.ID)
+	}
+}
+
+func TestChangeNeedsJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		refused     bool
+	}{
+		{"no body", "", "", true},
+		{"text", "text/plain", `{"collection": "Other"}`, true},
+		{"form", "application/x-www-form-urlencoded", "collection=Other", true},
+		{"json", "application/json", `{"collection": "Other"}`, false},
+		{"json with charset", "application/json; charset=utf-8", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &API{Persistence: journal()}
+			r := httptest.NewRequest(http.MethodPost, "/v1/entries/aaaa1111/complete", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			a.ServeHTTP(w, r)
+			// memory can not make changes, so json gets no further than
+			// the batch.
+			if refused := w.Code == http.StatusUnsupportedMediaType; refused != tt.refused {
+				t.Errorf("status = %d, refused %t, want %t: %s", w.Code, refused, tt.refused, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/logging"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// DefaultAddr is where the api listens when the config does not say, only
+// this host can reach it.
+const DefaultAddr = "localhost:8080"
+
+// shutdownWait is how long requests are given to finish once the server is
+// stopped.
+const shutdownWait = 5 * time.Second
+
+// Serve serves the journal as an http/json api until ctx is done, or
+// another handler over the journal.
+type Serve struct {
+	// Addr is the host and port to listen on, DefaultAddr if empty.
+	Addr string
+	// Token is expected as a bearer token on every request. It can only be
+	// empty if Addr is on the loopback interface, and then the journal is
+	// served read-only.
+	Token string
+	// ReadOnly refuses every request that would change the journal.
+	ReadOnly bool
+	// IncludePrivate serves private entries too.
+	IncludePrivate bool
+	// Handler is served, behind the token, the API if nil.
+	Handler http.Handler
+
+	Persistence store.Persistence
+}
+
+func (n *Serve) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not serve, no persistence")
+	}
+	addr := n.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	if n.Token == "" && !loopback(addr) {
+		return fmt.Errorf("can not serve on %s without a token, set serve.token in config", addr)
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	// Without a token any page open in a browser on this host can send
+	// requests, so it can only read.
+	readOnly := n.ReadOnly || n.Token == ""
+	h, what := n.Handler, "the journal"
+	if h == nil {
+		h, what = &API{ReadOnly: readOnly, IncludePrivate: n.IncludePrivate, Persistence: n.Persistence}, "the journal read-write"
+		if readOnly {
+			what = "the journal read-only"
+		}
+	}
+	srv := &http.Server{Handler: requireHost(addr, requireToken(n.Token, h))}
+	// Event streams only end with the server, or the client.
+	srv.BaseContext = func(net.Listener) context.Context { return ctx }
+
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), shutdownWait)
+		defer cancel()
+		done <- srv.Shutdown(sctx)
+	}()
+
+	fmt.Printf("serving %s on http://%s\n", what, l.Addr())
+	logging.Info("serving", "addr", l.Addr().String(), "readOnly", readOnly, "token", n.Token != "")
+
+	if err := srv.Serve(l); err != http.ErrServerClosed {
+		return err
+	}
+	return <-done
+}
+
+// requireToken only lets requests with token through to h, as a bearer
+// token or a token query parameter for clients that can not set headers,
+// like calendar apps. Every request is let through if token is empty.
+func requireToken(token string, h http.Handler) http.Handler {
+	if token == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query().Get("token")
+		if a := r.Header.Get("Authorization"); strings.HasPrefix(a, "Bearer ") {
+			got = strings.TrimPrefix(a, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or wrong token"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// requireHost only lets requests for a loopback host through to h, if addr
+// is on the loopback interface. A page that rebinds its own name to this
+// host can not read the journal then.
+func requireHost(addr string, h http.Handler) http.Handler {
+	if !loopback(addr) {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			// No port.
+			host = strings.Trim(r.Host, "[]")
+		}
+		if !loopbackHost(host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%s is not this host", r.Host))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// loopback is true if addr can only be reached from this host.
+func loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	return loopbackHost(host)
+}
+
+// loopbackHost is true if host names this host.
+func loopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,36 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireHost(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		addr   string
+		host   string
+		status int
+	}{
+		{"localhost:8080", "localhost:8080", http.StatusOK},
+		{"localhost:8080", "LOCALHOST", http.StatusOK},
+		{"localhost:8080", "127.0.0.1:8080", http.StatusOK},
+		{"localhost:8080", "[::1]:8080", http.StatusOK},
+		{"localhost:8080", "[::1]", http.StatusOK},
+		{"localhost:8080", "rebound.example.com:8080", http.StatusForbidden},
+		{"127.0.0.1:8080", "10.0.0.2:8080", http.StatusForbidden},
+		{":8080", "journal.example.com", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr+" "+tt.host, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/collections", nil)
+			r.Host = tt.host
+			w := httptest.NewRecorder()
+			requireHost(tt.addr, ok).ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package share
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// clipboards are the commands tried, in order, to copy to the clipboard.
+var clipboards = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+}
+
+// CopyToClipboard copies text to the system clipboard using the first
+// clipboard tool that is found on the path.
+func CopyToClipboard(text string) error {
+	for _, c := range clipboards {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return errors.New("no clipboard tool found")
+}
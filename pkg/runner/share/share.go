@@ -0,0 +1,73 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+type Share struct {
+	ID          string
+	Collection  string
+	Target      Target
+	Persistence store.Persistence
+	// IncludePrivate shares private entries too.
+	IncludePrivate bool
+}
+
+func (n *Share) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not share, no persistence")
+	}
+	if n.Target == nil {
+		return errors.New("can not share, no share target")
+	}
+
+	name, content, err := n.render(ctx)
+	if err != nil {
+		return err
+	}
+
+	url, err := n.Target.Upload(ctx, name, content)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("")
+	fmt.Println(url)
+	if err := CopyToClipboard(url); err == nil {
+		fmt.Println("(copied to clipboard)")
+	}
+	return nil
+}
+
+func (n *Share) render(ctx context.Context) (string, string, error) {
+	if n.ID != "" {
+		e, err := ref.Resolve(n.Persistence.ListAll(ctx), n.ID)
+		if err != nil {
+			return "", "", err
+		}
+		if e.Private && !n.IncludePrivate {
+			return "", "", app.Invalid("entry", n.ID, "it is private, share it with --include-private")
+		}
+		return e.Collection, printers.Markdown(e.Collection, e), nil
+	}
+
+	n.Collection = collection.Resolve(n.Collection)
+	if n.Collection == "" {
+		return "", "", errors.New("an entry id or collection is required to share")
+	}
+
+	all := n.Persistence.List(ctx, n.Collection)
+	if !n.IncludePrivate {
+		all = entry.WithoutPrivate(all)
+	}
+	return n.Collection, printers.Markdown(n.Collection, all...), nil
+}
@@ -0,0 +1,163 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Target is somewhere a rendered collection can be uploaded to.
+type Target interface {
+	// Upload sends content to the target and returns the URL it can be
+	// viewed at.
+	Upload(ctx context.Context, name, content string) (string, error)
+}
+
+const (
+	TargetGist = "gist"
+	TargetPost = "post"
+
+	defaultGistEndpoint = "https://api.github.com/gists"
+)
+
+// TargetFor returns the share target for the given kind.
+func TargetFor(kind, url, token string, public bool) (Target, error) {
+	switch kind {
+	case TargetGist, "":
+		if token == "" {
+			return nil, errors.New("a token is required to share to a gist")
+		}
+		return &Gist{Endpoint: url, Token: token, Public: public}, nil
+	case TargetPost:
+		if url == "" {
+			return nil, errors.New("a url is required to share with post")
+		}
+		return &Post{URL: url, Token: token}, nil
+	default:
+		return nil, fmt.Errorf("unknown share target: %s", kind)
+	}
+}
+
+// Gist uploads content as a GitHub gist.
+type Gist struct {
+	Endpoint string
+	Token    string
+	Public   bool
+}
+
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+type gistRequest struct {
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	Files       map[string]gistFile `json:"files"`
+}
+
+type gistResponse struct {
+	HTMLURL string `json:"html_url"`
+}
+
+func (g *Gist) Upload(ctx context.Context, name, content string) (string, error) {
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGistEndpoint
+	}
+
+	body, err := json.Marshal(gistRequest{
+		Description: name,
+		Public:      g.Public,
+		Files: map[string]gistFile{
+			fileName(name): {Content: content},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+g.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to create gist: %s", resp.Status)
+	}
+
+	gr := gistResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return "", err
+	}
+	return gr.HTMLURL, nil
+}
+
+// Post uploads content to a generic pastebin-like endpoint. The endpoint is
+// expected to respond with the URL of the paste, either in the Location
+// header or as the body of the response.
+type Post struct {
+	URL   string
+	Token string
+}
+
+func (p *Post) Upload(ctx context.Context, name, content string) (string, error) {
+	req, err := http.NewRequest(http.MethodPost, p.URL, strings.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "text/markdown; charset=utf-8")
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("failed to post: %s", resp.Status)
+	}
+
+	if loc := resp.Header.Get("Location"); loc != "" {
+		return loc, nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	url := strings.TrimSpace(string(b))
+	if url == "" {
+		return "", errors.New("post did not return a url")
+	}
+	return url, nil
+}
+
+func fileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '/', '\\', ',':
+			return '-'
+		}
+		return r
+	}, name)
+	return name + ".md"
+}
@@ -0,0 +1,190 @@
+package shutdown
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Steps of the shutdown routine.
+const (
+	// StepReview walks the open tasks of today.
+	StepReview = "review"
+	// StepInbox walks the open tasks of the inbox collection.
+	StepInbox = "inbox"
+	// StepPriorities adds the top priorities for tomorrow.
+	StepPriorities = "priorities"
+	// StepReflect adds a reflection note to today.
+	StepReflect = "reflect"
+)
+
+// DefaultSteps are the steps when none are configured.
+func DefaultSteps() []string {
+	return []string{StepReview, StepInbox, StepPriorities, StepReflect}
+}
+
+// maxPriorities is how many priorities are asked for.
+const maxPriorities = 3
+
+// Shutdown walks the end of day checklist.
+type Shutdown struct {
+	Steps []string
+	// Inbox is the collection reviewed by StepInbox.
+	Inbox string
+	// On is the day being shut down.
+	On time.Time
+	// In is where answers are read from, defaults to stdin.
+	In io.Reader
+	// AskReason asks why a task is struck.
+	AskReason bool
+
+	Persistence store.Persistence
+
+	in *bufio.Reader
+}
+
+func (n *Shutdown) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not shutdown, no persistence")
+	}
+	in := n.In
+	if in == nil {
+		in = os.Stdin
+	}
+	n.in = bufio.NewReader(in)
+
+	for i, step := range n.Steps {
+		fmt.Printf("\n[%d/%d] ", i+1, len(n.Steps))
+		var err error
+		switch step {
+		case StepReview:
+			fmt.Println("Review today's open tasks")
+			err = n.review(ctx, collection.DayOf(n.On))
+		case StepInbox:
+			fmt.Printf("Review %s\n", n.Inbox)
+			err = n.review(ctx, n.Inbox)
+		case StepPriorities:
+			fmt.Println("Top priorities for tomorrow")
+			err = n.priorities()
+		case StepReflect:
+			fmt.Println("Reflect on the day")
+			err = n.reflect()
+		default:
+			err = app.Invalid("shutdown step", step, fmt.Sprintf("expected %s", strings.Join(DefaultSteps(), ", ")))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("\nDone for the day.")
+	return nil
+}
+
+// review asks what to do with each open task of the collection.
+func (n *Shutdown) review(ctx context.Context, name string) error {
+	open := make([]*entry.Entry, 0)
+	for _, e := range n.Persistence.List(ctx, name) {
+		if e.Bullet == glyph.Task {
+			open = append(open, e)
+		}
+	}
+	if len(open) == 0 {
+		fmt.Println("  nothing open")
+		return nil
+	}
+
+	tomorrow := collection.DayOf(n.On.AddDate(0, 0, 1))
+	for _, e := range open {
+		answer, err := n.ask(fmt.Sprintf("  %s\n  [c]omplete, [m]igrate to tomorrow, [s]trike, enter to keep: ", e.String()))
+		if err != nil {
+			return err
+		}
+		switch answer {
+		case "c":
+			e.Complete()
+			err = n.Persistence.Store(e)
+		case "m":
+			if e.Collection == tomorrow {
+				continue
+			}
+			moved := e.Move(glyph.MovedCollection, tomorrow)
+			if err = n.Persistence.Store(e); err == nil {
+				err = n.Persistence.Store(moved)
+			}
+		case "s":
+			reason := ""
+			if n.AskReason {
+				if reason, err = n.ask("  why? (enter to skip) "); err != nil {
+					return err
+				}
+			}
+			e.Strike(reason)
+			err = n.Persistence.Store(e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// priorities adds priority tasks to tomorrow.
+func (n *Shutdown) priorities() error {
+	tomorrow := collection.DayOf(n.On.AddDate(0, 0, 1))
+	for i := 1; i <= maxPriorities; i++ {
+		answer, err := n.ask(fmt.Sprintf("  priority %d (enter to finish): ", i))
+		if err != nil {
+			return err
+		}
+		if answer == "" {
+			break
+		}
+		e := entry.New(tomorrow, glyph.Task, answer)
+		e.Signifier = glyph.Priority
+		if err := n.Persistence.Store(e); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("")
+	pp := printers.PrettyPrint{}
+	pp.Title(tomorrow)
+	pp.Collection(n.Persistence.List(context.Background(), tomorrow)...)
+	return nil
+}
+
+// reflect adds a note to today, if one is given.
+func (n *Shutdown) reflect() error {
+	answer, err := n.ask("  reflection (enter to skip): ")
+	if err != nil || answer == "" {
+		return err
+	}
+	return n.Persistence.Store(entry.New(collection.DayOf(n.On), glyph.Note, answer))
+}
+
+// ask prints the question and reads the answer. The end of the input is an
+// empty answer.
+func (n *Shutdown) ask(question string) (string, error) {
+	fmt.Print(question)
+	answer, err := n.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if err == io.EOF {
+		fmt.Println("")
+	}
+	return strings.TrimSpace(answer), nil
+}
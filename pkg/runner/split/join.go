@@ -0,0 +1,77 @@
+package split
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Join merges entries of one collection into the first of them. The first
+// entry keeps its id, so references to it still work, the others are
+// removed.
+type Join struct {
+	IDs []string
+	// Separator goes between the joined messages.
+	Separator string
+
+	Persistence store.Persistence
+}
+
+func (n *Join) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not join, no persistence")
+	}
+	if len(n.IDs) < 2 {
+		return errors.New("at least two entries are required to join")
+	}
+
+	all := n.Persistence.ListAll(ctx)
+	entries := make([]*entry.Entry, 0, len(n.IDs))
+	seen := make(map[string]bool)
+	for _, id := range n.IDs {
+		e, err := ref.Resolve(all, id)
+		if err != nil {
+			return err
+		}
+		if seen[e.ID] {
+			return fmt.Errorf("%s is given more than once", id)
+		}
+		seen[e.ID] = true
+		if len(entries) > 0 && e.Collection != entries[0].Collection {
+			return app.Invalid("entry", id, fmt.Sprintf("it is in %s, only entries of %s can be joined", e.Collection, entries[0].Collection))
+		}
+		entries = append(entries, e)
+	}
+
+	survivor := entries[0]
+	messages := make([]string, 0, len(entries))
+	for _, e := range entries {
+		messages = append(messages, e.Message)
+	}
+	survivor.Message = strings.Join(messages, n.Separator)
+
+	if err := apply(ctx, n.Persistence, func(tx store.Tx) error {
+		for _, e := range entries[1:] {
+			if err := tx.Delete(e); err != nil {
+				return err
+			}
+		}
+		return tx.Store(survivor)
+	}); err != nil {
+		return err
+	}
+
+	pp := printers.PrettyPrint{ShowID: true}
+	pp.Refs = ref.Refs(n.Persistence.ListAll(ctx))
+	fmt.Println("")
+	pp.Title(survivor.Collection)
+	pp.Collection(n.Persistence.List(ctx, survivor.Collection)...)
+	return nil
+}
@@ -0,0 +1,193 @@
+package split
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Split breaks an entry into sibling entries, one for each line of its
+// message, or each part of a compound message split on "; ".
+type Split struct {
+	ID string
+	// At are the parts, from 1, that start a new entry. Every part does if
+	// empty.
+	At []int
+	// Interactive asks which parts start a new entry.
+	Interactive bool
+	// In is where the answer is read from, defaults to stdin.
+	In io.Reader
+
+	Persistence store.Persistence
+}
+
+// parts splits a message into its lines, or the parts of a compound message,
+// and returns the separator to join parts back with.
+func parts(message string) ([]string, string) {
+	sep, join := "\n", "\n"
+	if !strings.Contains(message, sep) {
+		sep, join = ";", "; "
+	}
+	all := make([]string, 0)
+	for _, p := range strings.Split(message, sep) {
+		if p = strings.TrimSpace(p); p != "" {
+			all = append(all, p)
+		}
+	}
+	return all, join
+}
+
+func (n *Split) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not split, no persistence")
+	}
+
+	e, err := ref.Resolve(n.Persistence.ListAll(ctx), n.ID)
+	if err != nil {
+		return err
+	}
+	ps, join := parts(e.Message)
+	if len(ps) < 2 {
+		return fmt.Errorf("nothing to split, %s is a single line", n.ID)
+	}
+
+	if n.Interactive {
+		if n.At, err = n.ask(ps); err != nil {
+			return err
+		}
+	}
+	groups, err := group(ps, join, n.At)
+	if err != nil {
+		return err
+	}
+
+	// The entry keeps the first group, the rest are added after it.
+	all := n.Persistence.List(ctx, e.Collection)
+	e.Message = groups[0]
+	siblings := make([]*entry.Entry, 0, len(groups)-1)
+	moved := make([]*entry.Entry, 0)
+	prev := e
+	for _, g := range groups[1:] {
+		s := entry.New(e.Collection, e.Bullet, g)
+		s.Signifier = e.Signifier
+		s.Label = e.Label
+		var m []*entry.Entry
+		s.Order, m = entry.OrderAfter(all, prev)
+		moved = append(moved, m...)
+		all = append(all, s)
+		entry.Sort(all)
+		siblings = append(siblings, s)
+		prev = s
+	}
+
+	if err := apply(ctx, n.Persistence, func(tx store.Tx) error {
+		for _, s := range append(append([]*entry.Entry{e}, siblings...), moved...) {
+			if err := tx.Store(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	pp := printers.PrettyPrint{ShowID: true}
+	all = n.Persistence.ListAll(ctx)
+	pp.Refs = ref.Refs(all)
+	fmt.Println("")
+	pp.Title(e.Collection)
+	pp.Collection(n.Persistence.List(ctx, e.Collection)...)
+	return nil
+}
+
+// ask prints the parts and reads which of them start a new entry.
+func (n *Split) ask(ps []string) ([]int, error) {
+	for i, p := range ps {
+		fmt.Printf("%3d  %s\n", i+1, p)
+	}
+	fmt.Print("Start a new entry at parts, like 2,4 [all]: ")
+
+	in := n.In
+	if in == nil {
+		in = os.Stdin
+	}
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" || answer == "all" {
+		return nil, nil
+	}
+	at := make([]int, 0)
+	for _, s := range strings.Split(answer, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, app.Invalid("part", s, "expected a part number")
+		}
+		at = append(at, i)
+	}
+	return at, nil
+}
+
+// group joins the parts into the messages of the split entries, a new
+// message starts at each part in at.
+func group(ps []string, join string, at []int) ([]string, error) {
+	if len(at) == 0 {
+		return ps, nil
+	}
+	starts := make(map[int]bool)
+	for _, i := range at {
+		if i < 1 || i > len(ps) {
+			return nil, app.Invalid("part", strconv.Itoa(i), fmt.Sprintf("there are %d parts", len(ps)))
+		}
+		starts[i-1] = true
+	}
+
+	groups := make([]string, 0)
+	current := make([]string, 0)
+	for i, p := range ps {
+		if starts[i] && len(current) > 0 {
+			groups = append(groups, strings.Join(current, join))
+			current = current[:0]
+		}
+		current = append(current, p)
+	}
+	groups = append(groups, strings.Join(current, join))
+	if len(groups) < 2 {
+		return nil, errors.New("nothing to split, pick a part after the first")
+	}
+	return groups, nil
+}
+
+// apply runs fn in a transaction if the store supports them.
+func apply(ctx context.Context, p store.Persistence, fn func(tx store.Tx) error) error {
+	if t, ok := p.(store.Transactor); ok {
+		return t.Transact(ctx, fn)
+	}
+	return fn(&direct{p: p})
+}
+
+// direct stores entries as they are added to the transaction.
+type direct struct {
+	p store.Persistence
+}
+
+func (d *direct) Store(e *entry.Entry) error {
+	return d.p.Store(e)
+}
+
+func (d *direct) Delete(e *entry.Entry) error {
+	return fmt.Errorf("deleting entries is %w", app.ErrUnsupported)
+}
@@ -1,15 +1,27 @@
 package strike
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
 	"tableflip.dev/bujo/pkg/store"
 )
 
 type Strike struct {
-	ID          string
+	ID string
+	// Reason is why the entry is struck, if set.
+	Reason string
+	// Ask asks for the reason if it is not set.
+	Ask bool
+	// In is where the reason is read from, defaults to stdin.
+	In          io.Reader
 	Persistence store.Persistence
 }
 
@@ -20,23 +32,41 @@ func (n *Strike) Do(ctx context.Context) error {
 		return errors.New("can not strike, no persistence")
 	}
 
-	collection := ""
 	all := n.Persistence.ListAll(ctx)
-	for _, e := range all {
-		if e.ID == n.ID {
-			e.Strike()
-			if err := n.Persistence.Store(e); err != nil {
-				return err
-			}
-			collection = e.Collection
-			break
+	e, err := ref.Resolve(all, n.ID)
+	if err != nil {
+		return err
+	}
+	reason := n.Reason
+	if reason == "" && n.Ask {
+		if reason, err = n.ask(e.Message); err != nil {
+			return err
 		}
 	}
+	e.Strike(reason)
+	if err := n.Persistence.Store(e); err != nil {
+		return err
+	}
 
-	all = n.Persistence.List(ctx, collection)
+	pp.Refs = ref.Refs(all)
+	all = n.Persistence.List(ctx, e.Collection)
 	fmt.Println("")
-	pp.Title(collection)
+	pp.Title(e.Collection)
 	pp.Collection(all...)
 
 	return nil
 }
+
+// ask reads why the entry is struck, empty to give no reason.
+func (n *Strike) ask(message string) (string, error) {
+	in := n.In
+	if in == nil {
+		in = os.Stdin
+	}
+	fmt.Printf("Why strike %q? (enter to skip) ", message)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
@@ -0,0 +1,30 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	jsync "tableflip.dev/bujo/pkg/sync"
+)
+
+// Sync syncs the journal with its git repository once.
+type Sync struct {
+	Git *jsync.Git
+}
+
+func (n *Sync) Do(ctx context.Context) error {
+	if n.Git == nil {
+		return errors.New("can not sync, set sync.remote in config")
+	}
+
+	r, err := n.Git.Sync(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("synced with %s: %s\n", n.Git.Remote, r)
+	if r.Conflicts > 0 {
+		fmt.Printf("%d entries were changed on both sides, both versions were kept, review them with 'r' in bujo ui\n", r.Conflicts)
+	}
+	return nil
+}
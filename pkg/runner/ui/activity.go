@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// activityWindow is how far back the activity overlay goes.
+const activityWindow = 7 * 24 * time.Hour
+
+// activityPlacement is the activity overlay, across the top of the screen.
+var activityPlacement = Placement{Width: 90, MaxWidth: 160, Anchor: AnchorTop}
+
+// activity is an overlay with a feed of what happened in the journal.
+type activity struct {
+	active bool
+	// prev is shown again once the overlay is closed.
+	prev tui.Widget
+	// indexFocused is restored once the overlay is closed.
+	indexFocused bool
+}
+
+// toggleActivity shows or hides the activity overlay. Enter on an activity
+// closes the overlay and jumps to its entry.
+func (d *UI) toggleActivity(ctx context.Context, ui tui.UI) {
+	if d.activity.active {
+		d.endActivity(ui)
+		return
+	}
+
+	h, ok := d.Persistence.(store.Historian)
+	if !ok {
+		d.status.SetText("this journal has no activity")
+		return
+	}
+	now := time.Now()
+	all := h.Activity(ctx, now.Add(-activityWindow), now)
+	if len(all) == 0 {
+		d.status.SetText("no activity in the last week")
+		return
+	}
+
+	feed := tui.NewTable(1, 0)
+	for _, a := range all {
+		feed.AppendRow(tui.NewLabel(printers.ActivityLine(a)))
+	}
+	feed.OnItemActivated(func(t *tui.Table) {
+		i := t.Selected()
+		if i < 0 || i >= len(all) {
+			return
+		}
+		d.endActivity(ui)
+		e := all[i].Entry
+		d.pushJump()
+		d.openCollection(e.Collection)
+		d.focusCollection()
+		d.selectEntry(e)
+	})
+	feed.Select(0)
+	feed.SetFocused(true)
+
+	box := tui.NewVBox(feed, tui.NewSpacer())
+	box.SetBorder(true)
+	box.SetTitle("activity (enter to jump, 'a' to close)")
+
+	d.activity = activity{active: true, prev: d.current, indexFocused: d.indexes.IsFocused()}
+	// Nothing behind the overlay takes keys while it is open.
+	d.indexes.SetFocused(false)
+	d.collection.SetFocused(false)
+	d.setWidget(ui, d.overlay(box, activityPlacement))
+}
+
+// endActivity closes the activity overlay.
+func (d *UI) endActivity(ui tui.UI) {
+	d.setWidget(ui, d.activity.prev)
+	if d.activity.indexFocused {
+		d.focusIndex()
+	} else {
+		d.focusCollection()
+	}
+	d.activity = activity{}
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// archivedIcon is shown before the names of archived collections, in place
+// of an icon.
+const archivedIcon = "▤"
+
+// archived are the archived collections, listed after the collections of
+// the index while shown.
+type archived struct {
+	shown bool
+	// entries are the archived entries by collection, loaded while shown.
+	entries map[string][]*entry.Entry
+}
+
+// isArchived is true if name is an archived collection shown in the index.
+func (d *UI) isArchived(name string) bool {
+	if _, ok := d.cache[name]; ok {
+		return false
+	}
+	_, ok := d.archived.entries[name]
+	return ok
+}
+
+// readOnly is true, and says so, if the selected collection is archived.
+// Archived collections are read only, a change would put the entry back in
+// the journal alone.
+func (d *UI) readOnly() bool {
+	if !d.isArchived(d.selected) {
+		return false
+	}
+	d.status.SetText("archived collections are read only, unarchive it with z to change it")
+	return true
+}
+
+// archiver is the persistence, if it can archive.
+func (d *UI) archiver() (store.Archiver, error) {
+	a, ok := d.Persistence.(store.Archiver)
+	if !ok {
+		return nil, fmt.Errorf("archiving is %w", app.ErrUnsupported)
+	}
+	return a, nil
+}
+
+// toggleArchived shows or hides the archived collections in the index.
+func (d *UI) toggleArchived(ctx context.Context) {
+	if d.archived.shown {
+		d.archived = archived{}
+		d.status.SetText("hiding archived collections")
+		d.populateIndex()
+		return
+	}
+	a, err := d.archiver()
+	if err != nil {
+		d.status.SetText(failed("archived", err))
+		return
+	}
+	d.archived = archived{shown: true, entries: make(map[string][]*entry.Entry)}
+	for _, e := range a.ListArchived(ctx) {
+		d.archived.entries[e.Collection] = append(d.archived.entries[e.Collection], e)
+	}
+	d.status.SetText(fmt.Sprintf("showing archived collections, %d at the bottom of the index", len(d.archived.entries)))
+	d.populateIndex()
+}
+
+// toggleArchive archives the collection highlighted in the index, or the
+// one shown if the collection has focus, or unarchives it if it is.
+func (d *UI) toggleArchive(ctx context.Context) {
+	name := d.selected
+	if d.indexes.IsFocused() {
+		name = d.highlighted()
+	}
+	if name == "" {
+		return
+	}
+	if d.isView(name) {
+		d.status.SetText("a view is not a collection, it can not be archived")
+		return
+	}
+	a, err := d.archiver()
+	if err != nil {
+		d.status.SetText(failed("archive", err))
+		return
+	}
+
+	if d.isArchived(name) {
+		moved, err := a.Unarchive(ctx, name)
+		if err != nil {
+			d.status.SetText(failed("unarchive", err))
+			return
+		}
+		all := d.archived.entries[name]
+		delete(d.archived.entries, name)
+		d.update(name, all)
+		d.status.SetText(fmt.Sprintf("unarchived %s, %d entries", name, moved))
+		return
+	}
+
+	moved, err := a.Archive(ctx, name)
+	if err != nil {
+		d.status.SetText(failed("archive", err))
+		return
+	}
+	all := d.cache[name]
+	if d.archived.shown {
+		d.archived.entries[name] = all
+	}
+	d.update(name, nil)
+	if d.archived.shown {
+		d.status.SetText(fmt.Sprintf("archived %s, %d entries", name, moved))
+	} else {
+		d.status.SetText(fmt.Sprintf("archived %s, %d entries, ctrl+a shows archived collections", name, moved))
+	}
+}
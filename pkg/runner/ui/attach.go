@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/runner/attach"
+)
+
+// startAttach opens a prompt for a URL or path to attach to the selected
+// entry, with a label after it, or -N to remove attachment N.
+func (d *UI) startAttach(ctx context.Context, ui tui.UI) {
+	e, i := d.selectedEntry()
+	if e == nil {
+		return
+	}
+
+	d.startAdd(ctx, ui, d.selected, nil)
+	if !d.capture.active {
+		return
+	}
+	d.capture.submit = func(ctx context.Context, text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+		var status string
+		if strings.HasPrefix(text, "-") {
+			n, err := strconv.Atoi(text[1:])
+			if err != nil || !e.RemoveAttachment(n-1) {
+				return app.Invalid("attachment", text[1:], fmt.Sprintf("the entry has %d", len(e.Attachments)))
+			}
+			status = fmt.Sprintf("attachment %d removed", n)
+		} else {
+			fields := strings.Fields(text)
+			a := entry.NewAttachment(fields[0], strings.Join(fields[1:], " "))
+			e.AddAttachment(a)
+			status = fmt.Sprintf("attached %s", a)
+		}
+		if err := d.Persistence.Store(e); err != nil {
+			return err
+		}
+		d.refreshRows(i)
+		d.updatePreview()
+		d.status.SetText(status)
+		return nil
+	}
+	title := "attach a url or path, then a label"
+	if len(e.Attachments) > 0 {
+		title += fmt.Sprintf(", or -1 to -%d to remove one", len(e.Attachments))
+	}
+	d.capture.box.SetTitle(title)
+}
+
+// openAttachment opens the attachment of the selected entry with the
+// opener of the OS, asking which if it has more than one.
+func (d *UI) openAttachment(ctx context.Context, ui tui.UI) {
+	e, _ := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	switch len(e.Attachments) {
+	case 0:
+		d.status.SetText("the selected entry has no attachments, ctrl+k to attach one")
+		return
+	case 1:
+		d.open(e.Attachments[0])
+		return
+	}
+
+	// The target is only to open the prompt.
+	d.startAdd(ctx, ui, collection.DayOf(time.Now()), nil)
+	if !d.capture.active {
+		return
+	}
+	d.capture.submit = func(ctx context.Context, text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 1 || n > len(e.Attachments) {
+			return app.Invalid("attachment", text, fmt.Sprintf("expected 1 to %d", len(e.Attachments)))
+		}
+		d.open(e.Attachments[n-1])
+		return nil
+	}
+	names := make([]string, 0, len(e.Attachments))
+	for i, a := range e.Attachments {
+		name := a.Label
+		if name == "" {
+			name = a.Target
+		}
+		names = append(names, fmt.Sprintf("%d %s", i+1, name))
+	}
+	d.capture.box.SetTitle("open which: " + strings.Join(names, ", "))
+}
+
+// open opens a with the opener of the OS.
+func (d *UI) open(a entry.Attachment) {
+	if err := attach.Open(a); err != nil {
+		d.status.SetText(failed("open", err))
+		return
+	}
+	d.status.SetText(fmt.Sprintf("opened %s", a))
+}
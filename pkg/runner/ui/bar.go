@@ -0,0 +1,270 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/mattn/go-runewidth"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/printers"
+)
+
+// Segments of the bottom bar.
+const (
+	// SegmentMode is what the keys act on: the index, the collection or an
+	// overlay.
+	SegmentMode = "mode"
+	// SegmentContext is the open collection.
+	SegmentContext = "context"
+	// SegmentStatus is the result of the last action.
+	SegmentStatus = "status"
+	// SegmentPending is the bullet and collection of the entry being
+	// captured.
+	SegmentPending = "pending"
+	// SegmentClock is the time of day.
+	SegmentClock = "clock"
+	// SegmentHelp is the key help.
+	SegmentHelp = "help"
+	// SegmentSync is when the journal was last synced, if sync is
+	// configured.
+	SegmentSync = "sync"
+	// SegmentEntry is the ref, collection, age and tags of the selected
+	// entry, what the keys act on in the collection.
+	SegmentEntry = "entry"
+)
+
+// barSeparator is between segments on the same side.
+const barSeparator = " │ "
+
+// clockTick is how often the clock segment is redrawn.
+const clockTick = 15 * time.Second
+
+// Segment is a part of the bottom bar. Text is called every time the bar is
+// drawn, a segment with no text is left out.
+type Segment struct {
+	Name string
+	Text func() string
+}
+
+// Bar is the layout of the bottom bar.
+type Bar struct {
+	// Left and Right are the segments shown at each end, in order. Both
+	// empty is the entry and status on the left and the sync and help on
+	// the right.
+	Left  []string
+	Right []string
+	// Custom are more segments, for Left and Right to name.
+	Custom []Segment
+}
+
+// Valid returns an error if a segment in the layout is not known.
+func (b Bar) Valid() error {
+	known := make(map[string]bool)
+	for _, s := range []string{SegmentMode, SegmentContext, SegmentStatus, SegmentPending, SegmentClock, SegmentHelp, SegmentSync, SegmentEntry} {
+		known[s] = true
+	}
+	for _, s := range b.Custom {
+		known[s.Name] = true
+	}
+	for _, name := range append(append([]string{}, b.Left...), b.Right...) {
+		if !known[name] {
+			return app.Invalid("bar segment", name, "expected mode, context, status, pending, clock, help, sync, entry or a custom segment")
+		}
+	}
+	return nil
+}
+
+// layout returns the segments named by Left and Right, out of all.
+func (b Bar) layout(all map[string]Segment) (left, right []Segment) {
+	l, r := b.Left, b.Right
+	if len(l) == 0 && len(r) == 0 {
+		l, r = []string{SegmentEntry, SegmentStatus}, []string{SegmentSync, SegmentHelp}
+	}
+	for _, name := range l {
+		left = append(left, all[name])
+	}
+	for _, name := range r {
+		right = append(right, all[name])
+	}
+	return left, right
+}
+
+// shows returns true if the layout has the named segment.
+func (b Bar) shows(name string) bool {
+	for _, s := range append(append([]string{}, b.Left...), b.Right...) {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// bar is the bottom bar. It keeps the status and help text of a
+// tui.StatusBar for the status and help segments.
+type bar struct {
+	tui.WidgetBase
+
+	text     string
+	permText string
+
+	left  []Segment
+	right []Segment
+}
+
+var _ tui.Widget = &bar{}
+
+// SetText sets the status segment.
+func (b *bar) SetText(text string) {
+	b.text = text
+}
+
+// SetPermanentText sets the help segment.
+func (b *bar) SetPermanentText(text string) {
+	b.permText = text
+}
+
+// Draw draws the left segments, then as much of the right ones as fits
+// after them.
+func (b *bar) Draw(p *tui.Painter) {
+	p.WithStyle("statusbar", func(p *tui.Painter) {
+		width := b.Size().X
+		p.FillRect(0, 0, width, 1)
+		left := joinSegments(b.left)
+		p.DrawText(0, 0, left)
+		right := joinSegments(b.right)
+		x := width - runewidth.StringWidth(right)
+		if used := runewidth.StringWidth(left); left != "" && x < used+len(barSeparator) {
+			x = used + runewidth.StringWidth(barSeparator)
+			right = runewidth.Truncate(right, width-x, "…")
+		}
+		p.DrawText(x, 0, right)
+	})
+}
+
+func (b *bar) SizeHint() image.Point {
+	return image.Point{X: 10, Y: 1}
+}
+
+func (b *bar) SizePolicy() (tui.SizePolicy, tui.SizePolicy) {
+	return tui.Preferred, tui.Maximum
+}
+
+func joinSegments(segments []Segment) string {
+	texts := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if t := s.Text(); t != "" {
+			texts = append(texts, t)
+		}
+	}
+	return strings.Join(texts, barSeparator)
+}
+
+// newBar makes the bottom bar with the segments of d.Bar.
+func (d *UI) newBar() *bar {
+	b := &bar{}
+	b.left, b.right = d.Bar.layout(d.segments(b))
+	return b
+}
+
+// segments are the segments b can show, by name.
+func (d *UI) segments(b *bar) map[string]Segment {
+	all := map[string]Segment{
+		SegmentMode:    {Name: SegmentMode, Text: d.mode},
+		SegmentContext: {Name: SegmentContext, Text: func() string { return d.iconed(d.selected) }},
+		SegmentStatus:  {Name: SegmentStatus, Text: func() string { return b.text }},
+		SegmentPending: {Name: SegmentPending, Text: d.pending},
+		SegmentClock:   {Name: SegmentClock, Text: func() string { return time.Now().Format("15:04") }},
+		SegmentHelp:    {Name: SegmentHelp, Text: func() string { return b.permText }},
+		SegmentSync:    {Name: SegmentSync, Text: d.syncText},
+		SegmentEntry:   {Name: SegmentEntry, Text: d.entryContext},
+	}
+	for _, s := range d.Bar.Custom {
+		all[s.Name] = s
+	}
+	return all
+}
+
+// mode names what the keys act on.
+func (d *UI) mode() string {
+	switch {
+	case d.idle.locked:
+		return "locked"
+	case d.capture.active:
+		return "capture"
+	case d.review.active:
+		return "review"
+	case d.migrate.active:
+		return "migrate"
+	case d.dayReview.active:
+		return "day review"
+	case d.search.active:
+		return "search"
+	case d.bullets.active:
+		return "bullet"
+	case d.activity.active:
+		return "activity"
+	case d.tags.active:
+		return "tags"
+	case d.info.active:
+		return "info"
+	case d.preview.active:
+		return "preview"
+	case d.indexes.IsFocused():
+		return "index"
+	default:
+		return "collection"
+	}
+}
+
+// entryContext describes the selected entry while the keys act on the
+// collection: its ref, collection, age and tags.
+func (d *UI) entryContext() string {
+	if d.mode() != "collection" {
+		return ""
+	}
+	e, _ := d.selectedEntry()
+	if e == nil {
+		return ""
+	}
+	parts := []string{
+		d.entryRefs()[e.ID],
+		d.iconed(e.Collection),
+		printers.Duration(time.Since(e.Created.Time)) + " old",
+	}
+	if tags := e.Tags(); len(tags) > 0 {
+		parts = append(parts, "#"+strings.Join(tags, " #"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// pending describes the entry being captured, as it would be added.
+func (d *UI) pending() string {
+	if !d.capture.active || d.capture.submit != nil {
+		return ""
+	}
+	bullet, day, _ := parseCapture(d.capture.input.Text(), d.defaultBullet(d.capture.target))
+	target := d.capture.target
+	if day != "" {
+		target = day
+	}
+	return fmt.Sprintf("%s → %s", bullet.String(), target)
+}
+
+// tickClock redraws the bar so the clock segment keeps time.
+func (d *UI) tickClock(ctx context.Context, ui tui.UI) {
+	ticker := time.NewTicker(clockTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ui.Update(func() {})
+	}
+}
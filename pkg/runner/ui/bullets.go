@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// bulletPlacement is the bullet menu, in the middle of the top of the
+// screen and as big as the choices need.
+var bulletPlacement = Placement{Fit: true, MinWidth: 30, Anchor: AnchorTop}
+
+// menuBullets are the bullets the bullet menu sets, picked with 1 to 5.
+// Moved bullets are set by migrating, waiting by bujo wait.
+var menuBullets = []glyph.Bullet{
+	glyph.Task,
+	glyph.Completed,
+	glyph.Irrelevant,
+	glyph.Note,
+	glyph.Event,
+}
+
+// menuSignifiers are the signifiers the bullet menu sets, picked with a to
+// d.
+var menuSignifiers = []glyph.Signifier{
+	glyph.Priority,
+	glyph.Inspiration,
+	glyph.Investigation,
+	glyph.None,
+}
+
+// bulletMenu is an overlay to change the bullet or signifier of the
+// selected entry with a single key.
+type bulletMenu struct {
+	active bool
+	// prev is shown again once the overlay is closed.
+	prev tui.Widget
+}
+
+// bindBullet sets a keybinding that only fires while the bullet menu is
+// open.
+func (d *UI) bindBullet(ui tui.UI, seq string, fn func()) {
+	ui.SetKeybinding(seq, func() {
+		if d.idle.locked || !d.bullets.active || d.capture.active {
+			return
+		}
+		fn()
+	})
+}
+
+// bindBulletKeys sets the keys of the bullet menu.
+func (d *UI) bindBulletKeys(ctx context.Context, ui tui.UI) {
+	for i, b := range menuBullets {
+		bullet := b
+		d.bindBullet(ui, strconv.Itoa(i+1), func() {
+			d.endBullets(ui)
+			d.setBullet(ctx, ui, bullet)
+		})
+	}
+	for i, s := range menuSignifiers {
+		signifier := s
+		d.bindBullet(ui, string(rune('a'+i)), func() {
+			d.endBullets(ui)
+			d.setSignifier(signifier)
+		})
+	}
+	d.bindBullet(ui, "Esc", func() {
+		d.endBullets(ui)
+	})
+}
+
+// startBullets opens the bullet menu for the selected entry.
+func (d *UI) startBullets(ui tui.UI) {
+	e, _ := d.selectedEntry()
+	if e == nil || d.bullets.active {
+		return
+	}
+
+	lines := tui.NewVBox()
+	for i, b := range menuBullets {
+		lines.Append(tui.NewLabel(fmt.Sprintf("%d  %s %s", i+1, b.Glyph().Symbol, b.Glyph().Meaning)))
+	}
+	lines.Append(tui.NewLabel(""))
+	for i, s := range menuSignifiers {
+		lines.Append(tui.NewLabel(fmt.Sprintf("%c  %s %s", 'a'+i, s.Glyph().Symbol, s.Glyph().Meaning)))
+	}
+	box := tui.NewVBox(lines)
+	box.SetBorder(true)
+	box.SetTitle("bullet (ESC to close)")
+
+	d.bullets = bulletMenu{active: true, prev: d.current}
+	d.collection.SetFocused(false)
+	d.setWidget(ui, d.overlay(box, bulletPlacement))
+}
+
+func (d *UI) endBullets(ui tui.UI) {
+	if !d.bullets.active {
+		return
+	}
+	d.setWidget(ui, d.bullets.prev)
+	d.focusCollection()
+	d.bullets = bulletMenu{}
+}
+
+// setBullet changes the bullet of the selected entry. Striking asks why, as
+// '-' does.
+func (d *UI) setBullet(ctx context.Context, ui tui.UI, bullet glyph.Bullet) {
+	e, i := d.selectedEntry()
+	if e == nil || e.Bullet == bullet {
+		return
+	}
+	switch bullet {
+	case glyph.Irrelevant:
+		d.strikeSelected(ctx, ui)
+		return
+	case glyph.Completed:
+		e.Complete()
+	default:
+		e.Bullet = bullet
+	}
+	if err := d.Persistence.Store(e); err != nil {
+		d.status.SetText(failed("bullet", err))
+		return
+	}
+	d.status.SetText(fmt.Sprintf("bullet: %s", bullet.Glyph().Meaning))
+	d.refreshRows(i)
+	if bullet == glyph.Completed {
+		d.emit(eventCompleted)
+	}
+}
+
+// setSignifier changes the signifier of the selected entry.
+func (d *UI) setSignifier(signifier glyph.Signifier) {
+	e, i := d.selectedEntry()
+	if e == nil || e.Signifier == signifier {
+		return
+	}
+	e.Signifier = signifier
+	if err := d.Persistence.Store(e); err != nil {
+		d.status.SetText(failed("signifier", err))
+		return
+	}
+	d.status.SetText(fmt.Sprintf("signifier: %s", signifier.Glyph().Meaning))
+	d.refreshRows(i)
+}
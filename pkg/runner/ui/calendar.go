@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"time"
+
+	"tableflip.dev/bujo/pkg/collection"
+)
+
+// monthOf returns the month the named collection refers to, if it is a
+// dated collection. Otherwise it returns false.
+func monthOf(name string) (time.Time, bool) {
+	kind, t := collection.Parse(name)
+	if kind == collection.Other {
+		return time.Time{}, false
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local), true
+}
+
+// jumpMonth moves the index selection by delta months from the month of the
+// selected collection. The target month log is added to the index even if it
+// has no entries yet.
+func (d *UI) jumpMonth(delta int) {
+	base, ok := monthOf(d.highlighted())
+	if !ok {
+		now := time.Now()
+		base = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	d.focusMonth(base.AddDate(0, delta, 0))
+	d.emit(eventMonth)
+}
+
+// focusMonth selects the month log for the given time in the index,
+// materializing an empty collection for it if needed.
+func (d *UI) focusMonth(month time.Time) {
+	d.openCollection(collection.MonthOf(month))
+	d.focusIndex()
+}
@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// capture is a one-line prompt to quickly add an entry without leaving the
+// current view.
+type capture struct {
+	input  *tui.Entry
+	active bool
+
+	// where the entry goes, after is nil to add at the end.
+	target string
+	after  *entry.Entry
+	// targets can be cycled through with tab, if set.
+	targets []string
+	box     *tui.Box
+	// submit is called with the text in place of adding an entry, if set.
+	submit func(ctx context.Context, text string) error
+
+	// state to restore once the capture is done.
+	prev    tui.Widget
+	restore func()
+	// screen is what is shown with the prompt.
+	screen tui.Widget
+	// under is the prompt this one was opened over, if any. It is shown
+	// again once this one is done.
+	under *capture
+}
+
+// captureBullets are the short prefixes accepted in the capture prompt to
+// pick a bullet, anything else is captured with the default bullet of the
+// collection, a task unless one is set.
+var captureBullets = map[string]glyph.Bullet{
+	"*": glyph.Task,
+	"+": glyph.Task,
+	"-": glyph.Note,
+	"o": glyph.Event,
+}
+
+// startCapture opens the capture prompt for today's log. It opens in any
+// mode, over an overlay or another prompt, which get the keys back once it
+// is done.
+func (d *UI) startCapture(ctx context.Context, ui tui.UI) {
+	if d.marks.pending != "" {
+		d.cancelMark()
+	}
+	if !d.capture.active {
+		d.startAdd(ctx, ui, collection.DayOf(time.Now()), nil)
+		return
+	}
+
+	under := d.capture
+	under.input.SetFocused(false)
+	d.capture = capture{}
+	d.startAdd(ctx, ui, collection.DayOf(time.Now()), nil)
+	if !d.capture.active {
+		d.capture = under
+		under.input.SetFocused(true)
+		return
+	}
+	d.capture.under = &under
+	d.capture.screen = tui.NewVBox(under.screen, d.capture.box)
+	d.show(ui, d.capture.screen)
+}
+
+// startAdd opens the capture prompt to add to the target collection, right
+// after the given entry, or at the end if after is nil.
+func (d *UI) startAdd(ctx context.Context, ui tui.UI, target string, after *entry.Entry) {
+	if d.capture.active || target == "" {
+		return
+	}
+	if d.isView(target) {
+		d.status.SetText("can not add to a view, open a collection to add to it")
+		return
+	}
+	if d.isArchived(target) {
+		d.status.SetText("archived collections are read only, unarchive it with z to add to it")
+		return
+	}
+
+	input := tui.NewEntry()
+	input.SetSizePolicy(tui.Expanding, tui.Maximum)
+	input.OnSubmit(func(e *tui.Entry) {
+		submit := d.submitCapture
+		if d.capture.submit != nil {
+			submit = d.capture.submit
+		}
+		if err := submit(ctx, e.Text()); err != nil {
+			d.status.SetText(failed("capture", err))
+		}
+		d.endCapture(ui)
+	})
+
+	box := tui.NewHBox(input)
+	box.SetBorder(true)
+
+	d.capture = capture{
+		input:   input,
+		active:  true,
+		target:  target,
+		after:   after,
+		box:     box,
+		prev:    d.current,
+		restore: d.yieldFocus(),
+		screen:  tui.NewVBox(d.current, box),
+	}
+	d.titleCapture()
+
+	d.show(ui, d.capture.screen)
+
+	// The key that opened the prompt is still on its way to the focused
+	// widget, focus the input once it has passed so it is not typed.
+	go ui.Update(func() {
+		if d.capture.input == input {
+			input.SetFocused(true)
+		}
+	})
+}
+
+// titleCapture titles the capture prompt with where the entry goes.
+func (d *UI) titleCapture() {
+	if len(d.capture.targets) > 1 {
+		d.capture.box.SetTitle(fmt.Sprintf("add to %s (tab to change, '-' note, 'o' event)", d.capture.target))
+		return
+	}
+	d.capture.box.SetTitle(fmt.Sprintf("add to %s ('-' note, 'o' event)", d.capture.target))
+}
+
+func (d *UI) endCapture(ui tui.UI) {
+	if !d.capture.active {
+		return
+	}
+	d.capture.input.SetFocused(false)
+	if under := d.capture.under; under != nil {
+		d.capture = *under
+		d.show(ui, d.capture.screen)
+		d.capture.input.SetFocused(true)
+		return
+	}
+	d.setWidget(ui, d.capture.prev)
+	d.capture.restore()
+	d.capture = capture{}
+}
+
+// yieldFocus takes the keys from the widget that has them, for a prompt to
+// open over it, and returns a func that gives them back.
+func (d *UI) yieldFocus() func() {
+	switch {
+	case d.search.active:
+		input := d.search.input
+		input.SetFocused(false)
+		return func() { input.SetFocused(true) }
+	case d.dayReview.active && d.dayReview.input != nil:
+		input := d.dayReview.input
+		input.SetFocused(false)
+		return func() { input.SetFocused(true) }
+	case d.migrate.active:
+		table := d.migrate.table
+		table.SetFocused(false)
+		return func() { table.SetFocused(true) }
+	case d.review.active || d.dayReview.active || d.bullets.active:
+		// The overlay takes its keys with keybindings, which are held
+		// back while the prompt is open.
+		return func() {}
+	}
+	indexFocused := d.indexes.IsFocused()
+	d.indexes.SetFocused(false)
+	d.collection.SetFocused(false)
+	return func() {
+		if indexFocused {
+			d.focusIndex()
+		} else {
+			d.focusCollection()
+		}
+	}
+}
+
+// parseCapture splits the prefixes off captured text: a bullet from
+// captureBullets, then a ">day", like ">tomorrow" or ">2/28", to add to that
+// day log instead of the target. day is empty if there is none. Without a
+// bullet, it is def.
+func parseCapture(text string, def glyph.Bullet) (bullet glyph.Bullet, day string, rest string) {
+	bullet, rest = def, strings.TrimSpace(text)
+	if parts := strings.SplitN(rest, " ", 2); len(parts) == 2 {
+		if b, ok := captureBullets[parts[0]]; ok {
+			bullet = b
+			rest = strings.TrimSpace(parts[1])
+		}
+	}
+	if parts := strings.SplitN(rest, " ", 2); len(parts) == 2 && strings.HasPrefix(parts[0], ">") {
+		if resolved := collection.Resolve(parts[0][1:]); resolved != parts[0][1:] {
+			day = resolved
+			rest = strings.TrimSpace(parts[1])
+		}
+	}
+	return bullet, day, rest
+}
+
+// defaultBullet is the bullet captures to name get without one picked.
+func (d *UI) defaultBullet(name string) glyph.Bullet {
+	if b, ok := d.defaults[name]; ok {
+		return b
+	}
+	return glyph.Task
+}
+
+func (d *UI) submitCapture(ctx context.Context, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	bullet, day, text := parseCapture(text, d.defaultBullet(d.capture.target))
+	name := d.capture.target
+	after := d.capture.after
+	if day != "" {
+		name, after = day, nil
+	}
+
+	e := entry.New(name, bullet, text)
+	if after != nil {
+		var moved []*entry.Entry
+		e.Order, moved = entry.OrderAfter(d.cache[name], after)
+		for _, m := range moved {
+			if err := d.Persistence.Store(m); err != nil {
+				return err
+			}
+		}
+	}
+	if err := d.Persistence.Store(e); err != nil {
+		return err
+	}
+
+	d.refs = nil
+	if _, ok := d.cache[name]; !ok {
+		d.cache[name] = []*entry.Entry{e}
+		d.populateIndex()
+	} else {
+		d.cache[name] = append(d.cache[name], e)
+		entry.Sort(d.cache[name])
+	}
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+
+	d.status.SetText(fmt.Sprintf("captured to %s", name))
+	d.emit(eventCaptured)
+	return nil
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestCaptureOverOverlay(t *testing.T) {
+	s := &screen{}
+	d := &UI{status: &bar{}}
+	table := tui.NewTable(1, 0)
+	table.SetFocused(true)
+	d.migrate = migrate{active: true, table: table}
+	overlay := d.overlay(table, migratePlacement)
+	d.setWidget(s, overlay)
+
+	d.startCapture(context.Background(), s)
+	if !d.capture.active || !d.migrate.active {
+		t.Fatal("the capture did not open over the overlay")
+	}
+	if table.IsFocused() {
+		t.Error("the overlay kept the keys under the capture")
+	}
+
+	d.endCapture(s)
+	if d.capture.active || !d.migrate.active || d.onScreen != overlay {
+		t.Fatal("the overlay is not back once the capture is done")
+	}
+	if !table.IsFocused() {
+		t.Error("the overlay did not get the keys back")
+	}
+}
+
+func TestCaptureOverPrompt(t *testing.T) {
+	s := &screen{}
+	d := &UI{status: &bar{}, indexes: tui.NewTable(0, 0), collection: tui.NewTable(0, 0)}
+	d.setWidget(s, tui.NewLabel("journal"))
+
+	ctx := context.Background()
+	d.startAdd(ctx, s, "Inbox", nil)
+	d.capture.submit = func(context.Context, string) error { return nil }
+	prompt := d.capture.input
+	prompt.SetFocused(true)
+
+	d.startCapture(ctx, s)
+	if d.capture.input == prompt || d.capture.submit != nil {
+		t.Fatal("ctrl+n did not open a capture over the prompt")
+	}
+	if prompt.IsFocused() {
+		t.Error("the prompt kept the keys under the capture")
+	}
+
+	d.endCapture(s)
+	if !d.capture.active || d.capture.input != prompt || d.capture.submit == nil {
+		t.Fatal("the prompt is not back once the capture is done")
+	}
+	if !prompt.IsFocused() {
+		t.Error("the prompt did not get the keys back")
+	}
+}
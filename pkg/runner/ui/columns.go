@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// ColumnsAuto picks how many columns the collection view has from the
+// terminal width.
+const ColumnsAuto = "auto"
+
+const (
+	// columnWidth is the terminal width each column takes with ColumnsAuto.
+	columnWidth = 100
+	// maxColumns is the most columns the collection view is split into.
+	maxColumns = 3
+)
+
+// columns split the collection view into tables side by side on wide
+// terminals. Entries run down the first column and on to the next. The
+// focused column is d.collection, and its entries d.rows.
+type columns struct {
+	// mode is ColumnsAuto or a number of columns.
+	mode  string
+	width int
+	// at is the focused column.
+	at     int
+	tables []*tui.Table
+	rows   [][]*entry.Entry
+	// divider is the row of the done divider, counted down the columns, -1
+	// if there is none.
+	divider int
+
+	box *tui.Box
+	// newTable makes the table for a column.
+	newTable func() *tui.Table
+}
+
+// ValidColumns returns an error if mode is not ColumnsAuto or a number of
+// columns from 1 to maxColumns. Empty is ColumnsAuto.
+func ValidColumns(mode string) error {
+	if mode == "" || mode == ColumnsAuto {
+		return nil
+	}
+	if n, err := strconv.Atoi(mode); err == nil && n >= 1 && n <= maxColumns {
+		return nil
+	}
+	return app.Invalid("columns", mode, "expected auto, 1, 2 or 3")
+}
+
+// count returns how many columns to show.
+func (c *columns) count() int {
+	n := c.width / columnWidth
+	if c.mode != "" && c.mode != ColumnsAuto {
+		n, _ = strconv.Atoi(c.mode)
+	}
+	if n < 1 {
+		return 1
+	}
+	if n > maxColumns {
+		return maxColumns
+	}
+	return n
+}
+
+// layoutColumns puts the rendered rows into the columns, making the columns
+// first if their count changed.
+func (d *UI) layoutColumns(r rendered) {
+	n := d.columns.count()
+	if n != len(d.columns.tables) {
+		d.makeColumns(n)
+	}
+
+	for k, t := range d.columns.tables {
+		t.RemoveRows()
+		d.columns.rows[k] = make([]*entry.Entry, 0)
+	}
+	d.columns.divider = r.divider
+	per := (len(r.rows) + n - 1) / n
+	for i, w := range r.widgets {
+		k := i / per
+		d.columns.tables[k].AppendRow(w)
+		d.columns.rows[k] = append(d.columns.rows[k], r.rows[i])
+	}
+
+	at := d.columns.at
+	for at > 0 && len(d.columns.rows[at]) == 0 {
+		at--
+	}
+	d.useColumn(at)
+}
+
+// newColumnTable makes the table for a column of the collection view.
+func (d *UI) newColumnTable() *tui.Table {
+	t := tui.NewTable(1, 0)
+	t.SetSizePolicy(tui.Expanding, tui.Maximum)
+
+	t.OnItemActivated(func(t *tui.Table) {
+		if e, i := d.selectedEntry(); e == nil && i >= 0 {
+			if d.columnsRow(i) == d.columns.divider {
+				d.toggleDone()
+				return
+			}
+			d.showMore()
+			return
+		}
+		//if t.Selected() == 0 {
+		//	impl.Quit()
+		//	fmt.Printf("no selection; context unchanged\n")
+		//	return
+		//}
+		//_, err := cmd(fmt.Sprintf("kubectl config use-context %s", cfg.Contexts[t.Selected()-1].Name))
+		//if err != nil {
+		//	panic(err)
+		//}
+		//impl.Quit()
+		//fmt.Printf("selected %s\n", cfg.Contexts[t.Selected()-1].Name)
+		// TODO
+	})
+
+	t.OnSelectionChanged(func(t *tui.Table) {
+		d.showRef()
+		d.updatePreview()
+	})
+
+	return t
+}
+
+// makeColumns replaces the columns with n empty ones.
+func (d *UI) makeColumns(n int) {
+	for d.columns.box.Length() > 0 {
+		d.columns.box.Remove(0)
+	}
+	d.columns.tables = make([]*tui.Table, n)
+	d.columns.rows = make([][]*entry.Entry, n)
+	for k := range d.columns.tables {
+		t := d.columns.newTable()
+		d.columns.tables[k] = t
+		d.columns.box.Append(t)
+	}
+	if d.columns.at >= n {
+		d.columns.at = n - 1
+	}
+	d.useColumn(d.columns.at)
+}
+
+// useColumn makes column k the collection table, moving the focus to it if
+// the collection had it.
+func (d *UI) useColumn(k int) {
+	focused := d.collection != nil && d.collection.IsFocused()
+	if d.collection != nil {
+		d.collection.SetFocused(false)
+	}
+	d.columns.at = k
+	d.collection = d.columns.tables[k]
+	d.rows = d.columns.rows[k]
+	d.collection.SetFocused(focused)
+}
+
+// moveColumn moves the selection to the column delta away, on the same row
+// or the last row of a shorter column. It returns false if there is no
+// column there.
+func (d *UI) moveColumn(delta int) bool {
+	k := d.columns.at + delta
+	if k < 0 || k >= len(d.columns.tables) || len(d.columns.rows[k]) == 0 {
+		return false
+	}
+	i := d.collection.Selected()
+	d.collection.SetSelected(-1)
+	d.useColumn(k)
+	if i >= len(d.rows) {
+		i = len(d.rows) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	d.collection.Select(i)
+	return true
+}
+
+// resizeColumns lays the collection out again if the terminal width changes
+// how many columns there are, keeping the selected entry.
+func (d *UI) resizeColumns(width int) {
+	d.columns.width = width
+	if d.collection == nil || d.columns.count() == len(d.columns.tables) {
+		return
+	}
+	e, _ := d.selectedEntry()
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+	if e != nil {
+		d.selectEntry(e)
+	}
+}
+
+// columnsRow is the row counted down the columns of row i of the focused
+// column.
+func (d *UI) columnsRow(i int) int {
+	for k := 0; k < d.columns.at; k++ {
+		i += len(d.columns.rows[k])
+	}
+	return i
+}
+
+// selectColumnsRow selects the row counted down the columns, moving to its
+// column.
+func (d *UI) selectColumnsRow(i int) {
+	for k, rows := range d.columns.rows {
+		if i < len(rows) {
+			if k != d.columns.at {
+				d.collection.SetSelected(-1)
+				d.useColumn(k)
+			}
+			d.collection.Select(i)
+			return
+		}
+		i -= len(rows)
+	}
+}
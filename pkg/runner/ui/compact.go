@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Compact modes for the ui.
+const (
+	// CompactAuto is compact when the terminal is narrower than compactWidth.
+	CompactAuto = "auto"
+	CompactOn   = "on"
+	CompactOff  = "off"
+)
+
+// compactWidth is the terminal width under which CompactAuto is compact.
+const compactWidth = 80
+
+const (
+	helpText    = `Use left or right arrows to navigate, enter to open, 'o' to add, 'x' to complete, ctrl+n to capture, 'm' for more, 't' for the tutorial, 'c' for compact, 'k' for key, ESC or 'q' to QUIT`
+	helpCompact = `←→ nav ⏎ open o add x done ^n capture k key q quit`
+)
+
+// compact hides the index unless it is focused, and trims the chrome, to
+// keep the ui usable in narrow terminals.
+type compact struct {
+	// mode is one of the Compact modes.
+	mode string
+	// on is true while the layout is compact.
+	on    bool
+	width int
+	// indexShown is true while the index is in the selector.
+	indexShown bool
+
+	selector *tui.Box
+}
+
+// frame is the root box, it watches the terminal size to switch the layout
+// to compact before it is laid out.
+type frame struct {
+	*tui.Box
+	onResize func(size image.Point)
+}
+
+func (f *frame) Resize(size image.Point) {
+	f.onResize(size)
+	f.Box.Resize(size)
+}
+
+func (d *UI) onResize(size image.Point) {
+	d.compact.width = size.X
+	d.layoutCompact()
+	d.resizeColumns(size.X)
+}
+
+// toggleCompact switches between compact and the full layout, overriding
+// CompactAuto.
+func (d *UI) toggleCompact() {
+	if d.compact.on {
+		d.compact.mode = CompactOff
+	} else {
+		d.compact.mode = CompactOn
+	}
+	d.layoutCompact()
+}
+
+// layoutCompact updates the layout for the compact mode, the terminal width
+// and what is focused.
+func (d *UI) layoutCompact() {
+	switch d.compact.mode {
+	case CompactOn:
+		d.compact.on = true
+	case CompactOff:
+		d.compact.on = false
+	default:
+		d.compact.on = d.compact.width > 0 && d.compact.width < compactWidth
+	}
+
+	if d.compact.on {
+		d.status.SetPermanentText(helpCompact)
+	} else {
+		d.status.SetPermanentText(helpText)
+	}
+	d.indexView.SetBorder(!d.compact.on)
+
+	show := !d.compact.on || d.indexes.IsFocused()
+	if show == d.compact.indexShown {
+		return
+	}
+	if show {
+		d.compact.selector.Prepend(d.indexView)
+	} else {
+		d.compact.selector.Remove(0)
+	}
+	d.compact.indexShown = show
+}
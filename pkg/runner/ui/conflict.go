@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// conflictPlacement is the conflict review overlay, all of the screen so
+// the versions fit side by side.
+var conflictPlacement = Placement{}
+
+// review is an overlay to resolve the conflicting versions of entries left
+// by a sync, one conflict at a time.
+type review struct {
+	active    bool
+	conflicts []store.Conflict
+	at        int
+
+	// prev is shown again once the review is done.
+	prev tui.Widget
+}
+
+const layoutUS = "January 2, 2006"
+
+// maxVersions is how many versions of a conflict can be picked with the
+// number keys.
+const maxVersions = 9
+
+// bindReview sets a keybinding that only fires during a review.
+func (d *UI) bindReview(ui tui.UI, seq string, fn func()) {
+	ui.SetKeybinding(seq, func() {
+		if d.idle.locked || !d.review.active || d.capture.active {
+			return
+		}
+		fn()
+	})
+}
+
+// bindReviewKeys sets the keys of the review overlay.
+func (d *UI) bindReviewKeys(ctx context.Context, ui tui.UI) {
+	for i := 1; i <= maxVersions; i++ {
+		version := i - 1
+		d.bindReview(ui, strconv.Itoa(i), func() {
+			c := d.review.conflicts[d.review.at]
+			if version < len(c.Versions) {
+				d.resolveConflict(ctx, ui, c.Versions[version])
+			}
+		})
+	}
+	d.bindReview(ui, "m", func() {
+		d.resolveConflict(ctx, ui, d.review.conflicts[d.review.at].Merged())
+	})
+	d.bindReview(ui, "n", func() {
+		d.nextConflict(ui)
+	})
+	d.bindReview(ui, "Esc", func() {
+		d.endReview(ui)
+	})
+}
+
+// noteConflicts reports conflicts in the status bar.
+func (d *UI) noteConflicts(ctx context.Context) {
+	if r, ok := d.Persistence.(store.ConflictResolver); ok {
+		if n := len(r.Conflicts(ctx)); n > 0 {
+			d.status.SetText(fmt.Sprintf("%d conflicting entries, 'r' to review", n))
+		}
+	}
+}
+
+// startReview opens the review overlay, if there are conflicts.
+func (d *UI) startReview(ctx context.Context, ui tui.UI) {
+	r, ok := d.Persistence.(store.ConflictResolver)
+	if !ok {
+		d.status.SetText("this journal can not have conflicts")
+		return
+	}
+	conflicts := r.Conflicts(ctx)
+	if len(conflicts) == 0 {
+		d.status.SetText("no conflicts")
+		return
+	}
+	d.review = review{
+		active:    true,
+		conflicts: conflicts,
+		prev:      d.current,
+	}
+	d.indexes.SetFocused(false)
+	d.collection.SetFocused(false)
+	d.showConflict(ui)
+}
+
+func (d *UI) endReview(ui tui.UI) {
+	if !d.review.active {
+		return
+	}
+	d.setWidget(ui, d.review.prev)
+	d.focusCollection()
+	d.review = review{}
+}
+
+// showConflict shows the versions of the current conflict side by side.
+func (d *UI) showConflict(ui tui.UI) {
+	c := d.review.conflicts[d.review.at]
+
+	versions := tui.NewHBox()
+	for i, v := range c.Versions {
+		versions.Append(versionView(i+1, v))
+	}
+
+	help := tui.NewLabel("1-9 keep that version, 'm' merge them, 'n' skip, ESC to close")
+	view := tui.NewVBox(versions, tui.NewSpacer(), help)
+	view.SetBorder(true)
+	view.SetTitle(fmt.Sprintf("conflict %d/%d", d.review.at+1, len(d.review.conflicts)))
+
+	d.setWidget(ui, d.overlay(view, conflictPlacement))
+}
+
+func versionView(n int, e *entry.Entry) tui.Widget {
+	message := tui.NewLabel(e.String())
+	message.SetWordWrap(true)
+	created := tui.NewLabel("created " + e.Created.Format(layoutUS))
+
+	view := tui.NewVBox(message, created)
+	if e.Label != "" {
+		view.Append(tui.NewLabel("label " + string(e.Label)))
+	}
+	view.Append(tui.NewSpacer())
+	view.SetBorder(true)
+	view.SetTitle(fmt.Sprintf("%d: %s", n, e.Collection))
+	view.SetSizePolicy(tui.Expanding, tui.Expanding)
+	return view
+}
+
+// nextConflict moves to the next conflict, or ends the review after the
+// last one.
+func (d *UI) nextConflict(ui tui.UI) {
+	d.review.at++
+	if d.review.at >= len(d.review.conflicts) {
+		d.endReview(ui)
+		return
+	}
+	d.showConflict(ui)
+}
+
+func (d *UI) resolveConflict(ctx context.Context, ui tui.UI, keep *entry.Entry) {
+	r := d.Persistence.(store.ConflictResolver)
+	c := d.review.conflicts[d.review.at]
+	if err := r.ResolveConflict(ctx, c, keep); err != nil {
+		d.status.SetText(failed("resolve", err))
+		return
+	}
+
+	d.cache = d.Persistence.MapAll(ctx)
+	d.refs = nil
+	d.populateIndex()
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+
+	d.status.SetText(fmt.Sprintf("kept the %s version", keep.Collection))
+	d.nextConflict(ui)
+}
@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// dayReviewPlacement is the day review overlay, a box at the top.
+var dayReviewPlacement = Placement{Width: 80, MinWidth: 50, MaxWidth: 120, Height: 40, MinHeight: 8, Anchor: AnchorTop}
+
+// Questions the day review asks in its prompt.
+const (
+	askWhy  = "why"
+	askNote = "note"
+)
+
+// dayReview is an overlay that walks today's open tasks one at a time to
+// complete, migrate or strike each, then asks for a note about the day,
+// like bujo shutdown does on the command line.
+type dayReview struct {
+	active bool
+	day    string
+	tasks  []*entry.Entry
+	at     int
+	done   int
+	// asking is askWhy or askNote while the prompt is shown.
+	asking string
+	input  *tui.Entry
+
+	// prev is shown again once the review is done.
+	prev tui.Widget
+}
+
+// bindDayReview sets a keybinding that only fires while reviewing the day,
+// and not while typing in its prompt.
+func (d *UI) bindDayReview(ui tui.UI, seq string, fn func()) {
+	ui.SetKeybinding(seq, func() {
+		if d.idle.locked || !d.dayReview.active || d.dayReview.asking != "" || d.capture.active {
+			return
+		}
+		fn()
+	})
+}
+
+// bindDayReviewKeys sets the keys of the day review overlay.
+func (d *UI) bindDayReviewKeys(ctx context.Context, ui tui.UI) {
+	d.bindDayReview(ui, "c", func() {
+		d.reviewTask(ctx, ui, func(e *entry.Entry) error {
+			e.Complete()
+			if err := d.storeAll(ctx, e); err != nil {
+				return err
+			}
+			d.emit(eventCompleted)
+			return nil
+		})
+	})
+	d.bindDayReview(ui, "t", func() {
+		d.reviewTask(ctx, ui, func(e *entry.Entry) error {
+			return d.moveTask(ctx, e, glyph.MovedCollection, collection.DayOf(time.Now().AddDate(0, 0, 1)))
+		})
+	})
+	d.bindDayReview(ui, "f", func() {
+		d.reviewTask(ctx, ui, func(e *entry.Entry) error {
+			return d.moveTask(ctx, e, glyph.MovedFuture, collection.FutureOf(time.Now().AddDate(0, 1, 0)))
+		})
+	})
+	d.bindDayReview(ui, "s", func() {
+		if !d.AskReason {
+			d.reviewTask(ctx, ui, func(e *entry.Entry) error {
+				e.Strike("")
+				return d.storeAll(ctx, e)
+			})
+			return
+		}
+		d.dayReview.asking = askWhy
+		d.showDayReview(ctx, ui)
+	})
+	d.bindDayReview(ui, " ", func() {
+		d.reviewTask(ctx, ui, nil)
+	})
+	ui.SetKeybinding("Esc", func() {
+		r := &d.dayReview
+		if d.idle.locked || !r.active || d.capture.active {
+			return
+		}
+		if r.asking == askWhy {
+			r.asking = ""
+			d.showDayReview(ctx, ui)
+			return
+		}
+		d.endDayReview(ctx, ui)
+	})
+}
+
+// startDayReview opens the day review for today's open tasks.
+func (d *UI) startDayReview(ctx context.Context, ui tui.UI) {
+	today := collection.DayOf(time.Now())
+	tasks := make([]*entry.Entry, 0)
+	for _, e := range d.cache[today] {
+		if e.Bullet == glyph.Task {
+			tasks = append(tasks, e)
+		}
+	}
+	d.dayReview = dayReview{
+		active: true,
+		day:    today,
+		tasks:  tasks,
+		prev:   d.current,
+	}
+	if len(tasks) == 0 {
+		d.dayReview.asking = askNote
+	}
+	d.indexes.SetFocused(false)
+	d.collection.SetFocused(false)
+	d.showDayReview(ctx, ui)
+}
+
+// showDayReview shows the task being reviewed, or the prompt.
+func (d *UI) showDayReview(ctx context.Context, ui tui.UI) {
+	r := &d.dayReview
+
+	body := tui.NewVBox()
+	var title string
+	switch {
+	case r.asking == askNote:
+		title = fmt.Sprintf("review %s: a note about the day (enter to skip)", r.day)
+		summary := fmt.Sprintf("%d of %d open tasks changed.", r.done, len(r.tasks))
+		if len(r.tasks) == 0 {
+			summary = "Nothing open today."
+		}
+		body.Append(tui.NewLabel(summary))
+	default:
+		e := r.tasks[r.at]
+		title = fmt.Sprintf("review %s: task %d of %d (ESC to stop)", r.day, r.at+1, len(r.tasks))
+		message := tui.NewLabel(e.String())
+		message.SetWordWrap(true)
+		body.Append(message)
+		body.Append(tui.NewSpacer())
+		if r.asking == askWhy {
+			body.Append(tui.NewLabel("why strike it? (enter to skip, ESC to keep it)"))
+		} else {
+			help := tui.NewLabel("c complete, t migrate to tomorrow, f move to the future log, s strike, space keep")
+			help.SetWordWrap(true)
+			body.Append(help)
+		}
+	}
+
+	if r.asking != "" {
+		input := tui.NewEntry()
+		input.SetSizePolicy(tui.Expanding, tui.Maximum)
+		input.OnSubmit(func(in *tui.Entry) {
+			d.answerDayReview(ctx, ui, strings.TrimSpace(in.Text()))
+		})
+		r.input = input
+		body.Append(input)
+	} else {
+		r.input = nil
+	}
+
+	box := tui.NewVBox(body)
+	box.SetBorder(true)
+	box.SetTitle(title)
+	d.setWidget(ui, d.overlay(box, dayReviewPlacement))
+
+	// The key that opened the prompt is still on its way to the focused
+	// widget, focus the input once it has passed so it is not typed.
+	if input := r.input; input != nil {
+		go ui.Update(func() {
+			if d.dayReview.input == input {
+				input.SetFocused(true)
+			}
+		})
+	}
+}
+
+// answerDayReview takes the answer to the prompt: why the task is struck,
+// or the note about the day.
+func (d *UI) answerDayReview(ctx context.Context, ui tui.UI, answer string) {
+	r := &d.dayReview
+	switch r.asking {
+	case askWhy:
+		r.asking = ""
+		d.reviewTask(ctx, ui, func(e *entry.Entry) error {
+			e.Strike(answer)
+			return d.storeAll(ctx, e)
+		})
+	case askNote:
+		day := r.day
+		if answer != "" {
+			if err := d.storeAll(ctx, entry.New(day, glyph.Note, answer)); err != nil {
+				d.status.SetText(failed("add the note", err))
+				return
+			}
+		}
+		d.endDayReview(ctx, ui)
+		if answer != "" {
+			d.status.SetText(fmt.Sprintf("review done, note added to %s", day))
+		}
+	}
+}
+
+// reviewTask applies fn to a copy of the task being reviewed, nil keeps it
+// as it is, and moves on to the next task, or to the note after the last.
+// The task is left as it was if fn fails.
+func (d *UI) reviewTask(ctx context.Context, ui tui.UI, fn func(e *entry.Entry) error) {
+	r := &d.dayReview
+	if fn != nil {
+		e := r.tasks[r.at].Clone()
+		if err := fn(e); err != nil {
+			d.status.SetText(failed("review", err))
+			return
+		}
+		r.tasks[r.at] = e
+		r.done++
+	}
+	r.at++
+	if r.at >= len(r.tasks) {
+		r.asking = askNote
+	}
+	d.showDayReview(ctx, ui)
+}
+
+// moveTask moves e to the target collection, leaving it behind with bullet.
+func (d *UI) moveTask(ctx context.Context, e *entry.Entry, bullet glyph.Bullet, target string) error {
+	moved := e.Move(bullet, target)
+	return d.storeAll(ctx, e, moved)
+}
+
+// endDayReview closes the day review and shows what it changed.
+func (d *UI) endDayReview(ctx context.Context, ui tui.UI) {
+	r := &d.dayReview
+	if !r.active {
+		return
+	}
+	if r.input != nil {
+		r.input.SetFocused(false)
+	}
+	d.setWidget(ui, r.prev)
+	done := r.done
+	*r = dayReview{}
+
+	d.cache = d.Persistence.MapAll(ctx)
+	d.refs = nil
+	d.populateIndex()
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+	d.focusCollection()
+	d.status.SetText(fmt.Sprintf("review done, %d tasks changed", done))
+}
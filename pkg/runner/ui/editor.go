@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// editor returns the command to edit files with, from $VISUAL or $EDITOR.
+func editor() string {
+	if e := os.Getenv("VISUAL"); e != "" {
+		return e
+	}
+	if e := os.Getenv("EDITOR"); e != "" {
+		return e
+	}
+	return "vi"
+}
+
+// editMessage opens message in the editor and returns it as saved.
+func editMessage(message string) (string, error) {
+	f, err := ioutil.TempFile("", "bujo-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(message + "\n"); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// The editor may have arguments, like "code --wait".
+	args := strings.Fields(editor())
+	cmd := exec.Command(args[0], append(args[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// editEntry edits the message of e in the editor and stores it, returning
+// what happened for the status bar.
+func (d *UI) editEntry(ctx context.Context, e *entry.Entry) string {
+	message, err := editMessage(e.Message)
+	if err != nil {
+		return failed("edit", err)
+	}
+	switch message {
+	case "":
+		return "edit cancelled, the message was empty"
+	case e.Message:
+		return "no changes"
+	}
+	e.Message = message
+	if err := d.Persistence.Store(e); err != nil {
+		return failed("edit", err)
+	}
+	return fmt.Sprintf("edited %s", e.Collection)
+}
+
+// selectEntry selects the row of e in the collection view.
+func (d *UI) selectEntry(e *entry.Entry) {
+	for k, rows := range d.columns.rows {
+		for i, r := range rows {
+			if r != nil && r.ID == e.ID {
+				if k != d.columns.at {
+					d.collection.SetSelected(-1)
+					d.useColumn(k)
+				}
+				d.collection.Select(i)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// selectedEntry returns the entry selected in the collection view, if any,
+// and its row. A row that is not an entry returns nil and the row.
+func (d *UI) selectedEntry() (*entry.Entry, int) {
+	i := d.collection.Selected()
+	if i < 0 || i >= len(d.rows) {
+		return nil, -1
+	}
+	return d.rows[i], i
+}
+
+// showRef shows the ref of the selected entry in the status bar.
+func (d *UI) showRef() {
+	e, _ := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	d.status.SetText(d.entryRefs()[e.ID])
+}
+
+// entryRefs returns the refs of the cached entries, by id.
+func (d *UI) entryRefs() map[string]string {
+	if d.refs == nil {
+		d.refs = ref.Refs(d.allEntries())
+	}
+	return d.refs
+}
+
+// completeSelected completes the selected task.
+func (d *UI) completeSelected(ctx context.Context) {
+	e, i := d.selectedEntry()
+	if e == nil || e.Bullet != glyph.Task {
+		return
+	}
+	e.Complete()
+	if err := d.Persistence.Store(e); err != nil {
+		d.status.SetText(failed("complete", err))
+		return
+	}
+	d.refreshRows(i)
+	d.emit(eventCompleted)
+}
+
+// strikeSelected strikes the selected entry, asking why first if
+// AskReason is set.
+func (d *UI) strikeSelected(ctx context.Context, ui tui.UI) {
+	e, i := d.selectedEntry()
+	if e == nil || e.Bullet == glyph.Irrelevant {
+		return
+	}
+	strike := func(reason string) error {
+		e.Strike(reason)
+		if err := d.Persistence.Store(e); err != nil {
+			return err
+		}
+		d.refreshRows(i)
+		d.status.SetText("struck " + e.Message)
+		return nil
+	}
+	if !d.AskReason {
+		if err := strike(""); err != nil {
+			d.status.SetText(failed("strike", err))
+		}
+		return
+	}
+
+	d.startAdd(ctx, ui, d.selected, nil)
+	if !d.capture.active {
+		return
+	}
+	d.capture.submit = func(ctx context.Context, text string) error {
+		return strike(strings.TrimSpace(text))
+	}
+	d.capture.box.SetTitle(fmt.Sprintf("why strike %q? (enter to skip, ESC to keep it)", e.Message))
+}
+
+// refreshRows redraws the collection view, keeping the selection at row i.
+func (d *UI) refreshRows(i int) {
+	d.dirty = ""
+	d.populateCollection()
+	d.collection.Select(i)
+}
+
+// storeAll stores the entries, all or nothing with a store that has
+// transactions.
+func (d *UI) storeAll(ctx context.Context, entries ...*entry.Entry) error {
+	write := func(put func(e *entry.Entry) error) error {
+		for _, e := range entries {
+			if err := put(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if t, ok := d.Persistence.(store.Transactor); ok {
+		return t.Transact(ctx, func(tx store.Tx) error {
+			return write(tx.Store)
+		})
+	}
+	return write(d.Persistence.Store)
+}
+
+// failed describes a failed action for the status bar, with what can be done
+// about it.
+func failed(action string, err error) string {
+	if hint := app.Hint(err); hint != "" {
+		return fmt.Sprintf("%s failed: %s (%s)", action, err, hint)
+	}
+	return fmt.Sprintf("%s failed: %s", action, err)
+}
+
+// toggleHidden shows or hides expired notes.
+func (d *UI) toggleHidden() {
+	d.showHidden = !d.showHidden
+	if d.showHidden {
+		d.status.SetText("showing expired notes")
+	} else {
+		d.status.SetText("hiding expired notes")
+	}
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+}
+
+// toggleDoneTime shows or hides when completed tasks were completed.
+func (d *UI) toggleDoneTime() {
+	d.ShowDoneTime = !d.ShowDoneTime
+	if d.ShowDoneTime {
+		d.status.SetText("showing when tasks were done")
+	} else {
+		d.status.SetText("hiding when tasks were done")
+	}
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+}
+
+// togglePrivate marks the selected entry private, or not private.
+func (d *UI) togglePrivate() {
+	e, i := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	e.Private = !e.Private
+	if err := d.Persistence.Store(e); err != nil {
+		e.Private = !e.Private
+		d.status.SetText(failed("private", err))
+		return
+	}
+	if e.Private {
+		d.status.SetText("private, left out of exports and shares")
+	} else {
+		d.status.SetText("not private")
+	}
+	d.refreshRows(i)
+}
+
+// togglePinned pins the selected entry to the top of its collection, or
+// unpins it.
+func (d *UI) togglePinned() {
+	e, _ := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	e.Pinned = !e.Pinned
+	if err := d.Persistence.Store(e); err != nil {
+		e.Pinned = !e.Pinned
+		d.status.SetText(failed("pin", err))
+		return
+	}
+	if e.Pinned {
+		d.status.SetText("pinned")
+	} else {
+		d.status.SetText("unpinned")
+	}
+	// The entry moves, the selection follows it.
+	d.dirty = ""
+	d.populateCollection()
+	d.selectEntry(e)
+}
+
+// toggleDone shows or hides the done entries of the selected collection,
+// below the done divider.
+func (d *UI) toggleDone() {
+	name := d.selected
+	d.showDone[name] = !d.showDone[name]
+	d.dirty = ""
+	d.populateCollection()
+	d.selectColumnsRow(d.columns.divider)
+}
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+func TestEntryRefsAreCached(t *testing.T) {
+	d := newWatchUI(nil)
+	d.cache["Work"] = []*entry.Entry{task("aaaa1111", "one")}
+
+	refs := d.entryRefs()
+	if refs["aaaa1111"] == "" {
+		t.Fatalf("got %v, want a ref for aaaa1111", refs)
+	}
+	if again := d.entryRefs(); reflect.ValueOf(again).Pointer() != reflect.ValueOf(refs).Pointer() {
+		t.Error("the refs were made again without the cache changing")
+	}
+
+	// The watch adds an entry.
+	d.update("Work", append(d.cache["Work"], task("bbbb2222", "two")))
+	if refs := d.entryRefs(); refs["bbbb2222"] == "" {
+		t.Errorf("got %v, want a ref for the entry the watch added", refs)
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Focus styles of the selected row.
+const (
+	FocusReverse   = "reverse"
+	FocusBold      = "bold"
+	FocusUnderline = "underline"
+)
+
+// Cursor shapes of text prompts.
+const (
+	CursorBlock     = "block"
+	CursorUnderline = "underline"
+	CursorBar       = "bar"
+)
+
+var focusColors = map[string]tui.Color{
+	"default": tui.ColorDefault,
+	"black":   tui.ColorBlack,
+	"white":   tui.ColorWhite,
+	"red":     tui.ColorRed,
+	"green":   tui.ColorGreen,
+	"blue":    tui.ColorBlue,
+	"cyan":    tui.ColorCyan,
+	"magenta": tui.ColorMagenta,
+	"yellow":  tui.ColorYellow,
+}
+
+// Focus is how the ui shows what has focus and where the cursor is, for
+// terminals and eyes that the defaults do not suit. The zero value is the
+// default look.
+type Focus struct {
+	// Style of the selected row: FocusReverse, FocusBold or FocusUnderline.
+	// Empty is FocusReverse.
+	Style string
+	// Color of the selected row and of the border around what has focus,
+	// one of the terminal's eight colors. Empty leaves them uncolored.
+	Color string
+	// Marker is shown before the title of the focused pane.
+	Marker string
+	// Cursor is the shape of the cursor in prompts: CursorBlock,
+	// CursorUnderline or CursorBar. Empty leaves the terminal's own.
+	Cursor string
+	// Blink makes the cursor blink, if Cursor is set.
+	Blink bool
+}
+
+// Valid returns an error if the style, color or cursor is not known.
+func (f Focus) Valid() error {
+	switch f.Style {
+	case "", FocusReverse, FocusBold, FocusUnderline:
+	default:
+		return app.Invalid("focus style", f.Style, "expected reverse, bold or underline")
+	}
+	if _, ok := focusColors[f.Color]; f.Color != "" && !ok {
+		return app.Invalid("focus color", f.Color, "expected default, black, white, red, green, blue, cyan, magenta or yellow")
+	}
+	switch f.Cursor {
+	case "", CursorBlock, CursorUnderline, CursorBar:
+	default:
+		return app.Invalid("cursor", f.Cursor, "expected block, underline or bar")
+	}
+	return nil
+}
+
+// style sets the focus styles on t: the selected rows of tables and lists,
+// focused buttons and the borders of boxes with focus, which includes the
+// panes and every overlay.
+func (f Focus) style(t *tui.Theme, colors map[tui.Color]tui.Color) {
+	color := shown(focusColors[f.Color], colors)
+	s := tui.Style{Fg: color}
+	switch f.Style {
+	case FocusBold:
+		s.Bold = tui.DecorationOn
+	case FocusUnderline:
+		s.Underline = tui.DecorationOn
+	default:
+		s.Reverse = tui.DecorationOn
+	}
+	t.SetStyle("list.item.selected", s)
+	t.SetStyle("table.cell.selected", s)
+	t.SetStyle("button.focused", s)
+	if f.Color != "" {
+		t.SetStyle("box.focused.border", tui.Style{Fg: color, Bold: tui.DecorationOn})
+	}
+}
+
+// title is the title of a focused pane with the marker.
+func (f Focus) title(title string) string {
+	if f.Marker != "" {
+		return f.Marker + " " + title
+	}
+	return title
+}
+
+// setCursor asks the terminal for the cursor shape with DECSCUSR. Terminals
+// that do not know it ignore it.
+func (f Focus) setCursor(w io.Writer) {
+	if f.Cursor == "" {
+		return
+	}
+	n := map[string]int{CursorBlock: 1, CursorUnderline: 3, CursorBar: 5}[f.Cursor]
+	if !f.Blink {
+		n++
+	}
+	_, _ = fmt.Fprintf(w, "\x1b[%d q", n)
+}
+
+// resetCursor gives the terminal its own cursor shape back.
+func (f Focus) resetCursor(w io.Writer) {
+	if f.Cursor != "" {
+		_, _ = io.WriteString(w, "\x1b[0 q")
+	}
+}
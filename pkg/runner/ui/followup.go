@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// followUpPrefix starts the message of a follow up task.
+const followUpPrefix = "Follow up: "
+
+// followUpTargets are where a follow up can go, the first is the default:
+// tomorrow, a week from now, this month's log, or the collection of the
+// completed task.
+func followUpTargets(now time.Time, from string) []string {
+	targets := []string{
+		collection.DayOf(now.AddDate(0, 0, 1)),
+		collection.DayOf(now.AddDate(0, 0, 7)),
+		collection.MonthOf(now),
+	}
+	for _, t := range targets {
+		if t == from {
+			return targets
+		}
+	}
+	return append(targets, from)
+}
+
+// completeWithFollowUp completes the selected task and opens the capture
+// prompt with a follow up to it. Tab picks where the follow up goes.
+func (d *UI) completeWithFollowUp(ctx context.Context, ui tui.UI) {
+	e, _ := d.selectedEntry()
+	if e == nil || e.Bullet != glyph.Task {
+		return
+	}
+	d.completeSelected(ctx)
+	if e.Bullet != glyph.Completed {
+		// Completing failed, the status bar says why.
+		return
+	}
+
+	targets := followUpTargets(time.Now(), e.Collection)
+	d.startAdd(ctx, ui, targets[0], nil)
+	d.capture.targets = targets
+	d.capture.input.SetText(followUpPrefix + e.Message)
+	d.titleCapture()
+}
+
+// nextCaptureTarget moves the capture prompt to the next of its targets.
+func (d *UI) nextCaptureTarget() {
+	if !d.capture.active || len(d.capture.targets) < 2 {
+		return
+	}
+	at := 0
+	for i, t := range d.capture.targets {
+		if t == d.capture.target {
+			at = i
+		}
+	}
+	d.capture.target = d.capture.targets[(at+1)%len(d.capture.targets)]
+	d.titleCapture()
+}
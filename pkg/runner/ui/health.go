@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/health"
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// bannerAt is where the health banner is shown in the root box, under the
+// index and collection, or under the tutorial while it is shown.
+const bannerAt = 1
+
+// banner lists the problems found with the setup as the ui started, until
+// it is dismissed with ESC.
+type banner struct {
+	shown     bool
+	dismissed bool
+}
+
+// showBanner shows the Problems and what the clock check finds, if there
+// are any and the banner was not dismissed.
+func (d *UI) showBanner() {
+	if d.banner.dismissed {
+		return
+	}
+	all := make([]*entry.Entry, 0)
+	for _, c := range d.cache {
+		all = append(all, c...)
+	}
+	problems := append(append([]health.Problem{}, d.Problems...), health.Clock(all, time.Now())...)
+	if len(problems) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(problems))
+	for _, p := range problems {
+		logging.Warn("setup problem", "problem", p.What, "fix", p.Fix)
+		lines = append(lines, "• "+p.String())
+	}
+	text := tui.NewLabel(strings.Join(lines, "\n"))
+	text.SetWordWrap(true)
+	view := tui.NewVBox(text)
+	view.SetBorder(true)
+	view.SetTitle(fmt.Sprintf("%d setup problems (ESC to dismiss)", len(problems)))
+	if len(problems) == 1 {
+		view.SetTitle("a setup problem (ESC to dismiss)")
+	}
+
+	d.root.Insert(d.bannerAt(), view)
+	d.banner.shown = true
+}
+
+// dismissBanner hides the banner until the ui is started again.
+func (d *UI) dismissBanner() {
+	if !d.banner.shown {
+		return
+	}
+	d.root.Remove(d.bannerAt())
+	d.banner = banner{dismissed: true}
+}
+
+// bannerAt is where the banner is in the root box, the tutorial is put
+// above it.
+func (d *UI) bannerAt() int {
+	if d.tutorial.active {
+		return bannerAt + 1
+	}
+	return bannerAt
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/mitchellh/go-homedir"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/runner/backup"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// startImport opens a prompt for a markdown or todo.txt file to import.
+// Exports, which can be signed, are imported with bujo import.
+func (d *UI) startImport(ctx context.Context, ui tui.UI) {
+	// The file says where its entries go, the target is only to open the
+	// prompt.
+	d.startAdd(ctx, ui, collection.DayOf(time.Now()), nil)
+	if !d.capture.active {
+		return
+	}
+	d.capture.submit = func(ctx context.Context, text string) error {
+		path := strings.TrimSpace(text)
+		if path == "" {
+			return nil
+		}
+		if err := d.importFile(ctx, path); err != nil {
+			d.status.SetText(failed("import", err))
+		}
+		return nil
+	}
+	d.capture.box.SetTitle("import a markdown task list or todo.txt file")
+}
+
+// importFile adds the entries of a markdown or todo.txt file to the
+// journal, all at once if the store can.
+func (d *UI) importFile(ctx context.Context, path string) error {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return err
+	}
+	switch backup.FormatOf(path) {
+	case backup.FormatMarkdown, backup.FormatTodoTxt:
+	default:
+		return fmt.Errorf("only markdown and todo.txt files import here, use bujo import for %s", path)
+	}
+	entries, _, err := backup.Read(path, "")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		d.status.SetText("nothing to import in " + path)
+		return nil
+	}
+
+	write := func(put func(e *entry.Entry) error) error {
+		for _, e := range entries {
+			if err := put(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if t, ok := d.Persistence.(store.Transactor); ok {
+		err = t.Transact(ctx, func(tx store.Tx) error {
+			return write(tx.Store)
+		})
+	} else {
+		err = write(d.Persistence.Store)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.cache = d.Persistence.MapAll(ctx)
+	d.refs = nil
+	d.populateIndex()
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+	d.status.SetText(fmt.Sprintf("%d imported from %s", len(entries), path))
+	return nil
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// info is an overlay describing the selected collection.
+type info struct {
+	active bool
+	// prev is shown again once the overlay is closed.
+	prev tui.Widget
+}
+
+// infoPlacement is the info overlay, in the top left and as big as the
+// lines need.
+var infoPlacement = Placement{Fit: true, MinWidth: 40}
+
+// toggleInfo shows or hides the info overlay for the selected collection.
+func (d *UI) toggleInfo(ctx context.Context, ui tui.UI) {
+	if d.info.active {
+		d.setWidget(ui, d.info.prev)
+		d.info = info{}
+		return
+	}
+
+	i, ok := d.Persistence.(store.Inspector)
+	if !ok || d.selected == "" {
+		return
+	}
+	if d.isView(d.selected) {
+		d.status.SetText("a view is not a collection, it has no info")
+		return
+	}
+	if d.isArchived(d.selected) {
+		d.status.SetText("archived collections have no info, unarchive it with z first")
+		return
+	}
+	ci, err := i.CollectionInfo(ctx, d.selected)
+	if err != nil {
+		d.status.SetText(failed("info", err))
+		return
+	}
+
+	lines := tui.NewVBox()
+	for _, line := range printers.InfoLines(ci) {
+		lines.Append(tui.NewLabel(line))
+	}
+	box := tui.NewVBox(lines)
+	box.SetBorder(true)
+	box.SetTitle(fmt.Sprintf("%s ('i' to close)", d.iconed(ci.Name)))
+
+	d.info = info{active: true, prev: d.current}
+	d.setWidget(ui, d.overlay(box, infoPlacement))
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/mitchellh/go-homedir"
+
+	"tableflip.dev/bujo/pkg/runner/invite"
+)
+
+// startInvite opens a prompt for the file to write the invite of the
+// selected event to, named after the event unless changed.
+func (d *UI) startInvite(ctx context.Context, ui tui.UI) {
+	e, _ := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	ics, attendees, err := d.Meeting.For(e)
+	if err != nil {
+		d.status.SetText(failed("invite", err))
+		return
+	}
+
+	d.startAdd(ctx, ui, d.selected, nil)
+	if !d.capture.active {
+		return
+	}
+	d.capture.input.SetText(invite.FileName(e))
+	d.capture.submit = func(ctx context.Context, text string) error {
+		path, err := homedir.Expand(strings.TrimSpace(text))
+		if err != nil || path == "" {
+			return err
+		}
+		if err := ioutil.WriteFile(path, ics, 0644); err != nil {
+			d.status.SetText(failed("invite", err))
+			return nil
+		}
+		d.status.SetText(fmt.Sprintf("wrote an invite for %d to %s", attendees, path))
+		return nil
+	}
+	d.capture.box.SetTitle(fmt.Sprintf("write the invite for %d to", attendees))
+}
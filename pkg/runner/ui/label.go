@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/rollup"
+)
+
+var labelColors = map[glyph.Label]tui.Color{
+	glyph.LabelRed:     tui.ColorRed,
+	glyph.LabelYellow:  tui.ColorYellow,
+	glyph.LabelGreen:   tui.ColorGreen,
+	glyph.LabelCyan:    tui.ColorCyan,
+	glyph.LabelBlue:    tui.ColorBlue,
+	glyph.LabelMagenta: tui.ColorMagenta,
+}
+
+// theme is the default tui-go theme with the focus styles, plus a style per
+// color label, in the colors of a palette.
+func theme(f Focus, colors map[tui.Color]tui.Color) *tui.Theme {
+	t := tui.NewTheme()
+	f.style(t, colors)
+	for l, c := range labelColors {
+		t.SetStyle("label."+string(l), tui.Style{Fg: shown(c, colors)})
+	}
+	return t
+}
+
+// entryRow is the row for an entry in the collection table, with a gutter
+// mark showing the color label. Computed entries are evaluated against all,
+// unless it is nil. done is when a completed task was done, if shown.
+func entryRow(e *entry.Entry, all []*entry.Entry, done string) tui.Widget {
+	gutter := tui.NewLabel(" ")
+	if e.Label != glyph.NoLabel {
+		gutter.SetText("▌")
+		gutter.SetStyleName(string(e.Label))
+	}
+	shown := *e
+	if all != nil && rollup.Is(e.Message) {
+		shown.Message = rollup.Render(e.Message, all)
+	}
+	// A checklist is shown in the preview, the row has its progress.
+	if p := e.Progress(); p != "" {
+		shown.Message = shown.Headline() + "  [" + p + "]"
+	}
+	if c := e.Cluster(); c != "" {
+		shown.Message += "  " + c
+	}
+	msg := shown.String()
+	if done != "" {
+		msg += "  (" + done + ")"
+	}
+	if e.Recurrence != nil && e.Recurrence.Of != "" {
+		msg += "  " + glyph.Recurring
+	} else if e.Recurrence != nil {
+		msg += "  " + glyph.Recurring + " " + e.Recurrence.Rule
+	}
+	if n := len(e.Attachments); n > 0 {
+		msg += fmt.Sprintf("  %s%d", glyph.Attachment, n)
+	}
+	if e.Private {
+		msg += "  (private)"
+	}
+	if e.Pinned {
+		msg += "  (pinned)"
+	}
+	text := tui.NewLabel(msg)
+	text.SetSizePolicy(tui.Expanding, tui.Preferred)
+	return tui.NewHBox(gutter, text)
+}
+
+// cycleLabel moves the selected entry to the next color label.
+func (d *UI) cycleLabel(ctx context.Context) {
+	e, i := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	e.Label = e.Label.Next()
+	if err := d.Persistence.Store(e); err != nil {
+		d.status.SetText(failed("label", err))
+		return
+	}
+	d.status.SetText(fmt.Sprintf("label: %s", e.Label))
+	d.refreshRows(i)
+	d.emit(eventLabelled)
+}
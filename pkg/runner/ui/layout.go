@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// layoutName is what a layout can be called, it is a key of the config.
+var layoutName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Layout is how the ui is laid out, saved by name in ui.layouts to switch
+// between terminals of different sizes.
+type Layout struct {
+	// Compact is one of the Compact modes.
+	Compact string
+	// Columns is ColumnsAuto or a number of columns.
+	Columns string
+	// Bar is which segments the bottom bar shows, only Left and Right are
+	// kept.
+	Bar Bar
+}
+
+// Valid returns an error if the compact mode, columns or bar segments are
+// not known. custom are the segments the bar can name besides its own.
+func (l Layout) Valid(name string, custom []Segment) error {
+	if !layoutName.MatchString(name) {
+		return app.Invalid("layout", name, "expected lowercase letters, digits, - or _")
+	}
+	switch l.Compact {
+	case "", CompactAuto, CompactOn, CompactOff:
+	default:
+		return app.Invalid("layout compact", l.Compact, "expected auto, on or off")
+	}
+	if err := ValidColumns(l.Columns); err != nil {
+		return err
+	}
+	return Bar{Left: l.Bar.Left, Right: l.Bar.Right, Custom: custom}.Valid()
+}
+
+// applyLayout sets the named layout of Layouts before the ui runs.
+func (d *UI) applyLayout() error {
+	for name, l := range d.Layouts {
+		if err := l.Valid(name, d.Bar.Custom); err != nil {
+			return err
+		}
+	}
+	if d.Layout == "" {
+		return nil
+	}
+	l, ok := d.Layouts[d.Layout]
+	if !ok {
+		return app.Invalid("layout", d.Layout, d.unknownLayout())
+	}
+	d.Compact = l.Compact
+	d.Columns = l.Columns
+	if len(l.Bar.Left) > 0 || len(l.Bar.Right) > 0 {
+		d.Bar.Left, d.Bar.Right = l.Bar.Left, l.Bar.Right
+	}
+	return nil
+}
+
+// layoutNames are the names of the saved layouts, sorted.
+func (d *UI) layoutNames() []string {
+	names := make([]string, 0, len(d.Layouts))
+	for name := range d.Layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unknownLayout is why a layout that is not saved can not be used.
+func (d *UI) unknownLayout() string {
+	if len(d.Layouts) == 0 {
+		return "no layouts, save one in the ui with ctrl+p"
+	}
+	return "expected one of " + strings.Join(d.layoutNames(), ", ")
+}
+
+// startLayout opens a prompt to save the layout as it is, "save <name>",
+// or to switch to a saved one by its name.
+func (d *UI) startLayout(ctx context.Context, ui tui.UI) {
+	// The target is only to open the prompt.
+	d.startAdd(ctx, ui, collection.DayOf(time.Now()), nil)
+	if !d.capture.active {
+		return
+	}
+	d.capture.submit = func(ctx context.Context, text string) error {
+		fields := strings.Fields(text)
+		switch {
+		case len(fields) == 0:
+			return nil
+		case len(fields) == 2 && fields[0] == "save":
+			return d.saveLayout(fields[1])
+		case len(fields) == 1:
+			return d.loadLayout(fields[0])
+		}
+		return app.Invalid("layout", text, "expected save and a name, or the name of a saved layout")
+	}
+	if len(d.Layouts) == 0 {
+		d.capture.box.SetTitle("layout: save <name> to save the layout as it is")
+		return
+	}
+	d.capture.box.SetTitle(fmt.Sprintf("layout: save <name>, or load %s", strings.Join(d.layoutNames(), ", ")))
+}
+
+// currentLayout is the layout as it is now.
+func (d *UI) currentLayout() Layout {
+	l := Layout{
+		Compact: d.compact.mode,
+		Columns: d.columns.mode,
+		Bar:     Bar{Left: d.Bar.Left, Right: d.Bar.Right},
+	}
+	if l.Compact == "" {
+		l.Compact = CompactAuto
+	}
+	if l.Columns == "" {
+		l.Columns = ColumnsAuto
+	}
+	return l
+}
+
+// saveLayout saves the layout as it is under name in ui.layouts.
+func (d *UI) saveLayout(name string) error {
+	name = strings.ToLower(name)
+	l := d.currentLayout()
+	if err := l.Valid(name, d.Bar.Custom); err != nil {
+		return err
+	}
+	value := map[string]interface{}{
+		"compact": l.Compact,
+		"columns": l.Columns,
+	}
+	if len(l.Bar.Left) > 0 || len(l.Bar.Right) > 0 {
+		value["bar"] = map[string]interface{}{
+			"left":  l.Bar.Left,
+			"right": l.Bar.Right,
+		}
+	}
+	file, err := store.SetConfig("ui.layouts."+name, value)
+	if err != nil {
+		return err
+	}
+	if d.Layouts == nil {
+		d.Layouts = make(map[string]Layout)
+	}
+	d.Layouts[name] = l
+	d.status.SetText(fmt.Sprintf("layout %s saved to %s", name, file))
+	return nil
+}
+
+// loadLayout switches to the saved layout name.
+func (d *UI) loadLayout(name string) error {
+	name = strings.ToLower(name)
+	l, ok := d.Layouts[name]
+	if !ok {
+		return app.Invalid("layout", name, d.unknownLayout())
+	}
+
+	d.compact.mode = l.Compact
+	d.layoutCompact()
+	d.columns.mode = l.Columns
+	if len(l.Bar.Left) > 0 || len(l.Bar.Right) > 0 {
+		d.Bar.Left, d.Bar.Right = l.Bar.Left, l.Bar.Right
+		d.status.left, d.status.right = d.Bar.layout(d.segments(d.status))
+	}
+	e, _ := d.selectedEntry()
+	// Force the collection view to be refreshed, the columns may change.
+	d.dirty = ""
+	d.populateCollection()
+	if e != nil {
+		d.selectEntry(e)
+	}
+	d.status.SetText(fmt.Sprintf("layout %s", name))
+	return nil
+}
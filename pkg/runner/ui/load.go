@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// loadWait is how long reading the journal takes before its progress is
+// shown, most journals are read before then.
+const loadWait = 300 * time.Millisecond
+
+// loadJournal reads every collection of the journal. If that takes longer
+// than loadWait, how many collections are read and for how long is shown
+// on a line of the terminal, which the ui then draws over.
+func (d *UI) loadJournal(ctx context.Context) map[string][]*entry.Entry {
+	m, ok := d.Persistence.(store.ProgressMapper)
+	if !ok {
+		return d.Persistence.MapAll(ctx)
+	}
+	start := time.Now()
+	shown := false
+	all := m.MapAllProgress(ctx, func(read, total int) {
+		elapsed := time.Since(start)
+		if elapsed < loadWait {
+			return
+		}
+		shown = true
+		loadProgress(os.Stderr, read, total, elapsed)
+	})
+	if shown {
+		fmt.Fprintln(os.Stderr)
+	}
+	return all
+}
+
+// loadProgress writes a progress line over the last one.
+func loadProgress(w io.Writer, read, total int, elapsed time.Duration) {
+	if total < read {
+		total = read
+	}
+	fmt.Fprintf(w, "\rloading journal: %d of %d collections, %s ", read, total, elapsed.Round(100*time.Millisecond))
+}
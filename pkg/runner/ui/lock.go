@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// idleCheck is how often the ui checks if it has been idle for too long.
+const idleCheck = 5 * time.Second
+
+// idle locks the ui behind a lock screen after a period without key
+// presses, so the journal is not left on screen. An encrypted journal is
+// only unlocked with its passphrase.
+type idle struct {
+	last   time.Time
+	locked bool
+	// restore is shown again once unlocked.
+	restore tui.Widget
+}
+
+// lockScreen hides the journal until a key is pressed, or the passphrase
+// is entered if the journal is encrypted.
+type lockScreen struct {
+	*tui.Box
+	// unlock is called on any key, if set.
+	unlock func()
+}
+
+func (l *lockScreen) OnKeyEvent(ev tui.KeyEvent) {
+	if l.unlock != nil {
+		l.unlock()
+		return
+	}
+	l.Box.OnKeyEvent(ev)
+}
+
+// active is the widget on screen, it counts every key pressed as activity,
+// whatever the widget does with it.
+type active struct {
+	tui.Widget
+	touch func()
+}
+
+func (a *active) OnKeyEvent(ev tui.KeyEvent) {
+	a.touch()
+	a.Widget.OnKeyEvent(ev)
+}
+
+// bind sets a keybinding that is ignored while the ui is locked, while an
+// overlay with keys of its own is open, like a search being typed, unless a
+// capture prompt is open over it, or once the ui is quitting.
+func (d *UI) bind(ui tui.UI, seq string, fn func()) {
+	ui.SetKeybinding(seq, func() {
+		if d.idle.locked || d.shutdown.stopping || (d.overlayKeys() && !d.capture.active) {
+			return
+		}
+		// A held back quit only quits if the next key quits again.
+		asked := d.shutdown.asked
+		fn()
+		if asked {
+			d.shutdown.asked = false
+		}
+	})
+}
+
+// overlayKeys is true while an overlay that takes the keys is open, or a
+// mark letter is pending.
+func (d *UI) overlayKeys() bool {
+	return d.review.active || d.migrate.active || d.dayReview.active || d.marks.pending != "" || d.search.active || d.bullets.active
+}
+
+// touch records activity.
+func (d *UI) touch() {
+	d.idle.last = time.Now()
+}
+
+// watchIdle locks the ui once it has been idle for d.IdleLock, until ctx is
+// done.
+func (d *UI) watchIdle(ctx context.Context, ui tui.UI) {
+	ticker := time.NewTicker(idleCheck)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ui.Update(func() {
+			if !d.idle.locked && time.Since(d.idle.last) >= d.IdleLock {
+				d.lock(ui)
+			}
+		})
+	}
+}
+
+func (d *UI) lock(ui tui.UI) {
+	d.idle.locked = true
+	d.idle.restore = d.onScreen
+
+	u, ok := d.Persistence.(store.Unlocker)
+	if !ok || !u.Encrypted() {
+		ui.SetWidget(&lockScreen{Box: lockBox(tui.NewLabel("locked, press any key")), unlock: func() {
+			d.unlock(ui)
+		}})
+		return
+	}
+
+	label := tui.NewLabel("locked, enter the passphrase")
+	passphrase := tui.NewEntry()
+	passphrase.SetEchoMode(tui.EchoModePassword)
+	passphrase.SetFocused(true)
+	passphrase.OnSubmit(func(e *tui.Entry) {
+		if !u.Unlocks(e.Text()) {
+			label.SetText("wrong passphrase, try again")
+			e.SetText("")
+			return
+		}
+		d.unlock(ui)
+	})
+	box := tui.NewHBox(passphrase)
+	box.SetBorder(true)
+	ui.SetWidget(&lockScreen{Box: lockBox(tui.NewVBox(label, box))})
+}
+
+// lockBox centers w on the lock screen.
+func lockBox(w tui.Widget) *tui.Box {
+	return tui.NewVBox(
+		tui.NewSpacer(),
+		tui.NewHBox(tui.NewSpacer(), w, tui.NewSpacer()),
+		tui.NewSpacer(),
+	)
+}
+
+func (d *UI) unlock(ui tui.UI) {
+	if !d.idle.locked {
+		return
+	}
+	d.idle.locked = false
+	d.show(ui, d.idle.restore)
+	d.touch()
+}
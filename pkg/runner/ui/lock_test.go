@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// screen is a tui.UI that keeps the widget on screen.
+type screen struct {
+	tui.UI
+	root tui.Widget
+}
+
+func (s *screen) SetWidget(w tui.Widget) { s.root = w }
+
+// Update drops fn, the widgets it would focus are not on a real screen.
+func (s *screen) Update(fn func()) {}
+
+// press sends the keys of text, and then enter, to what is on screen.
+func (s *screen) press(text string) {
+	for _, r := range text {
+		s.root.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: r})
+	}
+	s.root.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+}
+
+// locked is an encrypted journal.
+type locked struct {
+	store.Persistence
+	passphrase string
+}
+
+func (l *locked) Encrypted() bool                { return true }
+func (l *locked) Unlocks(passphrase string) bool { return passphrase == l.passphrase }
+
+func TestOverlayKeysAreActivity(t *testing.T) {
+	s := &screen{}
+	d := &UI{status: &bar{}}
+	d.setWidget(s, d.overlay(tui.NewLabel("search"), Placement{}))
+
+	idle := time.Now().Add(-time.Hour)
+	d.idle.last = idle
+	s.root.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'a'})
+	if !d.idle.last.After(idle) {
+		t.Error("a key typed in an overlay was not counted as activity")
+	}
+}
+
+func TestLock(t *testing.T) {
+	s := &screen{}
+	d := &UI{}
+	journal := tui.NewLabel("journal")
+	d.show(s, journal)
+
+	d.lock(s)
+	s.root.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'x'})
+	if d.idle.locked || d.onScreen != journal {
+		t.Error("a plain journal is not unlocked by any key")
+	}
+}
+
+func TestLockEncrypted(t *testing.T) {
+	s := &screen{}
+	d := &UI{Persistence: &locked{passphrase: "hunter2"}}
+	journal := tui.NewLabel("journal")
+	d.show(s, journal)
+
+	d.lock(s)
+	s.root.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'x'})
+	s.root.OnKeyEvent(tui.KeyEvent{Key: tui.KeyBackspace2})
+	if !d.idle.locked {
+		t.Fatal("an encrypted journal is unlocked by any key")
+	}
+	s.press("wrong")
+	if !d.idle.locked {
+		t.Fatal("an encrypted journal is unlocked by the wrong passphrase")
+	}
+	s.press("hunter2")
+	if d.idle.locked || d.onScreen != journal {
+		t.Error("an encrypted journal is not unlocked by its passphrase")
+	}
+}
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// maxJumps is how many positions the jump list keeps.
+const maxJumps = 50
+
+// Keys that wait for a mark letter.
+const (
+	markSet  = "b"
+	markJump = "'"
+)
+
+// position is a collection, and the entry selected in it if any.
+type position struct {
+	collection string
+	id         string
+}
+
+// marks are positions kept by letter, like marks in vim, and the jump list
+// of positions jumped away from.
+type marks struct {
+	// pending is markSet or markJump while waiting for the letter.
+	pending string
+	set     map[rune]position
+
+	jumps []position
+	// at is where in jumps ctrl+o and tab move from. It is len(jumps) until
+	// ctrl+o is pressed.
+	at int
+}
+
+// bindMarkKeys sets the keys of marks and the jump list. They are set after
+// the other keys, which are ignored while a mark letter is pending.
+func (d *UI) bindMarkKeys(ui tui.UI) {
+	for r := 'a'; r <= 'z'; r++ {
+		letter := r
+		d.bindMark(ui, string(letter), func() {
+			if d.marks.pending != "" {
+				d.endMark(letter)
+				return
+			}
+			if string(letter) == markSet && d.navigating() {
+				d.startMark(markSet)
+			}
+		})
+	}
+	d.bindMark(ui, markJump, func() {
+		if d.marks.pending != "" {
+			d.cancelMark()
+			return
+		}
+		if d.navigating() {
+			d.startMark(markJump)
+		}
+	})
+	d.bindMark(ui, "Esc", func() {
+		if d.marks.pending != "" {
+			d.cancelMark()
+		}
+	})
+	d.bind(ui, "Ctrl+O", func() {
+		if d.navigating() {
+			d.jumpBack()
+		}
+	})
+	// Terminals send ctrl+i as tab.
+	d.bind(ui, "Tab", func() {
+		if d.navigating() {
+			d.jumpForward()
+		}
+	})
+}
+
+// bindMark sets a keybinding that fires while a mark letter is pending, as
+// well as when the ui is not locked or reviewing.
+func (d *UI) bindMark(ui tui.UI, seq string, fn func()) {
+	ui.SetKeybinding(seq, func() {
+		if d.idle.locked || d.review.active || d.migrate.active || d.capture.active {
+			return
+		}
+		fn()
+	})
+}
+
+// navigating returns true if the keys move around the journal, and not a
+// prompt or overlay.
+func (d *UI) navigating() bool {
+	m := d.mode()
+	return m == "index" || m == "collection"
+}
+
+func (d *UI) startMark(pending string) {
+	d.marks.pending = pending
+	if pending == markSet {
+		d.status.SetText("mark: press a letter to set")
+	} else {
+		d.status.SetText("mark: press a letter to jump to")
+	}
+}
+
+func (d *UI) cancelMark() {
+	d.marks.pending = ""
+	d.status.SetText("")
+}
+
+// endMark sets or jumps to the mark of letter.
+func (d *UI) endMark(letter rune) {
+	pending := d.marks.pending
+	d.marks.pending = ""
+	if pending == markSet {
+		if d.marks.set == nil {
+			d.marks.set = make(map[rune]position)
+		}
+		d.marks.set[letter] = d.position()
+		d.status.SetText(fmt.Sprintf("mark %c set", letter))
+		return
+	}
+	to, ok := d.marks.set[letter]
+	if !ok {
+		d.status.SetText(fmt.Sprintf("mark %c is not set", letter))
+		return
+	}
+	d.pushJump()
+	d.restore(to)
+	d.status.SetText(fmt.Sprintf("jumped to mark %c", letter))
+}
+
+// position is where the ui is now.
+func (d *UI) position() position {
+	p := position{collection: d.selected}
+	if e, _ := d.selectedEntry(); e != nil {
+		p.id = e.ID
+	}
+	return p
+}
+
+// pushJump adds where the ui is to the jump list, before jumping away. The
+// positions after a ctrl+o are dropped.
+func (d *UI) pushJump() {
+	here := d.position()
+	jumps := d.marks.jumps[:d.marks.at]
+	if n := len(jumps); n == 0 || jumps[n-1] != here {
+		jumps = append(jumps, here)
+	}
+	if len(jumps) > maxJumps {
+		jumps = jumps[len(jumps)-maxJumps:]
+	}
+	d.marks.jumps = jumps
+	d.marks.at = len(jumps)
+}
+
+// jumpBack goes to the position before the current one in the jump list.
+func (d *UI) jumpBack() {
+	if d.marks.at == 0 {
+		d.status.SetText("at the start of the jump list")
+		return
+	}
+	if d.marks.at == len(d.marks.jumps) {
+		// Keep where the ui is, so tab comes back to it.
+		here := d.position()
+		d.marks.jumps = append(d.marks.jumps, here)
+	}
+	d.marks.at--
+	d.restore(d.marks.jumps[d.marks.at])
+}
+
+// jumpForward goes back to the position ctrl+o left.
+func (d *UI) jumpForward() {
+	if d.marks.at >= len(d.marks.jumps)-1 {
+		d.status.SetText("at the end of the jump list")
+		return
+	}
+	d.marks.at++
+	d.restore(d.marks.jumps[d.marks.at])
+	if d.marks.at == len(d.marks.jumps)-1 {
+		// Back where ctrl+o was first pressed.
+		d.marks.jumps = d.marks.jumps[:d.marks.at]
+	}
+}
+
+// restore opens the collection of p and selects its entry, if it is still
+// there.
+func (d *UI) restore(p position) {
+	d.openCollection(p.collection)
+	d.focusCollection()
+	for _, e := range d.entriesOf(p.collection) {
+		if e.ID == p.id {
+			d.selectEntry(e)
+			return
+		}
+	}
+}
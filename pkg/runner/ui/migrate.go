@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/runner/batch"
+)
+
+// migratePlacement is the migrate overlay, all of the screen.
+var migratePlacement = Placement{}
+
+// migrate is an overlay to move the open tasks of a collection. Tasks
+// marked with space are moved together, in one batch.
+type migrate struct {
+	active bool
+	from   string
+	tasks  []*entry.Entry
+	// marked are the ids of the tasks to move together.
+	marked  map[string]bool
+	targets []string
+	table   *tui.Table
+
+	// prev is shown again once the overlay is closed.
+	prev tui.Widget
+}
+
+// migrateTargets are where open tasks can be migrated to: today, tomorrow,
+// this month's log and next month's future log, leaving out from.
+func migrateTargets(now time.Time, from string) []string {
+	targets := make([]string, 0, 4)
+	for _, t := range []string{
+		collection.DayOf(now),
+		collection.DayOf(now.AddDate(0, 0, 1)),
+		collection.MonthOf(now),
+		collection.FutureOf(now.AddDate(0, 1, 0)),
+	} {
+		if t != from {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// bindMigrate sets a keybinding that only fires while migrating.
+func (d *UI) bindMigrate(ui tui.UI, seq string, fn func()) {
+	ui.SetKeybinding(seq, func() {
+		if d.idle.locked || !d.migrate.active || d.capture.active {
+			return
+		}
+		fn()
+	})
+}
+
+// bindMigrateKeys sets the keys of the migrate overlay.
+func (d *UI) bindMigrateKeys(ctx context.Context, ui tui.UI) {
+	d.bindMigrate(ui, " ", func() {
+		d.toggleMarked(ui)
+	})
+	for i := 1; i <= 4; i++ {
+		target := i - 1
+		d.bindMigrate(ui, strconv.Itoa(i), func() {
+			if target < len(d.migrate.targets) {
+				d.migrateTo(ctx, ui, d.migrate.targets[target])
+			}
+		})
+	}
+	d.bindMigrate(ui, "Esc", func() {
+		d.endMigrate(ui)
+	})
+}
+
+// startMigrate opens the migrate overlay for the open tasks of the selected
+// collection.
+func (d *UI) startMigrate(ui tui.UI) {
+	tasks := make([]*entry.Entry, 0)
+	for _, e := range d.cache[d.selected] {
+		if e.Bullet == glyph.Task {
+			tasks = append(tasks, e)
+		}
+	}
+	if len(tasks) == 0 {
+		d.status.SetText("no open tasks to migrate")
+		return
+	}
+	d.migrate = migrate{
+		active:  true,
+		from:    d.selected,
+		tasks:   tasks,
+		marked:  make(map[string]bool),
+		targets: migrateTargets(time.Now(), d.selected),
+		prev:    d.current,
+	}
+	d.indexes.SetFocused(false)
+	d.collection.SetFocused(false)
+	d.showMigrate(ui, 0)
+}
+
+func (d *UI) endMigrate(ui tui.UI) {
+	if !d.migrate.active {
+		return
+	}
+	d.setWidget(ui, d.migrate.prev)
+	d.focusCollection()
+	d.migrate = migrate{}
+}
+
+// showMigrate shows the open tasks with their marks, selecting row i.
+func (d *UI) showMigrate(ui tui.UI, i int) {
+	m := &d.migrate
+
+	m.table = tui.NewTable(1, 0)
+	for _, e := range m.tasks {
+		mark := "[ ] "
+		if m.marked[e.ID] {
+			mark = "[x] "
+		}
+		m.table.AppendRow(tui.NewLabel(mark + e.Message))
+	}
+	m.table.Select(i)
+	m.table.SetFocused(true)
+
+	keys := make([]string, 0, len(m.targets))
+	for i, t := range m.targets {
+		keys = append(keys, fmt.Sprintf("%d %s", i+1, t))
+	}
+	help := tui.NewLabel("move to: " + strings.Join(keys, ", "))
+	help.SetWordWrap(true)
+
+	box := tui.NewVBox(m.table, tui.NewSpacer(), help)
+	box.SetBorder(true)
+	box.SetTitle(fmt.Sprintf("migrate %s (space to mark, ESC to close)", m.from))
+
+	d.setWidget(ui, d.overlay(box, migratePlacement))
+}
+
+// toggleMarked marks or unmarks the selected task.
+func (d *UI) toggleMarked(ui tui.UI) {
+	i := d.migrate.table.Selected()
+	if i < 0 || i >= len(d.migrate.tasks) {
+		return
+	}
+	id := d.migrate.tasks[i].ID
+	if d.migrate.marked[id] {
+		delete(d.migrate.marked, id)
+	} else {
+		d.migrate.marked[id] = true
+	}
+	if i+1 < len(d.migrate.tasks) {
+		i++
+	}
+	d.showMigrate(ui, i)
+}
+
+// failedOp returns the index of the op that failed, a batch stops at it, or
+// -1 if none of the n ops did.
+func failedOp(report *batch.Report, n int) int {
+	if len(report.Results) == 0 {
+		return -1
+	}
+	r := report.Results[len(report.Results)-1]
+	if r.Status != batch.StatusFailed || r.Line < 1 || r.Line > n {
+		return -1
+	}
+	return r.Line - 1
+}
+
+// countMoved returns how many of entries moved.
+func countMoved(entries []*entry.Entry, moved map[string]bool) int {
+	n := 0
+	for _, e := range entries {
+		if moved[e.ID] {
+			n++
+		}
+	}
+	return n
+}
+
+// migrateTo moves the marked tasks, or the selected task if none are
+// marked, to the target collection in one batch.
+func (d *UI) migrateTo(ctx context.Context, ui tui.UI, target string) {
+	m := &d.migrate
+	moving := make([]*entry.Entry, 0, len(m.marked))
+	for _, e := range m.tasks {
+		if m.marked[e.ID] {
+			moving = append(moving, e)
+		}
+	}
+	if len(moving) == 0 {
+		i := m.table.Selected()
+		if i < 0 || i >= len(m.tasks) {
+			return
+		}
+		moving = append(moving, m.tasks[i])
+	}
+
+	ops := make([]batch.Op, 0, len(moving))
+	for _, e := range moving {
+		ops = append(ops, batch.Op{Op: batch.OpMove, ID: e.ID, Collection: target})
+	}
+	report, err := batch.Apply(ctx, d.Persistence, ops)
+
+	d.cache = d.Persistence.MapAll(ctx)
+	d.refs = nil
+	d.populateIndex()
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+
+	// The journal says what moved, without transactions a failure can
+	// leave some of them moved.
+	moved := make(map[string]bool, len(moving))
+	for _, e := range d.cache[m.from] {
+		if e.Bullet == glyph.MovedCollection || e.Bullet == glyph.MovedFuture {
+			moved[e.ID] = true
+		}
+	}
+	i := failedOp(report, len(moving))
+	switch {
+	case err == nil:
+		d.status.SetText(fmt.Sprintf("moved %d to %s", len(moving), target))
+	case i >= 0:
+		r := report.Results[len(report.Results)-1]
+		d.status.SetText(fmt.Sprintf("moved %d to %s, moving %s failed: %s", countMoved(moving, moved), target, d.entryRefs()[moving[i].ID], r.Message))
+	default:
+		d.status.SetText(fmt.Sprintf("moved %d to %s, %s", countMoved(moving, moved), target, failed("migrate", err)))
+	}
+
+	left := make([]*entry.Entry, 0, len(m.tasks))
+	for _, e := range m.tasks {
+		if !moved[e.ID] {
+			left = append(left, e)
+		}
+	}
+	if len(left) == 0 {
+		d.endMigrate(ui)
+		return
+	}
+	m.tasks = left
+	m.marked = make(map[string]bool)
+	d.showMigrate(ui, 0)
+}
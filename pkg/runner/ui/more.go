@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	// firstPage is how many of the most recent entries of a collection are
+	// shown at first.
+	firstPage = 100
+	// morePage is how many more entries are shown each time more is asked
+	// for.
+	morePage = 20
+)
+
+// shown returns how many of the most recent entries of the named collection
+// are shown.
+func (d *UI) shown(name string) int {
+	if n, ok := d.limits[name]; ok {
+		return n
+	}
+	return firstPage
+}
+
+// moreRow is the row standing in for the entries that are not shown yet.
+func moreRow(hidden int) *tui.Label {
+	n := morePage
+	if hidden < n {
+		n = hidden
+	}
+	return tui.NewLabel(fmt.Sprintf("  … %d more (enter or 'm' to show %d more)", hidden, n))
+}
+
+// showMore shows morePage more entries of the selected collection. The
+// selection stays on the same entry, or on the more row so it can be asked
+// again.
+func (d *UI) showMore() {
+	name := d.selected
+	if name == "" || len(d.rows) == 0 || d.rows[0] != nil {
+		return
+	}
+	i := d.collection.Selected()
+	before := len(d.rows)
+	d.limits[name] = d.shown(name) + morePage
+	d.dirty = ""
+	d.populateCollection()
+	if i > 0 {
+		i += len(d.rows) - before
+	}
+	d.collection.Select(i)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// Start screens the ui can open to, anything else is a collection name.
+const (
+	OpenToday  = "today"
+	OpenMonth  = "month"
+	OpenFuture = "future"
+	// OpenLast opens the collection that was selected when the ui last quit.
+	OpenLast = "last"
+)
+
+// startCollection resolves d.Open to the collection to open to, or "" to
+// open to the top of the index.
+func (d *UI) startCollection() string {
+	now := time.Now()
+	switch d.Open {
+	case "":
+		return ""
+	case OpenToday:
+		return collection.DayOf(now)
+	case OpenMonth:
+		return collection.MonthOf(now)
+	case OpenFuture:
+		return collection.FutureOf(now)
+	case OpenLast:
+		if d.SessionPath == "" {
+			return ""
+		}
+		b, err := ioutil.ReadFile(d.SessionPath)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(b))
+	default:
+		return d.Open
+	}
+}
+
+// openCollection highlights the named collection in the index and shows it,
+// materializing an empty collection for it if needed.
+func (d *UI) openCollection(name string) {
+	if _, ok := d.cache[name]; !ok && !d.isView(name) {
+		d.cache[name] = []*entry.Entry{}
+		d.populateIndex()
+	}
+	for i, c := range d.index {
+		if c == name {
+			d.indexes.Select(i)
+			break
+		}
+	}
+	d.selectCollection()
+}
+
+// saveSession remembers the selected collection for OpenLast.
+func (d *UI) saveSession() {
+	if d.SessionPath == "" || d.selected == "" {
+		return
+	}
+	_ = ioutil.WriteFile(d.SessionPath, []byte(d.selected+"\n"), 0644)
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Anchor is where an overlay sits when it is smaller than the screen.
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorCenter
+)
+
+// Placement is how much of the screen an overlay takes, and where. The zero
+// Placement is all of the screen.
+type Placement struct {
+	// Width and Height are percents of the screen, 0 is all of it.
+	Width  int
+	Height int
+	// MinWidth, MinHeight, MaxWidth and MaxHeight bound the size in cells,
+	// 0 is no bound. The overlay is never bigger than the screen.
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+	// Fit shrinks the overlay to the size its content asks for, within the
+	// bounds above.
+	Fit    bool
+	Anchor Anchor
+}
+
+// rect is where the overlay goes on a screen of size, for content that asks
+// for hint.
+func (p Placement) rect(size, hint image.Point) image.Rectangle {
+	w := span(size.X, p.Width, p.MinWidth, p.MaxWidth, hint.X, p.Fit)
+	h := span(size.Y, p.Height, p.MinHeight, p.MaxHeight, hint.Y, p.Fit)
+
+	var at image.Point
+	switch p.Anchor {
+	case AnchorTop:
+		at = image.Point{X: (size.X - w) / 2}
+	case AnchorCenter:
+		at = image.Point{X: (size.X - w) / 2, Y: (size.Y - h) / 2}
+	}
+	return image.Rectangle{Min: at, Max: at.Add(image.Point{X: w, Y: h})}
+}
+
+// span is the length of one side of an overlay, out of total.
+func span(total, percent, min, max, hint int, fit bool) int {
+	n := total
+	if percent > 0 {
+		n = total * percent / 100
+	}
+	if fit && hint < n {
+		n = hint
+	}
+	if max > 0 && n > max {
+		n = max
+	}
+	if n < min {
+		n = min
+	}
+	if n > total {
+		n = total
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// pane lays its content out by a Placement, in all of the space it is
+// given.
+type pane struct {
+	tui.WidgetBase
+
+	content   tui.Widget
+	placement Placement
+	at        image.Rectangle
+}
+
+var _ tui.Widget = &pane{}
+
+func (p *pane) Draw(painter *tui.Painter) {
+	painter.Translate(p.at.Min.X, p.at.Min.Y)
+	defer painter.Restore()
+	p.content.Draw(painter)
+}
+
+func (p *pane) Resize(size image.Point) {
+	p.WidgetBase.Resize(size)
+	p.at = p.placement.rect(size, p.content.SizeHint())
+	p.content.Resize(p.at.Size())
+}
+
+func (p *pane) SizeHint() image.Point {
+	return p.content.SizeHint()
+}
+
+func (p *pane) SizePolicy() (tui.SizePolicy, tui.SizePolicy) {
+	return tui.Expanding, tui.Expanding
+}
+
+func (p *pane) OnKeyEvent(ev tui.KeyEvent) {
+	p.content.OnKeyEvent(ev)
+}
+
+// overlay is content placed over the screen, with the bottom bar below it.
+func (d *UI) overlay(content tui.Widget, placement Placement) tui.Widget {
+	return tui.NewVBox(&pane{content: content, placement: placement}, d.status)
+}
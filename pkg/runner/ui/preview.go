@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/recur"
+)
+
+const (
+	// previewLines is how many lines of a message the preview shows.
+	previewLines = 10
+	// maxCheckKey is how many items of a checklist have a key.
+	maxCheckKey = 9
+	// previewWidth is what the preview wraps to before the terminal size is
+	// known.
+	previewWidth = 76
+	// wordsPerMinute is the reading speed for the read time estimate.
+	wordsPerMinute = 200
+)
+
+// preview is a panel under the collection with the start of the selected
+// entry's message, it follows the selection until it is closed.
+type preview struct {
+	active bool
+	text   *tui.Label
+	box    *tui.Box
+	// view is the collection with the preview, a prompt can replace it.
+	view tui.Widget
+}
+
+// togglePreview shows or hides the preview of the selected entry.
+func (d *UI) togglePreview(ui tui.UI) {
+	if d.preview.active && d.onScreen == d.preview.view {
+		d.endPreview(ui)
+		return
+	}
+	if e, _ := d.selectedEntry(); e == nil {
+		return
+	}
+
+	text := tui.NewLabel("")
+	box := tui.NewVBox(text)
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Maximum)
+	d.preview = preview{active: true, text: text, box: box, view: tui.NewVBox(d.current, box)}
+	d.updatePreview()
+	d.show(ui, d.preview.view)
+}
+
+func (d *UI) endPreview(ui tui.UI) {
+	if !d.preview.active {
+		return
+	}
+	d.preview = preview{}
+	d.show(ui, d.current)
+}
+
+// updatePreview shows the selected entry in the preview, if it is open.
+func (d *UI) updatePreview() {
+	if !d.preview.active {
+		return
+	}
+	e, _ := d.selectedEntry()
+	if e == nil {
+		d.preview.text.SetText("")
+		d.preview.box.SetTitle("preview (space to close)")
+		return
+	}
+
+	width := previewWidth
+	if d.compact.width > 0 {
+		width = d.compact.width - 4
+	}
+	items := e.Checklist()
+	lines := wrap(e.Message, width)
+	if len(items) > 0 {
+		lines = append(wrap(e.Headline(), width), checklistLines(items, width)...)
+	}
+	if len(lines) > previewLines {
+		lines = append(lines[:previewLines-1], "…")
+	}
+	lines = append(lines, attachmentLines(e.Attachments, width)...)
+	lines = append(lines, reactionLines(e, width)...)
+	words := len(strings.Fields(e.Message))
+	d.preview.text.SetText(strings.Join(lines, "\n"))
+	title := fmt.Sprintf("%s, %s", plural(words, "word"), readTime(words))
+	if len(items) > 0 {
+		title = fmt.Sprintf("%s checked (1-9 to check), %s", e.Progress(), title)
+	}
+	if len(e.Attachments) > 0 {
+		title = fmt.Sprintf("%s (ctrl+g to open), %s", plural(len(e.Attachments), "attachment"), title)
+	}
+	if e.Bullet == glyph.Irrelevant && e.Reason != "" {
+		title = fmt.Sprintf("struck: %s, %s", e.Reason, title)
+	}
+	if r := recur.Of(d.allEntries(), e); r != nil {
+		title = fmt.Sprintf("%s %s ('w' to change), %s", glyph.Recurring, r.Recurrence.Rule, title)
+	}
+	d.preview.box.SetTitle(title + " (space to close)")
+}
+
+// checklistLines are the items of a checklist, numbered for the key that
+// checks them.
+func checklistLines(items []entry.CheckItem, width int) []string {
+	lines := make([]string, 0, len(items))
+	for i, item := range items {
+		n := " "
+		if i < maxCheckKey {
+			n = strconv.Itoa(i + 1)
+		}
+		mark := "[ ]"
+		if item.Done {
+			mark = "[x]"
+		}
+		for j, l := range wrap(item.Text, width-6) {
+			if j == 0 {
+				lines = append(lines, fmt.Sprintf("%s %s %s", n, mark, l))
+			} else {
+				lines = append(lines, "      "+l)
+			}
+		}
+	}
+	return lines
+}
+
+// reactionLines are who reacted to an entry with each reaction, cut to
+// width.
+func reactionLines(e *entry.Entry, width int) []string {
+	by := e.ReactedBy()
+	lines := make([]string, 0, len(by))
+	for _, r := range glyph.Reactions() {
+		if len(by[r]) == 0 {
+			continue
+		}
+		line := []rune(fmt.Sprintf("%s %s", r, strings.Join(by[r], ", ")))
+		if r == glyph.ReactionQuestion && !e.OpenQuestion() {
+			line = append(line, []rune(" (answered)")...)
+		}
+		if width > 1 && len(line) > width {
+			line = append(line[:width-1], '…')
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// attachmentLines are the attachments of an entry, numbered for the
+// prompt that opens them, cut to width.
+func attachmentLines(attachments []entry.Attachment, width int) []string {
+	lines := make([]string, 0, len(attachments))
+	for i, a := range attachments {
+		line := []rune(fmt.Sprintf("%s %d %s", glyph.Attachment, i+1, a))
+		if width > 1 && len(line) > width {
+			line = append(line[:width-1], '…')
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// toggleCheck checks or unchecks item n of the checklist of the selected
+// entry, from 0, while the preview is open.
+func (d *UI) toggleCheck(n int) {
+	e, i := d.selectedEntry()
+	if e == nil || len(e.Checklist()) <= n {
+		return
+	}
+	e.ToggleCheck(n)
+	if err := d.Persistence.Store(e); err != nil {
+		d.status.SetText(failed("check", err))
+		return
+	}
+	d.refreshRows(i)
+	d.updatePreview()
+	d.status.SetText(fmt.Sprintf("%s checked", e.Progress()))
+}
+
+// wrap breaks text into lines of at most width, on spaces where it can.
+func wrap(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	lines := make([]string, 0)
+	for _, para := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for len([]rune(word)) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				r := []rune(word)
+				lines = append(lines, string(r[:width]))
+				word = string(r[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) > width:
+				lines = append(lines, line)
+				line = word
+			default:
+				line += " " + word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// readTime estimates how long words take to read.
+func readTime(words int) string {
+	minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+	if minutes <= 1 {
+		return "under a minute to read"
+	}
+	return fmt.Sprintf("%d minutes to read", minutes)
+}
+
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
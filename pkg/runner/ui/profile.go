@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/logging"
+	"tableflip.dev/bujo/pkg/profile"
+)
+
+// traceFor is how long 'P' traces the ui for.
+const traceFor = 10 * time.Second
+
+// captureTrace writes a trace of the ui to TracePath in the background,
+// reporting progress in the status bar. Quitting ends the trace early.
+func (d *UI) captureTrace(ctx context.Context, ui tui.UI) {
+	if d.TracePath == "" {
+		d.status.SetText("tracing is not configured")
+		return
+	}
+	if d.tracing {
+		return
+	}
+	d.tracing = true
+	d.status.SetText(fmt.Sprintf("tracing for %s...", traceFor))
+
+	d.spawn(func() {
+		err := profile.Capture(ctx, d.TracePath, traceFor)
+		ui.Update(func() {
+			d.tracing = false
+			if err != nil {
+				d.status.SetText(failed("trace", err))
+				return
+			}
+			d.status.SetText("trace written to " + d.TracePath)
+		})
+	})
+}
+
+// cycleLogLevel moves the log to the next level, from debug to off and back
+// to debug, until the ui quits.
+func (d *UI) cycleLogLevel() {
+	l := logging.Default()
+	next := l.Level() + 1
+	if next > logging.LevelOff {
+		next = logging.LevelDebug
+	}
+	l.SetLevel(next)
+	logging.Info("log level changed", "level", next)
+	d.status.SetText(fmt.Sprintf("log level %s, logging to %s", next, l.Path()))
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// startReact opens a prompt for the reaction to put on the selected entry,
+// by its number or an alias. Reacting again takes it back.
+func (d *UI) startReact(ctx context.Context, ui tui.UI) {
+	e, i := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	if d.Author == "" {
+		d.status.SetText("can not react, set author in config")
+		return
+	}
+
+	// The target is only to open the prompt.
+	d.startAdd(ctx, ui, collection.DayOf(time.Now()), nil)
+	if !d.capture.active {
+		return
+	}
+	reactions := glyph.Reactions()
+	d.capture.submit = func(ctx context.Context, text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+		var r glyph.Reaction
+		if n, err := strconv.Atoi(text); err == nil {
+			if n < 1 || n > len(reactions) {
+				return app.Invalid("reaction", text, fmt.Sprintf("expected 1 to %d", len(reactions)))
+			}
+			r = reactions[n-1]
+		} else if r, err = glyph.ReactionForAlias(text); err != nil {
+			return err
+		}
+		added := e.React(r, d.Author, time.Now())
+		if err := d.Persistence.Store(e); err != nil {
+			return err
+		}
+		d.refreshRows(i)
+		d.updatePreview()
+		if added {
+			d.status.SetText(fmt.Sprintf("reacted %s", r))
+		} else {
+			d.status.SetText(fmt.Sprintf("took back %s", r))
+		}
+		return nil
+	}
+	choices := make([]string, 0, len(reactions))
+	for n, r := range reactions {
+		choices = append(choices, fmt.Sprintf("%d %s %s", n+1, r, r.Glyph().Noun))
+	}
+	d.capture.box.SetTitle(fmt.Sprintf("react as %s: %s, again to take it back", d.Author, strings.Join(choices, ", ")))
+}
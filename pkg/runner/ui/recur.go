@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/recur"
+)
+
+// dayCheck is how often the ui checks if the day rolled over.
+const dayCheck = time.Minute
+
+// watchDay adds the recurring entries due on the new day when the day rolls
+// over, until ctx is done.
+func (d *UI) watchDay(ctx context.Context, ui tui.UI) {
+	day := collection.DayOf(time.Now())
+	ticker := time.NewTicker(dayCheck)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if today := collection.DayOf(time.Now()); today != day {
+			day = today
+			ui.Update(func() {
+				d.addRecurring(ctx)
+			})
+		}
+	}
+}
+
+// addRecurring adds the recurring entries due today to today's log.
+func (d *UI) addRecurring(ctx context.Context) {
+	added, err := recur.Add(ctx, d.Persistence, time.Now())
+	if err != nil {
+		d.status.SetText(failed("add recurring entries", err))
+		return
+	}
+	if len(added) == 0 {
+		return
+	}
+	d.cache = d.Persistence.MapAll(ctx)
+	d.refs = nil
+	d.populateIndex()
+	// Force the collection view to be refreshed.
+	d.dirty = ""
+	d.populateCollection()
+	d.status.SetText(fmt.Sprintf("recurring: added %d to %s", len(added), added[0].Collection))
+}
+
+// startRule opens a prompt to change how often the selected entry, or the
+// recurring entry that added it, recurs.
+func (d *UI) startRule(ctx context.Context, ui tui.UI) {
+	e, i := d.selectedEntry()
+	if e == nil {
+		return
+	}
+	r := recur.Of(d.allEntries(), e)
+	if r == nil {
+		d.status.SetText("the selected entry does not recur, see bujo recur --help")
+		return
+	}
+
+	d.startAdd(ctx, ui, d.selected, nil)
+	if !d.capture.active {
+		return
+	}
+	d.capture.submit = func(ctx context.Context, text string) error {
+		rule := strings.TrimSpace(text)
+		if rule == "" || rule == r.Recurrence.Rule {
+			return nil
+		}
+		if err := recur.Valid(rule); err != nil {
+			return err
+		}
+		r.Recurrence.Rule = rule
+		if err := d.Persistence.Store(r); err != nil {
+			return err
+		}
+		d.refreshRows(i)
+		d.status.SetText(fmt.Sprintf("%s every %s", r.Message, rule))
+		return nil
+	}
+	d.capture.input.SetText(r.Recurrence.Rule)
+	d.capture.box.SetTitle(fmt.Sprintf("%s %s recurs (daily, weekdays, weekly, monthly or cron)", glyph.Recurring, r.Message))
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/rollup"
+)
+
+// maxRendered is how many collections have their rows kept.
+const maxRendered = 32
+
+// rendered are the rows built for a collection, kept so moving through the
+// index does not build them again.
+type rendered struct {
+	// key is what the rows were built from, see renderKey.
+	key     uint64
+	widgets []tui.Widget
+	// rows are the entries of widgets, nil for rows that are not an entry.
+	rows []*entry.Entry
+	// divider is the row of the done divider, -1 if there is none.
+	divider int
+}
+
+// renderKey hashes everything the rows of the named collection are built
+// from: the entries, which are hidden at now and how many are shown. The key
+// changes when the data does, so there is nothing to invalidate. Computed
+// entries are hashed as evaluated against all, their value depends on other
+// collections. Whether the done entries are shown is hashed too. Each entry is hashed by where it is too, as rows keep it to
+// act on and a reload has new entries even if they are the same.
+func (d *UI) renderKey(name string, col, all []*entry.Entry, now time.Time) uint64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s\x00%t\x00%d\x00%t\x00", name, d.showHidden, d.shown(name), d.showDone[name])
+	for _, e := range col {
+		_, _ = fmt.Fprintf(h, "%p\x00%s\x00%s\x00%s\x00%s\x00%s\x00%t\x00%t\x00%t\x00%s\x00", e, e.ID, e.Bullet, e.Signifier, e.Label, e.Message, e.Expired(now), e.Private, e.Pinned, d.doneTime(e))
+		if e.Recurrence != nil {
+			_, _ = fmt.Fprintf(h, "%s\x00", e.Recurrence.Rule)
+		}
+		if all != nil && rollup.Is(e.Message) {
+			_, _ = fmt.Fprintf(h, "%s\x00", rollup.Render(e.Message, all))
+		}
+	}
+	return h.Sum64()
+}
+
+// render returns the rows for the named collection, building them only if
+// the collection changed since they were last built.
+func (d *UI) render(name string) rendered {
+	col := d.entriesOf(name)
+	now := time.Now()
+	var all []*entry.Entry
+	if rollup.Any(col) {
+		all = d.allEntries()
+	}
+	key := d.renderKey(name, col, all, now)
+	if r, ok := d.rendered[name]; ok && r.key == key {
+		d.keepRendered(name, r)
+		return r
+	}
+
+	r := rendered{key: key, divider: -1}
+	add := func(w tui.Widget, e *entry.Entry) {
+		r.widgets = append(r.widgets, w)
+		r.rows = append(r.rows, e)
+	}
+
+	unprinted := 0
+	printed := make([]*entry.Entry, 0, len(col))
+	for _, e := range col {
+		if !d.showHidden && e.Expired(now) {
+			continue
+		}
+		if e.Bullet.Glyph().Printed {
+			printed = append(printed, e)
+		} else {
+			unprinted++
+		}
+	}
+	// Collections show their pinned entries first and their done entries
+	// last, behind the done divider. Views are in the order they say.
+	var pinned, done []*entry.Entry
+	if !d.isView(name) {
+		pinned, printed, done = arrange(printed)
+	}
+	// Only the most recent entries are shown, older ones are behind the more
+	// row.
+	if hidden := len(printed) - d.shown(name); hidden > 0 {
+		add(moreRow(hidden), nil)
+		printed = printed[hidden:]
+	}
+	for _, e := range pinned {
+		add(entryRow(e, all, d.doneTime(e)), e)
+	}
+	grouped := d.isView(name) && d.views[name].Grouped()
+	for i, e := range printed {
+		// Grouped views head the entries of each collection with its name.
+		if grouped && (i == 0 || printed[i-1].Collection != e.Collection) {
+			add(tui.NewLabel(d.iconed(e.Collection)), nil)
+		}
+		add(entryRow(e, all, d.doneTime(e)), e)
+	}
+	if len(done) > 0 {
+		r.divider = len(r.rows)
+		add(dividerRow(len(done), d.showDone[name]), nil)
+		if d.showDone[name] {
+			for _, e := range done {
+				add(entryRow(e, all, d.doneTime(e)), e)
+			}
+		}
+	}
+	if unprinted > 0 {
+		// This is a lie in the future, but true for now. A custom list object would help here.
+		add(tui.NewLabel("  contains tracks"), nil)
+	}
+
+	d.keepRendered(name, r)
+	return r
+}
+
+// arrange splits the entries of a collection into the pinned ones, the open
+// ones and the done ones, completed or struck, keeping their order. Done
+// entries are done even if they are pinned.
+func arrange(entries []*entry.Entry) (pinned, open, done []*entry.Entry) {
+	open = make([]*entry.Entry, 0, len(entries))
+	for _, e := range entries {
+		switch {
+		case e.Bullet == glyph.Completed || e.Bullet == glyph.Irrelevant:
+			done = append(done, e)
+		case e.Pinned:
+			pinned = append(pinned, e)
+		default:
+			open = append(open, e)
+		}
+	}
+	return pinned, open, done
+}
+
+// dividerRow is the row heading the done entries of a collection, which
+// are only shown once it is opened.
+func dividerRow(done int, shown bool) *tui.Label {
+	if shown {
+		return tui.NewLabel(fmt.Sprintf("  ▾ Done (%d) (enter to hide)", done))
+	}
+	return tui.NewLabel(fmt.Sprintf("  ▸ Done (%d) (enter to show)", done))
+}
+
+// keepRendered keeps the rows of the named collection, dropping the rows
+// of the collection shown least recently once more than maxRendered are
+// kept.
+func (d *UI) keepRendered(name string, r rendered) {
+	d.rendered[name] = r
+	for i, n := range d.recent {
+		if n == name {
+			d.recent = append(d.recent[:i], d.recent[i+1:]...)
+			break
+		}
+	}
+	d.recent = append(d.recent, name)
+	if len(d.recent) > maxRendered {
+		delete(d.rendered, d.recent[0])
+		d.recent = d.recent[1:]
+	}
+}
+
+// doneTime describes when e was completed, if it is shown.
+func (d *UI) doneTime(e *entry.Entry) string {
+	if !d.ShowDoneTime {
+		return ""
+	}
+	return printers.DoneTime(e)
+}
+
+// allEntries returns the entries of every cached collection, for computed
+// entries to be evaluated against.
+func (d *UI) allEntries() []*entry.Entry {
+	all := make([]*entry.Entry, 0)
+	for _, col := range d.cache {
+		all = append(all, col...)
+	}
+	return all
+}
@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// maxFound is how many entries the search overlay lists.
+const maxFound = 200
+
+// searchPlacement is the search overlay, in the middle of the top of the
+// screen.
+var searchPlacement = Placement{Width: 80, MinWidth: 60, MaxWidth: 120, Height: 80, MinHeight: 10, Anchor: AnchorTop}
+
+// search is an overlay to find entries in every collection, the results are
+// filtered as the query is typed.
+type search struct {
+	active  bool
+	input   *tui.Entry
+	results *tui.Table
+	box     *tui.Box
+	found   []*entry.Entry
+
+	// state to restore once the overlay is closed.
+	prev         tui.Widget
+	indexFocused bool
+}
+
+// startSearch opens the search overlay. Up and down pick a result, enter
+// closes the overlay and jumps to it.
+func (d *UI) startSearch(ui tui.UI) {
+	if d.search.active {
+		return
+	}
+
+	input := tui.NewEntry()
+	input.SetSizePolicy(tui.Expanding, tui.Maximum)
+	results := tui.NewTable(1, 0)
+
+	query := tui.NewHBox(input)
+	query.SetBorder(true)
+	query.SetSizePolicy(tui.Expanding, tui.Maximum)
+	query.SetTitle("search (up and down to pick, enter to jump, esc to close)")
+	box := tui.NewVBox(results, tui.NewSpacer())
+	box.SetBorder(true)
+
+	d.search = search{
+		active:       true,
+		input:        input,
+		results:      results,
+		box:          box,
+		prev:         d.current,
+		indexFocused: d.indexes.IsFocused(),
+	}
+	input.OnChanged(func(e *tui.Entry) {
+		d.filterSearch(e.Text())
+	})
+	input.OnSubmit(func(*tui.Entry) {
+		d.jumpSearch(ui)
+	})
+	d.filterSearch("")
+
+	// Nothing behind the overlay takes keys while it is open.
+	d.indexes.SetFocused(false)
+	d.collection.SetFocused(false)
+	d.setWidget(ui, d.overlay(tui.NewVBox(query, box), searchPlacement))
+
+	// The key that opened the overlay is still on its way to the focused
+	// widget, focus the input once it has passed so it is not typed.
+	go ui.Update(func() {
+		if d.search.input == input {
+			input.SetFocused(true)
+		}
+	})
+}
+
+// bindSearchKeys sets the keys that move through the results. The other
+// keys are ignored while the overlay is open, so they can be typed.
+func (d *UI) bindSearchKeys(ui tui.UI) {
+	d.bindSearch(ui, "Up", func() {
+		if i := d.search.results.Selected(); i > 0 {
+			d.search.results.Select(i - 1)
+		}
+	})
+	d.bindSearch(ui, "Down", func() {
+		if i := d.search.results.Selected(); i < len(d.search.found)-1 {
+			d.search.results.Select(i + 1)
+		}
+	})
+	d.bindSearch(ui, "Esc", func() {
+		d.endSearch(ui)
+	})
+}
+
+// bindSearch sets a keybinding that only fires while the search overlay is
+// open.
+func (d *UI) bindSearch(ui tui.UI, seq string, fn func()) {
+	ui.SetKeybinding(seq, func() {
+		if d.idle.locked || !d.search.active || d.capture.active {
+			return
+		}
+		fn()
+	})
+}
+
+// filterSearch lists the entries that match query, by collection.
+func (d *UI) filterSearch(query string) {
+	d.search.found = matching(d.cache, query)
+	d.search.results.RemoveRows()
+	for i, e := range d.search.found {
+		if i == maxFound {
+			break
+		}
+		d.search.results.AppendRow(tui.NewLabel(fmt.Sprintf("%-20s %s", d.iconed(e.Collection), e.String())))
+	}
+	if len(d.search.found) > maxFound {
+		d.search.found = d.search.found[:maxFound]
+	}
+	if len(d.search.found) > 0 {
+		d.search.results.Select(0)
+	}
+
+	switch {
+	case strings.TrimSpace(query) == "":
+		d.search.box.SetTitle("type to search every collection")
+	case len(d.search.found) == 0:
+		d.search.box.SetTitle("no matches")
+	default:
+		d.search.box.SetTitle(fmt.Sprintf("%d found", len(d.search.found)))
+	}
+}
+
+// jumpSearch closes the overlay and selects the picked entry.
+func (d *UI) jumpSearch(ui tui.UI) {
+	i := d.search.results.Selected()
+	if i < 0 || i >= len(d.search.found) {
+		return
+	}
+	e := d.search.found[i]
+	d.endSearch(ui)
+	d.pushJump()
+	d.openCollection(e.Collection)
+	d.focusCollection()
+	d.selectEntry(e)
+}
+
+// endSearch closes the search overlay.
+func (d *UI) endSearch(ui tui.UI) {
+	if !d.search.active {
+		return
+	}
+	d.search.input.SetFocused(false)
+	d.setWidget(ui, d.search.prev)
+	if d.search.indexFocused {
+		d.focusIndex()
+	} else {
+		d.focusCollection()
+	}
+	d.search = search{}
+}
+
+// matching returns the entries that have a word starting with each word of
+// query, so a word matches as it is typed. The collections are in order,
+// and entries in their order within them. An empty query matches nothing.
+func matching(cache map[string][]*entry.Entry, query string) []*entry.Entry {
+	found := make([]*entry.Entry, 0)
+	terms := store.Terms(query)
+	if len(terms) == 0 {
+		return found
+	}
+
+	collections := make([]string, 0, len(cache))
+	for c := range cache {
+		collections = append(collections, c)
+	}
+	sort.Strings(collections)
+	for _, c := range collections {
+		for _, e := range cache[c] {
+			if hasPrefixes(store.Terms(e.Message), terms) {
+				found = append(found, e)
+			}
+		}
+	}
+	return found
+}
+
+// hasPrefixes returns true if every prefix starts one of the words.
+func hasPrefixes(words, prefixes []string) bool {
+	for _, p := range prefixes {
+		ok := false
+		for _, w := range words {
+			if strings.HasPrefix(w, p) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// shutdownWait is how long the ui waits for background work to stop before
+// it quits anyway.
+const shutdownWait = 5 * time.Second
+
+// shutdown is how the ui stops: background work is told to stop, and the
+// ui quits once it has, so nothing is left running or half written.
+type shutdown struct {
+	// cancel stops the background work.
+	cancel context.CancelFunc
+	work   sync.WaitGroup
+	// stopping is set once the ui is on its way out.
+	stopping bool
+	// asked is set once a quit was held back for unsaved state, quitting
+	// again right away quits anyway.
+	asked bool
+}
+
+// spawn runs fn in the background, the ui waits for it to return before it
+// quits. fn is expected to return once the ctx of the ui is done.
+func (d *UI) spawn(fn func()) {
+	d.shutdown.work.Add(1)
+	go func() {
+		defer d.shutdown.work.Done()
+		fn()
+	}()
+}
+
+// unsaved describes what quitting now would lose, empty if nothing.
+func (d *UI) unsaved() string {
+	switch {
+	case d.capture.active && strings.TrimSpace(d.capture.input.Text()) != "":
+		return "the prompt has text that is not added yet"
+	case d.sharing:
+		return "a share is still uploading"
+	case d.sync.running:
+		return "a sync is still running"
+	case d.tracing:
+		return "a trace is still being written"
+	}
+	return ""
+}
+
+// quit quits the ui, first asking if something would be lost and
+// ConfirmQuit is set.
+func (d *UI) quit(ui tui.UI) {
+	if d.ConfirmQuit && !d.shutdown.asked {
+		if reason := d.unsaved(); reason != "" {
+			d.shutdown.asked = true
+			d.status.SetText(reason + ", quit again to quit anyway")
+			return
+		}
+	}
+	d.stop(ui)
+}
+
+// stop tells the background work to stop, and quits the ui once it has.
+// The ui keeps running meanwhile, as the work may need it to finish.
+func (d *UI) stop(ui tui.UI) {
+	if d.shutdown.stopping {
+		return
+	}
+	d.shutdown.stopping = true
+	d.shutdown.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.shutdown.work.Wait()
+		close(done)
+	}()
+	go func() {
+		select {
+		case <-done:
+		case <-time.After(shutdownWait):
+			logging.Warn("quitting before background work stopped", "waited", shutdownWait)
+		}
+		ui.Quit()
+	}()
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// syncState is the last sync of the journal, for the sync segment of the
+// bar.
+type syncState struct {
+	// running is set while a sync is.
+	running bool
+	// last is when the last sync finished, zero before the first.
+	last time.Time
+	// failed is set if the last sync failed.
+	failed bool
+}
+
+// syncText is the sync segment, empty if sync is not configured.
+func (d *UI) syncText() string {
+	switch {
+	case d.Sync == nil:
+		return ""
+	case d.sync.running:
+		return "syncing"
+	case d.sync.failed:
+		return "sync failed"
+	case d.sync.last.IsZero():
+		return "not synced"
+	}
+	return "synced " + d.sync.last.Format("15:04")
+}
+
+// syncJournal syncs the journal in the background, the watcher shows what
+// was pulled.
+func (d *UI) syncJournal(ctx context.Context, ui tui.UI, quiet bool) {
+	if d.Sync == nil {
+		d.status.SetText("sync is not configured, set sync.remote in config")
+		return
+	}
+	if d.sync.running || d.shutdown.stopping {
+		return
+	}
+	d.sync.running = true
+	if !quiet {
+		d.status.SetText("syncing with " + d.Sync.Remote + "...")
+	}
+	d.spawn(func() {
+		r, err := d.Sync.Sync(ctx)
+		ui.Update(func() {
+			d.sync.running = false
+			d.sync.failed = err != nil
+			if err != nil {
+				d.status.SetText(failed("sync", err))
+				return
+			}
+			d.sync.last = time.Now()
+			if r.Conflicts > 0 {
+				d.noteConflicts(ctx)
+			} else if !quiet {
+				d.status.SetText(fmt.Sprintf("synced: %s", r))
+			}
+		})
+	})
+}
+
+// syncEvery syncs the journal every SyncEvery until ctx is done.
+func (d *UI) syncEvery(ctx context.Context, ui tui.UI) {
+	ticker := time.NewTicker(d.SyncEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ui.Update(func() {
+			d.syncJournal(ctx, ui, true)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// tagsPlacement is the tag browser, in the middle of the top of the screen.
+var tagsPlacement = Placement{Width: 80, MinWidth: 40, MaxWidth: 120, Height: 80, MinHeight: 10, Anchor: AnchorTop}
+
+// tags is an overlay listing the #tags in use, enter lists the entries with
+// the picked tag.
+type tags struct {
+	active bool
+	// tag is the tag whose entries are listed, empty while the tags are.
+	tag string
+	// prev is shown again once the overlay is closed.
+	prev tui.Widget
+	// indexFocused is restored once the overlay is closed.
+	indexFocused bool
+}
+
+// tagCounts returns the tags in the cache, most used first, and how many
+// entries have each.
+func tagCounts(cache map[string][]*entry.Entry) ([]string, map[string]int) {
+	counts := make(map[string]int)
+	for _, c := range cache {
+		for _, e := range c {
+			for _, t := range e.Tags() {
+				counts[t]++
+			}
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for t := range counts {
+		names = append(names, t)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names, counts
+}
+
+// tagged returns the entries with tag, by collection.
+func tagged(cache map[string][]*entry.Entry, tag string) []*entry.Entry {
+	collections := make([]string, 0, len(cache))
+	for c := range cache {
+		collections = append(collections, c)
+	}
+	sort.Strings(collections)
+	found := make([]*entry.Entry, 0)
+	for _, c := range collections {
+		for _, e := range cache[c] {
+			if e.HasTag(tag) {
+				found = append(found, e)
+			}
+		}
+	}
+	return found
+}
+
+// toggleTags shows or hides the tag browser.
+func (d *UI) toggleTags(ui tui.UI) {
+	if d.tags.active {
+		d.endTags(ui)
+		return
+	}
+	names, _ := tagCounts(d.cache)
+	if len(names) == 0 {
+		d.status.SetText("no #tags in the journal")
+		return
+	}
+	d.tags = tags{active: true, prev: d.current, indexFocused: d.indexes.IsFocused()}
+	// Nothing behind the overlay takes keys while it is open.
+	d.indexes.SetFocused(false)
+	d.collection.SetFocused(false)
+	d.showTags(ui, "")
+}
+
+// showTags lists the tags, selecting from if it is still in use.
+func (d *UI) showTags(ui tui.UI, from string) {
+	names, counts := tagCounts(d.cache)
+	d.tags.tag = ""
+
+	list := tui.NewTable(1, 0)
+	at := 0
+	for i, t := range names {
+		list.AppendRow(tui.NewLabel(fmt.Sprintf("%4d  #%s", counts[t], t)))
+		if t == from {
+			at = i
+		}
+	}
+	list.OnItemActivated(func(t *tui.Table) {
+		if i := t.Selected(); i >= 0 && i < len(names) {
+			d.showTagged(ui, names[i])
+		}
+	})
+	list.Select(at)
+	list.SetFocused(true)
+
+	box := tui.NewVBox(list, tui.NewSpacer())
+	box.SetBorder(true)
+	box.SetTitle(fmt.Sprintf("%d tags (enter to list, '#' to close)", len(names)))
+	d.setWidget(ui, d.overlay(box, tagsPlacement))
+}
+
+// showTagged lists the entries with tag, enter closes the overlay and jumps
+// to the picked one.
+func (d *UI) showTagged(ui tui.UI, tag string) {
+	found := tagged(d.cache, tag)
+	d.tags.tag = tag
+
+	list := tui.NewTable(1, 0)
+	for _, e := range found {
+		list.AppendRow(tui.NewLabel(fmt.Sprintf("%-20s %s", d.iconed(e.Collection), e.String())))
+	}
+	list.OnItemActivated(func(t *tui.Table) {
+		i := t.Selected()
+		if i < 0 || i >= len(found) {
+			return
+		}
+		e := found[i]
+		d.endTags(ui)
+		d.pushJump()
+		d.openCollection(e.Collection)
+		d.focusCollection()
+		d.selectEntry(e)
+	})
+	list.Select(0)
+	list.SetFocused(true)
+
+	box := tui.NewVBox(list, tui.NewSpacer())
+	box.SetBorder(true)
+	box.SetTitle(fmt.Sprintf("#%s, %d entries (enter to jump, ESC for the tags)", tag, len(found)))
+	d.setWidget(ui, d.overlay(box, tagsPlacement))
+}
+
+// endTags closes the tag browser.
+func (d *UI) endTags(ui tui.UI) {
+	d.setWidget(ui, d.tags.prev)
+	if d.tags.indexFocused {
+		d.focusIndex()
+	} else {
+		d.focusCollection()
+	}
+	d.tags = tags{}
+}
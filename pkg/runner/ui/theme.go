@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// Theme modes. ThemeDark, ThemeLight and ThemeSolarized are also the names
+// of the built in palettes.
+const (
+	ThemeDark      = "dark"
+	ThemeLight     = "light"
+	ThemeSolarized = "solarized"
+	ThemeAuto      = "auto"
+)
+
+// colorIsRGB marks a tui.Color as 24 bit, tui-go passes colors past its own
+// to tcell as they are and this is tcell's ColorIsRGB.
+const colorIsRGB tui.Color = 1 << 24
+
+// themeCheck is how often the schedule is checked while the ui is open.
+const themeCheck = time.Minute
+
+// backgroundWait is how long the terminal is given to say what its
+// background is.
+const backgroundWait = 200 * time.Millisecond
+
+// Palette is what each of the eight colors the ui is drawn with, like
+// "yellow", is shown as. A color is one of the eight names, a number of
+// the 256 color palette from 9 or #rrggbb. Colors left out are shown as
+// they are.
+type Palette map[string]string
+
+// palettes are the built in themes. The colors are picked for a dark
+// background, the others change the ones that do not read on theirs.
+var palettes = map[string]Palette{
+	ThemeDark: {},
+	ThemeLight: {
+		"white":  "black",
+		"yellow": "136",
+		"cyan":   "30",
+		"green":  "28",
+	},
+	ThemeSolarized: {
+		"black":   "235",
+		"white":   "244",
+		"red":     "160",
+		"green":   "64",
+		"yellow":  "136",
+		"blue":    "33",
+		"magenta": "125",
+		"cyan":    "37",
+	},
+}
+
+// Theme is which colors the ui uses.
+type Theme struct {
+	// Mode is the name of a palette, ThemeDark, ThemeLight, ThemeSolarized
+	// or one of Palettes, or ThemeAuto. Empty is ThemeDark, the colors the
+	// ui always had.
+	Mode string
+	// Schedule is when ThemeAuto is light, like 07:00-19:00, it is dark the
+	// rest of the day. Without one, the terminal is asked for its
+	// background as the ui starts.
+	Schedule string
+	// Palettes are themes from the config, by name.
+	Palettes map[string]Palette
+}
+
+// Valid returns an error if the mode, a palette or the schedule is not
+// known.
+func (t Theme) Valid() error {
+	for name, p := range t.Palettes {
+		if name == ThemeAuto {
+			return app.Invalid("theme", name, "auto can not be the name of a theme")
+		}
+		if _, err := p.colors(); err != nil {
+			return err
+		}
+	}
+	if t.Mode != "" && t.Mode != ThemeAuto && t.palette(t.Mode) == nil {
+		return app.Invalid("theme", t.Mode, fmt.Sprintf("expected auto or %s", strings.Join(t.names(), ", ")))
+	}
+	if t.Schedule != "" {
+		if _, _, err := t.hours(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// names are the themes there are, the built in ones first.
+func (t Theme) names() []string {
+	names := []string{ThemeDark, ThemeLight, ThemeSolarized}
+	custom := make([]string, 0, len(t.Palettes))
+	for name := range t.Palettes {
+		if _, ok := palettes[name]; !ok {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// palette returns the named palette, the config overrides the built in
+// ones. It is nil if there is none.
+func (t Theme) palette(name string) Palette {
+	if p, ok := t.Palettes[name]; ok {
+		return p
+	}
+	return palettes[name]
+}
+
+// hours are the minutes into the day that the schedule is light from and
+// until.
+func (t Theme) hours() (int, int, error) {
+	parts := strings.Split(t.Schedule, "-")
+	if len(parts) != 2 {
+		return 0, 0, app.Invalid("theme schedule", t.Schedule, "expected when it is light, like 07:00-19:00")
+	}
+	at := make([]int, 2)
+	for i, p := range parts {
+		c, err := time.Parse("15:04", strings.TrimSpace(p))
+		if err != nil {
+			return 0, 0, app.Invalid("theme schedule", t.Schedule, "expected when it is light, like 07:00-19:00")
+		}
+		at[i] = c.Hour()*60 + c.Minute()
+	}
+	return at[0], at[1], nil
+}
+
+// scheduled returns whether the schedule is light at now, ok is false if
+// there is no schedule to follow.
+func (t Theme) scheduled(now time.Time) (light, ok bool) {
+	if t.Mode != ThemeAuto || t.Schedule == "" {
+		return false, false
+	}
+	from, until, err := t.hours()
+	if err != nil {
+		return false, false
+	}
+	m := now.Hour()*60 + now.Minute()
+	if from <= until {
+		return m >= from && m < until, true
+	}
+	// Light over midnight, like 20:00-06:00 for night shifts.
+	return m >= from || m < until, true
+}
+
+// current returns the name of the palette used at now. An auto theme
+// without a schedule asks the terminal.
+func (t Theme) current(now time.Time) string {
+	switch t.Mode {
+	case "":
+		return ThemeDark
+	case ThemeAuto:
+		if light, ok := t.scheduled(now); ok {
+			return themeName(light)
+		}
+		light, _ := lightBackground()
+		return themeName(light)
+	}
+	return t.Mode
+}
+
+// colors returns what each tui-go color is shown as.
+func (p Palette) colors() (map[tui.Color]tui.Color, error) {
+	colors := make(map[tui.Color]tui.Color, len(p))
+	for name, value := range p {
+		from, ok := focusColors[name]
+		if !ok || name == "default" {
+			return nil, app.Invalid("theme color", name, "expected black, white, red, green, blue, cyan, magenta or yellow")
+		}
+		to, err := parseColor(value)
+		if err != nil {
+			return nil, err
+		}
+		colors[from] = to
+	}
+	return colors, nil
+}
+
+// parseColor reads a color name, a number of the 256 color palette or
+// #rrggbb. The numbers below 9 are tui-go's own colors, so those are given
+// by name.
+func parseColor(value string) (tui.Color, error) {
+	if c, ok := focusColors[value]; ok {
+		return c, nil
+	}
+	if strings.HasPrefix(value, "#") && len(value) == 7 {
+		if v, err := strconv.ParseUint(value[1:], 16, 32); err == nil {
+			return colorIsRGB | tui.Color(v), nil
+		}
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 9 && n <= 255 {
+		return tui.Color(n), nil
+	}
+	return 0, app.Invalid("theme color", value, "expected a color name, a number from 9 to 255 or #rrggbb")
+}
+
+// shown returns what c is shown as in colors.
+func shown(c tui.Color, colors map[tui.Color]tui.Color) tui.Color {
+	if to, ok := colors[c]; ok {
+		return to
+	}
+	return c
+}
+
+// setTheme switches to the named palette.
+func (d *UI) setTheme(ui tui.UI, name string) {
+	// Valid has checked the palettes parse.
+	colors, _ := d.Theme.palette(name).colors()
+	d.theme = name
+	ui.SetTheme(theme(d.Focus, colors))
+}
+
+// nextTheme switches to the theme after the one in use and saves it as
+// ui.theme in the config, which also stops an auto theme following its
+// schedule.
+func (d *UI) nextTheme(ui tui.UI) {
+	names := d.Theme.names()
+	next := names[0]
+	for i, name := range names {
+		if name == d.theme && i+1 < len(names) {
+			next = names[i+1]
+		}
+	}
+	d.Theme.Mode = next
+	d.setTheme(ui, next)
+	if _, err := store.SetConfig("ui.theme", next); err != nil {
+		d.status.SetText(failed(fmt.Sprintf("save the %s theme", next), err))
+		return
+	}
+	d.status.SetText(fmt.Sprintf("%s theme", next))
+}
+
+// followSchedule switches between the light and dark palettes as the
+// schedule of t says, until ctx is done or another theme is picked.
+func (d *UI) followSchedule(ctx context.Context, ui tui.UI, t Theme) {
+	last, _ := t.scheduled(time.Now())
+	ticker := time.NewTicker(themeCheck)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			light, _ := t.scheduled(now)
+			if light == last {
+				continue
+			}
+			last = light
+			ui.Update(func() {
+				if d.Theme.Mode != ThemeAuto {
+					return
+				}
+				d.setTheme(ui, themeName(light))
+				d.status.SetText(fmt.Sprintf("%s theme, as scheduled", themeName(light)))
+			})
+		}
+	}
+}
+
+func themeName(light bool) string {
+	if light {
+		return ThemeLight
+	}
+	return ThemeDark
+}
+
+// lightBackground returns whether the background of the terminal is light,
+// ok is false if it can not tell. $COLORFGBG is used if the terminal sets
+// it, otherwise the terminal is asked with OSC 11. Terminals that do not
+// answer are given backgroundWait.
+func lightBackground() (light, ok bool) {
+	if v := os.Getenv("COLORFGBG"); v != "" {
+		parts := strings.Split(v, ";")
+		if bg, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
+			return bg == 7 || bg > 8, true
+		}
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, false
+	}
+	defer tty.Close()
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = tty
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return false, false
+	}
+	// Reads give up after a tenth of a second without input, so a terminal
+	// that does not answer does not keep a key press from the ui.
+	if _, err := stty("raw", "-echo", "min", "0", "time", "1"); err != nil {
+		return false, false
+	}
+	defer func() { _, _ = stty(saved) }()
+
+	if _, err := tty.WriteString("\x1b]11;?\x07"); err != nil {
+		return false, false
+	}
+	answer := make([]byte, 0, 32)
+	buf := make([]byte, 32)
+	deadline := time.Now().Add(backgroundWait)
+	for time.Now().Before(deadline) {
+		n, _ := tty.Read(buf)
+		answer = append(answer, buf[:n]...)
+		if strings.HasSuffix(string(answer), "\x07") || strings.HasSuffix(string(answer), "\x1b\\") {
+			break
+		}
+	}
+	return parseBackground(string(answer))
+}
+
+// parseBackground reads an OSC 11 answer, like
+// ESC ]11;rgb:ffff/ffff/dddd BEL, and returns whether the color is light.
+func parseBackground(answer string) (light, ok bool) {
+	i := strings.Index(answer, "rgb:")
+	if i < 0 {
+		return false, false
+	}
+	rgb := strings.TrimRight(answer[i+len("rgb:"):], "\x07\x1b\\")
+	parts := strings.Split(rgb, "/")
+	if len(parts) != 3 {
+		return false, false
+	}
+	weights := []float64{0.299, 0.587, 0.114}
+	luma := 0.0
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil {
+			return false, false
+		}
+		max := float64(uint64(1)<<(4*uint(len(p))) - 1)
+		luma += weights[i] * float64(v) / max
+	}
+	return luma > 0.5, true
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Events the ui emits as the user acts, used to drive the tutorial.
+const (
+	eventCaptured  = "captured"
+	eventOpened    = "opened"
+	eventCompleted = "completed"
+	eventLabelled  = "labelled"
+	eventMonth     = "month"
+)
+
+type tutorialStep struct {
+	text  string
+	event string
+}
+
+var tutorialSteps = []tutorialStep{
+	{text: "Press ctrl+n, type a task and press enter to capture it to today.", event: eventCaptured},
+	{text: "Press left, pick today with up or down and press enter to open it.", event: eventOpened},
+	{text: "Pick the task with up or down and press 'x' to complete it.", event: eventCompleted},
+	{text: "Press 'L' to give an entry a color label, again to change it.", event: eventLabelled},
+	{text: "Press left, then '[' or ']' to jump between month logs.", event: eventMonth},
+}
+
+// tutorial walks through the basics of the ui, advancing as the user does
+// each step.
+type tutorial struct {
+	active bool
+	step   int
+	view   *tui.Box
+	text   *tui.Label
+}
+
+// tutorialAt is where the tutorial is shown in the root box, under the
+// index and collection.
+const tutorialAt = 1
+
+func (d *UI) toggleTutorial() {
+	if d.tutorial.active {
+		d.endTutorial()
+		return
+	}
+
+	text := tui.NewLabel("")
+	text.SetWordWrap(true)
+	view := tui.NewVBox(text)
+	view.SetBorder(true)
+
+	d.tutorial = tutorial{active: true, view: view, text: text}
+	d.root.Insert(tutorialAt, view)
+	d.showTutorialStep()
+}
+
+func (d *UI) endTutorial() {
+	if !d.tutorial.active {
+		return
+	}
+	d.root.Remove(tutorialAt)
+	d.tutorial = tutorial{}
+}
+
+func (d *UI) showTutorialStep() {
+	d.tutorial.view.SetTitle(fmt.Sprintf("tutorial %d/%d ('t' to close)", d.tutorial.step+1, len(tutorialSteps)))
+	d.tutorial.text.SetText(tutorialSteps[d.tutorial.step].text)
+}
+
+// emit reports that the user did something.
+func (d *UI) emit(event string) {
+	if !d.tutorial.active || tutorialSteps[d.tutorial.step].event != event {
+		return
+	}
+	d.tutorial.step++
+	if d.tutorial.step >= len(tutorialSteps) {
+		d.endTutorial()
+		d.status.SetText("tutorial done!")
+		return
+	}
+	d.showTutorialStep()
+}
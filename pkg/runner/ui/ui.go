@@ -4,31 +4,214 @@ import (
 	"context"
 	"fmt"
 	"github.com/marcusolsson/tui-go"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"tableflip.dev/bujo/pkg/collection"
 	"tableflip.dev/bujo/pkg/entry"
 	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/health"
+	"tableflip.dev/bujo/pkg/notify"
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/runner/invite"
+	"tableflip.dev/bujo/pkg/runner/share"
 	"tableflip.dev/bujo/pkg/store"
+	jsync "tableflip.dev/bujo/pkg/sync"
+	"tableflip.dev/bujo/pkg/view"
+	"time"
 )
 
 type UI struct {
 	Persistence store.Persistence
+	Share       share.Target
+	// EagerSelect loads a collection as soon as it is highlighted in the
+	// index, instead of when it is selected with enter.
+	EagerSelect bool
+	// Open is what the ui opens to: one of OpenToday, OpenMonth, OpenFuture,
+	// OpenLast or a collection name. Empty opens to the top of the index.
+	Open string
+	// SessionPath is where the selected collection is kept for OpenLast.
+	SessionPath string
+	// Compact is one of CompactAuto, CompactOn or CompactOff. Empty is
+	// CompactAuto.
+	Compact string
+	// IdleLock locks the ui after this long without a key press, if set.
+	IdleLock time.Duration
+	// TracePath is where 'P' writes a trace of the ui. Empty disables it.
+	TracePath string
+	// Columns is ColumnsAuto or how many columns the collection view has.
+	// Empty is ColumnsAuto.
+	Columns string
+	// Notify is told when the ui stops seeing changes to the journal, if
+	// set.
+	Notify notify.Sink
+	// Focus is how what has focus and the cursor are shown.
+	Focus Focus
+	// Theme is which colors the ui uses.
+	Theme Theme
+	// Bar is which segments the bottom bar shows, and where.
+	Bar Bar
+	// Layouts are the saved layouts, by name.
+	Layouts map[string]Layout
+	// Layout is the name of the layout to start with, it takes the place
+	// of Compact, Columns and the segments of Bar.
+	Layout string
+	// ShowDoneTime shows when completed tasks were completed, 'd' toggles
+	// it.
+	ShowDoneTime bool
+	// AskReason asks why an entry is struck with '-'.
+	AskReason bool
+	// Author is who reactions given with '+' are by.
+	Author string
+	// Meeting is who is invited to events, and for how long, with ctrl+e.
+	Meeting invite.Meeting
+	// ConfirmQuit asks before quitting while the prompt has text or a share
+	// or trace is not done.
+	ConfirmQuit bool
+	// Views are listed before the collections in the index, and shown as if
+	// they were one.
+	Views []view.View
+	// Sync syncs the journal with 'y', if set.
+	Sync *jsync.Git
+	// SyncEvery syncs the journal in the background this often, if set
+	// with Sync.
+	SyncEvery time.Duration
+	// Problems were found with the setup before the ui started, they are
+	// shown in a banner with what the ui finds itself.
+	Problems []health.Problem
+
+	status   *bar
+	root     *tui.Box
+	current  tui.Widget
+	capture  capture
+	tutorial tutorial
+	// theme is the name of the palette in use.
+	theme    string
+	banner   banner
+	compact  compact
+	columns  columns
+	idle     idle
+	review   review
+	info     info
+	activity activity
+	search   search
+	migrate  migrate
+	// dayReview walks today's open tasks, see dayreview.go.
+	dayReview dayReview
+	bullets   bulletMenu
+	tags      tags
+	preview   preview
+	spread    spread
+	marks     marks
+	tracing   bool
+	sharing   bool
+	sync      syncState
+	shutdown  shutdown
+	// onScreen is current, or current with a prompt.
+	onScreen tui.Widget
+	// showHidden shows expired notes.
+	showHidden bool
+	// editing is the entry to edit in the editor once the ui quits.
+	editing *entry.Entry
+	// notice is shown in the status bar when the ui starts again.
+	notice string
 
 	cache map[string][]*entry.Entry
+	// refs are the refs of the cached entries, by id. They are made when
+	// first asked for, see entryRefs, and dropped when entries are added to
+	// or removed from the cache.
+	refs map[string]string
+	// views are the Views by name.
+	views map[string]*view.View
+	// archived are the archived collections, while shown.
+	archived archived
+	// icons are shown before collection names, by collection.
+	icons map[string]string
+	// defaults are the bullets captures get without one picked, by
+	// collection.
+	defaults map[string]glyph.Bullet
+	// limits is how many entries are shown for collections that were
+	// expanded past firstPage.
+	limits map[string]int
+	// showDone are the collections whose done entries are shown below the
+	// done divider.
+	showDone map[string]bool
+	// rendered are the rows last built for the maxRendered collections
+	// shown last, named in recent from least recently shown.
+	rendered map[string]rendered
+	recent   []string
 
 	dirty string
 	index []string
+	// selected is the collection shown in the collection view.
+	selected string
 
 	indexes    *tui.Table
 	indexTitle string
 	indexView  *tui.Box
 
+	// collection is the focused column of the collection view.
 	collection      *tui.Table
 	collectionView  *tui.Box
 	collectionTitle string
+	// rows are the entries shown in the collection table, by row. Rows that
+	// are not an entry are nil.
+	rows []*entry.Entry
 }
 
 func (d *UI) Do(ctx context.Context) error {
+	if err := d.applyLayout(); err != nil {
+		return err
+	}
+	if err := ValidColumns(d.Columns); err != nil {
+		return err
+	}
+	d.views = make(map[string]*view.View, len(d.Views))
+	for i := range d.Views {
+		if err := d.Views[i].Validate(); err != nil {
+			return err
+		}
+		d.views[d.Views[i].Name] = &d.Views[i]
+	}
+	if err := d.Focus.Valid(); err != nil {
+		return err
+	}
+	if err := d.Theme.Valid(); err != nil {
+		return err
+	}
+	if err := d.Bar.Valid(); err != nil {
+		return err
+	}
+	for {
+		if err := d.run(ctx); err != nil {
+			return err
+		}
+		// The ui quits to hand the terminal to the editor, and starts again
+		// on the collection of the edited entry.
+		if d.editing == nil {
+			return nil
+		}
+		d.notice = d.editEntry(ctx, d.editing)
+		d.Open = d.editing.Collection
+		d.Compact = d.compact.mode
+		d.Columns = d.columns.mode
+		d.info = info{}
+		d.activity = activity{}
+		d.search = search{}
+		d.dirty = ""
+	}
+}
+
+// keyPlacement is the key overlay, 'k', in the top left.
+var keyPlacement = Placement{Fit: true}
+
+func (d *UI) run(ctx context.Context) error {
+	// Background work runs until the ui stops it, see stop.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	d.shutdown = shutdown{cancel: cancel}
+
 	iTable := tui.NewTable(1, 0)
 
 	index := tui.NewVBox(
@@ -39,15 +222,13 @@ func (d *UI) Do(ctx context.Context) error {
 	index.SetSizePolicy(tui.Preferred, tui.Expanding)
 	index.SetBorder(true)
 
-	cTable := tui.NewTable(1, 0)
-	cTable.SetFocused(true)
-
-	cTable.SetSizePolicy(tui.Expanding, tui.Maximum)
+	cColumns := tui.NewHBox()
+	cColumns.SetSizePolicy(tui.Expanding, tui.Maximum)
 
-	status := tui.NewStatusBar("")
-	status.SetPermanentText(`Use left️ or right arrows to navigate, 'k' for key, ESC or 'q' to QUIT`)
+	status := d.newBar()
+	status.SetPermanentText(helpText)
 
-	collection := tui.NewVBox(cTable)
+	collection := tui.NewVBox(cColumns)
 	collection.SetBorder(true)
 	collection.SetSizePolicy(tui.Expanding, tui.Maximum)
 
@@ -58,87 +239,525 @@ func (d *UI) Do(ctx context.Context) error {
 		tui.NewSpacer(),
 		status,
 	)
+	framed := &frame{Box: root, onResize: d.onResize}
 
 	key := keyUI()
 	key.SetBorder(true)
 	key.SetTitle("key")
 
-	popup := tui.NewVBox(
-		tui.NewHBox(key, tui.NewSpacer()),
-		tui.NewSpacer(),
-		status,
-	)
-
-	ui, err := tui.New(root)
+	// The journal is read, and the terminal asked for its background,
+	// before tui-go has the terminal.
+	d.cache = d.loadJournal(ctx)
+	d.refs = nil
+	current := d.Theme.current(time.Now())
+	ui, err := tui.New(framed)
 	if err != nil {
 		return err
 	}
+	d.setTheme(ui, current)
 
+	d.status = status
+	d.root = root
+	popup := d.overlay(key, keyPlacement)
+	d.current = framed
+	d.onScreen = framed
 	d.indexes = iTable
 	d.indexTitle = "index"
 	d.indexView = index
-	d.collection = cTable
 	d.collectionView = collection
-	d.cache = d.Persistence.MapAll(ctx)
+	d.compact = compact{mode: d.Compact, indexShown: true, selector: selector}
+	d.limits = make(map[string]int)
+	d.showDone = make(map[string]bool)
+	d.rendered = make(map[string]rendered)
+	if i, ok := d.Persistence.(store.Iconer); ok {
+		d.icons = i.Icons(ctx)
+	}
+	if b, ok := d.Persistence.(store.BulletDefaulter); ok {
+		d.defaults = b.DefaultBullets(ctx)
+	}
 
 	d.populateIndex()
+	d.banner.shown = false
+	d.showBanner()
+
+	d.columns = columns{mode: d.Columns, box: cColumns, newTable: func() *tui.Table {
+		return d.newColumnTable()
+	}}
+	d.collection = nil
+	d.makeColumns(1)
+	d.collection.SetFocused(true)
 
-	cTable.OnItemActivated(func(t *tui.Table) {
-		//if t.Selected() == 0 {
-		//	impl.Quit()
-		//	fmt.Printf("no selection; context unchanged\n")
-		//	return
-		//}
-		//_, err := cmd(fmt.Sprintf("kubectl config use-context %s", cfg.Contexts[t.Selected()-1].Name))
-		//if err != nil {
-		//	panic(err)
-		//}
-		//impl.Quit()
-		//fmt.Printf("selected %s\n", cfg.Contexts[t.Selected()-1].Name)
-		// TODO
+	iTable.OnSelectionChanged(func(table *tui.Table) {
+		if d.EagerSelect {
+			d.selectCollection()
+		}
 	})
 
-	iTable.OnSelectionChanged(func(table *tui.Table) {
-		d.populateCollection()
+	iTable.OnItemActivated(func(table *tui.Table) {
+		d.pushJump()
+		d.selectCollection()
+		d.focusCollection()
+		d.emit(eventOpened)
 	})
 
 	isKey := false
-	ui.SetKeybinding("k", func() {
+	d.bind(ui, "k", func() {
+		if d.capture.active {
+			return
+		}
 		if isKey {
-			ui.SetWidget(root)
+			d.setWidget(ui, framed)
 			isKey = false
 		} else {
-			ui.SetWidget(popup)
+			d.setWidget(ui, popup)
 			isKey = true
 		}
 	})
 
-	ui.SetKeybinding("Left", func() {
+	d.bind(ui, "s", func() {
+		if d.capture.active {
+			return
+		}
+		d.shareCollection(ctx, ui)
+	})
+
+	d.bind(ui, "[", func() {
+		if d.capture.active || !d.indexes.IsFocused() {
+			return
+		}
+		if d.weekHighlighted() {
+			d.jumpWeek(-1)
+			return
+		}
+		d.jumpMonth(-1)
+	})
+
+	d.bind(ui, "]", func() {
+		if d.capture.active || !d.indexes.IsFocused() {
+			return
+		}
+		if d.weekHighlighted() {
+			d.jumpWeek(1)
+			return
+		}
+		d.jumpMonth(1)
+	})
+
+	d.bind(ui, "Ctrl+W", func() {
+		if d.capture.active {
+			return
+		}
+		d.openWeek()
+	})
+
+	d.bind(ui, "L", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.cycleLabel(ctx)
+	})
+
+	d.bind(ui, "u", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.togglePinned()
+	})
+
+	d.bind(ui, "v", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.togglePrivate()
+	})
+
+	d.bind(ui, "x", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.completeSelected(ctx)
+	})
+
+	d.bind(ui, "f", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.completeWithFollowUp(ctx, ui)
+	})
+
+	d.bind(ui, "Tab", func() {
+		d.nextCaptureTarget()
+	})
+
+	d.bind(ui, "t", func() {
+		if d.capture.active {
+			return
+		}
+		d.toggleTutorial()
+	})
+
+	d.bind(ui, "m", func() {
+		if d.capture.active || !d.collection.IsFocused() {
+			return
+		}
+		d.showMore()
+	})
+
+	d.bind(ui, "c", func() {
+		if d.capture.active {
+			return
+		}
+		d.toggleCompact()
+	})
+
+	d.bind(ui, "P", func() {
+		if d.capture.active {
+			return
+		}
+		d.captureTrace(ctx, ui)
+	})
+
+	d.bind(ui, "y", func() {
+		if d.capture.active {
+			return
+		}
+		d.syncJournal(ctx, ui, false)
+	})
+
+	d.bind(ui, "z", func() {
+		if d.capture.active {
+			return
+		}
+		d.toggleArchive(ctx)
+	})
+
+	d.bind(ui, "Ctrl+A", func() {
+		if d.capture.active {
+			return
+		}
+		d.toggleArchived(ctx)
+	})
+
+	d.bind(ui, "Ctrl+T", func() {
+		d.nextTheme(ui)
+	})
+
+	d.bind(ui, "Ctrl+L", func() {
+		d.cycleLogLevel()
+	})
+
+	d.bind(ui, "h", func() {
+		if d.capture.active {
+			return
+		}
+		d.toggleHidden()
+	})
+
+	d.bind(ui, "d", func() {
+		if d.capture.active {
+			return
+		}
+		d.toggleDoneTime()
+	})
+
+	d.bind(ui, "/", func() {
+		if d.capture.active || d.activity.active || d.tags.active {
+			return
+		}
+		d.startSearch(ui)
+	})
+
+	d.bind(ui, "#", func() {
+		if d.capture.active || d.activity.active {
+			return
+		}
+		d.toggleTags(ui)
+	})
+
+	d.bind(ui, "-", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.strikeSelected(ctx, ui)
+	})
+
+	d.bind(ui, "*", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.startBullets(ui)
+	})
+
+	d.bind(ui, "w", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.startRule(ctx, ui)
+	})
+
+	d.bind(ui, "E", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		if e, _ := d.selectedEntry(); e != nil {
+			d.editing = e
+			d.stop(ui)
+		}
+	})
+
+	d.bind(ui, "i", func() {
+		if d.capture.active {
+			return
+		}
+		d.toggleInfo(ctx, ui)
+	})
+
+	d.bind(ui, "a", func() {
+		if d.capture.active || d.tags.active {
+			return
+		}
+		d.toggleActivity(ctx, ui)
+	})
+
+	d.bind(ui, " ", func() {
+		if d.capture.active || !d.collection.IsFocused() {
+			return
+		}
+		d.togglePreview(ui)
+	})
+
+	for n := 1; n <= maxCheckKey; n++ {
+		item := n - 1
+		d.bind(ui, strconv.Itoa(n), func() {
+			if d.capture.active || !d.preview.active || !d.collection.IsFocused() || d.readOnly() {
+				return
+			}
+			d.toggleCheck(item)
+		})
+	}
+
+	d.bind(ui, "g", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.startMigrate(ui)
+	})
+
+	d.bind(ui, "Ctrl+P", func() {
+		if d.capture.active {
+			return
+		}
+		d.startLayout(ctx, ui)
+	})
+
+	d.bind(ui, "Ctrl+D", func() {
+		if d.capture.active {
+			return
+		}
+		d.startDayReview(ctx, ui)
+	})
+
+	d.bind(ui, "r", func() {
+		if d.capture.active {
+			return
+		}
+		d.startReview(ctx, ui)
+	})
+
+	d.bind(ui, "o", func() {
+		if d.capture.active || !d.collection.IsFocused() {
+			return
+		}
+		e, _ := d.selectedEntry()
+		d.startAdd(ctx, ui, d.selected, e)
+	})
+
+	// Ctrl+N captures in any mode, over any overlay or prompt.
+	ui.SetKeybinding("Ctrl+N", func() {
+		if d.idle.locked || d.shutdown.stopping {
+			return
+		}
+		d.startCapture(ctx, ui)
+	})
+
+	d.bind(ui, "Ctrl+K", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.startAttach(ctx, ui)
+	})
+
+	d.bind(ui, "Ctrl+G", func() {
+		if d.capture.active || !d.collection.IsFocused() {
+			return
+		}
+		d.openAttachment(ctx, ui)
+	})
+
+	d.bind(ui, "+", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.startReact(ctx, ui)
+	})
+
+	d.bind(ui, "Ctrl+E", func() {
+		if d.capture.active || !d.collection.IsFocused() || d.readOnly() {
+			return
+		}
+		d.startInvite(ctx, ui)
+	})
+
+	d.bind(ui, "Ctrl+R", func() {
+		if d.capture.active {
+			return
+		}
+		d.startImport(ctx, ui)
+	})
+
+	d.bind(ui, "Left", func() {
+		if d.capture.active {
+			return
+		}
+		if d.collection.IsFocused() && d.moveColumn(-1) {
+			return
+		}
 		d.focusIndex()
 	})
 
-	ui.SetKeybinding("Right", func() {
+	d.bind(ui, "Right", func() {
+		if d.capture.active {
+			return
+		}
+		if d.collection.IsFocused() {
+			d.moveColumn(1)
+			return
+		}
 		d.focusCollection()
 	})
 
-	ui.SetKeybinding("Esc", func() { ui.Quit() })
-	ui.SetKeybinding("q", func() { ui.Quit() })
+	d.bind(ui, "Esc", func() {
+		if d.capture.active {
+			// Closed below, after the overlay keys.
+			return
+		}
+		if d.activity.active {
+			d.endActivity(ui)
+			return
+		}
+		if d.tags.active {
+			if d.tags.tag != "" {
+				d.showTags(ui, d.tags.tag)
+			} else {
+				d.endTags(ui)
+			}
+			return
+		}
+		if d.preview.active {
+			d.endPreview(ui)
+			return
+		}
+		if d.banner.shown {
+			d.dismissBanner()
+			return
+		}
+		d.quit(ui)
+	})
+	d.bind(ui, "q", func() {
+		if d.capture.active {
+			return
+		}
+		d.quit(ui)
+	})
+	d.bind(ui, "Ctrl+C", func() {
+		d.quit(ui)
+	})
+
+	// After the keys above, so ending a review with ESC does not also quit.
+	d.bindReviewKeys(ctx, ui)
+	d.bindMigrateKeys(ctx, ui)
+	d.bindDayReviewKeys(ctx, ui)
+	d.bindBulletKeys(ctx, ui)
+	d.bindMarkKeys(ui)
+	d.bindSearchKeys(ui)
+
+	// After the overlay keys, so the ESC that closes a capture opened over
+	// an overlay does not also close the overlay.
+	ui.SetKeybinding("Esc", func() {
+		if d.idle.locked || !d.capture.active {
+			return
+		}
+		d.endCapture(ui)
+	})
 
-	d.populateCollection()
+	if name := d.startCollection(); name != "" {
+		d.openCollection(name)
+	} else {
+		d.selectCollection()
+	}
 	d.focusCollection()
+	d.noteConflicts(ctx)
+	if d.editing != nil {
+		d.selectEntry(d.editing)
+		d.status.SetText(d.notice)
+		d.editing = nil
+	}
+
+	d.addRecurring(ctx)
+	d.spawn(func() { d.watchDay(ctx, ui) })
+
+	if d.IdleLock > 0 {
+		d.touch()
+		d.spawn(func() { d.watchIdle(ctx, ui) })
+	}
+
+	if d.Bar.shows(SegmentClock) {
+		d.spawn(func() { d.tickClock(ctx, ui) })
+	}
+
+	if w, ok := d.Persistence.(store.Watcher); ok {
+		d.spawn(func() { d.watch(ctx, w, ui) })
+	}
+
+	if d.Sync != nil && d.SyncEvery > 0 {
+		d.spawn(func() { d.syncEvery(ctx, ui) })
+	}
 
+	if _, ok := d.Theme.scheduled(time.Now()); ok {
+		t := d.Theme
+		d.spawn(func() { d.followSchedule(ctx, ui, t) })
+	}
+
+	d.Focus.setCursor(os.Stdout)
+	defer d.Focus.resetCursor(os.Stdout)
 	if err := ui.Run(); err != nil {
 		return err
 	}
+	d.saveSession()
 	return nil
 }
 
+func (d *UI) setWidget(ui tui.UI, w tui.Widget) {
+	d.current = w
+	// A preview is under what was shown before.
+	d.preview = preview{}
+	d.show(ui, w)
+}
+
+// show puts w on screen. Every key pressed while it is shown keeps the ui
+// from locking.
+func (d *UI) show(ui tui.UI, w tui.Widget) {
+	d.onScreen = w
+	ui.SetWidget(&active{Widget: w, touch: d.touch})
+}
+
 func (d *UI) focusIndex() {
 	d.indexes.SetFocused(true)
-	d.indexView.SetTitle(strings.ToUpper(d.indexTitle))
+	d.indexView.SetTitle(d.Focus.title(strings.ToUpper(d.indexTitle)))
 
 	d.collection.SetFocused(false)
 	d.collectionView.SetTitle("")
+	d.layoutCompact()
 }
 
 func (d *UI) focusCollection() {
@@ -146,55 +765,129 @@ func (d *UI) focusCollection() {
 	d.indexView.SetTitle(d.indexTitle)
 
 	d.collection.SetFocused(true)
-	d.collectionView.SetTitle(d.collectionTitle)
+	d.collectionView.SetTitle(d.Focus.title(d.iconed(d.collectionTitle)))
+	d.layoutCompact()
 }
 
 func (d *UI) populateIndex() {
+	selected := d.highlighted()
+
 	d.indexes.RemoveRows()
-	d.indexes.Select(0)
 
-	i := make(map[string]bool, 0)
-	for c, _ := range d.cache {
-		if _, ok := i[c]; !ok {
-			i[c] = true
+	names := make([]string, 0, len(d.cache))
+	for c := range d.cache {
+		names = append(names, c)
+	}
+	collection.Sort(names)
+
+	// Views come first, in the order of the config. A collection hides a
+	// view of the same name.
+	d.index = make([]string, 0, len(d.Views)+len(names))
+	for _, v := range d.Views {
+		if _, ok := d.cache[v.Name]; !ok {
+			d.index = append(d.index, v.Name)
 		}
 	}
+	d.index = append(d.index, names...)
 
-	d.index = make([]string, 0, len(i))
-	for k, _ := range i {
-		d.index = append(d.index, k)
-		d.indexes.AppendRow(tui.NewLabel(k))
+	// Archived collections come last, while shown.
+	archived := make([]string, 0, len(d.archived.entries))
+	for c := range d.archived.entries {
+		if _, ok := d.cache[c]; !ok {
+			archived = append(archived, c)
+		}
+	}
+	collection.Sort(archived)
+	d.index = append(d.index, archived...)
+
+	// Keep the selection on the same collection if it is still around.
+	at := 0
+	for i, k := range d.index {
+		d.indexes.AppendRow(tui.NewLabel(d.iconed(k)))
+		if k == selected {
+			at = i
+		}
 	}
+	d.indexes.Select(at)
 }
 
-func (d *UI) populateCollection() {
-	selected := ""
-	if d.indexes.Selected() >= 0 {
-		selected = d.index[d.indexes.Selected()]
+// iconed returns the collection name with its icon, if it has one.
+func (d *UI) iconed(name string) string {
+	if d.isView(name) {
+		return viewIcon + " " + name
+	}
+	if d.isArchived(name) {
+		return archivedIcon + " " + name
+	}
+	if icon, ok := d.icons[name]; ok {
+		return icon + " " + name
 	}
+	return name
+}
+
+// highlighted returns the collection highlighted in the index.
+func (d *UI) highlighted() string {
+	if i := d.indexes.Selected(); i >= 0 && i < len(d.index) {
+		return d.index[i]
+	}
+	return ""
+}
+
+// selectCollection shows the highlighted collection in the collection view.
+func (d *UI) selectCollection() {
+	d.selected = d.highlighted()
+	d.populateCollection()
+}
+
+func (d *UI) populateCollection() {
+	selected := d.selected
 
 	if d.dirty != selected {
-		d.collection.RemoveRows()
+		if d.collectionTitle != selected {
+			d.columns.at = 0
+		}
 		d.collectionTitle = selected
-		unprinted := 0
-		if col, ok := d.cache[selected]; ok {
-			for _, e := range col {
-				if e.Bullet.Glyph().Printed {
-					d.collection.AppendRow(tui.NewLabel(e.String()))
-				} else {
-					unprinted++
-				}
-			}
-			if unprinted > 0 {
-				// This is a lie in the future, but true for now. A custom list object would help here.
-				d.collection.AppendRow(tui.NewLabel("  contains tracks"))
-
-			}
+		r := rendered{divider: -1}
+		if _, ok := d.cache[selected]; ok || d.isView(selected) || d.isArchived(selected) {
+			r = d.render(selected)
 		}
+		d.layoutColumns(r)
+		d.layoutSpread(selected)
 		d.dirty = selected
 	}
 }
 
+// shareCollection uploads the selected collection to the share target and
+// reports the resulting url in the status bar.
+func (d *UI) shareCollection(ctx context.Context, ui tui.UI) {
+	if d.Share == nil {
+		d.status.SetText("share is not configured")
+		return
+	}
+	selected := d.selected
+	if selected == "" {
+		return
+	}
+	content := printers.Markdown(selected, entry.WithoutPrivate(d.entriesOf(selected))...)
+
+	d.status.SetText("sharing " + selected + "...")
+	d.sharing = true
+	d.spawn(func() {
+		url, err := d.Share.Upload(ctx, selected, content)
+		ui.Update(func() {
+			d.sharing = false
+			if err != nil {
+				d.status.SetText(failed("share", err))
+				return
+			}
+			if err := share.CopyToClipboard(url); err == nil {
+				url += " (copied)"
+			}
+			d.status.SetText(url)
+		})
+	})
+}
+
 func keyUI() *tui.Box {
 	bullets := glyph.DefaultBullets()
 	bl := make([]glyph.Glyph, 0, len(bullets))
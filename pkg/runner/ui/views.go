@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// viewIcon is shown before the names of views, in place of an icon.
+const viewIcon = "◇"
+
+// isView is true if name is a view and not a collection.
+func (d *UI) isView(name string) bool {
+	if _, ok := d.cache[name]; ok {
+		return false
+	}
+	_, ok := d.views[name]
+	return ok
+}
+
+// entriesOf returns the entries of the named collection, or of the view
+// with that name.
+func (d *UI) entriesOf(name string) []*entry.Entry {
+	if d.isView(name) {
+		return d.views[name].Entries(d.cache)
+	}
+	if d.isArchived(name) {
+		return d.archived.entries[name]
+	}
+	return d.cache[name]
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/logging"
+	"tableflip.dev/bujo/pkg/notify"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// watchRestartDelay is how long to wait before watching again, once a
+// watch ends.
+var watchRestartDelay = 5 * time.Second
+
+const (
+	// watchBatch is how long events are collected before the collections
+	// they name are refreshed together, so a burst costs one redraw.
+	watchBatch = 250 * time.Millisecond
+)
+
+// watch keeps the cache in sync with changes made to the store, restarting
+// the watch if it ends before ctx is done.
+func (d *UI) watch(ctx context.Context, w store.Watcher, ui tui.UI) {
+	failing := false
+	for {
+		events, err := w.Watch(ctx)
+		if err == nil {
+			failing = false
+			d.batch(ctx, events, ui)
+		} else if !failing {
+			// Only the first failure is reported, not every retry.
+			failing = true
+			d.watchFailed(ctx, ui, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRestartDelay):
+		}
+	}
+}
+
+// watchFailed reports that changes to the journal are no longer seen.
+func (d *UI) watchFailed(ctx context.Context, ui tui.UI, err error) {
+	logging.Warn("watching for changes failed", "err", err)
+	ui.Update(func() {
+		d.status.SetText(failed("watching for changes", err))
+	})
+	if d.Notify != nil {
+		_ = d.Notify.Notify(ctx, notify.Message{Title: "watching for changes failed", Body: err.Error()})
+	}
+}
+
+// batch drains events, coalescing repeated events for a collection and
+// refreshing every collection seen within watchBatch in a single update.
+// The pending changes of a collection are nil once it needs to be read
+// again.
+func (d *UI) batch(ctx context.Context, events <-chan store.Event, ui tui.UI) {
+	pending := make(map[string][]store.Change)
+	var flush <-chan time.Time
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				d.flush(ctx, pending, ui)
+				return
+			}
+			changes, seen := pending[e.Collection]
+			if len(e.Changes) == 0 || (seen && changes == nil) {
+				pending[e.Collection] = nil
+			} else {
+				pending[e.Collection] = append(changes, e.Changes...)
+			}
+			if flush == nil {
+				flush = time.After(watchBatch)
+			}
+		case <-flush:
+			d.flush(ctx, pending, ui)
+			pending = make(map[string][]store.Change)
+			flush = nil
+		}
+	}
+}
+
+// flush applies the pending changes in one ui update.
+func (d *UI) flush(ctx context.Context, pending map[string][]store.Change, ui tui.UI) {
+	if len(pending) == 0 {
+		return
+	}
+	logging.Debug("journal changed", "collections", len(pending))
+	ui.Update(func() {
+		for collection, changes := range pending {
+			if changes == nil {
+				d.refresh(ctx, collection)
+			} else {
+				d.patch(collection, changes)
+			}
+		}
+	})
+}
+
+// refresh reloads a collection from the store into the cache.
+func (d *UI) refresh(ctx context.Context, collection string) {
+	d.update(collection, d.Persistence.List(ctx, collection))
+}
+
+// patch applies the changes to single entries to the cached collection,
+// without reading the rest of it again.
+func (d *UI) patch(collection string, changes []store.Change) {
+	cached := d.cache[collection]
+	all := make([]*entry.Entry, 0, len(cached)+len(changes))
+	at := make(map[string]int, len(cached))
+	for _, e := range cached {
+		at[e.ID] = len(all)
+		all = append(all, e)
+	}
+	removed := make(map[string]bool)
+	for _, c := range changes {
+		i, ok := at[c.ID]
+		switch {
+		case c.Kind == store.ChangeRemoved:
+			removed[c.ID] = true
+		case ok:
+			all[i] = c.Entry
+			delete(removed, c.ID)
+		default:
+			at[c.ID] = len(all)
+			all = append(all, c.Entry)
+			delete(removed, c.ID)
+		}
+	}
+	if len(removed) > 0 {
+		kept := all[:0]
+		for _, e := range all {
+			if !removed[e.ID] {
+				kept = append(kept, e)
+			}
+		}
+		all = kept
+	}
+	entry.Sort(all)
+	d.update(collection, all)
+}
+
+// update puts the entries of a collection in the cache and redraws what
+// shows it.
+func (d *UI) update(collection string, all []*entry.Entry) {
+	_, known := d.cache[collection]
+	d.refs = nil
+
+	switch {
+	case len(all) == 0 && known:
+		delete(d.cache, collection)
+		delete(d.rendered, collection)
+		d.populateIndex()
+	case len(all) > 0:
+		d.cache[collection] = all
+		if !known {
+			d.populateIndex()
+		}
+	}
+
+	if d.collectionTitle == collection || d.isView(d.collectionTitle) {
+		// Force the collection view to be refreshed, keeping the selected
+		// entry selected.
+		selected, _ := d.selectedEntry()
+		d.dirty = ""
+		d.populateCollection()
+		if selected != nil {
+			d.selectEntry(selected)
+		}
+	}
+}
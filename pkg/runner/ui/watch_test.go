@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/store"
+)
+
+// updates is a tui.UI that runs updates as they are made, and counts them.
+type updates struct {
+	tui.UI
+	mu    sync.Mutex
+	count int
+}
+
+func (u *updates) Update(fn func()) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.count++
+	fn()
+}
+
+// journal is a store that lists entries by collection.
+type journal struct {
+	store.Persistence
+	entries map[string][]*entry.Entry
+}
+
+func (j *journal) List(ctx context.Context, collection string) []*entry.Entry {
+	return j.entries[collection]
+}
+
+func newWatchUI(p store.Persistence) *UI {
+	return &UI{
+		Persistence: p,
+		cache:       make(map[string][]*entry.Entry),
+		rendered:    make(map[string]rendered),
+		indexes:     tui.NewTable(0, 0),
+	}
+}
+
+func task(id, message string) *entry.Entry {
+	e := entry.New("Work", glyph.Task, message)
+	e.ID = id
+	return e
+}
+
+// eventually waits for ok, under the lock of u.
+func eventually(t *testing.T, u *updates, what string, ok func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		u.mu.Lock()
+		done := ok()
+		u.mu.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func messages(entries []*entry.Entry) []string {
+	m := make([]string, 0, len(entries))
+	for _, e := range entries {
+		m = append(m, e.Message)
+	}
+	return m
+}
+
+func TestWatchRestarts(t *testing.T) {
+	defer func(delay time.Duration) { watchRestartDelay = delay }(watchRestartDelay)
+	watchRestartDelay = 10 * time.Millisecond
+
+	w := store.NewFakeWatcher()
+	defer w.Close()
+	u := &updates{}
+	d := newWatchUI(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		d.watch(ctx, w, u)
+		close(done)
+	}()
+
+	eventually(t, u, "the watch", func() bool { return w.Watching() == 1 })
+	first := task("aaaa", "first")
+	w.Send(store.Event{Collection: "Work", Changes: []store.Change{{ID: first.ID, Kind: store.ChangeAdded, Entry: first}}})
+	eventually(t, u, "the first change", func() bool { return len(d.cache["Work"]) == 1 })
+
+	// The watch ends, as if the store stopped watching, and is started
+	// again.
+	w.Restart()
+	eventually(t, u, "the watch to restart", func() bool { return w.Watching() == 1 })
+	second := task("bbbb", "second")
+	w.Send(store.Event{Collection: "Work", Changes: []store.Change{{ID: second.ID, Kind: store.ChangeAdded, Entry: second}}})
+	eventually(t, u, "the change after the restart", func() bool { return len(d.cache["Work"]) == 2 })
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch did not stop with its context")
+	}
+}
+
+func TestWatchBatches(t *testing.T) {
+	old := task("aaaa", "old")
+	j := &journal{entries: map[string][]*entry.Entry{"Home": {task("cccc", "read again")}}}
+	u := &updates{}
+	d := newWatchUI(j)
+	d.cache["Work"] = []*entry.Entry{old}
+
+	renamed := task("aaaa", "renamed")
+	added := task("bbbb", "added")
+	events := make(chan store.Event, 5)
+	events <- store.Event{Collection: "Work", Changes: []store.Change{{ID: renamed.ID, Kind: store.ChangeModified, Entry: renamed}}}
+	events <- store.Event{Collection: "Work", Changes: []store.Change{{ID: added.ID, Kind: store.ChangeAdded, Entry: added}}}
+	events <- store.Event{Collection: "Home", Changes: []store.Change{{ID: "dddd", Kind: store.ChangeAdded, Entry: task("dddd", "patched")}}}
+	// Home can not be patched once it has to be read again.
+	events <- store.Event{Collection: "Home"}
+	events <- store.Event{Collection: "Work", Changes: []store.Change{{ID: old.ID, Kind: store.ChangeRemoved}}}
+	close(events)
+
+	d.batch(context.Background(), events, u)
+
+	if u.count != 1 {
+		t.Errorf("got %d updates, want 1", u.count)
+	}
+	if got := messages(d.cache["Work"]); len(got) != 1 || got[0] != "added" {
+		t.Errorf("Work is %v, want [added]", got)
+	}
+	if got := messages(d.cache["Home"]); len(got) != 1 || got[0] != "read again" {
+		t.Errorf("Home is %v, want [read again]", got)
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// spread is the seven days of a week log side by side, under the entries
+// of the week log itself.
+type spread struct {
+	// box is in the collection view while a week log is shown.
+	box *tui.Box
+}
+
+// layoutSpread shows the spread of the week under the collection if name
+// is a week log, and takes it away otherwise. Days without entries are
+// collapsed to their name, so the days with entries get the room.
+func (d *UI) layoutSpread(name string) {
+	if d.spread.box != nil {
+		// The spread is always after the columns.
+		d.collectionView.Remove(1)
+		d.spread.box = nil
+	}
+	kind, monday := collection.Parse(name)
+	if kind != collection.Week {
+		return
+	}
+
+	box := tui.NewHBox()
+	for i := 0; i < 7; i++ {
+		day := monday.AddDate(0, 0, i)
+		box.Append(d.spreadDay(day, d.cache[collection.DayOf(day)]))
+	}
+	d.collectionView.Append(box)
+	d.spread.box = box
+}
+
+// spreadDay is the column of a day in the spread.
+func (d *UI) spreadDay(day time.Time, entries []*entry.Entry) tui.Widget {
+	title := day.Format("Mon 2")
+	if len(entries) == 0 {
+		empty := tui.NewVBox(tui.NewLabel(day.Format(" Mon ")), tui.NewLabel(day.Format(" 2")))
+		empty.SetSizePolicy(tui.Minimum, tui.Maximum)
+		return empty
+	}
+	now := time.Now()
+	col := tui.NewVBox()
+	for _, e := range entries {
+		if e.Expired(now) || !e.Bullet.Glyph().Printed {
+			continue
+		}
+		shown := *e
+		shown.Message = e.Headline()
+		label := tui.NewLabel(shown.String())
+		label.SetWordWrap(true)
+		col.Append(label)
+	}
+	col.Append(tui.NewSpacer())
+	col.SetBorder(true)
+	col.SetTitle(title)
+	col.SetSizePolicy(tui.Expanding, tui.Maximum)
+	return col
+}
+
+// weekHighlighted is true if a week log is highlighted in the index.
+func (d *UI) weekHighlighted() bool {
+	kind, _ := collection.Parse(d.highlighted())
+	return kind == collection.Week
+}
+
+// jumpWeek moves the index selection by delta weeks from the week log
+// highlighted in it. The week log is added to the index even if it has no
+// entries yet.
+func (d *UI) jumpWeek(delta int) {
+	_, monday := collection.Parse(d.highlighted())
+	d.openCollection(collection.WeekOf(monday.AddDate(0, 0, 7*delta)))
+	d.focusIndex()
+}
+
+// openWeek shows this week's log.
+func (d *UI) openWeek() {
+	d.openCollection(collection.WeekOf(time.Now()))
+	d.focusCollection()
+}
@@ -0,0 +1,128 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+
+	"tableflip.dev/bujo/pkg/printers"
+	"tableflip.dev/bujo/pkg/ref"
+	"tableflip.dev/bujo/pkg/rollup"
+	"tableflip.dev/bujo/pkg/store"
+	"tableflip.dev/bujo/pkg/view"
+)
+
+// Show prints the entries of a view.
+type Show struct {
+	View        view.View
+	ShowID      bool
+	ShowHidden  bool // include expired notes.
+	Persistence store.Persistence
+}
+
+func (n *Show) Do(ctx context.Context) error {
+	if n.Persistence == nil {
+		return errors.New("can not show view, no persistence")
+	}
+	if err := n.View.Validate(); err != nil {
+		return err
+	}
+
+	pp := printers.PrettyPrint{ShowID: n.ShowID}
+	if i, ok := n.Persistence.(store.Iconer); ok {
+		pp.Icons = i.Icons(ctx)
+	}
+	if n.ShowID {
+		pp.Refs = ref.Refs(n.Persistence.ListAll(ctx))
+	}
+
+	found := n.View.Entries(n.Persistence.MapAll(ctx))
+	if !n.ShowHidden {
+		now := time.Now()
+		shown := found[:0]
+		for _, e := range found {
+			if !e.Expired(now) {
+				shown = append(shown, e)
+			}
+		}
+		found = shown
+	}
+	if rollup.Any(found) {
+		pp.All = n.Persistence.ListAll(ctx)
+	}
+
+	fmt.Println("")
+
+	if !n.View.Grouped() {
+		pp.Title(n.View.Name)
+		pp.Collection(found...)
+		return nil
+	}
+	for len(found) > 0 {
+		c := found[0].Collection
+		i := 0
+		for i < len(found) && found[i].Collection == c {
+			i++
+		}
+		pp.Title(c)
+		pp.Collection(found[:i]...)
+		found = found[i:]
+	}
+	return nil
+}
+
+// List prints the views in the config.
+type List struct {
+	Views []view.View
+}
+
+func (n *List) Do(ctx context.Context) error {
+	if len(n.Views) == 0 {
+		fmt.Println("no views, add them to views in the config")
+		return nil
+	}
+
+	bold := color.New(color.Bold)
+	tbl := uitable.New()
+	tbl.Separator = "  "
+	tbl.AddRow(bold.Sprint("Name"), bold.Sprint("Collections"), bold.Sprint("Filter"), bold.Sprint("Sort"))
+	for _, v := range n.Views {
+		collections := strings.Join(v.Collections, ", ")
+		if collections == "" {
+			collections = "*"
+		}
+		sort := v.Sort
+		if sort == "" {
+			sort = view.SortOrder
+		}
+		if v.Grouped() {
+			sort += ", by collection"
+		}
+		tbl.AddRow(v.Name, collections, filter(v), sort)
+	}
+	_, _ = fmt.Fprintln(color.Output, tbl)
+	return nil
+}
+
+// filter describes which entries a view includes.
+func filter(v view.View) string {
+	parts := make([]string, 0, 3)
+	if v.Bullet != "" {
+		parts = append(parts, v.Bullet)
+	}
+	if v.Label != "" {
+		parts = append(parts, v.Label)
+	}
+	if v.Tag != "" {
+		parts = append(parts, "#"+strings.TrimPrefix(v.Tag, "#"))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
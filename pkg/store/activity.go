@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// Kinds of activity.
+const (
+	ActivityAdded     = "added"
+	ActivityCompleted = "completed"
+	ActivityMoved     = "moved"
+	ActivityStruck    = "struck"
+	ActivityEdited    = "edited"
+	ActivityResolved  = "resolved"
+)
+
+// Activity is something that happened to an entry.
+type Activity struct {
+	At    time.Time
+	Kind  string
+	Entry *entry.Entry
+}
+
+// Historian is implemented by persistence that can tell what happened in the
+// journal.
+type Historian interface {
+	// Activity returns what happened from since until until, newest first.
+	Activity(ctx context.Context, since, until time.Time) []Activity
+}
+
+// edited is how long after an entry was created a write counts as a change,
+// and not the write that added it.
+const edited = 2 * time.Second
+
+// Activity is made from what the journal keeps: each entry is added when it
+// was created, and completed, moved or struck when it says it was. It is
+// edited when its file was last written after that, so only the last edit is
+// known. Compact and encrypt keep the time a file was written, a sync does
+// not. Entries from before the times were kept are changed as their bullet
+// says when their file was last written. Resolved conflicts come from the
+// resolution history.
+func (p *persistence) Activity(ctx context.Context, since, until time.Time) []Activity {
+	in := func(t time.Time) bool {
+		return !t.Before(since) && t.Before(until)
+	}
+
+	all := make([]Activity, 0)
+	for key := range p.d.Keys(ctx.Done()) {
+		e, err := p.read(key)
+		if err != nil {
+			continue
+		}
+		if in(e.Created.Time) {
+			all = append(all, Activity{At: e.Created.Time, Kind: ActivityAdded, Entry: e})
+		}
+		last, kind := e.Created.Time, changed(e)
+		if at := changedAt(e); at != nil {
+			if in(at.Time) {
+				all = append(all, Activity{At: at.Time, Kind: kind, Entry: e})
+			}
+			last, kind = at.Time, ActivityEdited
+		}
+		fi, err := p.stat(key)
+		if err != nil || fi.ModTime().Sub(last) < edited || !in(fi.ModTime()) {
+			continue
+		}
+		all = append(all, Activity{At: fi.ModTime(), Kind: kind, Entry: e})
+	}
+
+	for _, r := range p.Resolutions() {
+		if in(r.Resolved) && r.Kept != nil {
+			r.Kept.ID = r.ID
+			all = append(all, Activity{At: r.Resolved, Kind: ActivityResolved, Entry: r.Kept})
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].At.After(all[j].At)
+	})
+	return all
+}
+
+// changedAt is when e was changed to its bullet, if it says.
+func changedAt(e *entry.Entry) *entry.Timestamp {
+	switch e.Bullet {
+	case glyph.Completed:
+		return e.CompletedAt
+	case glyph.MovedCollection, glyph.MovedFuture:
+		return e.MovedAt
+	case glyph.Irrelevant:
+		return e.StruckAt
+	default:
+		return nil
+	}
+}
+
+// changed is the kind of the last change to e, by its bullet.
+func changed(e *entry.Entry) string {
+	switch e.Bullet {
+	case glyph.Completed:
+		return ActivityCompleted
+	case glyph.MovedCollection, glyph.MovedFuture:
+		return ActivityMoved
+	case glyph.Irrelevant:
+		return ActivityStruck
+	default:
+		return ActivityEdited
+	}
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// activities returns the kinds of activity of the journal, oldest first.
+func activities(p *persistence) []string {
+	all := p.Activity(context.Background(), time.Time{}, time.Now().Add(time.Hour))
+	kinds := make([]string, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		kinds = append(kinds, all[i].Kind)
+	}
+	return kinds
+}
+
+func TestActivityRecorded(t *testing.T) {
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+	ctx := context.Background()
+
+	hour := func(n int) time.Time { return time.Now().Add(time.Duration(n-10) * time.Hour) }
+	e := entry.New("Today", glyph.Task, "task")
+	e.Created = entry.Timestamp{Time: hour(1)}
+	e.Complete()
+	e.CompletedAt.Time = hour(2)
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	// As if it was written when it was completed.
+	if err := os.Chtimes(p.filename(keyToPathTransform(toKey(e))), hour(2), hour(2)); err != nil {
+		t.Fatal(err)
+	}
+	assertKinds(t, activities(p), ActivityAdded, ActivityCompleted)
+
+	// Compacting rewrites the file, it is not an edit.
+	if _, err := p.Compact(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertKinds(t, activities(p), ActivityAdded, ActivityCompleted)
+
+	// A later write is.
+	e.Label = glyph.LabelRed
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	assertKinds(t, activities(p), ActivityAdded, ActivityCompleted, ActivityEdited)
+}
+
+func TestActivityLegacy(t *testing.T) {
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+
+	e := entry.New("Today", glyph.Completed, "completed before it was kept")
+	e.Created = entry.Timestamp{Time: time.Now().Add(-time.Hour)}
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	assertKinds(t, activities(p), ActivityAdded, ActivityCompleted)
+}
+
+func assertKinds(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/peterbourgon/diskv/v3"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// Archiver is implemented by persistence that can move collections out of
+// the journal into an archive, and back.
+type Archiver interface {
+	// Archive moves every entry of the collection into the archive and
+	// returns how many were moved.
+	Archive(ctx context.Context, collection string) (int, error)
+	// Unarchive moves every entry of the collection back into the journal.
+	Unarchive(ctx context.Context, collection string) (int, error)
+	// Archived lists the archived collections.
+	Archived(ctx context.Context) []string
+	// ListArchived returns the entries in the archive, for search and
+	// export. They are not part of ListAll.
+	ListArchived(ctx context.Context) []*entry.Entry
+	// ArchiveEntries moves the entries into the archive and returns how many
+	// were moved.
+	ArchiveEntries(ctx context.Context, entries ...*entry.Entry) (int, error)
+}
+
+// archiveSuffix is added to the base path for the archive. The archive can
+// not live inside of the base path, every file in there is an entry.
+const archiveSuffix = ".archive"
+
+func (p *persistence) archive() *diskv.Diskv {
+	if p.a == nil {
+		p.a = diskv.New(diskv.Options{
+			BasePath:          p.d.BasePath + archiveSuffix,
+			AdvancedTransform: keyToPathTransform,
+			InverseTransform:  pathToKeyTransform,
+		})
+	}
+	return p.a
+}
+
+func (p *persistence) Archive(ctx context.Context, collection string) (int, error) {
+	return moveCollection(ctx, p.d, p.archive(), collection)
+}
+
+func (p *persistence) Unarchive(ctx context.Context, collection string) (int, error) {
+	return moveCollection(ctx, p.archive(), p.d, collection)
+}
+
+func (p *persistence) ArchiveEntries(ctx context.Context, entries ...*entry.Entry) (int, error) {
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, toKey(e))
+	}
+	return moveKeys(p.d, p.archive(), keys)
+}
+
+func (p *persistence) Archived(ctx context.Context) []string {
+	all := make(map[string]bool)
+	for key := range p.archive().Keys(ctx.Done()) {
+		all[fromCollection(keyToPathTransform(key).Path[0])] = true
+	}
+	collections := make([]string, 0, len(all))
+	for c := range all {
+		collections = append(collections, c)
+	}
+	sort.Strings(collections)
+	return collections
+}
+
+func (p *persistence) ListArchived(ctx context.Context) []*entry.Entry {
+	a := p.archive()
+	all := make([]*entry.Entry, 0)
+	for key := range a.Keys(ctx.Done()) {
+		val, err := a.Read(key)
+		if err != nil {
+			logging.Warn("skipping an archived entry", "key", key, "err", err)
+			continue
+		}
+		e, err := p.decode(key, val)
+		if err != nil {
+			logging.Warn("skipping an archived entry", "key", key, "err", err)
+			continue
+		}
+		all = append(all, e)
+	}
+	entry.Sort(all)
+	return all
+}
+
+// moveCollection moves the raw data of every key in collection from one
+// diskv to another.
+func moveCollection(ctx context.Context, from, to *diskv.Diskv, collection string) (int, error) {
+	ck := toCollection(collection)
+
+	keys := make([]string, 0)
+	for key := range from.Keys(ctx.Done()) {
+		if keyToPathTransform(key).Path[0] == ck {
+			keys = append(keys, key)
+		}
+	}
+
+	return moveKeys(from, to, keys)
+}
+
+// moveKeys moves the raw data of keys from one diskv to another.
+func moveKeys(from, to *diskv.Diskv, keys []string) (int, error) {
+	moved := 0
+	for _, key := range keys {
+		val, err := from.Read(key)
+		if err != nil {
+			return moved, err
+		}
+		if err := to.Write(key, val); err != nil {
+			return moved, err
+		}
+		if err := from.Erase(key); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+var errRemoteArchive = fmt.Errorf("%w, archive a remote journal on the host it lives on", app.ErrUnsupported)
+
+func (r *remote) Archive(ctx context.Context, collection string) (int, error) {
+	return 0, errRemoteArchive
+}
+
+func (r *remote) Unarchive(ctx context.Context, collection string) (int, error) {
+	return 0, errRemoteArchive
+}
+
+func (r *remote) ArchiveEntries(ctx context.Context, entries ...*entry.Entry) (int, error) {
+	return 0, errRemoteArchive
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// BulletDefaulter is implemented by persistence that can keep the bullet an
+// entry added to a collection gets when none is picked.
+type BulletDefaulter interface {
+	// DefaultBullets returns the default bullet of each collection that has
+	// one.
+	DefaultBullets(ctx context.Context) map[string]glyph.Bullet
+	// SetDefaultBullet sets the default bullet of a collection, an empty
+	// bullet removes it.
+	SetDefaultBullet(ctx context.Context, collection string, bullet glyph.Bullet) error
+}
+
+// bulletsSuffix is added to the base path for the default bullets file.
+// Like icons, a remote journal pulls and pushes it with the journal.
+const bulletsSuffix = ".bullets.json"
+
+func (p *persistence) bulletsPath() string {
+	return p.d.BasePath + bulletsSuffix
+}
+
+func (p *persistence) DefaultBullets(ctx context.Context) map[string]glyph.Bullet {
+	bullets := make(map[string]glyph.Bullet)
+	b, err := ioutil.ReadFile(p.bulletsPath())
+	if err != nil {
+		return bullets
+	}
+	_ = json.Unmarshal(b, &bullets)
+	return bullets
+}
+
+func (p *persistence) SetDefaultBullet(ctx context.Context, collection string, bullet glyph.Bullet) error {
+	bullets := p.DefaultBullets(ctx)
+	if bullet == "" {
+		delete(bullets, collection)
+	} else {
+		bullets[collection] = bullet
+	}
+	if len(bullets) == 0 {
+		if err := os.Remove(p.bulletsPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.MarshalIndent(bullets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p.bulletsPath(), b, 0644)
+}
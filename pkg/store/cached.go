@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// pulledSuffix is added to the base path of a remote journal for when its
+// mirror was last pulled.
+const pulledSuffix = ".pulled"
+
+// defaultRemoteCache is how long a pull is read from if the config does not
+// say.
+const defaultRemoteCache = 5 * time.Minute
+
+// errCached is returned by writes to a cached journal.
+var errCached = fmt.Errorf("%w, the cached copy of a remote journal is read only", app.ErrUnsupported)
+
+// LoadCached loads the journal for a command that only reads it. A remote
+// journal is read from its local mirror, without pulling, if it was pulled
+// within the RemoteCache of the config, and can not be written. Otherwise it
+// is Load.
+func LoadCached(cfg Config) (Persistence, error) {
+	if cfg == nil {
+		var err error
+		cfg, err = LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Remote() == "" || !pulledWithin(cfg.BasePath(), cfg.RemoteCache()) {
+		return Load(cfg)
+	}
+	logging.Debug("reading the mirror of the remote journal", "path", cfg.BasePath(), "remote", cfg.Remote())
+	p, err := local(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if p.sealer != nil {
+		if err := p.secure(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return &cached{p: p}, nil
+}
+
+// pulledWithin returns true if the mirror at base was pulled less than d
+// ago.
+func pulledWithin(base string, d time.Duration) bool {
+	b, err := ioutil.ReadFile(pulledPath(base))
+	if err != nil {
+		return false
+	}
+	at, err := time.Parse(time.RFC3339, strings.TrimSpace(string(b)))
+	return err == nil && time.Since(at) < d
+}
+
+// pulledPath is where the time the mirror at base was pulled is kept.
+func pulledPath(base string) string {
+	return strings.TrimRight(base, string(os.PathSeparator)) + pulledSuffix
+}
+
+// cached reads the mirror of a remote journal. Only the reads of the local
+// journal are passed through, so nothing can be written to the mirror that
+// the remote does not have.
+type cached struct {
+	p *persistence
+}
+
+var (
+	_ Persistence = (*cached)(nil)
+	_ Iconer      = (*cached)(nil)
+	_ Historian   = (*cached)(nil)
+	_ Searcher    = (*cached)(nil)
+	_ Inspector   = (*cached)(nil)
+	_ Unlocker    = (*cached)(nil)
+)
+
+func (c *cached) MapAll(ctx context.Context) map[string][]*entry.Entry {
+	return c.p.MapAll(ctx)
+}
+
+func (c *cached) MapAllProgress(ctx context.Context, progress func(read, total int)) map[string][]*entry.Entry {
+	return c.p.MapAllProgress(ctx, progress)
+}
+
+func (c *cached) ListAll(ctx context.Context) []*entry.Entry {
+	return c.p.ListAll(ctx)
+}
+
+func (c *cached) List(ctx context.Context, collection string) []*entry.Entry {
+	return c.p.List(ctx, collection)
+}
+
+func (c *cached) Collections(ctx context.Context, prefix string) []string {
+	return c.p.Collections(ctx, prefix)
+}
+
+func (c *cached) Store(e *entry.Entry) error {
+	return errCached
+}
+
+func (c *cached) Icons(ctx context.Context) map[string]string {
+	return c.p.Icons(ctx)
+}
+
+func (c *cached) SetIcon(ctx context.Context, collection, icon string) error {
+	return errCached
+}
+
+func (c *cached) DefaultBullets(ctx context.Context) map[string]glyph.Bullet {
+	return c.p.DefaultBullets(ctx)
+}
+
+func (c *cached) SetDefaultBullet(ctx context.Context, collection string, bullet glyph.Bullet) error {
+	return errCached
+}
+
+func (c *cached) Activity(ctx context.Context, since, until time.Time) []Activity {
+	return c.p.Activity(ctx, since, until)
+}
+
+func (c *cached) Search(ctx context.Context, query string) []*entry.Entry {
+	return c.p.Search(ctx, query)
+}
+
+func (c *cached) Tags(ctx context.Context) map[string]int {
+	return c.p.Tags(ctx)
+}
+
+func (c *cached) CollectionInfo(ctx context.Context, name string) (CollectionInfo, error) {
+	return c.p.CollectionInfo(ctx, name)
+}
+
+func (c *cached) Encrypted() bool {
+	return c.p.Encrypted()
+}
+
+func (c *cached) Unlocks(passphrase string) bool {
+	return c.p.Unlocks(passphrase)
+}
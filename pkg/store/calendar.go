@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/caldav"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// Due returns the day the entry needs attention, or nil.
+func Due(e *entry.Entry) *entry.Timestamp {
+	switch e.Bullet {
+	case glyph.Waiting:
+		return e.FollowUp
+	case glyph.Task, glyph.Event:
+		return e.On
+	}
+	return nil
+}
+
+// syncCalendar writes or updates the calendar item of an open, dated entry
+// and removes the item once the entry is completed, struck or made private.
+// Private entries are not shared, so their messages never reach the
+// calendar. The uid of the item is kept on the entry.
+func syncCalendar(ctx context.Context, c *caldav.Client, e *entry.Entry) error {
+	if e.Private || e.Bullet == glyph.Completed || e.Bullet == glyph.Irrelevant {
+		if e.CalendarUID == "" {
+			return nil
+		}
+		if err := c.Delete(ctx, e.CalendarUID); err != nil {
+			return err
+		}
+		e.CalendarUID = ""
+		return nil
+	}
+
+	day := Due(e)
+	if day == nil {
+		return nil
+	}
+	if e.CalendarUID == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		e.CalendarUID = fmt.Sprintf("%x@bujo", b)
+	}
+	return c.Put(ctx, e.CalendarUID, caldav.Event(e.CalendarUID, e.Message, day.Time))
+}
+
+// updateCalendar brings the calendar item of e in step with it, once e is
+// written, and writes e again if the uid of its item changed. A failure is
+// only logged, the entry is already kept.
+func (p *persistence) updateCalendar(e *entry.Entry) {
+	if p.calendar == nil {
+		return
+	}
+	uid := e.CalendarUID
+	if err := syncCalendar(context.Background(), p.calendar, e); err != nil {
+		logging.Warn("failed to update calendar", "entry", e.ID, "err", err)
+		return
+	}
+	if e.CalendarUID == uid {
+		return
+	}
+	key, data, err := p.encode(e)
+	if err == nil {
+		err = p.d.Write(key, data)
+	}
+	if err != nil {
+		logging.Warn("failed to keep the calendar uid", "entry", e.ID, "err", err)
+	}
+}
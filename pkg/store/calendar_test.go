@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// calendarServer records the requests made to a calendar.
+type calendarServer struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (c *calendarServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, r.Method)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (c *calendarServer) seen() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.requests)
+}
+
+func dated(message string) *entry.Entry {
+	e := entry.New("Today", glyph.Task, message)
+	e.On = &entry.Timestamp{Time: time.Now()}
+	return e
+}
+
+func TestTransactCalendar(t *testing.T) {
+	cal := &calendarServer{}
+	srv := httptest.NewServer(cal)
+	defer srv.Close()
+	p, cleanup := newTestStore(t, testConfig{calendar: CalendarAccount{URL: srv.URL}})
+	defer cleanup()
+	ctx := context.Background()
+
+	failed := errors.New("failed")
+	err := p.Transact(ctx, func(tx Tx) error {
+		if err := tx.Store(dated("undone")); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("got %v, want %v", err, failed)
+	}
+	if n := cal.seen(); n != 0 {
+		t.Fatalf("a failed transaction made %d calendar requests, want 0", n)
+	}
+
+	e := dated("done")
+	if err := p.Transact(ctx, func(tx Tx) error { return tx.Store(e) }); err != nil {
+		t.Fatal(err)
+	}
+	if n := cal.seen(); n != 1 {
+		t.Fatalf("got %d calendar requests, want 1", n)
+	}
+	all := p.ListAll(ctx)
+	if len(all) != 1 || all[0].CalendarUID == "" || all[0].CalendarUID != e.CalendarUID {
+		t.Errorf("the calendar uid was not kept: %+v", all)
+	}
+}
+
+func TestStoreCalendar(t *testing.T) {
+	cal := &calendarServer{}
+	srv := httptest.NewServer(cal)
+	defer srv.Close()
+	p, cleanup := newTestStore(t, testConfig{calendar: CalendarAccount{URL: srv.URL}})
+	defer cleanup()
+
+	e := dated("task")
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	e.Complete()
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	if got := cal.requests; len(got) != 2 || got[0] != http.MethodPut || got[1] != http.MethodDelete {
+		t.Errorf("got %v, want a PUT and a DELETE", got)
+	}
+	if all := p.ListAll(context.Background()); len(all) != 1 || all[0].CalendarUID != "" {
+		t.Errorf("the calendar uid was not cleared: %+v", all)
+	}
+}
+
+func TestStoreCalendarPrivate(t *testing.T) {
+	cal := &calendarServer{}
+	srv := httptest.NewServer(cal)
+	defer srv.Close()
+	p, cleanup := newTestStore(t, testConfig{calendar: CalendarAccount{URL: srv.URL}})
+	defer cleanup()
+
+	private := dated("private")
+	private.Private = true
+	if err := p.Store(private); err != nil {
+		t.Fatal(err)
+	}
+	if n := cal.seen(); n != 0 {
+		t.Fatalf("a private entry made %d calendar requests, want 0", n)
+	}
+
+	e := dated("made private")
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	e.Private = true
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	if got := cal.requests; len(got) != 2 || got[0] != http.MethodPut || got[1] != http.MethodDelete {
+		t.Errorf("got %v, want a PUT and a DELETE", got)
+	}
+	for _, e := range p.ListAll(context.Background()) {
+		if e.CalendarUID != "" {
+			t.Errorf("%q kept calendar uid %q", e.Message, e.CalendarUID)
+		}
+	}
+}
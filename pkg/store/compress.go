@@ -0,0 +1,96 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+)
+
+// Compactor is implemented by persistence that can compress the entries it
+// has already stored.
+type Compactor interface {
+	// Compact compresses all stored entries that are not yet compressed and
+	// returns how many were converted.
+	Compact(ctx context.Context) (int, error)
+}
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func isCompressed(b []byte) bool {
+	return bytes.HasPrefix(b, gzipMagic)
+}
+
+func compress(b []byte) ([]byte, error) {
+	buf := bytes.Buffer{}
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress returns b uncompressed. Entries written before compression was
+// enabled are returned as is.
+func decompress(b []byte) ([]byte, error) {
+	if !isCompressed(b) {
+		return b, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func (p *persistence) Compact(ctx context.Context) (int, error) {
+	converted := 0
+	for key := range p.d.Keys(ctx.Done()) {
+		val, err := p.d.Read(key)
+		if err != nil {
+			return converted, err
+		}
+		data, err := p.compact(val)
+		if err != nil {
+			return converted, err
+		}
+		if data == nil {
+			continue
+		}
+		if err := rewrite(p.d, key, data); err != nil {
+			return converted, err
+		}
+		converted++
+	}
+	return converted, nil
+}
+
+// compact returns val compressed, or nil if it already is. Encrypted values
+// are opened, compressed and sealed again, the seal is always outermost.
+func (p *persistence) compact(val []byte) ([]byte, error) {
+	if !isEncrypted(val) {
+		if isCompressed(val) {
+			return nil, nil
+		}
+		return compress(val)
+	}
+	if p.sealer == nil {
+		return nil, errNoPassphrase
+	}
+	opened, err := p.sealer.open(val)
+	if err != nil {
+		return nil, err
+	}
+	if isCompressed(opened) {
+		return nil, nil
+	}
+	data, err := compress(opened)
+	if err != nil {
+		return nil, err
+	}
+	return p.sealer.seal(data)
+}
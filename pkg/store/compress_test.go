@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestCompactEncrypted(t *testing.T) {
+	ctx := context.Background()
+	p, cleanup := newTestStore(t, testConfig{passphrase: "hunter2"})
+	defer cleanup()
+	for _, m := range []string{"one", "two", "three"} {
+		if err := p.Store(entry.New("today", glyph.Task, m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := p.Compact(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("compacted %d, want 3", n)
+	}
+	for key := range p.d.Keys(nil) {
+		val, err := p.d.Read(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isEncrypted(val) {
+			t.Errorf("%s is not encrypted after compact", key)
+		}
+	}
+
+	got := p.ListAll(ctx)
+	if len(got) != 3 {
+		t.Fatalf("read %d entries after compact, want 3", len(got))
+	}
+
+	// Compacting again leaves everything as it is.
+	if n, err := p.Compact(ctx); err != nil || n != 0 {
+		t.Errorf("compact again = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	ctx := context.Background()
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+	if err := p.Store(entry.New("today", glyph.Note, "plain")); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := p.Compact(ctx); err != nil || n != 1 {
+		t.Fatalf("compact = %d, %v, want 1, nil", n, err)
+	}
+	got := p.ListAll(ctx)
+	if len(got) != 1 || got[0].Message != "plain" {
+		t.Errorf("read %v after compact", got)
+	}
+}
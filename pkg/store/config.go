@@ -3,34 +3,59 @@ package store
 import (
 	"log"
 	"os"
+	"os/exec"
+	"strings"
+	"time"
 
-	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/logging"
 )
 
 // TODO: this is next so we can start recording stuff.
 
 type Config interface {
 	BasePath() string
+	Compress() bool
+	// Remote is the url of a journal on another host, like
+	// ssh://user@host/path/to/journal. Empty for a local journal.
+	Remote() string
+	// RemoteCache is how long commands that only read use the local mirror
+	// of a remote journal after it was pulled, see LoadCached.
+	RemoteCache() time.Duration
+	// Calendar is the CalDAV calendar dated entries are written to. The url
+	// is empty if there is none.
+	Calendar() CalendarAccount
+	// Encrypted is true if entries are encrypted as they are written.
+	Encrypted() bool
+	// Passphrase is what the key of an encrypted journal is made from.
+	Passphrase() string
+}
+
+// CalendarAccount is a CalDAV calendar collection and its credentials.
+type CalendarAccount struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"-"`
 }
 
 func LoadConfig() (Config, error) {
-	homeDir, err := homedir.Dir()
-	if err != nil {
-		log.Printf("Couldn't detect home dir, using cwd: %s", err)
-		homeDir = "."
-	}
-	// Walk the file tree from here backwards looking for a .bujo file.
-	viper.SetDefault("path", homeDir+"/.bujo.db")
+	viper.SetDefault("path", DefaultPath())
+	viper.SetDefault("remote_cache", defaultRemoteCache)
 	viper.SetConfigName(".bujo") // .yaml is implicit
 	viper.SetEnvPrefix("BUJO")
 	viper.AutomaticEnv()
 
-	if override := os.Getenv("BUJO_CONFIG_PATH"); override != "" {
+	// $BUJO_CONFIG_PATH comes first, then the XDG config, then home.
+	override := os.Getenv("BUJO_CONFIG_PATH")
+	if override != "" {
 		viper.AddConfigPath(override)
+	} else if _, err := os.Stat(xdgConfigFile()); err == nil {
+		viper.SetConfigFile(xdgConfigFile())
 	}
 
-	viper.AddConfigPath(homeDir)
+	viper.AddConfigPath(home())
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -39,13 +64,102 @@ func LoadConfig() (Config, error) {
 		}
 	}
 
-	return &fileConfig{Path: viper.GetString("path")}, nil
+	// Dated collections are named with the configured scheme.
+	scheme, err := collection.SchemeFor(viper.GetString("collections.scheme"))
+	if err != nil {
+		return nil, err
+	}
+	collection.Use(scheme)
+
+	// $BUJO_LOG_LEVEL is read before the config, to debug a single command.
+	levelName := os.Getenv("BUJO_LOG_LEVEL")
+	if levelName == "" {
+		levelName = viper.GetString("log.level")
+	}
+	level, err := logging.ParseLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+	logging.Configure(LogPath(), level)
+
+	return &fileConfig{
+		Path:       viper.GetString("path"),
+		Compressed: viper.GetBool("compress"),
+		RemoteURL:  viper.GetString("remote"),
+		Cache:      viper.GetDuration("remote_cache"),
+		CalendarAccount: CalendarAccount{
+			URL:      viper.GetString("calendar.url"),
+			Username: viper.GetString("calendar.username"),
+			Password: calendarPassword(),
+		},
+		Encrypt: viper.GetBool("encrypt.enabled"),
+	}, nil
 }
 
 type fileConfig struct {
-	Path string `json:"path"`
+	Path       string `json:"path"`
+	Compressed bool   `json:"compress"`
+	RemoteURL  string `json:"remote"`
+	// Cache is how long a pull of the remote is read from.
+	Cache time.Duration `json:"remote_cache"`
+
+	CalendarAccount CalendarAccount `json:"calendar"`
+	Encrypt         bool            `json:"encrypt"`
+}
+
+func calendarPassword() string {
+	if pw := viper.GetString("calendar.password"); pw != "" {
+		return pw
+	}
+	return os.Getenv("BUJO_CALENDAR_PASSWORD")
+}
+
+// passphrase is $BUJO_PASSPHRASE, or what encrypt.passphrase_command prints,
+// like a password manager or keychain.
+func passphrase() string {
+	if pass := os.Getenv("BUJO_PASSPHRASE"); pass != "" {
+		return pass
+	}
+	command := viper.GetString("encrypt.passphrase_command")
+	if command == "" {
+		return ""
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		logging.Warn("passphrase command failed", "command", command, "err", err)
+		return ""
+	}
+	return strings.TrimSpace(string(out))
 }
 
 func (f *fileConfig) BasePath() string {
 	return f.Path
 }
+
+func (f *fileConfig) Compress() bool {
+	return f.Compressed
+}
+
+func (f *fileConfig) Remote() string {
+	return f.RemoteURL
+}
+
+func (f *fileConfig) RemoteCache() time.Duration {
+	return f.Cache
+}
+
+func (f *fileConfig) Calendar() CalendarAccount {
+	return f.CalendarAccount
+}
+
+func (f *fileConfig) Encrypted() bool {
+	return f.Encrypt
+}
+
+// Passphrase is only looked up when it is asked for, the command might
+// prompt.
+func (f *fileConfig) Passphrase() string {
+	return passphrase()
+}
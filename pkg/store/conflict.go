@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// Conflict is an entry with more than one version in the journal, like when
+// a file sync kept the copies of two hosts that changed the same entry.
+type Conflict struct {
+	ID       string
+	Versions []*entry.Entry
+
+	// keys of the versions, by version.
+	keys []string
+}
+
+// Resolution is how a conflict was resolved.
+type Resolution struct {
+	ID        string         `json:"id"`
+	Resolved  time.Time      `json:"resolved"`
+	Kept      *entry.Entry   `json:"kept"`
+	Discarded []*entry.Entry `json:"discarded"`
+}
+
+// ConflictResolver is implemented by persistence that can find and resolve
+// conflicting versions of entries.
+type ConflictResolver interface {
+	// Conflicts returns the entries with more than one version.
+	Conflicts(ctx context.Context) []Conflict
+	// ResolveConflict replaces every version of the conflict with keep, all
+	// or nothing, and records the resolution.
+	ResolveConflict(ctx context.Context, c Conflict, keep *entry.Entry) error
+	// Resolutions returns the recorded resolutions, oldest first.
+	Resolutions() []Resolution
+}
+
+// resolutionsSuffix is added to the base path for the resolution history.
+const resolutionsSuffix = ".conflicts.json"
+
+// maxResolutions is how many resolutions are kept in the history.
+const maxResolutions = 200
+
+func (p *persistence) Conflicts(ctx context.Context) []Conflict {
+	byID := make(map[string]*Conflict)
+	for key := range p.d.Keys(ctx.Done()) {
+		e, err := p.read(key)
+		if err != nil {
+			continue
+		}
+		c, ok := byID[e.ID]
+		if !ok {
+			c = &Conflict{ID: e.ID}
+			byID[e.ID] = c
+		}
+		c.Versions = append(c.Versions, e)
+		c.keys = append(c.keys, key)
+	}
+
+	conflicts := make([]Conflict, 0)
+	for _, c := range byID {
+		if len(c.Versions) > 1 {
+			conflicts = append(conflicts, *c)
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ID < conflicts[j].ID
+	})
+	return conflicts
+}
+
+func (p *persistence) ResolveConflict(ctx context.Context, c Conflict, keep *entry.Entry) error {
+	if keep.ID != c.ID {
+		return errors.New("the kept entry is not a version of the conflict")
+	}
+	err := p.Transact(ctx, func(t Tx) error {
+		// Versions are erased by key, the key of a copy may not match its
+		// content.
+		for _, key := range c.keys {
+			t.(*tx).erase(key)
+		}
+		return t.Store(keep)
+	})
+	if err != nil {
+		return err
+	}
+
+	discarded := make([]*entry.Entry, 0, len(c.Versions))
+	for _, v := range c.Versions {
+		if v != keep {
+			discarded = append(discarded, v)
+		}
+	}
+	return p.record(Resolution{
+		ID:        c.ID,
+		Resolved:  time.Now(),
+		Kept:      keep,
+		Discarded: discarded,
+	})
+}
+
+// Merged returns the first version of the conflict with the distinct
+// messages of every version.
+func (c Conflict) Merged() *entry.Entry {
+	merged := *c.Versions[0]
+	seen := make(map[string]bool)
+	messages := make([]string, 0, len(c.Versions))
+	for _, v := range c.Versions {
+		if !seen[v.Message] {
+			seen[v.Message] = true
+			messages = append(messages, v.Message)
+		}
+	}
+	merged.Message = strings.Join(messages, "; ")
+	return &merged
+}
+
+func (p *persistence) resolutionsPath() string {
+	return p.d.BasePath + resolutionsSuffix
+}
+
+func (p *persistence) Resolutions() []Resolution {
+	all := make([]Resolution, 0)
+	b, err := ioutil.ReadFile(p.resolutionsPath())
+	if err != nil {
+		return all
+	}
+	if p.sealer != nil {
+		if b, err = p.sealer.open(b); err != nil {
+			return all
+		}
+	}
+	_ = json.Unmarshal(b, &all)
+	return all
+}
+
+func (p *persistence) record(r Resolution) error {
+	all := append(p.Resolutions(), r)
+	if len(all) > maxResolutions {
+		all = all[len(all)-maxResolutions:]
+	}
+	return p.writeResolutions(all)
+}
+
+// writeResolutions writes the resolution history, sealed with the key of
+// the journal if it is encrypted, the versions have their messages.
+func (p *persistence) writeResolutions(all []Resolution) error {
+	b, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	if p.sealer != nil {
+		if b, err = p.sealer.seal(b); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(p.resolutionsPath()), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(p.resolutionsPath(), b, 0600); err != nil {
+		return err
+	}
+	// A history written before keeps its mode otherwise.
+	return os.Chmod(p.resolutionsPath(), 0600)
+}
+
+func (r *remote) ResolveConflict(ctx context.Context, c Conflict, keep *entry.Entry) error {
+	return fmt.Errorf("%w, resolve conflicts of a remote journal on the host it lives on", app.ErrUnsupported)
+}
@@ -7,8 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/peterbourgon/diskv/v3"
+	"io/ioutil"
 	"strings"
+	"sync"
+	"tableflip.dev/bujo/pkg/caldav"
 	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/logging"
 )
 
 type Persistence interface {
@@ -19,6 +23,14 @@ type Persistence interface {
 	Store(e *entry.Entry) error
 }
 
+// ProgressMapper is implemented by persistence that can tell how far along
+// reading the whole journal is.
+type ProgressMapper interface {
+	// MapAllProgress is MapAll, calling progress with how many of the
+	// collections have been read as it goes.
+	MapAllProgress(ctx context.Context, progress func(read, total int)) map[string][]*entry.Entry
+}
+
 func Load(cfg Config) (Persistence, error) {
 	if cfg == nil {
 		var err error
@@ -28,16 +40,83 @@ func Load(cfg Config) (Persistence, error) {
 		}
 	}
 
-	return &persistence{d: diskv.New(diskv.Options{
-		BasePath:          cfg.BasePath(),
-		AdvancedTransform: keyToPathTransform,
-		InverseTransform:  pathToKeyTransform,
-		CacheSizeMax:      1024 * 1024, // 1MB
-	})}, nil
+	p, err := local(cfg)
+	if err != nil {
+		return nil, err
+	}
+	logging.Debug("loaded journal", "path", cfg.BasePath(), "remote", cfg.Remote(), "encrypted", p.sealer != nil)
+
+	// A remote journal uses the base path as a local mirror.
+	if cfg.Remote() != "" {
+		r, err := newRemote(p, cfg.BasePath(), cfg.Remote())
+		if err != nil {
+			return nil, err
+		}
+		if p.sealer != nil {
+			if err := p.secure(context.Background()); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+	if p.sealer != nil {
+		if err := p.secure(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// local is the journal kept at the base path of cfg.
+func local(cfg Config) (*persistence, error) {
+	p := &persistence{
+		d: diskv.New(diskv.Options{
+			BasePath:          cfg.BasePath(),
+			AdvancedTransform: keyToPathTransform,
+			InverseTransform:  pathToKeyTransform,
+			CacheSizeMax:      1024 * 1024, // 1MB
+		}),
+		compress: cfg.Compress(),
+	}
+
+	if account := cfg.Calendar(); account.URL != "" {
+		p.calendar = &caldav.Client{
+			URL:      account.URL,
+			Username: account.Username,
+			Password: account.Password,
+		}
+	}
+
+	if cfg.Encrypted() {
+		passphrase := cfg.Passphrase()
+		if passphrase == "" {
+			return nil, errNoPassphrase
+		}
+		p.sealer = newSealer(passphrase)
+	}
+	return p, nil
 }
 
 type persistence struct {
-	d *diskv.Diskv
+	d        *diskv.Diskv
+	compress bool
+	// sealer encrypts entries as they are written, if set.
+	sealer *sealer
+
+	// a is the archive, see archive().
+	a *diskv.Diskv
+
+	// calendar is kept in step with dated entries, if set.
+	calendar *caldav.Client
+
+	// tx is held while a transaction is written, see Transact.
+	tx sync.RWMutex
+
+	// x is the search index, see Search.
+	x index
+
+	// reads coalesces overlapping reads of all entries or a collection.
+	reads flight
 }
 
 func (p *persistence) read(key string) (*entry.Entry, error) {
@@ -45,6 +124,24 @@ func (p *persistence) read(key string) (*entry.Entry, error) {
 	if err != nil {
 		return nil, err
 	}
+	return p.decode(key, val)
+}
+
+// decode reads the entry stored at key from its value.
+func (p *persistence) decode(key string, val []byte) (*entry.Entry, error) {
+	if isEncrypted(val) {
+		if p.sealer == nil {
+			return nil, errNoPassphrase
+		}
+		var err error
+		if val, err = p.sealer.open(val); err != nil {
+			return nil, err
+		}
+	}
+	val, err := decompress(val)
+	if err != nil {
+		return nil, err
+	}
 	e := entry.Entry{}
 	if err := json.Unmarshal(val, &e); err != nil {
 		return nil, err
@@ -58,14 +155,40 @@ func (p *persistence) read(key string) (*entry.Entry, error) {
 }
 
 func (p *persistence) MapAll(ctx context.Context) map[string][]*entry.Entry {
+	return p.MapAllProgress(ctx, nil)
+}
+
+func (p *persistence) MapAllProgress(ctx context.Context, progress func(read, total int)) map[string][]*entry.Entry {
+	// Each collection is a directory of the base path, and the keys are
+	// walked one directory after the other.
+	total := 0
+	if progress != nil {
+		if dirs, err := ioutil.ReadDir(p.d.BasePath); err == nil {
+			for _, d := range dirs {
+				if d.IsDir() {
+					total++
+				}
+			}
+		}
+	}
+	read := 0
+	last := ""
+
 	all := make(map[string][]*entry.Entry, 0)
 	for key := range p.d.Keys(ctx.Done()) {
 		pk := keyToPathTransform(key)
 		ck := fromCollection(pk.Path[0])
+		if progress != nil && ck != last {
+			if last != "" {
+				read++
+				progress(read, total)
+			}
+			last = ck
+		}
 
 		e, err := p.read(key)
 		if err != nil {
-			fmt.Printf("%s: %s\n", key, err) // TODO: print this to STDERR
+			logging.Error("skipping an entry that can not be read", "key", key, "err", err)
 			continue
 		}
 
@@ -75,55 +198,93 @@ func (p *persistence) MapAll(ctx context.Context) map[string][]*entry.Entry {
 			all[ck] = append(c, e)
 		}
 	}
-	// TODO: sort these based on ?
+	if progress != nil && last != "" {
+		progress(read+1, total)
+	}
+	for _, c := range all {
+		entry.Sort(c)
+	}
 	return all
 }
 
 func (p *persistence) ListAll(ctx context.Context) []*entry.Entry {
+	return p.reads.do("", func() []*entry.Entry {
+		return p.listAll(ctx)
+	})
+}
+
+func (p *persistence) listAll(ctx context.Context) []*entry.Entry {
 	all := make([]*entry.Entry, 0)
 	for key := range p.d.Keys(ctx.Done()) {
 		e, err := p.read(key)
 		if err != nil {
-			fmt.Printf("%s: %s\n", key, err) // TODO: print this to STDERR
+			logging.Error("skipping an entry that can not be read", "key", key, "err", err)
 			continue
 		}
 		all = append(all, e)
 	}
-	// TODO: sort these based on ?
+	entry.Sort(all)
 	return all
 }
 
 func (p *persistence) List(ctx context.Context, collection string) []*entry.Entry {
+	// Collection names are not empty, "" is all of them.
+	return p.reads.do(collection, func() []*entry.Entry {
+		return p.list(ctx, collection)
+	})
+}
+
+func (p *persistence) list(ctx context.Context, collection string) []*entry.Entry {
 	ck := toCollection(collection)
 	all := make([]*entry.Entry, 0)
 	for key := range p.d.Keys(ctx.Done()) {
 		if pk := keyToPathTransform(key); pk.Path[0] == ck {
 			e, err := p.read(key)
 			if err != nil {
-				fmt.Printf("%s: %s\n", key, err) // TODO: print this to STDERR
+				logging.Error("skipping an entry that can not be read", "key", key, "err", err)
 				continue
 			}
 			all = append(all, e)
 		}
 	}
-	// TODO: sort these based on created.
+	entry.Sort(all)
 	// TODO: add a filter for done?
 	return all
 }
 
 func (p *persistence) Store(e *entry.Entry) error {
+	key, data, err := p.encode(e)
+	if err != nil {
+		return err
+	}
+	if err := p.d.Write(key, data); err != nil {
+		return err
+	}
+	p.updateCalendar(e)
+	return nil
+}
+
+// encode returns the key and the data to write for an entry.
+func (p *persistence) encode(e *entry.Entry) (string, []byte, error) {
 	if e.Schema == "" {
 		e.Schema = entry.CurrentSchema
 	}
 	key := toKey(e)
 	data, err := json.Marshal(e)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
-	if err := p.d.Write(key, data); err != nil {
-		return err
+	if p.compress {
+		if data, err = compress(data); err != nil {
+			return "", nil, err
+		}
 	}
-	return nil
+	if p.sealer != nil {
+		if data, err = p.sealer.seal(data); err != nil {
+			return "", nil, err
+		}
+	}
+	return key, data, nil
 }
 
 func (p *persistence) Collections(ctx context.Context, prefix string) []string {
@@ -169,13 +330,33 @@ func toKey(e *entry.Entry) string {
 	collection := toCollection(e.Collection)
 	then := e.Created.Time.Format(layoutISO)
 
+	AssignID(e)
+
+	return fmt.Sprintf("%s-%s-%s", collection, then, e.ID)
+}
+
+// AssignID gives e the id it is stored with, if it does not have one yet.
+func AssignID(e *entry.Entry) {
 	if e.ID == "" {
 		b, _ := json.Marshal(e)
 		id := md5.Sum(b)
 		e.ID = fmt.Sprintf("%x", id[:8])
 	}
+}
 
-	return fmt.Sprintf("%s-%s-%s", collection, then, e.ID)
+// ValidID reports if id has the form AssignID gives ids. The id names the
+// file an entry is stored in, so ids from outside the journal are checked
+// before they are stored.
+func ValidID(id string) bool {
+	if len(id) != 16 {
+		return false
+	}
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
 }
 
 func toCollection(s string) string {
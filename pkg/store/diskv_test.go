@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestUnreadableEntriesAreSkipped(t *testing.T) {
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := p.Store(entry.New("Work", glyph.Task, "task")); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.d.Write("V29yaw==-2020-01-01-garbage", []byte("not an entry")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is printed, it would end up in --json and batch output.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	all, list, mapped := p.listAll(ctx), p.list(ctx, "Work"), p.MapAll(ctx)
+	os.Stdout = stdout
+	_ = w.Close()
+	out, _ := ioutil.ReadAll(r)
+
+	if len(out) != 0 {
+		t.Errorf("printed %q", out)
+	}
+	if len(all) != 1 || len(list) != 1 || len(mapped["Work"]) != 1 {
+		t.Errorf("got %d, %d and %d entries, want 1 each", len(all), len(list), len(mapped["Work"]))
+	}
+}
@@ -0,0 +1,326 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/peterbourgon/diskv/v3"
+	"golang.org/x/crypto/pbkdf2"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// Encrypter is implemented by persistence that can encrypt the entries it
+// has already stored.
+type Encrypter interface {
+	// Encrypt encrypts all stored entries, archived ones too, that are not
+	// yet encrypted with a key made from passphrase, and returns how many
+	// were converted.
+	Encrypt(ctx context.Context, passphrase string) (int, error)
+}
+
+// Unlocker is implemented by persistence that can check the passphrase of
+// an encrypted journal, like to unlock the ui.
+type Unlocker interface {
+	// Encrypted is true if the journal was opened with a passphrase.
+	Encrypted() bool
+	// Unlocks is true if passphrase is the one the journal was opened with.
+	Unlocks(passphrase string) bool
+}
+
+// errNoPassphrase is returned when the journal is encrypted and there is no
+// passphrase to open it with.
+var errNoPassphrase = app.Invalid("passphrase", "", "the journal is encrypted, set $BUJO_PASSPHRASE or encrypt.passphrase_command in the config")
+
+// errWrongPassphrase is returned when an entry does not open with the key
+// made from the passphrase.
+var errWrongPassphrase = errors.New("can not decrypt the journal, the passphrase is wrong")
+
+// An encrypted value is encryptedMagic, the salt its key was made with, the
+// nonce and then the sealed entry.
+var encryptedMagic = []byte("bujo-aes1\x00")
+
+const (
+	saltSize = 16
+	keySize  = 32
+	// kdfIterations of PBKDF2 make guessing the passphrase slow.
+	kdfIterations = 200000
+)
+
+func isEncrypted(b []byte) bool {
+	return bytes.HasPrefix(b, encryptedMagic)
+}
+
+// sealer encrypts entries with AES-GCM, with a key made from a passphrase.
+// Each value keeps the salt of its key, so values written with another salt,
+// like by another host of a remote journal, open too.
+type sealer struct {
+	passphrase []byte
+
+	mu sync.Mutex
+	// salt is used for new values, the salt of the first value opened or a
+	// new one.
+	salt []byte
+	// aeads are the ciphers by salt, making a key is slow on purpose.
+	aeads map[string]cipher.AEAD
+}
+
+func newSealer(passphrase string) *sealer {
+	return &sealer{passphrase: []byte(passphrase), aeads: make(map[string]cipher.AEAD)}
+}
+
+func (s *sealer) aead(salt []byte) (cipher.AEAD, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.salt == nil {
+		s.salt = salt
+	}
+	if a, ok := s.aeads[string(salt)]; ok {
+		return a, nil
+	}
+	block, err := aes.NewCipher(pbkdf2.Key(s.passphrase, salt, kdfIterations, keySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	a, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s.aeads[string(salt)] = a
+	return a, nil
+}
+
+// seal returns b encrypted.
+func (s *sealer) seal(b []byte) ([]byte, error) {
+	s.mu.Lock()
+	salt := s.salt
+	s.mu.Unlock()
+	if salt == nil {
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	}
+	a, err := s.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, a.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(encryptedMagic)+len(salt)+len(nonce)+len(b)+a.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return a.Seal(out, nonce, b, encryptedMagic), nil
+}
+
+// open returns b decrypted. Values that are not encrypted are returned as
+// is.
+func (s *sealer) open(b []byte) ([]byte, error) {
+	if !isEncrypted(b) {
+		return b, nil
+	}
+	b = b[len(encryptedMagic):]
+	if len(b) < saltSize {
+		return nil, errors.New("encrypted entry is too short")
+	}
+	salt := b[:saltSize]
+	a, err := s.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	b = b[saltSize:]
+	if len(b) < a.NonceSize() {
+		return nil, errors.New("encrypted entry is too short")
+	}
+	plain, err := a.Open(nil, b[:a.NonceSize()], b[a.NonceSize():], encryptedMagic)
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return plain, nil
+}
+
+func (p *persistence) Encrypted() bool {
+	return p.sealer != nil
+}
+
+func (p *persistence) Unlocks(passphrase string) bool {
+	return p.sealer != nil && subtle.ConstantTimeCompare(p.sealer.passphrase, []byte(passphrase)) == 1
+}
+
+// checkPassphrase opens the first encrypted entry, so a wrong passphrase
+// fails once rather than for every entry read.
+func (p *persistence) checkPassphrase(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for key := range p.d.Keys(ctx.Done()) {
+		val, err := p.d.Read(key)
+		if err != nil || !isEncrypted(val) {
+			continue
+		}
+		_, err = p.sealer.open(val)
+		return err
+	}
+	return nil
+}
+
+func (p *persistence) Encrypt(ctx context.Context, passphrase string) (int, error) {
+	if passphrase == "" {
+		return 0, errNoPassphrase
+	}
+	if p.sealer == nil {
+		p.sealer = newSealer(passphrase)
+	}
+	if err := p.checkPassphrase(ctx); err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	for _, d := range []*diskv.Diskv{p.d, p.archive()} {
+		n, err := p.encryptAll(ctx, d)
+		converted += n
+		if err != nil {
+			return converted, err
+		}
+	}
+	if err := p.encryptResolutions(); err != nil {
+		return converted, err
+	}
+
+	return converted, p.removeIndex()
+}
+
+// encryptResolutions seals the conflict resolution history if it was
+// written in plain text.
+func (p *persistence) encryptResolutions() error {
+	b, err := ioutil.ReadFile(p.resolutionsPath())
+	if os.IsNotExist(err) || (err == nil && isEncrypted(b)) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	all := make([]Resolution, 0)
+	if err := json.Unmarshal(b, &all); err != nil {
+		return fmt.Errorf("failed to read the conflict history: %v", err)
+	}
+	return p.writeResolutions(all)
+}
+
+// removeIndex removes the search index from disk, it keeps the words of
+// every message. It is only kept in memory from then on.
+func (p *persistence) removeIndex() error {
+	p.x.mu.Lock()
+	defer p.x.mu.Unlock()
+	if err := os.Remove(indexPath(p.d.BasePath)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove the search index: %v", err)
+	}
+	p.x.keys, p.x.path = nil, ""
+	return nil
+}
+
+// secure is run as an encrypted journal is loaded. It removes a search
+// index left from before the journal was encrypted, checks the passphrase
+// and warns if entries are still in plain text.
+func (p *persistence) secure(ctx context.Context) error {
+	if err := p.removeIndex(); err != nil {
+		return err
+	}
+	if err := p.checkPassphrase(ctx); err != nil {
+		return err
+	}
+	if err := CheckEncrypted(p.d.BasePath); err != nil {
+		logging.Warn("journal is not all encrypted", "err", err)
+		fmt.Fprintf(os.Stderr, "warning: %v, run bujo encrypt\n", err)
+	}
+	return nil
+}
+
+// CheckEncrypted checks that every entry of the journal at base, archived
+// ones too, and the conflict resolution history are encrypted. Only the start
+// of each file is read.
+func CheckEncrypted(base string) error {
+	plain := 0
+	for _, dir := range []string{base, base + archiveSuffix} {
+		err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !fi.Mode().IsRegular() || strings.HasPrefix(fi.Name(), ".") {
+				return nil
+			}
+			if !sealed(path) {
+				plain++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	history := base + resolutionsSuffix
+	if _, err := os.Stat(history); err == nil && !sealed(history) {
+		if plain > 0 {
+			return fmt.Errorf("%d entries and the conflict history are not encrypted", plain)
+		}
+		return errors.New("the conflict history is not encrypted")
+	}
+	if plain > 0 {
+		return fmt.Errorf("%d entries are not encrypted", plain)
+	}
+	return nil
+}
+
+// sealed returns true if the file at path starts as an encrypted value.
+func sealed(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	magic := make([]byte, len(encryptedMagic))
+	_, err = io.ReadFull(f, magic)
+	return err == nil && isEncrypted(magic)
+}
+
+func (p *persistence) encryptAll(ctx context.Context, d *diskv.Diskv) (int, error) {
+	converted := 0
+	for key := range d.Keys(ctx.Done()) {
+		val, err := d.Read(key)
+		if err != nil {
+			return converted, err
+		}
+		if isEncrypted(val) {
+			continue
+		}
+		data, err := p.sealer.seal(val)
+		if err != nil {
+			return converted, err
+		}
+		if err := rewrite(d, key, data); err != nil {
+			return converted, err
+		}
+		converted++
+	}
+	return converted, nil
+}
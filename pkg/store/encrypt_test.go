@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestLoadEncryptedPlaintext(t *testing.T) {
+	// A plain journal with a search index on disk.
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+	base := p.d.BasePath
+	for _, m := range []string{"one", "two"} {
+		if err := p.Store(entry.New("today", glyph.Task, m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(indexPath(base), []byte(`{"keys": {}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kept := entry.New("today", glyph.Task, "kept")
+	if err := p.record(Resolution{ID: kept.ID, Kept: kept}); err != nil {
+		t.Fatal(err)
+	}
+
+	// encrypt.enabled is set, without bujo encrypt.
+	if _, err := Load(testConfig{path: base, passphrase: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(indexPath(base)); !os.IsNotExist(err) {
+		t.Errorf("the search index is still on disk: %v", err)
+	}
+	if err := CheckEncrypted(base); err == nil || err.Error() != "2 entries and the conflict history are not encrypted" {
+		t.Errorf("got %v, want 2 entries and the conflict history not encrypted", err)
+	}
+
+	e, err := Load(testConfig{path: base, passphrase: "hunter2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.(Encrypter).Encrypt(context.Background(), "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckEncrypted(base); err != nil {
+		t.Errorf("got %v after bujo encrypt, want all encrypted", err)
+	}
+	if b, err := ioutil.ReadFile(base + resolutionsSuffix); err != nil || strings.Contains(string(b), "kept") {
+		t.Errorf("the conflict history is readable: %v", err)
+	}
+	if fi, err := os.Stat(base + resolutionsSuffix); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("the conflict history is not private: %v", err)
+	}
+	if r := e.(ConflictResolver).Resolutions(); len(r) != 1 || r[0].Kept.Message != "kept" {
+		t.Errorf("got resolutions %+v, want the one kept", r)
+	}
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeWatcher is a Watcher that only emits the events it is given, so the
+// consumers of a watch can be driven deterministically.
+type FakeWatcher struct {
+	mu       sync.Mutex
+	watching []*fakeWatch
+	closed   bool
+}
+
+type fakeWatch struct {
+	events chan Event
+	done   <-chan struct{}
+}
+
+var _ Watcher = (*FakeWatcher)(nil)
+
+func NewFakeWatcher() *FakeWatcher {
+	return &FakeWatcher{}
+}
+
+// Watch returns a new stream of the events sent after the call. The stream
+// is closed when ctx is done, or when the FakeWatcher is restarted or closed.
+func (f *FakeWatcher) Watch(ctx context.Context) (<-chan Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWatch{events: make(chan Event), done: ctx.Done()}
+	if f.closed {
+		close(w.events)
+		return w.events, nil
+	}
+	f.watching = append(f.watching, w)
+
+	go func() {
+		<-ctx.Done()
+		f.remove(w)
+	}()
+	return w.events, nil
+}
+
+// Send delivers the events, in order, to every current watch. It blocks
+// until each watch has received them or its context is done.
+func (f *FakeWatcher) Send(events ...Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, e := range events {
+		for _, w := range f.watching {
+			select {
+			case w.events <- e:
+			case <-w.done:
+			}
+		}
+	}
+}
+
+// Restart closes every current watch, as if the underlying store watch
+// failed, while still allowing new watches to be started.
+func (f *FakeWatcher) Restart() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, w := range f.watching {
+		close(w.events)
+	}
+	f.watching = nil
+}
+
+// Close closes every current watch and any started after.
+func (f *FakeWatcher) Close() {
+	f.Restart()
+
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+}
+
+// Watching returns how many watches are currently open.
+func (f *FakeWatcher) Watching() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.watching)
+}
+
+func (f *FakeWatcher) remove(w *fakeWatch) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, o := range f.watching {
+		if o == w {
+			close(w.events)
+			f.watching = append(f.watching[:i], f.watching[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"sync"
+
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// flight coalesces reads of the same entries that overlap, like a ui and a
+// watcher listing one collection at once, so the journal is read once for
+// all of them.
+type flight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    sync.WaitGroup
+	entries []*entry.Entry
+}
+
+// do returns what read returns for key. Callers that ask for key while it
+// is being read wait for that read, and get copies of its entries, as
+// entries are changed in place before they are stored. A read cut short by
+// the context of its caller is cut short for them too.
+func (f *flight) do(key string, read func() []*entry.Entry) []*entry.Entry {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = make(map[string]*flightCall)
+	}
+	if c, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		c.done.Wait()
+		return copies(c.entries)
+	}
+	c := &flightCall{}
+	c.done.Add(1)
+	f.calls[key] = c
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		delete(f.calls, key)
+		f.mu.Unlock()
+		c.done.Done()
+	}()
+	c.entries = read()
+	return c.entries
+}
+
+// copies returns a copy of each entry, sorted as they are.
+func copies(entries []*entry.Entry) []*entry.Entry {
+	out := make([]*entry.Entry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.Clone())
+	}
+	return out
+}
@@ -0,0 +1,27 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestCopiesShareNothing(t *testing.T) {
+	e := entry.New("Today", glyph.Task, "task")
+	e.React(glyph.ReactionUp, "me", time.Now())
+	e.AddAttachment(entry.NewAttachment("https://example.com", ""))
+	e.On = &entry.Timestamp{Time: time.Now()}
+	on := e.On.Time
+
+	// What a caller that joined a read gets.
+	c := copies([]*entry.Entry{e})[0]
+	c.Reactions[0].By = "someone else"
+	c.Attachments[0].Label = "changed"
+	c.On.Time = c.On.AddDate(0, 0, 1)
+
+	if e.Reactions[0].By != "me" || e.Attachments[0].Label != "" || !e.On.Equal(on) {
+		t.Errorf("a change to the copy changed the entry: %+v", e)
+	}
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+// Iconer is implemented by persistence that can keep an icon, like an emoji,
+// for a collection.
+type Iconer interface {
+	// Icons returns the icon of each collection that has one.
+	Icons(ctx context.Context) map[string]string
+	// SetIcon sets the icon of a collection, an empty icon removes it.
+	SetIcon(ctx context.Context, collection, icon string) error
+}
+
+// iconsSuffix is added to the base path for the icons file. A remote journal
+// keeps it next to its path, and pulls and pushes it with the journal.
+const iconsSuffix = ".icons.json"
+
+func (p *persistence) iconsPath() string {
+	return p.d.BasePath + iconsSuffix
+}
+
+func (p *persistence) Icons(ctx context.Context) map[string]string {
+	icons := make(map[string]string)
+	b, err := ioutil.ReadFile(p.iconsPath())
+	if err != nil {
+		return icons
+	}
+	_ = json.Unmarshal(b, &icons)
+	return icons
+}
+
+func (p *persistence) SetIcon(ctx context.Context, collection, icon string) error {
+	icons := p.Icons(ctx)
+	if icon == "" {
+		delete(icons, collection)
+	} else {
+		icons[collection] = icon
+	}
+	if len(icons) == 0 {
+		if err := os.Remove(p.iconsPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.MarshalIndent(icons, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p.iconsPath(), b, 0644)
+}
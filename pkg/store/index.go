@@ -0,0 +1,266 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// indexSuffix is added to the store path for the search index.
+const indexSuffix = ".index.json"
+
+// Searcher is implemented by persistence with an index of the words, #tags
+// and @mentions of the messages, so a search does not read every entry.
+type Searcher interface {
+	// Search returns the entries with every term in the query, see Terms.
+	Search(ctx context.Context, query string) []*entry.Entry
+	// Tags returns how many entries have each #tag and @mention.
+	Tags(ctx context.Context) map[string]int
+}
+
+// Terms returns what a message is indexed by: its words in lower case, and
+// its #tags and @mentions with their mark as well as without.
+func Terms(message string) []string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0)
+	add := func(t string) {
+		if t != "" && !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	word := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+	}
+	fields := strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !word(r) && r != '#' && r != '@'
+	})
+	for _, f := range fields {
+		mark := ""
+		if f[0] == '#' || f[0] == '@' {
+			mark = f[:1]
+		}
+		w := strings.Trim(f, "#@-_")
+		if w == "" {
+			continue
+		}
+		if mark != "" {
+			add(mark + w)
+		}
+		add(w)
+	}
+	return terms
+}
+
+// isTag returns true for a #tag or @mention term.
+func isTag(term string) bool {
+	return strings.HasPrefix(term, "#") || strings.HasPrefix(term, "@")
+}
+
+// indexed is what the index knows about a key.
+type indexed struct {
+	// Stamp is the modification time of the key when it was indexed.
+	Stamp time.Time `json:"stamp"`
+	Terms []string  `json:"terms"`
+}
+
+// index maps terms to the keys that have them. Only the terms of each key
+// are saved, the postings are built when it is loaded.
+type index struct {
+	mu   sync.Mutex
+	path string
+	keys map[string]indexed
+	// postings are the keys with each term.
+	postings map[string]map[string]bool
+}
+
+func (x *index) load() {
+	x.keys = make(map[string]indexed)
+	x.postings = make(map[string]map[string]bool)
+	if x.path == "" {
+		// Built again from the entries.
+	} else if b, err := ioutil.ReadFile(x.path); err == nil {
+		// A broken index is built again.
+		_ = json.Unmarshal(b, &x.keys)
+	}
+	for key, i := range x.keys {
+		x.post(key, i.Terms)
+	}
+}
+
+func (x *index) save() error {
+	if x.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(x.keys)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(x.path, b, 0600)
+}
+
+func (x *index) post(key string, terms []string) {
+	for _, t := range terms {
+		if x.postings[t] == nil {
+			x.postings[t] = make(map[string]bool)
+		}
+		x.postings[t][key] = true
+	}
+}
+
+func (x *index) put(key string, stamp time.Time, e *entry.Entry) {
+	x.drop(key)
+	terms := Terms(e.Message)
+	x.keys[key] = indexed{Stamp: stamp, Terms: terms}
+	x.post(key, terms)
+}
+
+func (x *index) drop(key string) {
+	for _, t := range x.keys[key].Terms {
+		delete(x.postings[t], key)
+		if len(x.postings[t]) == 0 {
+			delete(x.postings, t)
+		}
+	}
+	delete(x.keys, key)
+}
+
+// lookup returns the keys with every term.
+func (x *index) lookup(terms []string) []string {
+	keys := make([]string, 0)
+	if len(terms) == 0 {
+		return keys
+	}
+	for key := range x.postings[terms[0]] {
+		all := true
+		for _, t := range terms[1:] {
+			all = all && x.postings[t][key]
+		}
+		if all {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// searchIndex returns the index, loaded and brought up to date with the
+// store. Only keys written since they were indexed are read.
+func (p *persistence) searchIndex(ctx context.Context) *index {
+	x := &p.x
+	// The index of an encrypted journal is not written, it has the words of
+	// every message.
+	if x.path == "" && p.sealer == nil {
+		x.path = indexPath(p.d.BasePath)
+	}
+	if x.keys == nil {
+		x.load()
+	}
+
+	changed := false
+	stamps := p.modTimes(ctx)
+	for key, stamp := range stamps {
+		if i, ok := x.keys[key]; ok && i.Stamp.Equal(stamp) {
+			continue
+		}
+		if e, err := p.read(key); err == nil {
+			x.put(key, stamp, e)
+			changed = true
+		}
+	}
+	for key := range x.keys {
+		if _, ok := stamps[key]; !ok {
+			x.drop(key)
+			changed = true
+		}
+	}
+	if changed {
+		_ = x.save()
+	}
+	return x
+}
+
+// watched updates a loaded index with the entries a watch saw change, nil
+// for removed keys, so the next search has less to catch up on.
+func (x *index) watched(stamps map[string]time.Time, changed map[string]*entry.Entry) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.keys == nil || len(changed) == 0 {
+		return
+	}
+	for key, e := range changed {
+		if e == nil {
+			x.drop(key)
+		} else {
+			x.put(key, stamps[key], e)
+		}
+	}
+	_ = x.save()
+}
+
+func (p *persistence) Search(ctx context.Context, query string) []*entry.Entry {
+	p.x.mu.Lock()
+	defer p.x.mu.Unlock()
+	x := p.searchIndex(ctx)
+
+	found := make([]*entry.Entry, 0)
+	for _, key := range x.lookup(Terms(query)) {
+		if e, err := p.read(key); err == nil {
+			found = append(found, e)
+		}
+	}
+	entry.Sort(found)
+	return found
+}
+
+func (p *persistence) Tags(ctx context.Context) map[string]int {
+	p.x.mu.Lock()
+	defer p.x.mu.Unlock()
+	x := p.searchIndex(ctx)
+
+	tags := make(map[string]int)
+	for t, keys := range x.postings {
+		if isTag(t) {
+			tags[t] = len(keys)
+		}
+	}
+	return tags
+}
+
+// CheckIndex makes sure the search index of the journal at base can be
+// written. An index that can not be saved is built again by every search.
+func CheckIndex(base string) error {
+	path := indexPath(base)
+	if fi, err := os.Stat(path); err == nil {
+		if !fi.Mode().IsRegular() {
+			return app.Invalid("index", path, "it is not a file, remove it and it is built again")
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return app.Invalid("index", path, fmt.Sprintf("can not write to it: %v", err))
+		}
+		return f.Close()
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".bujo-check-")
+	if err != nil {
+		return app.Invalid("index", path, fmt.Sprintf("can not write next to the journal: %v", err))
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// indexPath is where the index of the store at base is kept.
+func indexPath(base string) string {
+	return strings.TrimRight(base, string(os.PathSeparator)) + indexSuffix
+}
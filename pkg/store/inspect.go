@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/peterbourgon/diskv/v3"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+// CollectionInfo describes a collection and what it takes on disk.
+type CollectionInfo struct {
+	Name    string
+	Kind    collection.Kind
+	Entries int
+	// ByBullet counts the entries by bullet.
+	ByBullet map[glyph.Bullet]int
+	// Created is when the oldest entry was created.
+	Created time.Time
+	// Modified is when an entry was last written.
+	Modified time.Time
+	// Bytes is the size of the entries on disk.
+	Bytes int64
+}
+
+// Inspector is implemented by persistence that can describe a collection.
+type Inspector interface {
+	CollectionInfo(ctx context.Context, name string) (CollectionInfo, error)
+}
+
+func (p *persistence) CollectionInfo(ctx context.Context, name string) (CollectionInfo, error) {
+	kind, _ := collection.Parse(name)
+	info := CollectionInfo{
+		Name:     name,
+		Kind:     kind,
+		ByBullet: make(map[glyph.Bullet]int),
+	}
+
+	ck := toCollection(name)
+	for key := range p.d.Keys(ctx.Done()) {
+		pk := keyToPathTransform(key)
+		if pk.Path[0] != ck {
+			continue
+		}
+		e, err := p.read(key)
+		if err != nil {
+			continue
+		}
+		info.Entries++
+		info.ByBullet[e.Bullet]++
+		if info.Created.IsZero() || e.Created.Before(info.Created) {
+			info.Created = e.Created.Time
+		}
+
+		if fi, err := p.stat(key); err == nil {
+			info.Bytes += fi.Size()
+			if fi.ModTime().After(info.Modified) {
+				info.Modified = fi.ModTime()
+			}
+		}
+	}
+
+	if info.Entries == 0 {
+		return info, fmt.Errorf("%w: %s", app.ErrCollectionNotFound, name)
+	}
+	return info, nil
+}
+
+// stat returns the file info of the entry stored at key.
+// rewrite writes data to key of d, keeping the time its file was written,
+// for maintenance that does not change the entry.
+func rewrite(d *diskv.Diskv, key string, data []byte) error {
+	pk := keyToPathTransform(key)
+	filename := filepath.Join(append([]string{d.BasePath}, append(pk.Path, pk.FileName)...)...)
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if err := d.Write(key, data); err != nil {
+		return err
+	}
+	return os.Chtimes(filename, fi.ModTime(), fi.ModTime())
+}
+
+func (p *persistence) stat(key string) (os.FileInfo, error) {
+	pk := keyToPathTransform(key)
+	return os.Stat(filepath.Join(append([]string{p.d.BasePath}, append(pk.Path, pk.FileName)...)...))
+}
@@ -0,0 +1,344 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// ErrConflict is returned when an entry was changed on the remote since it
+// was last pulled.
+var ErrConflict = fmt.Errorf("%w on the remote, run again to pull the latest", app.ErrConflict)
+
+// Transport moves journal files between a local cache and a remote journal.
+type Transport interface {
+	// Pull mirrors the remote journal into the local directory.
+	Pull(ctx context.Context, local string) error
+	// Push copies a single file, relative to the local directory, to the
+	// remote journal.
+	Push(ctx context.Context, local, rel string) error
+	// Checksum returns the md5 of a remote file, or "" if it does not exist.
+	Checksum(ctx context.Context, rel string) (string, error)
+	// PullSidecar copies the file kept next to the remote journal with the
+	// suffix, like ".icons.json", to path, or removes path if the remote has
+	// none.
+	PullSidecar(ctx context.Context, path, suffix string) error
+	// PushSidecar copies path to the file next to the remote journal with
+	// the suffix, or removes that file if path does not exist.
+	PushSidecar(ctx context.Context, path, suffix string) error
+}
+
+// remote is a journal that lives on another host. It is mirrored into the
+// local path on load and every write is pushed back.
+type remote struct {
+	*persistence
+	t    Transport
+	base string
+
+	mu sync.Mutex
+	// pulled is the checksum of each file as it was when last pulled, used to
+	// detect remote changes.
+	pulled map[string]string
+}
+
+func newRemote(p *persistence, base, remoteURL string) (*remote, error) {
+	t, err := NewTransport(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	r := &remote{persistence: p, t: t, base: base}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	if err := r.pull(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *remote) pull(ctx context.Context) error {
+	if err := os.MkdirAll(r.base, 0755); err != nil {
+		return err
+	}
+	start := time.Now()
+	if err := r.t.Pull(ctx, r.base); err != nil {
+		logging.Error("failed to pull remote journal", "path", r.base, "err", err)
+		return fmt.Errorf("failed to pull remote journal: %v", err)
+	}
+	for suffix, path := range r.sidecars() {
+		if err := r.t.PullSidecar(ctx, path, suffix); err != nil {
+			logging.Error("failed to pull remote journal", "path", path, "err", err)
+			return fmt.Errorf("failed to pull remote journal: %v", err)
+		}
+	}
+
+	pulled := make(map[string]string)
+	err := filepath.Walk(r.base, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(r.base, path)
+		if err != nil {
+			return err
+		}
+		sum, err := fileChecksum(path)
+		if err != nil {
+			return err
+		}
+		pulled[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.pulled = pulled
+	r.mu.Unlock()
+	logging.Debug("pulled remote journal", "path", r.base, "files", len(pulled), "took", time.Since(start))
+	// Commands that only read use the mirror while the pull is recent.
+	return ioutil.WriteFile(pulledPath(r.base), []byte(time.Now().Format(time.RFC3339)), 0600)
+}
+
+func (r *remote) Store(e *entry.Entry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	key := toKey(e)
+	rel := keyToRel(key)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sum, err := r.t.Checksum(ctx, rel)
+	if err != nil {
+		return err
+	}
+	if sum != "" && sum != r.pulled[rel] {
+		logging.Warn("remote changed since the pull", "key", key)
+		return ErrConflict
+	}
+
+	if err := r.persistence.Store(e); err != nil {
+		return err
+	}
+	if err := r.t.Push(ctx, r.base, rel); err != nil {
+		logging.Error("failed to push", "key", key, "err", err)
+		return fmt.Errorf("failed to push %s: %v", rel, err)
+	}
+
+	sum, err = fileChecksum(filepath.Join(r.base, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	r.pulled[rel] = sum
+	return nil
+}
+
+// sidecars returns the local path of each file kept next to the journal,
+// by its suffix.
+func (r *remote) sidecars() map[string]string {
+	return map[string]string{
+		iconsSuffix:   r.iconsPath(),
+		bulletsSuffix: r.bulletsPath(),
+	}
+}
+
+// pushSidecar pushes the sidecar file with the suffix after a local write.
+// Sidecars are small and only for display, the last write wins.
+func (r *remote) pushSidecar(ctx context.Context, suffix string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := r.sidecars()[suffix]
+	if err := r.t.PushSidecar(ctx, path, suffix); err != nil {
+		logging.Error("failed to push", "path", path, "err", err)
+		return fmt.Errorf("failed to push %s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func (r *remote) SetIcon(ctx context.Context, collection, icon string) error {
+	if err := r.persistence.SetIcon(ctx, collection, icon); err != nil {
+		return err
+	}
+	return r.pushSidecar(ctx, iconsSuffix)
+}
+
+func (r *remote) SetDefaultBullet(ctx context.Context, collection string, bullet glyph.Bullet) error {
+	if err := r.persistence.SetDefaultBullet(ctx, collection, bullet); err != nil {
+		return err
+	}
+	return r.pushSidecar(ctx, bulletsSuffix)
+}
+
+func (r *remote) Compact(ctx context.Context) (int, error) {
+	return 0, fmt.Errorf("%w, compact a remote journal on the host it lives on", app.ErrUnsupported)
+}
+
+func (r *remote) Encrypt(ctx context.Context, passphrase string) (int, error) {
+	return 0, fmt.Errorf("%w, encrypt a remote journal on the host it lives on", app.ErrUnsupported)
+}
+
+// keyToRel returns the file path of a key relative to the base path.
+func keyToRel(key string) string {
+	pk := keyToPathTransform(key)
+	return strings.Join(append(pk.Path, pk.FileName), "/")
+}
+
+func fileChecksum(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", md5.Sum(b)), nil
+}
+
+// NewTransport returns the transport for the remote url. Supported are
+// ssh://[user@]host[:port]/path and sftp:// urls of the same shape.
+func NewTransport(remoteURL string) (Transport, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ssh", "sftp":
+		if u.Host == "" || u.Path == "" {
+			return nil, fmt.Errorf("remote url requires a host and path: %s", remoteURL)
+		}
+		return &sshTransport{
+			host: u.Hostname(),
+			port: u.Port(),
+			user: u.User.Username(),
+			path: u.Path,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported remote scheme: %s", u.Scheme)
+	}
+}
+
+// sshTransport uses the system ssh and rsync, so ssh config, keys and agents
+// work as they do for the user.
+type sshTransport struct {
+	host string
+	port string
+	user string
+	path string
+}
+
+func (s *sshTransport) target() string {
+	if s.user != "" {
+		return s.user + "@" + s.host
+	}
+	return s.host
+}
+
+func (s *sshTransport) sshArgs() []string {
+	if s.port != "" {
+		return []string{"-p", s.port}
+	}
+	return nil
+}
+
+// ssh runs a command on the remote. Each argument is quoted, the remote
+// shell sees them as they are.
+func (s *sshTransport) ssh(ctx context.Context, args ...string) *exec.Cmd {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return exec.CommandContext(ctx, "ssh", append(s.sshArgs(), s.target(), strings.Join(quoted, " "))...)
+}
+
+// shellQuote quotes s for a posix shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// rsync runs rsync over ssh. Paths are not split by the remote shell, see
+// --protect-args.
+func (s *sshTransport) rsync(ctx context.Context, args ...string) error {
+	sshCmd := strings.Join(append([]string{"ssh"}, s.sshArgs()...), " ")
+	cmd := exec.CommandContext(ctx, "rsync", append([]string{"-az", "--protect-args", "-e", sshCmd}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (s *sshTransport) Pull(ctx context.Context, local string) error {
+	// The remote might not exist yet for a new journal.
+	mkdir := s.ssh(ctx, "mkdir", "-p", "--", s.path)
+	if err := mkdir.Run(); err != nil {
+		return err
+	}
+	// --delete drops what was deleted or moved on the remote from the mirror.
+	return s.rsync(ctx, "--delete", s.target()+":"+strings.TrimSuffix(s.path, "/")+"/", local+"/")
+}
+
+func (s *sshTransport) Push(ctx context.Context, local, rel string) error {
+	// -R with the "/./" marker recreates the relative directories remotely.
+	return s.rsync(ctx, "-R", strings.TrimSuffix(local, "/")+"/./"+rel, s.target()+":"+s.path)
+}
+
+// sidecar returns the remote path of the file next to the journal with the
+// suffix.
+func (s *sshTransport) sidecar(suffix string) string {
+	return strings.TrimSuffix(s.path, "/") + suffix
+}
+
+func (s *sshTransport) PullSidecar(ctx context.Context, path, suffix string) error {
+	out, err := s.ssh(ctx, "cat", "--", s.sidecar(suffix)).Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return err
+		}
+		// Not on the remote.
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return ioutil.WriteFile(path, out, 0644)
+}
+
+func (s *sshTransport) PushSidecar(ctx context.Context, path, suffix string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return s.ssh(ctx, "rm", "-f", "--", s.sidecar(suffix)).Run()
+	}
+	return s.rsync(ctx, path, s.target()+":"+s.sidecar(suffix))
+}
+
+func (s *sshTransport) Checksum(ctx context.Context, rel string) (string, error) {
+	path := strings.TrimSuffix(s.path, "/") + "/" + rel
+	cmd := s.ssh(ctx, "md5sum", "--", path)
+	out, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			// Not on the remote yet.
+			return "", nil
+		}
+		return "", err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	// A sum is marked with \ when the name has to be escaped.
+	return strings.TrimPrefix(fields[0], `\`), nil
+}
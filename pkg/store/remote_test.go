@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []string{
+		"plain",
+		"with spaces",
+		"it's",
+		"$(touch pwned); `id` && *",
+		"'",
+		"",
+		"new\nline",
+	}
+	for _, want := range tests {
+		cmd := exec.Command("sh", "-c", "printf %s "+shellQuote(want))
+		got, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%q: %v", want, err)
+		}
+		if string(got) != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSSHQuotesRemoteArgs(t *testing.T) {
+	s := &sshTransport{host: "example.com", port: "2222", user: "me", path: "/home/me/my journal"}
+	cmd := s.ssh(context.Background(), "mkdir", "-p", "--", s.path+"/$(id)")
+	want := []string{"ssh", "-p", "2222", "me@example.com", `'mkdir' '-p' '--' '/home/me/my journal/$(id)'`}
+	if strings.Join(cmd.Args, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", cmd.Args, want)
+	}
+}
+
+// fakeTransport keeps the sidecars of a remote journal in memory.
+type fakeTransport struct {
+	sidecars map[string][]byte
+}
+
+func (f *fakeTransport) Pull(ctx context.Context, local string) error { return nil }
+
+func (f *fakeTransport) Push(ctx context.Context, local, rel string) error { return nil }
+
+func (f *fakeTransport) Checksum(ctx context.Context, rel string) (string, error) { return "", nil }
+
+func (f *fakeTransport) PullSidecar(ctx context.Context, path, suffix string) error {
+	b, ok := f.sidecars[suffix]
+	if !ok {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+func (f *fakeTransport) PushSidecar(ctx context.Context, path, suffix string) error {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		delete(f.sidecars, suffix)
+		return nil
+	}
+	f.sidecars[suffix] = b
+	return err
+}
+
+func TestRemoteSidecars(t *testing.T) {
+	ctx := context.Background()
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+	defer os.Remove(p.iconsPath())
+	defer os.Remove(p.bulletsPath())
+	defer os.Remove(pulledPath(p.d.BasePath))
+
+	ft := &fakeTransport{sidecars: make(map[string][]byte)}
+	r := &remote{persistence: p, t: ft, base: p.d.BasePath}
+
+	// Writes are pushed.
+	if err := r.SetIcon(ctx, "work", "💼"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetDefaultBullet(ctx, "work", glyph.Task); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ft.sidecars[iconsSuffix]; !ok {
+		t.Error("icons were not pushed")
+	}
+	if _, ok := ft.sidecars[bulletsSuffix]; !ok {
+		t.Error("default bullets were not pushed")
+	}
+
+	// Removing the last one removes it on the remote.
+	if err := r.SetIcon(ctx, "work", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ft.sidecars[iconsSuffix]; ok {
+		t.Error("icons are still on the remote")
+	}
+
+	// A pull mirrors the remote, the icon set there is pulled and the
+	// default bullet removed there is dropped.
+	ft.sidecars[iconsSuffix] = []byte(`{"home":"🏠"}`)
+	delete(ft.sidecars, bulletsSuffix)
+	if err := r.pull(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Icons(ctx)["home"]; got != "🏠" {
+		t.Errorf("pulled icon = %q, want 🏠", got)
+	}
+	if got := r.DefaultBullets(ctx); len(got) != 0 {
+		t.Errorf("pulled default bullets = %v, want none", got)
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Renamer is implemented by persistence that can rename a collection.
+type Renamer interface {
+	// Rename moves every entry of the collection from into the collection
+	// to and returns how many were moved.
+	Rename(ctx context.Context, from, to string) (int, error)
+}
+
+func (p *persistence) Rename(ctx context.Context, from, to string) (int, error) {
+	if from == to {
+		return 0, nil
+	}
+	ck := toCollection(from)
+
+	keys := make([]string, 0)
+	for key := range p.d.Keys(ctx.Done()) {
+		if keyToPathTransform(key).Path[0] == ck {
+			keys = append(keys, key)
+		}
+	}
+
+	// The collection is renamed all or nothing.
+	err := p.Transact(ctx, func(tx Tx) error {
+		for _, key := range keys {
+			e, err := p.read(key)
+			if err != nil {
+				return err
+			}
+			if err := tx.Delete(e); err != nil {
+				return err
+			}
+			e.Collection = to
+			if err := tx.Store(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// The icon follows the collection.
+	if icon, ok := p.Icons(ctx)[from]; ok {
+		if err := p.SetIcon(ctx, from, ""); err != nil {
+			return 0, err
+		}
+		if err := p.SetIcon(ctx, to, icon); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+func (r *remote) Rename(ctx context.Context, from, to string) (int, error) {
+	return 0, fmt.Errorf("%w, rename collections of a remote journal on the host it lives on", app.ErrUnsupported)
+}
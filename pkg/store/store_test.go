@@ -0,0 +1,41 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+// testConfig is a local journal in a temp dir.
+type testConfig struct {
+	path       string
+	compress   bool
+	passphrase string
+	calendar   CalendarAccount
+}
+
+func (c testConfig) BasePath() string           { return c.path }
+func (c testConfig) Compress() bool             { return c.compress }
+func (c testConfig) Remote() string             { return "" }
+func (c testConfig) RemoteCache() time.Duration { return 0 }
+func (c testConfig) Calendar() CalendarAccount  { return c.calendar }
+func (c testConfig) Encrypted() bool            { return c.passphrase != "" }
+func (c testConfig) Passphrase() string         { return c.passphrase }
+
+// newTestStore returns a journal in a new temp dir, and a func to remove it.
+func newTestStore(t *testing.T, cfg testConfig) (*persistence, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "bujo-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.path = dir
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	p, err := local(cfg)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return p, cleanup
+}
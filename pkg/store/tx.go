@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// Tx collects the changes of a transaction.
+type Tx interface {
+	Store(e *entry.Entry) error
+	Delete(e *entry.Entry) error
+}
+
+// Transactor is implemented by persistence that can apply a group of
+// changes all or nothing.
+type Transactor interface {
+	// Transact runs fn and then applies the changes it made to tx. Nothing
+	// is applied if fn returns an error. If applying a change fails, the
+	// changes already applied are undone. A watcher sees the changes all at
+	// once.
+	Transact(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// change is a write of data to key, or an erase if data is nil.
+type change struct {
+	key  string
+	data []byte
+}
+
+type tx struct {
+	p       *persistence
+	changes []change
+	// stored are the entries written, their calendar items are updated once
+	// the transaction is applied.
+	stored []*entry.Entry
+}
+
+func (t *tx) Store(e *entry.Entry) error {
+	key, data, err := t.p.encode(e)
+	if err != nil {
+		return err
+	}
+	t.changes = append(t.changes, change{key: key, data: data})
+	t.stored = append(t.stored, e)
+	return nil
+}
+
+func (t *tx) Delete(e *entry.Entry) error {
+	if e.ID == "" {
+		return errors.New("can not delete an entry without an id")
+	}
+	t.changes = append(t.changes, change{key: toKey(e)})
+	return nil
+}
+
+// erase removes key, for data that may not decode to an entry with that key.
+func (t *tx) erase(key string) {
+	t.changes = append(t.changes, change{key: key})
+}
+
+func (p *persistence) Transact(ctx context.Context, fn func(tx Tx) error) error {
+	t := &tx{p: p}
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	p.tx.Lock()
+	defer p.tx.Unlock()
+
+	// Keep what was there before to undo a failed transaction.
+	before := make(map[string][]byte)
+	order := make([]string, 0, len(t.changes))
+	for _, c := range t.changes {
+		if _, ok := before[c.key]; ok {
+			continue
+		}
+		before[c.key] = nil
+		order = append(order, c.key)
+		if p.d.Has(c.key) {
+			val, err := p.d.Read(c.key)
+			if err != nil {
+				return err
+			}
+			before[c.key] = val
+		}
+	}
+
+	for _, c := range t.changes {
+		if err := p.apply(c); err != nil {
+			if rerr := p.undo(order, before); rerr != nil {
+				return fmt.Errorf("%v, and undoing the transaction failed: %v", err, rerr)
+			}
+			return err
+		}
+	}
+	for _, e := range t.stored {
+		p.updateCalendar(e)
+	}
+	return nil
+}
+
+func (p *persistence) apply(c change) error {
+	if c.data == nil {
+		if !p.d.Has(c.key) {
+			return nil
+		}
+		return p.d.Erase(c.key)
+	}
+	return p.d.Write(c.key, c.data)
+}
+
+func (p *persistence) undo(keys []string, before map[string][]byte) error {
+	var last error
+	for _, key := range keys {
+		if err := p.apply(change{key: key, data: before[key]}); err != nil {
+			last = err
+		}
+	}
+	return last
+}
+
+func (r *remote) Transact(ctx context.Context, fn func(tx Tx) error) error {
+	return fmt.Errorf("%w, transactions are not supported for a remote journal", app.ErrUnsupported)
+}
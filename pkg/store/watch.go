@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/peterbourgon/diskv/v3"
+
+	"tableflip.dev/bujo/pkg/entry"
+)
+
+// Change kinds of an entry in an Event.
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeRemoved  = "removed"
+)
+
+// Event is a change to the entries of a collection.
+type Event struct {
+	Collection string `json:"collection"`
+	// Changes are the entries that changed. If it is empty, the collection
+	// should be read again.
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Change is a change to one entry of a collection.
+type Change struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	// Entry is the entry as it was read after the change, nil if it was
+	// removed.
+	Entry *entry.Entry `json:"entry,omitempty"`
+}
+
+// Watcher is implemented by persistence that can report changes made to the
+// journal, including changes made by other processes.
+type Watcher interface {
+	// Watch streams events until ctx is done, then closes the channel.
+	Watch(ctx context.Context) (<-chan Event, error)
+}
+
+const (
+	watchInterval = 2 * time.Second
+)
+
+// Watch polls the store for changed files. Changes found in the same poll
+// are coalesced into one event per collection.
+func (p *persistence) Watch(ctx context.Context) (<-chan Event, error) {
+	events := make(chan Event)
+	seen := p.modTimes(ctx)
+
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// A transaction is seen all at once.
+			p.tx.RLock()
+			now := p.modTimes(ctx)
+			p.tx.RUnlock()
+			for _, ev := range p.changed(seen, now) {
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+			seen = now
+		}
+	}()
+
+	return events, nil
+}
+
+// modTimes returns the modification time of every key in the store.
+func (p *persistence) modTimes(ctx context.Context) map[string]time.Time {
+	times := make(map[string]time.Time)
+	for key := range p.d.Keys(ctx.Done()) {
+		if fi, err := os.Stat(p.filename(keyToPathTransform(key))); err == nil {
+			times[key] = fi.ModTime()
+		}
+	}
+	return times
+}
+
+// changed returns an event for each collection with added, removed or
+// modified keys, and drops any cached values for them so they are read
+// again. An entry that can not be read leaves the changes of its collection
+// empty, so the whole collection is read again. The search index is kept in
+// step, if it is loaded.
+func (p *persistence) changed(before, after map[string]time.Time) []Event {
+	changes := make(map[string][]Change)
+	unreadable := make(map[string]bool)
+	indexed := make(map[string]*entry.Entry)
+	for key, t := range after {
+		bt, ok := before[key]
+		if ok && bt.Equal(t) {
+			continue
+		}
+		c := fromCollection(keyToPathTransform(key).Path[0])
+		e, err := p.reread(key)
+		if err != nil {
+			unreadable[c] = true
+			continue
+		}
+		kind := ChangeModified
+		if !ok {
+			kind = ChangeAdded
+		}
+		changes[c] = append(changes[c], Change{ID: e.ID, Kind: kind, Entry: e})
+		indexed[key] = e
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			pk := keyToPathTransform(key)
+			c := fromCollection(pk.Path[0])
+			changes[c] = append(changes[c], Change{ID: pk.FileName, Kind: ChangeRemoved})
+			indexed[key] = nil
+		}
+	}
+
+	p.x.watched(after, indexed)
+
+	for c := range unreadable {
+		changes[c] = nil
+	}
+
+	events := make([]Event, 0, len(changes))
+	for c, cs := range changes {
+		events = append(events, Event{Collection: c, Changes: cs})
+	}
+	return events
+}
+
+// reread reads a key from disk, dropping its cached value.
+func (p *persistence) reread(key string) (*entry.Entry, error) {
+	rc, err := p.d.ReadStream(key, true)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	val, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	return p.decode(key, val)
+}
+
+func (p *persistence) filename(pk *diskv.PathKey) string {
+	return filepath.Join(append([]string{p.d.BasePath}, append(pk.Path, pk.FileName)...)...)
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+func TestChangedCoalesces(t *testing.T) {
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+	ctx := context.Background()
+
+	kept := entry.New("Work", glyph.Task, "kept")
+	edited := entry.New("Work", glyph.Task, "edited")
+	removed := entry.New("Home", glyph.Task, "removed")
+	for _, e := range []*entry.Entry{kept, edited, removed} {
+		if err := p.Store(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := p.modTimes(ctx)
+
+	// Everything below happens between two polls.
+	added := entry.New("Work", glyph.Note, "added")
+	if err := p.Store(added); err != nil {
+		t.Fatal(err)
+	}
+	edited.Complete()
+	if err := p.Store(edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Transact(ctx, func(tx Tx) error { return tx.Delete(removed) }); err != nil {
+		t.Fatal(err)
+	}
+	after := p.modTimes(ctx)
+	// The edit may land in the same tick of the clock as the first write.
+	before[toKey(edited)] = before[toKey(edited)].Add(-time.Second)
+
+	events := p.changed(before, after)
+	sort.Slice(events, func(i, j int) bool { return events[i].Collection < events[j].Collection })
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want one for each of 2 collections: %+v", len(events), events)
+	}
+
+	home, work := events[0], events[1]
+	if home.Collection != "Home" || len(home.Changes) != 1 ||
+		home.Changes[0] != (Change{ID: removed.ID, Kind: ChangeRemoved}) {
+		t.Errorf("got %+v, want %s removed from Home", home, removed.ID)
+	}
+
+	got := make(map[string]string)
+	for _, c := range work.Changes {
+		got[c.ID] = c.Kind
+		if c.Entry == nil || c.Entry.ID != c.ID {
+			t.Errorf("change %s has entry %+v", c.ID, c.Entry)
+		}
+	}
+	want := map[string]string{added.ID: ChangeAdded, edited.ID: ChangeModified}
+	if work.Collection != "Work" || len(got) != len(want) || len(work.Changes) != len(want) {
+		t.Fatalf("got %+v, want %v in Work", work, want)
+	}
+	for id, kind := range want {
+		if got[id] != kind {
+			t.Errorf("%s was %q, want %q", id, got[id], kind)
+		}
+	}
+}
+
+func TestChangedUnreadable(t *testing.T) {
+	p, cleanup := newTestStore(t, testConfig{})
+	defer cleanup()
+	ctx := context.Background()
+
+	e := entry.New("Work", glyph.Task, "task")
+	if err := p.Store(e); err != nil {
+		t.Fatal(err)
+	}
+	before := p.modTimes(ctx)
+	if err := p.d.Write("V29yaw==-2020-01-01-garbage", []byte("not an entry")); err != nil {
+		t.Fatal(err)
+	}
+
+	events := p.changed(before, p.modTimes(ctx))
+	if len(events) != 1 || events[0].Collection != "Work" || events[0].Changes != nil {
+		t.Errorf("got %+v, want Work to be read again", events)
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+
+	"tableflip.dev/bujo/pkg/app"
+)
+
+// Where the config and journal are kept, following the XDG base directory
+// spec. The ~/.bujo.yaml and ~/.bujo.db of older versions are still used if
+// they exist.
+const (
+	// xdgName is the directory bujo uses under the XDG base directories.
+	xdgName = "bujo"
+	// xdgConfigName is the config file in the XDG config directory.
+	xdgConfigName = "config.yaml"
+	// legacyConfigName is the config file in $BUJO_CONFIG_PATH or home.
+	legacyConfigName = ".bujo.yaml"
+	// legacyPath is the journal in home.
+	legacyPath = ".bujo.db"
+	// logName is the log file in the XDG state directory.
+	logName = "bujo.log"
+)
+
+func home() string {
+	dir, err := homedir.Dir()
+	if err != nil {
+		return "."
+	}
+	return dir
+}
+
+// xdgDir returns the bujo directory in the XDG base directory named by env,
+// or in fallback under home if it is not set.
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, xdgName)
+	}
+	return filepath.Join(home(), fallback, xdgName)
+}
+
+// xdgConfigFile is the config file in the XDG config directory.
+func xdgConfigFile() string {
+	return filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), xdgConfigName)
+}
+
+// DefaultPath is where the journal is kept when no path is configured: the
+// ~/.bujo.db of older versions if it exists, otherwise bujo in the XDG data
+// directory.
+func DefaultPath() string {
+	legacy := filepath.Join(home(), legacyPath)
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	return xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// LogPath is where bujo logs to: log.path in the config, or bujo.log in the
+// XDG state directory.
+func LogPath() string {
+	if path := viper.GetString("log.path"); path != "" {
+		if expanded, err := homedir.Expand(path); err == nil {
+			return expanded
+		}
+		return path
+	}
+	return filepath.Join(xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state")), logName)
+}
+
+// ConfigFile returns the config file in use, or where one is written if
+// there is none yet.
+func ConfigFile() string {
+	if f := viper.ConfigFileUsed(); f != "" {
+		return f
+	}
+	if override := os.Getenv("BUJO_CONFIG_PATH"); override != "" {
+		return filepath.Join(override, legacyConfigName)
+	}
+	return xdgConfigFile()
+}
+
+// FirstRun returns true if there is no config file, the path is not set on
+// the env and there is no journal at the default path. LoadConfig must be
+// called first.
+func FirstRun() bool {
+	if viper.ConfigFileUsed() != "" || os.Getenv("BUJO_PATH") != "" {
+		return false
+	}
+	_, err := os.Stat(DefaultPath())
+	return os.IsNotExist(err)
+}
+
+// CheckWritable makes sure the journal can be kept at path, creating the
+// directory if it does not exist.
+func CheckWritable(path string) error {
+	if path == "" {
+		return app.Invalid("path", path, "the journal needs a directory")
+	}
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+		return app.Invalid("path", path, "it is a file, the journal needs a directory")
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return app.Invalid("path", path, fmt.Sprintf("can not create it: %v", err))
+	}
+	f, err := ioutil.TempFile(path, ".bujo-check-")
+	if err != nil {
+		return app.Invalid("path", path, fmt.Sprintf("can not write to it: %v", err))
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// SetConfig sets key to value in the config file, creating it if needed.
+// Only what is in the file is written back, not defaults or the env. It
+// returns the file written.
+func SetConfig(key string, value interface{}) (string, error) {
+	file := ConfigFile()
+	v := viper.New()
+	v.SetConfigFile(file)
+	if _, err := os.Stat(file); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return "", err
+		}
+	} else if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", err
+	}
+	v.Set(key, value)
+	if err := v.WriteConfigAs(file); err != nil {
+		return "", err
+	}
+	return file, nil
+}
@@ -0,0 +1,298 @@
+// Package sync keeps a local journal in step with a git repository, so the
+// same journal can be written on more than one host.
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"tableflip.dev/bujo/pkg/logging"
+)
+
+// DefaultBranch is the branch synced when the config does not say.
+const DefaultBranch = "main"
+
+// conflictDir holds the remote version of an entry changed on both sides,
+// next to the local one. The journal reads it as another version of the
+// same entry, to be reviewed like any conflict.
+const conflictDir = "conflict"
+
+// gitSuffix is added to the journal path for the git repository. It can not
+// live inside of the journal, every file in there is an entry.
+const gitSuffix = ".git"
+
+// Result is what a sync did.
+type Result struct {
+	// Pushed is how many files were changed locally since the last sync.
+	Pushed int
+	// Pulled is how many files were changed on the remote since the last
+	// sync.
+	Pulled int
+	// Conflicts is how many entries were changed on both sides.
+	Conflicts int
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%d pushed, %d pulled, %d conflicts", r.Pushed, r.Pulled, r.Conflicts)
+}
+
+// Git syncs a journal with a branch of a git repository. The journal is
+// made the work tree of a git repository next to it on the first sync. It uses the system git, so the
+// ssh config, keys and credential helpers of the user work as they do for
+// git.
+type Git struct {
+	// Dir is the journal.
+	Dir string
+	// Remote is the url of the repository.
+	Remote string
+	// Branch is DefaultBranch if empty.
+	Branch string
+}
+
+// Validate checks the sync can be run.
+func (g *Git) Validate() error {
+	if g.Dir == "" {
+		return errors.New("sync: missing journal path")
+	}
+	if g.Remote == "" {
+		return errors.New("sync: missing remote, set sync.remote in config")
+	}
+	return nil
+}
+
+func (g *Git) branch() string {
+	if g.Branch == "" {
+		return DefaultBranch
+	}
+	return g.Branch
+}
+
+// Sync commits the local changes, merges the remote ones and pushes the
+// result. An entry changed on both sides keeps both versions, see
+// conflictDir. Only fetching and pushing stop when ctx is done, the local
+// steps always finish so the journal is not left mid merge.
+func (g *Git) Sync(ctx context.Context) (Result, error) {
+	r := Result{}
+	if err := g.Validate(); err != nil {
+		return r, err
+	}
+	if err := g.ensureRepo(); err != nil {
+		return r, err
+	}
+
+	changed, err := g.commit("sync from " + hostname())
+	if err != nil {
+		return r, err
+	}
+	r.Pushed = changed
+
+	if _, err := g.gitContext(ctx, "fetch", "-q", "origin"); err != nil {
+		return r, err
+	}
+	upstream := "refs/remotes/origin/" + g.branch()
+	_, noHead := g.git("rev-parse", "-q", "--verify", "HEAD")
+	if _, err := g.git("rev-parse", "-q", "--verify", upstream); err == nil {
+		if noHead != nil {
+			// Nothing was written here yet, the journal is the remote one.
+			if r.Pulled, err = g.count("ls-tree", "-r", "--name-only", upstream); err != nil {
+				return r, err
+			}
+			_, err = g.git("reset", "-q", "--hard", upstream)
+			return r, err
+		}
+		// Journals started on two hosts have no history in common.
+		since := []string{"diff", "--name-only", "HEAD..." + upstream}
+		if _, err := g.git("merge-base", "HEAD", upstream); err != nil {
+			since = []string{"diff", "--name-only", "HEAD", upstream}
+		}
+		if r.Pulled, err = g.count(since...); err != nil {
+			return r, err
+		}
+		if r.Pulled > 0 {
+			if r.Conflicts, err = g.merge(upstream); err != nil {
+				return r, err
+			}
+		}
+		ahead, err := g.count("rev-list", "--count", upstream+"..HEAD")
+		if err != nil || ahead == 0 {
+			return r, err
+		}
+	} else if noHead != nil {
+		// Nothing to push yet.
+		return r, nil
+	}
+
+	_, err = g.gitContext(ctx, "push", "-q", "origin", "HEAD:refs/heads/"+g.branch())
+	return r, err
+}
+
+// ensureRepo makes the journal the work tree of a git repository with origin
+// at Remote, if it is not one yet.
+func (g *Git) ensureRepo() error {
+	if _, err := os.Stat(g.gitDir()); os.IsNotExist(err) {
+		if err := os.MkdirAll(g.Dir, 0755); err != nil {
+			return err
+		}
+		if _, err := g.git("init", "-q"); err != nil {
+			return err
+		}
+		if _, err := g.git("symbolic-ref", "HEAD", "refs/heads/"+g.branch()); err != nil {
+			return err
+		}
+		logging.Info("made a git repository for the journal", "path", g.gitDir())
+	}
+	// Commits need an identity, sync commits are made as bujo if git has
+	// none.
+	if _, err := g.git("config", "user.email"); err != nil {
+		if _, err := g.git("config", "user.name", "bujo"); err != nil {
+			return err
+		}
+		if _, err := g.git("config", "user.email", "bujo@"+hostname()); err != nil {
+			return err
+		}
+	}
+	if url, err := g.git("remote", "get-url", "origin"); err != nil {
+		_, err = g.git("remote", "add", "origin", g.Remote)
+		return err
+	} else if url != g.Remote {
+		_, err = g.git("remote", "set-url", "origin", g.Remote)
+		return err
+	}
+	return nil
+}
+
+// commit commits every change to the journal, returning how many files
+// changed.
+func (g *Git) commit(message string) (int, error) {
+	if _, err := g.git("add", "-A"); err != nil {
+		return 0, err
+	}
+	changed, err := g.count("diff", "--cached", "--name-only")
+	if err != nil || changed == 0 {
+		return 0, err
+	}
+	_, err = g.git("commit", "-q", "-m", message)
+	return changed, err
+}
+
+// merge merges upstream, keeping both versions of entries changed on both
+// sides. It returns how many entries were.
+func (g *Git) merge(upstream string) (int, error) {
+	_, mergeErr := g.git("merge", "-q", "--no-edit", "--allow-unrelated-histories", upstream)
+	if mergeErr == nil {
+		return 0, nil
+	}
+	out, err := g.git("diff", "--name-only", "--diff-filter=U")
+	if err != nil || out == "" {
+		// Not a conflict, nothing to resolve.
+		_, _ = g.git("merge", "--abort")
+		return 0, mergeErr
+	}
+	paths := strings.Split(out, "\n")
+	for _, path := range paths {
+		if err := g.keepBoth(path); err != nil {
+			_, _ = g.git("merge", "--abort")
+			return 0, err
+		}
+	}
+	if _, err := g.git("commit", "-q", "--no-edit"); err != nil {
+		return 0, err
+	}
+	return len(paths), nil
+}
+
+// keepBoth resolves a conflicted file with the local version in place and
+// the remote one in conflictDir next to it. A file removed on one side
+// keeps the version that was not.
+func (g *Git) keepBoth(path string) error {
+	ours, oursErr := g.git("show", ":2:"+path)
+	theirs, theirsErr := g.git("show", ":3:"+path)
+	if oursErr != nil && theirsErr != nil {
+		return fmt.Errorf("sync: no version of %s to keep", path)
+	}
+	write := func(rel, content string) error {
+		full := filepath.Join(g.Dir, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(full, []byte(content), 0644); err != nil {
+			return err
+		}
+		_, err := g.git("add", "--", rel)
+		return err
+	}
+	switch {
+	case oursErr != nil:
+		return write(path, theirs)
+	case theirsErr != nil:
+		return write(path, ours)
+	}
+	if err := write(path, ours); err != nil {
+		return err
+	}
+	return write(filepath.Join(filepath.Dir(path), conflictDir, filepath.Base(path)), theirs)
+}
+
+// count runs git and returns the number it printed, or the number of lines
+// it printed for commands that list.
+func (g *Git) count(args ...string) (int, error) {
+	out, err := g.git(args...)
+	if err != nil || out == "" {
+		return 0, err
+	}
+	if n, err := strconv.Atoi(out); err == nil {
+		return n, nil
+	}
+	return len(strings.Split(out, "\n")), nil
+}
+
+// gitTimeout bounds the local git commands.
+const gitTimeout = time.Minute
+
+func (g *Git) git(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	return g.gitContext(ctx, args...)
+}
+
+// gitContext runs git in the journal, returning what it printed without
+// the trailing newline. Git never prompts, there may be no terminal.
+func (g *Git) gitContext(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.Dir
+	cmd.Env = append(os.Environ(),
+		"GIT_DIR="+g.gitDir(),
+		"GIT_WORK_TREE="+g.Dir,
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_MERGE_AUTOEDIT=no",
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// The first line says what went wrong, git adds hints after.
+		msg := strings.SplitN(strings.TrimSpace(stderr.String()), "\n", 2)[0]
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, msg)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+func (g *Git) gitDir() string {
+	return filepath.Clean(g.Dir) + gitSuffix
+}
+
+func hostname() string {
+	if h, err := os.Hostname(); err == nil {
+		return h
+	}
+	return "localhost"
+}
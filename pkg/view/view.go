@@ -0,0 +1,193 @@
+// Package view picks entries from across the journal by a filter kept in
+// the config, to be shown as if they were a collection.
+package view
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"tableflip.dev/bujo/pkg/app"
+	"tableflip.dev/bujo/pkg/collection"
+	"tableflip.dev/bujo/pkg/entry"
+	"tableflip.dev/bujo/pkg/glyph"
+)
+
+const (
+	// SortOrder keeps the entries in the order they were added, the default.
+	SortOrder = "order"
+	// SortPriority puts priority entries first.
+	SortPriority = "priority"
+	// SortCreated puts the newest entries first.
+	SortCreated = "created"
+
+	// GroupCollection keeps the entries of each collection together.
+	GroupCollection = "collection"
+	// GroupNone mixes the entries of every collection, the default.
+	GroupNone = "none"
+)
+
+// View is a named filter over the journal, kept in the config like:
+//
+// views:
+//   - name: Work focus
+//     collections: ["Project*"]
+//     bullet: task
+//     tag: work
+//     reaction: question
+//     sort: priority
+//     group: collection
+type View struct {
+	Name string `mapstructure:"name"`
+	// Collections are the collections the view looks in, * matches any
+	// part of a name. Every collection if empty.
+	Collections []string `mapstructure:"collections"`
+	// Bullet only includes entries with this bullet, by alias, if set.
+	Bullet string `mapstructure:"bullet"`
+	// Label only includes entries with this color label, if set.
+	Label string `mapstructure:"label"`
+	// Tag only includes entries with this #tag, if set.
+	Tag string `mapstructure:"tag"`
+	// Reaction only includes entries someone reacted to with this, by
+	// alias, if set. A question only counts while it is open.
+	Reaction string `mapstructure:"reaction"`
+	// Sort is SortOrder, SortPriority or SortCreated.
+	Sort string `mapstructure:"sort"`
+	// Group is GroupNone or GroupCollection.
+	Group string `mapstructure:"group"`
+}
+
+// Validate checks the view can be shown.
+func (v *View) Validate() error {
+	if v.Name == "" {
+		return errors.New("view: missing name")
+	}
+	if v.Bullet != "" {
+		if _, err := glyph.BulletForAlias(v.Bullet); err != nil {
+			return err
+		}
+	}
+	if _, err := glyph.LabelFor(v.Label); err != nil {
+		return err
+	}
+	if v.Reaction != "" {
+		if _, err := glyph.ReactionForAlias(v.Reaction); err != nil {
+			return err
+		}
+	}
+	switch v.Sort {
+	case "", SortOrder, SortPriority, SortCreated:
+	default:
+		return app.Invalid("sort", v.Sort, "expected order, priority or created")
+	}
+	switch v.Group {
+	case "", GroupNone, GroupCollection:
+	default:
+		return app.Invalid("group", v.Group, "expected none or collection")
+	}
+	return nil
+}
+
+// Grouped is true if the entries of each collection are kept together.
+func (v *View) Grouped() bool {
+	return v.Group == GroupCollection
+}
+
+// Entries returns the entries of the view from the collections of the
+// journal, sorted and grouped as the view says. The view is expected to be
+// valid.
+func (v *View) Entries(all map[string][]*entry.Entry) []*entry.Entry {
+	bullet := glyph.Any
+	if v.Bullet != "" {
+		bullet, _ = glyph.BulletForAlias(v.Bullet)
+	}
+	label, _ := glyph.LabelFor(v.Label)
+	tag := strings.TrimPrefix(v.Tag, "#")
+	var reaction glyph.Reaction
+	if v.Reaction != "" {
+		reaction, _ = glyph.ReactionForAlias(v.Reaction)
+	}
+
+	names := make([]string, 0, len(all))
+	for c := range all {
+		if v.matches(c) {
+			names = append(names, c)
+		}
+	}
+	collection.Sort(names)
+
+	at := make(map[string]int, len(names))
+	found := make([]*entry.Entry, 0)
+	for i, c := range names {
+		at[c] = i
+		for _, e := range all[c] {
+			if bullet != glyph.Any && e.Bullet != bullet {
+				continue
+			}
+			if label != glyph.NoLabel && e.Label != label {
+				continue
+			}
+			if tag != "" && !e.HasTag(tag) {
+				continue
+			}
+			if reaction != "" && !e.HasReaction(reaction) {
+				continue
+			}
+			found = append(found, e)
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if v.Grouped() && a.Collection != b.Collection {
+			return at[a.Collection] < at[b.Collection]
+		}
+		switch v.Sort {
+		case SortPriority:
+			if pa, pb := a.Signifier == glyph.Priority, b.Signifier == glyph.Priority; pa != pb {
+				return pa
+			}
+		case SortCreated:
+			return a.Created.After(b.Created.Time)
+		}
+		if !v.Grouped() {
+			return a.SortKey() < b.SortKey()
+		}
+		return false
+	})
+	return found
+}
+
+// matches is true if the view looks in the named collection.
+func (v *View) matches(name string) bool {
+	if len(v.Collections) == 0 {
+		return true
+	}
+	for _, p := range v.Collections {
+		if pattern(p).MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pattern is the regexp of a collection pattern, where * matches any part
+// of a name, slashes included.
+func pattern(p string) *regexp.Regexp {
+	parts := strings.Split(p, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// Find returns the view with name, or nil.
+func Find(views []View, name string) *View {
+	for i := range views {
+		if views[i].Name == name {
+			return &views[i]
+		}
+	}
+	return nil
+}